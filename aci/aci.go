@@ -127,7 +127,11 @@ func createOrUpdateACIContainers(ctx context.Context, aciContext store.AciContex
 		}
 	}
 
-	err = future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+	err = waitWithEvents(ctx, func() error {
+		return future.WaitForCompletionRef(ctx, containerGroupsClient.Client)
+	}, func() (containerinstance.ContainerGroup, error) {
+		return containerGroupsClient.Get(ctx, aciContext.ResourceGroup, *groupDefinition.Name)
+	}, groupDisplay)
 	if err != nil {
 		return err
 	}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 
 	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/docker/compose-cli/aci/convert"
@@ -44,19 +45,19 @@ func newComposeService(ctx store.AciContext) aciComposeService {
 	}
 }
 
-func (cs *aciComposeService) Build(ctx context.Context, project *types.Project) error {
+func (cs *aciComposeService) Build(ctx context.Context, project *types.Project, opts compose.BuildOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (cs *aciComposeService) Push(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (cs *aciComposeService) Push(ctx context.Context, project *types.Project, opts compose.PushOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (cs *aciComposeService) Pull(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (cs *aciComposeService) Pull(ctx context.Context, project *types.Project, opts compose.PullOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (cs *aciComposeService) Create(ctx context.Context, project *types.Project) error {
+func (cs *aciComposeService) Create(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
@@ -64,8 +65,11 @@ func (cs *aciComposeService) Start(ctx context.Context, project *types.Project,
 	return errdefs.ErrNotImplemented
 }
 
-func (cs *aciComposeService) Up(ctx context.Context, project *types.Project, detach bool) error {
+func (cs *aciComposeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 	logrus.Debugf("Up on project with name %q", project.Name)
+	if err := ValidateTags(options.Tags); err != nil {
+		return err
+	}
 
 	if err := autocreateFileshares(ctx, project); err != nil {
 		return err
@@ -76,6 +80,7 @@ func (cs *aciComposeService) Up(ctx context.Context, project *types.Project, det
 		return err
 	}
 
+	addUserTags(&groupDefinition, mergeTags(cs.ctx.Tags, options.Tags))
 	addTag(&groupDefinition, composeContainerTag)
 	return createOrUpdateACIContainers(ctx, cs.ctx, groupDefinition)
 }
@@ -102,8 +107,11 @@ func (cs aciComposeService) warnKeepVolumeOnDown(ctx context.Context, projectNam
 	return nil
 }
 
-func (cs *aciComposeService) Down(ctx context.Context, project string) error {
+func (cs *aciComposeService) Down(ctx context.Context, project string, options compose.DownOptions) error {
 	logrus.Debugf("Down on project with name %q", project)
+	if !options.CreatedSince.IsZero() {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ACI can only remove the whole container group")
+	}
 
 	if err := cs.warnKeepVolumeOnDown(ctx, project); err != nil {
 		return err
@@ -120,6 +128,14 @@ func (cs *aciComposeService) Down(ctx context.Context, project string) error {
 	return err
 }
 
+func (cs *aciComposeService) Stop(ctx context.Context, projectName string, options compose.StopOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Restart(ctx context.Context, projectName string, options compose.RestartOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
 func (cs *aciComposeService) Ps(ctx context.Context, project string) ([]compose.ContainerSummary, error) {
 	groupsClient, err := login.NewContainerGroupsClient(cs.ctx.SubscriptionID)
 	if err != nil {
@@ -158,12 +174,13 @@ func (cs *aciComposeService) Ps(ctx context.Context, project string) ([]compose.
 			Service:    *container.Name,
 			State:      convert.GetStatus(container, group),
 			Publishers: publishers,
+			Tags:       userTags(group),
 		})
 	}
 	return res, nil
 }
 
-func (cs *aciComposeService) List(ctx context.Context, project string) ([]compose.Stack, error) {
+func (cs *aciComposeService) List(ctx context.Context, project string, options compose.ListOptions) ([]compose.Stack, error) {
 	containerGroups, err := getACIContainerGroups(ctx, cs.ctx.SubscriptionID, cs.ctx.ResourceGroup)
 	if err != nil {
 		return nil, err
@@ -194,10 +211,58 @@ func (cs *aciComposeService) List(ctx context.Context, project string) ([]compos
 	return stacks, nil
 }
 
-func (cs *aciComposeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer) error {
+func (cs *aciComposeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Convert(ctx context.Context, project *types.Project, options compose.ConvertOptions) ([]byte, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Health(ctx context.Context, projectName string, service string, index int) ([]compose.ContainerHealth, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Exec(ctx context.Context, project *types.Project, opts compose.ExecOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Events(ctx context.Context, projectName string, options compose.EventsOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (cs *aciComposeService) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
+func (cs *aciComposeService) ResolveImageDigests(ctx context.Context, project *types.Project) (map[string]string, error) {
 	return nil, errdefs.ErrNotImplemented
 }
+
+func (cs *aciComposeService) Divergences(ctx context.Context, project *types.Project) ([]compose.ContainerDivergence, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Images(ctx context.Context, project *types.Project) ([]compose.ImageSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) RestartFailed(ctx context.Context, projectName string, opts compose.RestartFailedOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Stats(ctx context.Context, projectName string) (map[string]compose.ContainerStats, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Snapshot(ctx context.Context, projectName string, opts compose.SnapshotOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Restore(ctx context.Context, opts compose.RestoreOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Commit(ctx context.Context, projectName string, opts compose.CommitOptions) (string, error) {
+	return "", errdefs.ErrNotImplemented
+}
+
+func (cs *aciComposeService) Unsupported(ctx context.Context, project *types.Project) ([]string, error) {
+	return convert.Unsupported(project), nil
+}
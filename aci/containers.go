@@ -85,6 +85,7 @@ func (cs *aciContainerService) Run(ctx context.Context, r containers.ContainerCo
 	if err != nil {
 		return err
 	}
+	addUserTags(&groupDefinition, cs.ctx.Tags)
 	addTag(&groupDefinition, singleContainerTag)
 
 	return createACIContainers(ctx, cs.ctx, groupDefinition)
@@ -38,6 +38,7 @@ type ContextParams struct {
 	Location       string
 	SubscriptionID string
 	ResourceGroup  string
+	Tags           map[string]string
 }
 
 // ErrSubscriptionNotFound is returned when a required subscription is not found
@@ -61,6 +62,9 @@ func newContextCreateHelper() contextCreateACIHelper {
 }
 
 func (helper contextCreateACIHelper) createContextData(ctx context.Context, opts ContextParams) (interface{}, string, error) {
+	if err := ValidateTags(opts.Tags); err != nil {
+		return nil, "", err
+	}
 	subs, err := helper.resourceGroupHelper.GetSubscriptionIDs(ctx)
 	if err != nil {
 		return nil, "", err
@@ -110,6 +114,7 @@ func (helper contextCreateACIHelper) createContextData(ctx context.Context, opts
 		SubscriptionID: subscriptionID,
 		Location:       location,
 		ResourceGroup:  *group.Name,
+		Tags:           opts.Tags,
 	}, description, nil
 }
 
@@ -60,6 +60,30 @@ func TestCreateSpecifiedSubscriptionAndGroup(t *testing.T) {
 	assert.DeepEqual(t, data, aciContext("1234", "myResourceGroup", "eastus"))
 }
 
+func TestCreateWithTags(t *testing.T) {
+	ctx := context.TODO()
+	opts := options("1234", "myResourceGroup")
+	opts.Tags = map[string]string{"team": "payments", "env": "dev"}
+	m := testContextMocks()
+	m.resourceGroupHelper.On("GetSubscriptionIDs", ctx).Return([]subscription.Model{subModel("1234", "Subscription1")}, nil)
+	m.resourceGroupHelper.On("GetGroup", ctx, "1234", "myResourceGroup").Return(group("myResourceGroup", "eastus"), nil)
+
+	data, _, err := m.contextCreateHelper.createContextData(ctx, opts)
+	assert.NilError(t, err)
+	expected := aciContext("1234", "myResourceGroup", "eastus")
+	expected.Tags = map[string]string{"team": "payments", "env": "dev"}
+	assert.DeepEqual(t, data, expected)
+}
+
+func TestErrorOnInvalidTags(t *testing.T) {
+	opts := options("1234", "myResourceGroup")
+	opts.Tags = map[string]string{"cost&center": "42"}
+	m := testContextMocks()
+
+	_, _, err := m.contextCreateHelper.createContextData(context.TODO(), opts)
+	assert.ErrorContains(t, err, `tag name "cost&center" cannot contain`)
+}
+
 func TestErrorOnNonExistentResourceGroup(t *testing.T) {
 	ctx := context.TODO()
 	opts := options("1234", "myResourceGroup")
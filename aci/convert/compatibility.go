@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"github.com/compose-spec/compose-go/compatibility"
+	"github.com/compose-spec/compose-go/types"
+)
+
+// compatibleComposeAttributes are the compose attributes converted into ACI container groups
+var compatibleComposeAttributes = []string{
+	"services.command",
+	"services.depends_on",
+	"services.deploy",
+	"services.deploy.resources.limits",
+	"services.deploy.resources.limits.cpus",
+	"services.deploy.resources.limits.memory",
+	"services.deploy.resources.reservations",
+	"services.deploy.resources.reservations.cpus",
+	"services.deploy.resources.reservations.memory",
+	"services.deploy.restart_policy",
+	"services.deploy.restart_policy.condition",
+	"services.domainname",
+	"services.environment",
+	"services.env_file",
+	"services.healthcheck",
+	"services.healthcheck.interval",
+	"services.healthcheck.retries",
+	"services.healthcheck.start_period",
+	"services.healthcheck.test",
+	"services.healthcheck.timeout",
+	"services.image",
+	"services.ports",
+	"services.ports.protocol",
+	"services.ports.published",
+	"services.ports.target",
+	"services.restart",
+	"services.secrets",
+	"services.secrets.source",
+	"services.secrets.target",
+	"services.volumes",
+	"services.volumes.read_only",
+	"services.volumes.source",
+	"services.volumes.target",
+	"secrets.file",
+	"secrets.name",
+	"volumes",
+	"volumes.driver",
+	"volumes.driver_opts",
+	"volumes.name",
+}
+
+// Unsupported lists the compose file attributes ACI integration doesn't convert
+func Unsupported(project *types.Project) []string {
+	checker := &compatibility.AllowList{
+		Supported: compatibleComposeAttributes,
+	}
+	compatibility.Check(project, checker)
+	var unsupported []string
+	for _, err := range checker.Errors() {
+		unsupported = append(unsupported, err.Error())
+	}
+	return unsupported
+}
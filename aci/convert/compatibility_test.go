@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package convert
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestUnsupported(t *testing.T) {
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:       "web",
+				Image:      "nginx",
+				Privileged: true,
+				Labels:     types.Labels{"team": "web"},
+			},
+		},
+	}
+	assert.DeepEqual(t, Unsupported(project), []string{
+		"services.labels: unsupported attribute",
+		"services.privileged: unsupported attribute",
+	})
+	assert.Equal(t, len(Unsupported(&types.Project{Services: []types.ServiceConfig{{Name: "web", Image: "nginx"}}})), 0)
+}
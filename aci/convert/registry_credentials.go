@@ -20,7 +20,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
@@ -37,6 +36,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/docker/compose-cli/aci/login"
+	"github.com/docker/compose-cli/utils/httpclient"
 )
 
 const (
@@ -156,7 +156,7 @@ func (c cliRegistryHelper) autoLoginAcr(registry string) error {
 		"access_token": {token.AccessToken},
 	}
 	repoAuthURL := fmt.Sprintf("https://%s/oauth2/exchange", registry)
-	res, err := http.Post(repoAuthURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+	res, err := httpclient.New().Post(repoAuthURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
 	if err != nil {
 		return errors.Wrap(err, "could not query ACR token")
 	}
@@ -0,0 +1,155 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/progress"
+)
+
+// eventWarning is the type ACI sets on events reporting a failure, like failed image pull or probe
+const eventWarning = "Warning"
+
+// groupEvent is an ACI event, attached to the container (or group) which reported it
+type groupEvent struct {
+	resource string
+	event    containerinstance.Event
+}
+
+// waitWithEvents waits for container group creation to complete, streaming group and container events meanwhile. On
+// failure, last warning reported by each container is sent to the progress writer as the error detail
+func waitWithEvents(ctx context.Context, wait func() error, get func() (containerinstance.ContainerGroup, error), groupDisplay string) error {
+	w := progress.ContextWriter(ctx)
+	known := map[string]struct{}{}
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	done := make(chan error)
+	go func() {
+		done <- wait()
+	}()
+
+	var (
+		completed bool
+		err       error
+	)
+	for !completed {
+		select {
+		case err = <-done:
+			completed = true
+		case <-ticker.C:
+		}
+		// Events are best effort, failing to get them must not fail the deployment
+		group, getErr := get()
+		if getErr != nil {
+			continue
+		}
+		for _, e := range newGroupEvents(group, groupDisplay, known) {
+			w.Event(progress.NewEvent(e.resource, progress.Working, fmt.Sprintf("%s %s", to.String(e.event.Name), to.String(e.event.Message))))
+		}
+		if err != nil {
+			for _, e := range lastWarnings(group, groupDisplay) {
+				w.Event(progress.ErrorMessageEvent(e.resource, to.String(e.event.Message)))
+			}
+		}
+	}
+	return err
+}
+
+// newGroupEvents returns events from the container group and its containers which have not been reported yet,
+// oldest first. ACI doesn't assign events an ID, so those are identified by their content
+func newGroupEvents(group containerinstance.ContainerGroup, groupDisplay string, known map[string]struct{}) []groupEvent {
+	var events []groupEvent
+	for _, e := range allGroupEvents(group, groupDisplay) {
+		key := fmt.Sprintf("%s/%s/%s/%d/%s", e.resource, to.String(e.event.Name), to.String(e.event.Message), to.Int32(e.event.Count), eventTime(e.event))
+		if _, ok := known[key]; ok {
+			continue
+		}
+		known[key] = struct{}{}
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i].event).Before(eventTime(events[j].event))
+	})
+	return events
+}
+
+// lastWarnings returns, for each container (or the group itself), the most recent warning event
+func lastWarnings(group containerinstance.ContainerGroup, groupDisplay string) []groupEvent {
+	last := map[string]groupEvent{}
+	var resources []string
+	for _, e := range allGroupEvents(group, groupDisplay) {
+		if to.String(e.event.Type) != eventWarning {
+			continue
+		}
+		previous, ok := last[e.resource]
+		if !ok {
+			resources = append(resources, e.resource)
+		}
+		if !ok || !eventTime(e.event).Before(eventTime(previous.event)) {
+			last[e.resource] = e
+		}
+	}
+	warnings := make([]groupEvent, 0, len(resources))
+	for _, r := range resources {
+		warnings = append(warnings, last[r])
+	}
+	return warnings
+}
+
+func allGroupEvents(group containerinstance.ContainerGroup, groupDisplay string) []groupEvent {
+	var events []groupEvent
+	if group.ContainerGroupProperties == nil {
+		return events
+	}
+	if view := group.InstanceView; view != nil && view.Events != nil {
+		for _, e := range *view.Events {
+			events = append(events, groupEvent{resource: groupDisplay, event: e})
+		}
+	}
+	if group.Containers == nil {
+		return events
+	}
+	for _, c := range *group.Containers {
+		name := to.String(c.Name)
+		if name == convert.ComposeDNSSidecarName || c.ContainerProperties == nil || c.InstanceView == nil || c.InstanceView.Events == nil {
+			continue
+		}
+		for _, e := range *c.InstanceView.Events {
+			events = append(events, groupEvent{resource: name, event: e})
+		}
+	}
+	return events
+}
+
+func eventTime(e containerinstance.Event) time.Time {
+	if e.LastTimestamp != nil {
+		return e.LastTimestamp.Time
+	}
+	if e.FirstTimestamp != nil {
+		return e.FirstTimestamp.Time
+	}
+	return time.Time{}
+}
@@ -0,0 +1,112 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/aci/convert"
+)
+
+func event(name, message, eventType string, seconds int) containerinstance.Event {
+	return containerinstance.Event{
+		Name:          to.StringPtr(name),
+		Message:       to.StringPtr(message),
+		Type:          to.StringPtr(eventType),
+		Count:         to.Int32Ptr(1),
+		LastTimestamp: &date.Time{Time: time.Unix(int64(seconds), 0)},
+	}
+}
+
+func groupWithEvents(groupEvents []containerinstance.Event, containerEvents map[string][]containerinstance.Event) containerinstance.ContainerGroup {
+	var containers []containerinstance.Container
+	for name, events := range containerEvents {
+		events := events
+		containers = append(containers, containerinstance.Container{
+			Name: to.StringPtr(name),
+			ContainerProperties: &containerinstance.ContainerProperties{
+				InstanceView: &containerinstance.ContainerPropertiesInstanceView{Events: &events},
+			},
+		})
+	}
+	return containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			InstanceView: &containerinstance.ContainerGroupPropertiesInstanceView{Events: &groupEvents},
+			Containers:   &containers,
+		},
+	}
+}
+
+func TestNewGroupEvents(t *testing.T) {
+	known := map[string]struct{}{}
+	group := groupWithEvents(nil, map[string][]containerinstance.Event{
+		"web": {
+			event("Started", "Started container", "Normal", 3),
+			event("Pulling", "pulling image nginx", "Normal", 1),
+		},
+		convert.ComposeDNSSidecarName: {
+			event("Started", "Started container", "Normal", 2),
+		},
+	})
+
+	events := newGroupEvents(group, "Group demo", known)
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].resource, "web")
+	assert.Equal(t, to.String(events[0].event.Name), "Pulling")
+	assert.Equal(t, to.String(events[1].event.Name), "Started")
+
+	assert.Equal(t, len(newGroupEvents(group, "Group demo", known)), 0)
+
+	group = groupWithEvents([]containerinstance.Event{
+		event("Failed", "Subscription quota exceeded", eventWarning, 4),
+	}, map[string][]containerinstance.Event{
+		"web": {
+			event("Started", "Started container", "Normal", 3),
+			event("Pulling", "pulling image nginx", "Normal", 1),
+		},
+	})
+	events = newGroupEvents(group, "Group demo", known)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].resource, "Group demo")
+	assert.Equal(t, to.String(events[0].event.Message), "Subscription quota exceeded")
+}
+
+func TestLastWarnings(t *testing.T) {
+	group := groupWithEvents(nil, map[string][]containerinstance.Event{
+		"web": {
+			event("Failed", "Failed to pull image", eventWarning, 2),
+			event("Killing", "Killing container", eventWarning, 5),
+			event("Started", "Started container", "Normal", 6),
+		},
+		"db": {
+			event("Started", "Started container", "Normal", 1),
+		},
+	})
+
+	warnings := lastWarnings(group, "Group demo")
+	assert.Equal(t, len(warnings), 1)
+	assert.Equal(t, warnings[0].resource, "web")
+	assert.Equal(t, to.String(warnings[0].event.Message), "Killing container")
+
+	assert.Equal(t, len(lastWarnings(containerinstance.ContainerGroup{}, "Group demo")), 0)
+}
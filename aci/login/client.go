@@ -28,6 +28,7 @@ import (
 
 	"github.com/docker/compose-cli/errdefs"
 	"github.com/docker/compose-cli/internal"
+	"github.com/docker/compose-cli/utils/httpclient"
 )
 
 // NewContainerGroupsClient get client toi manipulate containerGrouos
@@ -50,6 +51,7 @@ func setupClient(aciClient *autorest.Client) error {
 		return err
 	}
 	aciClient.Authorizer = auth
+	aciClient.Sender = httpclient.New()
 	return nil
 }
 
@@ -32,6 +32,8 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure/auth"
 
 	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/utils/httpclient"
 )
 
 var (
@@ -70,7 +72,7 @@ func (helper azureAPIHelper) queryAPIWithHeader(ctx context.Context, authorizati
 	}
 	req = req.WithContext(ctx)
 	req.Header.Add("Authorization", authorizationHeader)
-	res, err := http.DefaultClient.Do(req)
+	res, err := httpclient.New().Do(req)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -82,7 +84,7 @@ func (helper azureAPIHelper) queryAPIWithHeader(ctx context.Context, authorizati
 }
 
 func (helper azureAPIHelper) queryToken(data url.Values, tenantID string) (azureToken, error) {
-	res, err := http.Post(fmt.Sprintf(tokenEndpoint, tenantID), "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+	res, err := httpclient.New().Post(fmt.Sprintf(tokenEndpoint, tenantID), "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
 	if err != nil {
 		return azureToken{}, err
 	}
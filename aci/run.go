@@ -0,0 +1,148 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/aci/convert"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/containers"
+)
+
+func (cs *aciComposeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	groupName := strings.ToLower(fmt.Sprintf("%s-%s-run-%d", project.Name, opts.Service, time.Now().Unix()))
+	oneOff, err := oneOffProject(project, groupName, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := autocreateFileshares(ctx, &oneOff); err != nil {
+		return 0, err
+	}
+
+	groupDefinition, err := convert.ToContainerGroup(ctx, cs.ctx, oneOff, cs.storageLogin)
+	if err != nil {
+		return 0, err
+	}
+	addUserTags(&groupDefinition, cs.ctx.Tags)
+	addTag(&groupDefinition, singleContainerTag)
+
+	logrus.Debugf("Running one-off container for service %q in container group %q", opts.Service, groupName)
+	err = createACIContainers(ctx, cs.ctx, groupDefinition)
+	if err != nil {
+		return 0, err
+	}
+	if opts.AutoRemove {
+		defer func() {
+			// user may have canceled ctx, still delete the container group
+			if _, err := deleteACIContainerGroup(context.Background(), cs.ctx, groupName); err != nil {
+				logrus.Warnf("failed to delete container group %q: %v", groupName, err)
+			}
+		}()
+	}
+
+	logsCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		logsCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	err = streamLogs(logsCtx, cs.ctx, groupName, opts.Service, containers.LogsRequest{
+		Writer: opts.Writer,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if logsCtx.Err() == context.DeadlineExceeded {
+		return 0, fmt.Errorf("service %q one-off container did not complete within %s", opts.Service, opts.Timeout)
+	}
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	group, err := getACIContainerGroup(ctx, cs.ctx, groupName)
+	if err != nil {
+		return 0, err
+	}
+	return getContainerExitCode(group, opts.Service)
+}
+
+// oneOffProject builds a single service project to run command once in a dedicated container group
+func oneOffProject(project *types.Project, groupName string, opts compose.RunOptions) (types.Project, error) {
+	service, err := project.GetService(opts.Service)
+	if err != nil {
+		return types.Project{}, err
+	}
+	if len(opts.Command) > 0 {
+		service.Command = opts.Command
+	}
+	if len(opts.Environment) > 0 {
+		environment := types.MappingWithEquals{}
+		for k, v := range service.Environment {
+			environment[k] = v
+		}
+		for _, env := range opts.Environment {
+			kv := strings.SplitN(env, "=", 2)
+			if len(kv) == 2 {
+				environment[kv[0]] = &kv[1]
+			} else {
+				environment[kv[0]] = nil
+			}
+		}
+		service.Environment = environment
+	}
+	service.DependsOn = nil
+	if service.Deploy == nil {
+		service.Deploy = &types.DeployConfig{}
+	} else {
+		deploy := *service.Deploy
+		service.Deploy = &deploy
+	}
+	service.Deploy.RestartPolicy = &types.RestartPolicy{
+		Condition: containers.RestartPolicyNone,
+	}
+
+	return types.Project{
+		Name:       groupName,
+		WorkingDir: project.WorkingDir,
+		Services:   []types.ServiceConfig{service},
+		Volumes:    project.Volumes,
+		Secrets:    project.Secrets,
+	}, nil
+}
+
+func getContainerExitCode(group containerinstance.ContainerGroup, containerName string) (int, error) {
+	if group.ContainerGroupProperties != nil && group.Containers != nil {
+		for _, c := range *group.Containers {
+			if c.Name == nil || *c.Name != containerName {
+				continue
+			}
+			if c.InstanceView != nil && c.InstanceView.CurrentState != nil && c.InstanceView.CurrentState.ExitCode != nil {
+				return int(*c.InstanceView.CurrentState.ExitCode), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("cannot get exit code of container %q", containerName)
+}
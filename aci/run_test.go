@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/api/containers"
+)
+
+func TestOneOffProject(t *testing.T) {
+	project := &types.Project{
+		Name: "myproject",
+		Services: []types.ServiceConfig{
+			{
+				Name:    "migrate",
+				Image:   "myapp",
+				Command: types.ShellCommand{"serve"},
+				Environment: types.MappingWithEquals{
+					"DB":    to.StringPtr("db"),
+					"DEBUG": to.StringPtr("false"),
+				},
+				DependsOn: types.DependsOnConfig{
+					"db": types.ServiceDependency{},
+				},
+				Deploy: &types.DeployConfig{
+					RestartPolicy: &types.RestartPolicy{Condition: containers.RestartPolicyAny},
+				},
+			},
+			{
+				Name:  "db",
+				Image: "mysql",
+			},
+		},
+	}
+
+	oneOff, err := oneOffProject(project, "myproject-migrate-run-1", compose.RunOptions{
+		Service:     "migrate",
+		Command:     []string{"migrate", "--all"},
+		Environment: []string{"DEBUG=true"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, oneOff.Name, "myproject-migrate-run-1")
+	assert.Equal(t, len(oneOff.Services), 1)
+
+	service := oneOff.Services[0]
+	assert.DeepEqual(t, service.Command, types.ShellCommand{"migrate", "--all"})
+	assert.Equal(t, *service.Environment["DB"], "db")
+	assert.Equal(t, *service.Environment["DEBUG"], "true")
+	assert.Equal(t, len(service.DependsOn), 0)
+	assert.Equal(t, service.Deploy.RestartPolicy.Condition, containers.RestartPolicyNone)
+
+	// original project is left untouched
+	assert.Equal(t, *project.Services[0].Environment["DEBUG"], "false")
+	assert.Equal(t, project.Services[0].Deploy.RestartPolicy.Condition, containers.RestartPolicyAny)
+
+	_, err = oneOffProject(project, "myproject-unknown-run-1", compose.RunOptions{Service: "unknown"})
+	assert.ErrorContains(t, err, "unknown")
+}
+
+func TestGetContainerExitCode(t *testing.T) {
+	group := containerinstance.ContainerGroup{
+		ContainerGroupProperties: &containerinstance.ContainerGroupProperties{
+			Containers: &[]containerinstance.Container{
+				{
+					Name: to.StringPtr("migrate"),
+					ContainerProperties: &containerinstance.ContainerProperties{
+						InstanceView: &containerinstance.ContainerPropertiesInstanceView{
+							CurrentState: &containerinstance.ContainerState{
+								ExitCode: to.Int32Ptr(3),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	exitCode, err := getContainerExitCode(group, "migrate")
+	assert.NilError(t, err)
+	assert.Equal(t, exitCode, 3)
+
+	_, err = getContainerExitCode(group, "other")
+	assert.ErrorContains(t, err, "cannot get exit code")
+}
@@ -0,0 +1,112 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Azure resource tags limits, see https://docs.microsoft.com/azure/azure-resource-manager/management/tag-resources
+	maxTags           = 50
+	maxTagNameLength  = 512
+	maxTagValueLength = 256
+	tagNameForbidden  = "<>%&\\?/"
+)
+
+// ValidateTags checks tags can be set on Azure resources, and aren't used by the ACI integration itself
+func ValidateTags(tags map[string]string) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("too many tags: %d, Azure resources accept at most %d", len(tags), maxTags)
+	}
+	for _, name := range sortedTagNames(tags) {
+		switch {
+		case name == "":
+			return errors.New("tag name cannot be empty")
+		case isInternalTag(name):
+			return fmt.Errorf("tag %q is reserved by the ACI integration", name)
+		case strings.ContainsAny(name, tagNameForbidden):
+			return fmt.Errorf("tag name %q cannot contain any of %q", name, tagNameForbidden)
+		case len(name) > maxTagNameLength:
+			return fmt.Errorf("tag name %q is longer than %d characters", name, maxTagNameLength)
+		case len(tags[name]) > maxTagValueLength:
+			return fmt.Errorf("tag %q value is longer than %d characters", name, maxTagValueLength)
+		}
+	}
+	return nil
+}
+
+// mergeTags returns the context default tags overridden by the ones set for a single deployment
+func mergeTags(defaults map[string]string, overrides map[string]string) map[string]string {
+	if len(defaults) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(defaults)+len(overrides))
+	for name, value := range defaults {
+		tags[name] = value
+	}
+	for name, value := range overrides {
+		tags[name] = value
+	}
+	return tags
+}
+
+func addUserTags(groupDefinition *containerinstance.ContainerGroup, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	if groupDefinition.Tags == nil {
+		groupDefinition.Tags = make(map[string]*string, len(tags))
+	}
+	for name, value := range tags {
+		groupDefinition.Tags[name] = to.StringPtr(value)
+	}
+}
+
+// userTags returns the container group tags, without the ones the ACI integration uses to track resources
+func userTags(group containerinstance.ContainerGroup) map[string]string {
+	var tags map[string]string
+	for name, value := range group.Tags {
+		if isInternalTag(name) || value == nil {
+			continue
+		}
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags[name] = *value
+	}
+	return tags
+}
+
+func isInternalTag(name string) bool {
+	return name == singleContainerTag || name == composeContainerTag || name == dockerVolumeTag
+}
+
+func sortedTagNames(tags map[string]string) []string {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package aci
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2019-12-01/containerinstance"
+	"github.com/Azure/go-autorest/autorest/to"
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateTags(t *testing.T) {
+	assert.NilError(t, ValidateTags(nil))
+	assert.NilError(t, ValidateTags(map[string]string{"team": "payments", "env": ""}))
+
+	assert.Error(t, ValidateTags(map[string]string{"": "payments"}), "tag name cannot be empty")
+	assert.Error(t, ValidateTags(map[string]string{"cost/center": "42"}), `tag name "cost/center" cannot contain any of "<>%&\\?/"`)
+	assert.Error(t, ValidateTags(map[string]string{composeContainerTag: "x"}), `tag "docker-compose-application" is reserved by the ACI integration`)
+	assert.Error(t, ValidateTags(map[string]string{"team": strings.Repeat("a", 257)}), `tag "team" value is longer than 256 characters`)
+
+	tooMany := map[string]string{}
+	for i := 0; i <= maxTags; i++ {
+		tooMany[strings.Repeat("t", i+1)] = "v"
+	}
+	assert.Error(t, ValidateTags(tooMany), "too many tags: 51, Azure resources accept at most 50")
+}
+
+func TestMergeTags(t *testing.T) {
+	assert.Assert(t, mergeTags(nil, nil) == nil)
+	tags := mergeTags(map[string]string{"team": "payments", "env": "dev"}, map[string]string{"env": "prod"})
+	assert.DeepEqual(t, tags, map[string]string{"team": "payments", "env": "prod"})
+}
+
+func TestUserTagsOnContainerGroup(t *testing.T) {
+	group := containerinstance.ContainerGroup{}
+	addUserTags(&group, map[string]string{"team": "payments"})
+	addTag(&group, composeContainerTag)
+
+	assert.Equal(t, len(group.Tags), 2)
+	assert.DeepEqual(t, userTags(group), map[string]string{"team": "payments"})
+
+	assert.Assert(t, userTags(containerinstance.ContainerGroup{Tags: map[string]*string{
+		singleContainerTag: to.StringPtr(singleContainerTag),
+	}}) == nil)
+}
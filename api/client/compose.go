@@ -28,35 +28,43 @@ import (
 type composeService struct {
 }
 
-func (c *composeService) Build(ctx context.Context, project *types.Project) error {
+func (c *composeService) Build(ctx context.Context, project *types.Project, opts compose.BuildOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Push(ctx context.Context, project *types.Project) error {
+func (c *composeService) Push(ctx context.Context, project *types.Project, opts compose.PushOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Pull(ctx context.Context, project *types.Project, opts compose.PullOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Create(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Pull(ctx context.Context, project *types.Project) error {
+func (c *composeService) Start(ctx context.Context, project *types.Project, consumer compose.LogConsumer) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Create(ctx context.Context, project *types.Project) error {
+func (c *composeService) Up(context.Context, *types.Project, compose.UpOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Start(ctx context.Context, project *types.Project, consumer compose.LogConsumer) error {
+func (c *composeService) Down(context.Context, string, compose.DownOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Up(context.Context, *types.Project, bool) error {
+func (c *composeService) Stop(context.Context, string, compose.StopOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Down(context.Context, string) error {
+func (c *composeService) Restart(context.Context, string, compose.RestartOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Logs(context.Context, string, compose.LogConsumer) error {
+func (c *composeService) Logs(context.Context, string, compose.LogConsumer, compose.LogOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
@@ -64,10 +72,62 @@ func (c *composeService) Ps(context.Context, string) ([]compose.ContainerSummary
 	return nil, errdefs.ErrNotImplemented
 }
 
-func (c *composeService) List(context.Context, string) ([]compose.Stack, error) {
+func (c *composeService) List(context.Context, string, compose.ListOptions) ([]compose.Stack, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Convert(context.Context, *types.Project, compose.ConvertOptions) ([]byte, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Health(context.Context, string, string, int) ([]compose.ContainerHealth, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Exec(context.Context, *types.Project, compose.ExecOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Events(context.Context, string, compose.EventsOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (c *composeService) RunOneOffContainer(context.Context, *types.Project, compose.RunOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) ResolveImageDigests(context.Context, *types.Project) (map[string]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Divergences(context.Context, *types.Project) ([]compose.ContainerDivergence, error) {
 	return nil, errdefs.ErrNotImplemented
 }
 
-func (c *composeService) Convert(context.Context, *types.Project, string) ([]byte, error) {
+func (c *composeService) Images(context.Context, *types.Project) ([]compose.ImageSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) RestartFailed(context.Context, string, compose.RestartFailedOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Stats(context.Context, string) (map[string]compose.ContainerStats, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Snapshot(context.Context, string, compose.SnapshotOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Restore(context.Context, compose.RestoreOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Commit(context.Context, string, compose.CommitOptions) (string, error) {
+	return "", errdefs.ErrNotImplemented
+}
+
+func (c *composeService) Unsupported(context.Context, *types.Project) ([]string, error) {
 	return nil, errdefs.ErrNotImplemented
 }
@@ -18,6 +18,9 @@ package compose
 
 import (
 	"context"
+	"io"
+	"os"
+	"time"
 
 	"github.com/compose-spec/compose-go/types"
 )
@@ -25,27 +28,254 @@ import (
 // Service manages a compose project
 type Service interface {
 	// Build executes the equivalent to a `compose build`
-	Build(ctx context.Context, project *types.Project) error
+	Build(ctx context.Context, project *types.Project, opts BuildOptions) error
 	// Push executes the equivalent ot a `compose push`
-	Push(ctx context.Context, project *types.Project) error
+	Push(ctx context.Context, project *types.Project, opts PushOptions) (TransferSummary, error)
 	// Pull executes the equivalent of a `compose pull`
-	Pull(ctx context.Context, project *types.Project) error
+	Pull(ctx context.Context, project *types.Project, opts PullOptions) (TransferSummary, error)
 	// Create executes the equivalent to a `compose create`
-	Create(ctx context.Context, project *types.Project) error
+	Create(ctx context.Context, project *types.Project, opts CreateOptions) error
 	// Start executes the equivalent to a `compose start`
 	Start(ctx context.Context, project *types.Project, consumer LogConsumer) error
 	// Up executes the equivalent to a `compose up`
-	Up(ctx context.Context, project *types.Project, detach bool) error
+	Up(ctx context.Context, project *types.Project, options UpOptions) error
 	// Down executes the equivalent to a `compose down`
-	Down(ctx context.Context, projectName string) error
+	Down(ctx context.Context, projectName string, options DownOptions) error
+	// Stop executes the equivalent to a `compose stop`
+	Stop(ctx context.Context, projectName string, options StopOptions) error
+	// Restart executes the equivalent to a `compose restart`
+	Restart(ctx context.Context, projectName string, options RestartOptions) error
 	// Logs executes the equivalent to a `compose logs`
-	Logs(ctx context.Context, projectName string, consumer LogConsumer) error
+	Logs(ctx context.Context, projectName string, consumer LogConsumer, options LogOptions) error
 	// Ps executes the equivalent to a `compose ps`
 	Ps(ctx context.Context, projectName string) ([]ContainerSummary, error)
 	// List executes the equivalent to a `docker stack ls`
-	List(ctx context.Context, projectName string) ([]Stack, error)
+	List(ctx context.Context, projectName string, options ListOptions) ([]Stack, error)
 	// Convert translate compose model into backend's native format
-	Convert(ctx context.Context, project *types.Project, format string) ([]byte, error)
+	Convert(ctx context.Context, project *types.Project, options ConvertOptions) ([]byte, error)
+	// Health executes the equivalent to a `compose alpha health`
+	Health(ctx context.Context, projectName string, service string, index int) ([]ContainerHealth, error)
+	// Exec executes the equivalent to a `compose exec`, and returns command exit code
+	Exec(ctx context.Context, project *types.Project, opts ExecOptions) (int, error)
+	// Events executes the equivalent to a `compose alpha events`
+	Events(ctx context.Context, projectName string, options EventsOptions) error
+	// RunOneOffContainer creates a one-off container to run a command for a service, and returns command exit code
+	RunOneOffContainer(ctx context.Context, project *types.Project, opts RunOptions) (int, error)
+	// ResolveImageDigests resolves services images to a reference pinned by the digest registry currently exposes
+	ResolveImageDigests(ctx context.Context, project *types.Project) (map[string]string, error)
+	// Divergences lists project containers which don't match the compose model anymore
+	Divergences(ctx context.Context, project *types.Project) ([]ContainerDivergence, error)
+	// Images lists the images project containers run
+	Images(ctx context.Context, project *types.Project) ([]ImageSummary, error)
+	// RestartFailed restarts project containers which exited with a non-zero code or are unhealthy, in dependency order, and returns their names
+	RestartFailed(ctx context.Context, projectName string, opts RestartFailedOptions) ([]string, error)
+	// Stats takes a single resources usage sample of project running containers, indexed by container ID
+	Stats(ctx context.Context, projectName string) (map[string]ContainerStats, error)
+	// Snapshot archives the content of project named volumes to a directory, and returns the archives paths
+	Snapshot(ctx context.Context, projectName string, opts SnapshotOptions) ([]string, error)
+	// Restore creates volumes from a directory written by Snapshot, and returns the restored volumes names
+	Restore(ctx context.Context, opts RestoreOptions) ([]string, error)
+	// Commit creates an image from a service container, and returns the image ID
+	Commit(ctx context.Context, projectName string, opts CommitOptions) (string, error)
+	// Unsupported lists the compose file features the backend doesn't support, which it would ignore or fail on
+	Unsupported(ctx context.Context, project *types.Project) ([]string, error)
+}
+
+const (
+	// RecreateDiverged recreate containers whose configuration diverged from the compose model
+	RecreateDiverged = "diverged"
+	// RecreateForce recreate all containers, even if their configuration didn't change
+	RecreateForce = "force"
+)
+
+// Registry isn't checked for newer images unless a pull strategy is set
+const (
+	// PullMissing only pull images which are missing, and warn when registry has a newer digest for a tag
+	PullMissing = "missing"
+	// PullAlways pull images which have been updated on registry and recreate containers using them
+	PullAlways = "always"
+)
+
+const (
+	// RemoveImagesAll removes the images of all services
+	RemoveImagesAll = "all"
+	// RemoveImagesLocal only removes the images compose built without a custom image name
+	RemoveImagesLocal = "local"
+)
+
+// CreateOptions group options of the Create API
+type CreateOptions struct {
+	// Recreate define the strategy to apply on existing containers
+	Recreate string
+	// Pull define the strategy to apply when registry has a newer image for a tag
+	Pull string
+	// Offline prevents any check against registry
+	Offline bool
+	// VerifySignatures requires images to be signed, see PullOptions
+	VerifySignatures bool
+	// Verbose reports progress for each image layer being pulled
+	Verbose bool
+	// EnvFile is the env file used to resolve the project, recorded for later commands to reuse it
+	EnvFile string
+	// QuietBuild only reports build output when a build fails, and a line per service built otherwise
+	QuietBuild bool
+	// QuietPull only reports pull output when a pull fails
+	QuietPull bool
+	// ReplaceImageOnTagChange pulls service images and recreates containers whose image tag now points to another image
+	ReplaceImageOnTagChange bool
+	// SinceBuild rebuilds service images whose build context changed since they were built, see BuildOptions
+	SinceBuild bool
+}
+
+// RestartFailedOptions group options of the RestartFailed API
+type RestartFailedOptions struct {
+	// DryRun only reports containers which would be restarted
+	DryRun bool
+}
+
+// SnapshotOptions group options of the Snapshot API
+type SnapshotOptions struct {
+	// Dir is the directory volumes archives are written to
+	Dir string
+}
+
+// RestoreOptions group options of the Restore API
+type RestoreOptions struct {
+	// Dir is the directory volumes archives are read from
+	Dir string
+}
+
+// CommitOptions group options of the Commit API
+type CommitOptions struct {
+	// Service is the service to commit a container of
+	Service string
+	// Index is the number of the container to commit, required when service is scaled
+	Index int
+	// Reference is the repository and tag to apply to the image
+	Reference string
+	// Message is the commit message
+	Message string
+	// Changes are Dockerfile instructions applied to the image
+	Changes []string
+	// Pause pauses the container while it is committed
+	Pause bool
+}
+
+// DownOptions group options of the Down API
+type DownOptions struct {
+	// Timeout is the grace period before containers are killed. Nil applies containers default, zero kills immediately
+	Timeout *time.Duration
+	// CreatedSince only removes project containers and networks created since then, zero removes them all. It's a time
+	// of the client clock, backends account for their own clock differing
+	CreatedSince time.Time
+	// Images, when set to RemoveImagesAll or RemoveImagesLocal, removes service images once containers are removed.
+	// Images already removed are skipped
+	Images string
+}
+
+// StopOptions group options of the Stop API
+type StopOptions struct {
+	// Services are the services to stop, all of them if empty. Services depending on them are stopped first
+	Services []string
+	// NoDeps only stops the selected services, concurrently, ignoring dependencies between services
+	NoDeps bool
+}
+
+// RestartOptions group options of the Restart API
+type RestartOptions struct {
+	// Services are the services to restart, all of them if empty. Services depending on them are restarted after
+	Services []string
+	// NoDeps only restarts the selected services, concurrently, ignoring dependencies between services
+	NoDeps bool
+}
+
+// ConvertOptions group options of the Convert API
+type ConvertOptions struct {
+	// Format is the output format, like yaml or json
+	Format string
+	// Parameters converts to the parameter values the backend deploys the converted model with, rather than to the model
+	Parameters bool
+}
+
+// ListOptions group options of the List API
+type ListOptions struct {
+	// All also lists projects without running containers, and takes stopped containers into account
+	All bool
+}
+
+// BuildOptions group options of the Build API
+type BuildOptions struct {
+	// Tags are additional tags applied to the repository of each built image
+	Tags []string
+	// Labels are additional labels set on each built image, e.g. a source revision
+	Labels map[string]string
+	// LogDir, when set, gets the complete build output of each service in a `<service>.log` file, console only
+	// reporting which services were built
+	LogDir string
+	// SinceBuild only builds services whose build context changed since their image was built with SinceBuild
+	SinceBuild bool
+}
+
+// PullOptions group options of the Pull API
+type PullOptions struct {
+	// VerifySignatures requires images to be pulled by a digest resolved from signed trust data
+	VerifySignatures bool
+	// Verbose reports progress for each image layer being pulled
+	Verbose bool
+	// IgnoreBuildable skips services declaring a build, as their image gets built anyway
+	IgnoreBuildable bool
+}
+
+// PushOptions group options of the Push API
+type PushOptions struct {
+	// IgnoreFailures pushes the images of all services even if some fail, failures only being reported in summary
+	IgnoreFailures bool
+}
+
+// TransferSummary reports the outcome of pushing or pulling the image of each service
+type TransferSummary struct {
+	// Succeeded lists services whose image got transferred
+	Succeeded []string
+	// Skipped maps services whose image wasn't transferred to the reason why
+	Skipped map[string]string
+	// Failed maps services whose image transfer failed to the error
+	Failed map[string]error
+}
+
+// ExecOptions group options of the Exec API
+type ExecOptions struct {
+	// Service is the service to run command in
+	Service string
+	// Index selects the service container to run command in, starting at 1
+	Index int
+	// Command is the command to execute, with its arguments
+	Command []string
+	// Environment sets additional environment variables for the command
+	Environment []string
+	// User runs the command as user
+	User string
+	// Privileged gives extended privileges to the command
+	Privileged bool
+	// Tty allocates a pseudo-TTY
+	Tty bool
+	// DetachKeys overrides the key sequence for detaching
+	DetachKeys string
+	// WorkingDir overrides the directory to run command in, defaulting to service working_dir, then image WORKDIR
+	WorkingDir string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// UpOptions group options of the Up API
+type UpOptions struct {
+	// Detach will create services and return immediately
+	Detach bool
+	// SkipPreflight disables backend specific checks ran before resources get deployed
+	SkipPreflight bool
+	// Tags are set on the resources created, overriding the ones from the context, on backends supporting them
+	Tags map[string]string
 }
 
 // PortPublisher hold status about published port
@@ -58,12 +288,134 @@ type PortPublisher struct {
 
 // ContainerSummary hold high-level description of a container
 type ContainerSummary struct {
-	ID         string
-	Name       string
-	Project    string
-	Service    string
-	State      string
+	ID      string
+	Name    string
+	Project string
+	Service string
+	State   string
+	// Command is the command container runs, on backends exposing it
+	Command string `json:",omitempty"`
+	// Health is the container healthcheck status, empty if it has no healthcheck
+	Health     string
 	Publishers []PortPublisher
+	// Tags set on the resource running the container, on backends supporting them
+	Tags map[string]string `json:",omitempty"`
+}
+
+// ContainerStats is a sample of a container resources usage
+type ContainerStats struct {
+	// CPUPercent is the CPU usage, 100% being one CPU fully used
+	CPUPercent float64
+	// MemoryUsage is the memory used by container, in bytes
+	MemoryUsage uint64
+	// MemoryLimit is the memory available to container, in bytes
+	MemoryLimit uint64
+}
+
+// ContainerDivergence explains how a container diverged from the compose model, and how `up` would handle it
+type ContainerDivergence struct {
+	ContainerSummary
+	Reason string
+	Action string
+}
+
+// ImageSummary describes the image a container runs
+type ImageSummary struct {
+	ContainerName string
+	Service       string
+	// Image is the image reference container was created from
+	Image   string
+	ImageID string
+	// Digest is the repository digest of the image container runs, empty if it wasn't pulled from or pushed to a registry
+	Digest string
+	// Mismatch is set when the image currently tagged for the service isn't the one container runs, e.g. after a build
+	Mismatch bool
+}
+
+// SinceContainerStart is a LogOptions.Since value to only get logs since container last started
+const SinceContainerStart = "container-start"
+
+// LogOptions group options of the Logs API
+type LogOptions struct {
+	// Follow keeps streaming new log lines
+	Follow bool
+	// Since only shows logs since a timestamp or relative duration, or since container last started
+	Since string
+	// Services restricts logs to these services containers, all services when empty
+	Services []string
+	// NoExited only collects logs of running containers
+	NoExited bool
+	// Tail is the number of lines to show from the end of each container logs, or "all"
+	Tail string
+	// Timestamps prefixes each log line with the time it was emitted
+	Timestamps bool
+}
+
+// RunOptions group options of the RunOneOffContainer API
+type RunOptions struct {
+	// Service is the service to run a one-off container for
+	Service string
+	// Command overrides the service command
+	Command []string
+	// Environment sets additional environment variables for the command
+	Environment []string
+	// User overrides the service user
+	User string
+	// WorkingDir overrides the service working_dir
+	WorkingDir string
+	// AutoRemove removes the one-off container once command completed
+	AutoRemove bool
+	// KeepVolumes preserves anonymous volumes of the one-off container when AutoRemove removes it
+	KeepVolumes bool
+	// Timeout stops waiting for command completion, zero means no timeout
+	Timeout time.Duration
+	// Writer receives the container logs
+	Writer io.Writer
+	// Signals are forwarded to the one-off container, which is then waited for until it exits
+	Signals <-chan os.Signal
+	// QuietPull only reports pull output when a pull fails
+	QuietPull bool
+	// RemoveOrphans removes containers of services no longer declared by the compose file
+	RemoveOrphans bool
+}
+
+// EventsOptions group options of the Events API
+type EventsOptions struct {
+	// Filters restrict events by type, event or service. Values for a key are OR'ed, distinct keys are AND'ed
+	Filters  map[string][]string
+	Consumer func(event Event) error
+}
+
+// Event is a container runtime event served by the Events API
+type Event struct {
+	Timestamp  time.Time
+	Type       string
+	Service    string
+	Container  string
+	Status     string
+	Attributes map[string]string
+}
+
+// ContainerHealth hold healthcheck status of a container
+type ContainerHealth struct {
+	ID            string
+	Name          string
+	Service       string
+	Status        string
+	FailingStreak int
+	Log           []HealthcheckResult
+	// State is the container state, e.g. running or exited
+	State string
+	// ExitCode is the container exit code, only relevant once exited
+	ExitCode int
+}
+
+// HealthcheckResult hold the result of a single healthcheck probe
+type HealthcheckResult struct {
+	Start    time.Time
+	End      time.Time
+	ExitCode int
+	Output   string
 }
 
 // ServiceStatus hold status about a service
@@ -97,9 +449,49 @@ type Stack struct {
 	Name   string
 	Status string
 	Reason string
+	// Containers is the number of project containers by state, if the backend reports it
+	Containers map[string]int
+	// Created is the creation time of the newest project container, if the backend reports it
+	Created time.Time
+	// Exited is the time the last project container exited, zero if some container is still up
+	Exited time.Time
+	// WorkingDir, ConfigFiles and EnvFile are the ones project was created with, if the backend records them
+	WorkingDir  string
+	ConfigFiles []string
+	EnvFile     string
 }
 
 // LogConsumer is a callback to process log messages from services
 type LogConsumer interface {
 	Log(service, container, message string)
 }
+
+const (
+	// StdoutStream identifies messages a container wrote to its standard output
+	StdoutStream = "stdout"
+	// StderrStream identifies messages a container wrote to its standard error
+	StderrStream = "stderr"
+)
+
+// StreamLogConsumer is a LogConsumer which also gets to know the stream a message was written to.
+// Backends which can't tell streams apart just call Log
+type StreamLogConsumer interface {
+	LogConsumer
+	LogStream(service, container, stream, message string)
+}
+
+// ReplicaLogConsumer is a LogConsumer which gets told the service replica each container is, so logs of several
+// replicas can be told apart. Backends call ContainerReplica before any message of container is logged
+type ReplicaLogConsumer interface {
+	LogConsumer
+	// ContainerReplica names the service replica container is, as `service_number`
+	ContainerReplica(container, name string)
+}
+
+// ExitedLogConsumer is a LogConsumer which gets told containers are not running anymore, so their logs can be marked.
+// Backends call ContainerExited before any message of container is logged
+type ExitedLogConsumer interface {
+	LogConsumer
+	// ContainerExited marks container as not running, name identifying the service replica it was
+	ContainerExited(container, name string)
+}
@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	// NameSeparatorEnv overrides the separator between project, service and container number in resource names
+	NameSeparatorEnv = "COMPOSE_NAME_SEPARATOR"
+	// DefaultNameSeparator is the separator docker-compose v1 uses in resource names
+	DefaultNameSeparator = "_"
+)
+
+// NameSeparator returns the separator used to build resource names, so that side-by-side installs don't collide
+func NameSeparator() string {
+	if separator, ok := os.LookupEnv(NameSeparatorEnv); ok && separator != "" {
+		return separator
+	}
+	return DefaultNameSeparator
+}
+
+// ResourceName joins the parts of a resource name, typically project and service names, with the name separator
+func ResourceName(parts ...string) string {
+	return strings.Join(parts, NameSeparator())
+}
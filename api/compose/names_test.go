@@ -0,0 +1,36 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResourceName(t *testing.T) {
+	defer os.Unsetenv(NameSeparatorEnv) //nolint:errcheck
+
+	assert.Equal(t, ResourceName("myproject", "web", "1"), "myproject_web_1")
+
+	assert.NilError(t, os.Setenv(NameSeparatorEnv, "-"))
+	assert.Equal(t, ResourceName("myproject", "web", "1"), "myproject-web-1")
+
+	assert.NilError(t, os.Setenv(NameSeparatorEnv, ""))
+	assert.Equal(t, ResourceName("myproject", "web"), "myproject_web")
+}
@@ -16,13 +16,38 @@
 
 package compose
 
+import "os"
+
 const (
-	// ProjectTag allow to track resource related to a compose project
-	ProjectTag = "com.docker.compose.project"
-	// NetworkTag allow to track resource related to a compose network
-	NetworkTag = "com.docker.compose.network"
-	// ServiceTag allow to track resource related to a compose service
-	ServiceTag = "com.docker.compose.service"
-	// VolumeTag allow to track resource related to a compose volume
-	VolumeTag = "com.docker.compose.volume"
+	// LabelPrefixEnv namespaces the labels compose relies on, so that forks or test harnesses don't see each other's
+	// resources
+	LabelPrefixEnv = "COMPOSE_LABEL_PREFIX"
+	// DefaultLabelPrefix is the label namespace shared with docker-compose v1
+	DefaultLabelPrefix = "com.docker.compose"
 )
+
+// LabelPrefix returns the namespace of the labels compose relies on. It's read on every call, so that a prefix set
+// once the process started is honored
+func LabelPrefix() string {
+	if prefix := os.Getenv(LabelPrefixEnv); prefix != "" {
+		return prefix
+	}
+	return DefaultLabelPrefix
+}
+
+// Label returns the compose label named name, in the current label namespace
+func Label(name string) string {
+	return LabelPrefix() + "." + name
+}
+
+// ProjectTag allow to track resource related to a compose project
+func ProjectTag() string { return Label("project") }
+
+// NetworkTag allow to track resource related to a compose network
+func NetworkTag() string { return Label("network") }
+
+// ServiceTag allow to track resource related to a compose service
+func ServiceTag() string { return Label("service") }
+
+// VolumeTag allow to track resource related to a compose volume
+func VolumeTag() string { return Label("volume") }
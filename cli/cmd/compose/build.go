@@ -18,18 +18,31 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
-	"github.com/compose-spec/compose-go/cli"
+	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	local_compose "github.com/docker/compose-cli/local/compose"
 	"github.com/docker/compose-cli/progress"
 )
 
 type buildOptions struct {
 	composeOptions
+	scanOptions
+	Tags       []string
+	Labels     []string
+	LogDir     string
+	SinceBuild bool
+	ShmSize    string
 }
 
+const sinceBuildHelp = "Only build services whose build context, Dockerfile or args changed since their image was built with --since-build"
+
 func buildCommand() *cobra.Command {
 	opts := buildOptions{}
 	buildCmd := &cobra.Command{
@@ -41,31 +54,86 @@ func buildCommand() *cobra.Command {
 	}
 	buildCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	buildCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	buildCmd.Flags().StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
+	buildCmd.Flags().StringArrayVar(&opts.Tags, "build-tag", []string{}, "Additional tag to apply to built images, in their repository, e.g. a commit SHA")
+	buildCmd.Flags().StringArrayVar(&opts.Labels, "label", []string{}, "Set a label on built images, e.g. a git SHA")
+	buildCmd.Flags().StringVar(&opts.LogDir, "build-log-dir", "", "Write the complete build output of each service to <service>.log in this directory, only reporting built services on console")
+	buildCmd.Flags().BoolVar(&opts.SinceBuild, "since-build", false, sinceBuildHelp)
+	buildCmd.Flags().StringVar(&opts.ShmSize, "build-shm-size", "", "Size of /dev/shm during builds, e.g. 2g, overriding the build shm_size services declare")
+	addScanFlags(buildCmd.Flags(), &opts.scanOptions)
 
 	return buildCmd
 }
 
 func runBuild(ctx context.Context, opts buildOptions, services []string) error {
+	err := opts.scanOptions.validate()
+	if err != nil {
+		return err
+	}
+	shmSize, err := toShmSize(opts.ShmSize)
+	if err != nil {
+		return err
+	}
 	c, err := client.NewWithDefaultLocalBackend(ctx)
 	if err != nil {
 		return err
 	}
 
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	err = filter(project, services)
+	if err != nil {
+		return err
+	}
+	if shmSize > 0 {
+		local_compose.SetBuildShmSize(project, shmSize)
+	}
+
 	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
-		options, err := opts.toProjectOptions()
-		if err != nil {
-			return "", err
-		}
-		project, err := cli.ProjectFromOptions(options)
-		if err != nil {
-			return "", err
-		}
+		return "", c.ComposeService().Build(ctx, project, compose.BuildOptions{
+			Tags:       opts.Tags,
+			Labels:     toLabels(opts.Labels),
+			LogDir:     opts.LogDir,
+			SinceBuild: opts.SinceBuild,
+		})
+	})
+	if err != nil || !opts.check {
+		return err
+	}
+	return runImageChecks(ctx, project, newImageScanner(), opts.severityThreshold, os.Stdout)
+}
 
-		err = filter(project, services)
-		if err != nil {
-			return "", err
+// toShmSize parses --build-shm-size, a size like `2g` or a number of bytes
+func toShmSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+	shmSize, err := units.RAMInBytes(size)
+	if err != nil || shmSize <= 0 {
+		return 0, fmt.Errorf("invalid --build-shm-size %q, must be a positive size like 2g", size)
+	}
+	return shmSize, nil
+}
+
+// toLabels converts `key=value` flags into labels, a flag without value setting an empty label
+func toLabels(flags []string) map[string]string {
+	if len(flags) == 0 {
+		return nil
+	}
+	labels := map[string]string{}
+	for _, l := range flags {
+		parts := strings.SplitN(l, "=", 2)
+		if len(parts) == 1 {
+			labels[parts[0]] = ""
+		} else {
+			labels[parts[0]] = parts[1]
 		}
-		return "", c.ComposeService().Build(ctx, project)
-	})
-	return err
+	}
+	return labels
 }
@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestToShmSize(t *testing.T) {
+	size, err := toShmSize("")
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(0))
+
+	size, err = toShmSize("2g")
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(2*1024*1024*1024))
+
+	size, err = toShmSize("67108864")
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(64*1024*1024))
+
+	_, err = toShmSize("2 gigs")
+	assert.Error(t, err, `invalid --build-shm-size "2 gigs", must be a positive size like 2g`)
+
+	_, err = toShmSize("0")
+	assert.Error(t, err, `invalid --build-shm-size "0", must be a positive size like 2g`)
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type commitOptions struct {
+	composeOptions
+	index   int
+	message string
+	changes []string
+	pause   bool
+}
+
+func commitCommand() *cobra.Command {
+	opts := commitOptions{}
+	cmd := &cobra.Command{
+		Use:   "commit SERVICE [REPOSITORY[:TAG]]",
+		Short: "Create a new image from a service container",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCommit(cmd.Context(), opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().IntVar(&opts.index, "index", 0, "Index of the container to commit, required if the service is scaled")
+	cmd.Flags().StringVarP(&opts.message, "message", "m", "", "Commit message")
+	cmd.Flags().StringArrayVarP(&opts.changes, "change", "c", []string{}, "Apply Dockerfile instruction to the created image")
+	cmd.Flags().BoolVar(&opts.pause, "pause", true, "Pause container during commit")
+	return cmd
+}
+
+func runCommit(ctx context.Context, opts commitOptions, args []string) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+
+	commit := compose.CommitOptions{
+		Service: args[0],
+		Index:   opts.index,
+		Message: opts.message,
+		Changes: opts.changes,
+		Pause:   opts.pause,
+	}
+	if len(args) > 1 {
+		commit.Reference = args[1]
+	}
+	id, err := c.ComposeService().Commit(ctx, projectName, commit)
+	if err != nil {
+		return err
+	}
+	fmt.Println(id)
+	return nil
+}
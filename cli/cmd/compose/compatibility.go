@@ -0,0 +1,143 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+const (
+	compatibilityFlag = "compatibility"
+	// compatibilityEnv enables docker-compose v1 compatibility mode, like --compatibility does
+	compatibilityEnv = "COMPOSE_COMPATIBILITY"
+)
+
+// compatibilityMode tells if deploy resources must be converted into container limits, as docker-compose v1 --compatibility does
+func compatibilityMode() bool {
+	enabled, err := strconv.ParseBool(os.Getenv(compatibilityEnv))
+	return err == nil && enabled
+}
+
+// projectFromOptions loads the compose project, converting deploy resources when running in compatibility mode. A
+// project is only loaded once per CLI invocation, see loadedProjects
+func projectFromOptions(options *cli.ProjectOptions) (*types.Project, error) {
+	return loadedProjects.load(options, func() (*types.Project, error) {
+		project, err := cli.ProjectFromOptions(options)
+		if err != nil {
+			return nil, err
+		}
+		files, err := composeFilePaths(options, project)
+		if err != nil {
+			return nil, err
+		}
+		if err := local_compose.ApplySpecAttributes(project, files, options.Environment); err != nil {
+			return nil, err
+		}
+		if err := local_compose.ApplyProfiles(project, activeProfiles(options.Environment)); err != nil {
+			return nil, err
+		}
+		if err := local_compose.ApplyEnvFiles(project); err != nil {
+			return nil, err
+		}
+		mergeVolumesByTarget(project)
+		if err := loadLabelFiles(project); err != nil {
+			return nil, err
+		}
+		if err := local_compose.ValidateGenericResources(project); err != nil {
+			return nil, err
+		}
+		if compatibilityMode() {
+			if err := applyCompatibility(project); err != nil {
+				return nil, err
+			}
+		}
+		return project, nil
+	})
+}
+
+// activeProfiles lists the profiles COMPOSE_PROFILES activates, --profile flags overriding it
+func activeProfiles(environment map[string]string) []string {
+	var profiles []string
+	for _, profile := range strings.Split(environment[local_compose.ProfilesEnv], ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// composeFilePaths resolves the paths of the compose files project got loaded from, as compose-go does. A project read
+// from stdin has no file to read again
+func composeFilePaths(options *cli.ProjectOptions, project *types.Project) ([]string, error) {
+	dir := options.WorkingDir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = wd
+	}
+	var files []string
+	for _, file := range project.ComposeFiles {
+		if file == "-" {
+			continue
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// applyCompatibility maps deploy.resources onto the equivalent service level attributes, unless those are already set
+func applyCompatibility(project *types.Project) error {
+	for i, service := range project.Services {
+		if service.Deploy == nil {
+			continue
+		}
+		if limits := service.Deploy.Resources.Limits; limits != nil {
+			if service.CPUS == 0 && limits.NanoCPUs != "" {
+				cpus, err := strconv.ParseFloat(limits.NanoCPUs, 32)
+				if err != nil {
+					return errors.Wrapf(err, "service %q: invalid deploy.resources.limits.cpus %q", service.Name, limits.NanoCPUs)
+				}
+				if cpus <= 0 {
+					return errors.Errorf("service %q: deploy.resources.limits.cpus must be positive, got %q", service.Name, limits.NanoCPUs)
+				}
+				service.CPUS = float32(cpus)
+			}
+			if service.MemLimit == 0 {
+				service.MemLimit = limits.MemoryBytes
+			}
+		}
+		if reservations := service.Deploy.Resources.Reservations; reservations != nil && service.MemReservation == 0 {
+			service.MemReservation = reservations.MemoryBytes
+		}
+		project.Services[i] = service
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestApplyCompatibility(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{
+				Name: "web",
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{
+						Limits: &types.Resource{
+							NanoCPUs:    "1.5",
+							MemoryBytes: 64 * 1024 * 1024,
+						},
+						Reservations: &types.Resource{
+							MemoryBytes: 32 * 1024 * 1024,
+						},
+					},
+				},
+			},
+			{
+				Name:     "db",
+				CPUS:     2,
+				MemLimit: 128 * 1024 * 1024,
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{
+						Limits: &types.Resource{
+							NanoCPUs:    "0.5",
+							MemoryBytes: 64 * 1024 * 1024,
+						},
+					},
+				},
+			},
+			{
+				Name: "worker",
+			},
+		},
+	}
+	assert.NilError(t, applyCompatibility(project))
+
+	web := project.Services[0]
+	assert.Equal(t, web.CPUS, float32(1.5))
+	assert.Equal(t, web.MemLimit, types.UnitBytes(64*1024*1024))
+	assert.Equal(t, web.MemReservation, types.UnitBytes(32*1024*1024))
+
+	db := project.Services[1]
+	assert.Equal(t, db.CPUS, float32(2))
+	assert.Equal(t, db.MemLimit, types.UnitBytes(128*1024*1024))
+
+	worker := project.Services[2]
+	assert.Equal(t, worker.CPUS, float32(0))
+	assert.Equal(t, worker.MemLimit, types.UnitBytes(0))
+}
+
+func TestApplyCompatibilityInvalidCPUs(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{
+				Name: "web",
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{
+						Limits: &types.Resource{NanoCPUs: "half"},
+					},
+				},
+			},
+		},
+	}
+	err := applyCompatibility(project)
+	assert.ErrorContains(t, err, `service "web": invalid deploy.resources.limits.cpus "half"`)
+
+	project.Services[0].Deploy.Resources.Limits.NanoCPUs = "-0.5"
+	err = applyCompatibility(project)
+	assert.ErrorContains(t, err, `service "web": deploy.resources.limits.cpus must be positive, got "-0.5"`)
+}
+
+func TestCompatibilityMode(t *testing.T) {
+	defer os.Unsetenv(compatibilityEnv) //nolint:errcheck
+	assert.Assert(t, !compatibilityMode())
+
+	assert.NilError(t, os.Setenv(compatibilityEnv, "true"))
+	assert.Assert(t, compatibilityMode())
+
+	assert.NilError(t, os.Setenv(compatibilityEnv, "0"))
+	assert.Assert(t, !compatibilityMode())
+}
@@ -18,27 +18,51 @@ package compose
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/compose-spec/compose-go/cli"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"github.com/docker/compose-cli/context/store"
+	local_compose "github.com/docker/compose-cli/local/compose"
 )
 
 type composeOptions struct {
 	Name        string
 	DomainName  string
+	Tags        map[string]string
 	WorkingDir  string
 	ConfigPaths []string
+	Profiles    []string
 	Environment []string
+	EnvFile     string
 	Format      string
 	Detach      bool
 	Build       bool
 	Quiet       bool
+	QuietLint   bool
+
+	SkipPreflight bool
+}
+
+// verifySignatures tells if images signature must be checked, either requested by flag or by DOCKER_CONTENT_TRUST
+func verifySignatures(flag bool) bool {
+	if flag {
+		return true
+	}
+	trusted, err := strconv.ParseBool(os.Getenv("DOCKER_CONTENT_TRUST"))
+	return err == nil && trusted
 }
 
+const profileHelp = "Activate a profile, services which don't belong to any profile being always enabled. Defaults to COMPOSE_PROFILES"
+
 func addComposeCommonFlags(f *pflag.FlagSet, opts *composeOptions) {
 	f.StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	f.StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
@@ -55,7 +79,7 @@ func (o *composeOptions) toProjectName() (string, error) {
 		return "", err
 	}
 
-	project, err := cli.ProjectFromOptions(options)
+	project, err := projectFromOptions(options)
 	if err != nil {
 		return "", err
 	}
@@ -63,25 +87,98 @@ func (o *composeOptions) toProjectName() (string, error) {
 }
 
 func (o *composeOptions) toProjectOptions() (*cli.ProjectOptions, error) {
+	dotEnv, err := o.dotEnv()
+	if err != nil {
+		return nil, err
+	}
+	// variables set by .env file can be overridden by shell environment, then by command line
+	var profiles []string
+	if len(o.Profiles) > 0 {
+		profiles = []string{fmt.Sprintf("%s=%s", local_compose.ProfilesEnv, strings.Join(o.Profiles, ","))}
+	}
 	return cli.NewProjectOptions(o.ConfigPaths,
+		cli.WithEnv(dotEnv),
 		cli.WithOsEnv,
 		cli.WithEnv(o.Environment),
+		cli.WithEnv(profiles),
 		cli.WithWorkingDirectory(o.WorkingDir),
 		cli.WithName(o.Name))
 }
 
+// dotEnv loads variables from the env file set by --env-file, or from the .env file in project directory if any
+func (o *composeOptions) dotEnv() ([]string, error) {
+	file, err := o.envFile()
+	if err != nil || file == "" {
+		return nil, err
+	}
+	env, err := godotenv.Read(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", file)
+	}
+	var vars []string
+	for k, v := range env {
+		vars = append(vars, fmt.Sprintf("%s=%s", k, v))
+	}
+	return vars, nil
+}
+
+// envFile resolves the absolute path of the env file to load variables from, if any
+func (o *composeOptions) envFile() (string, error) {
+	if o.EnvFile != "" {
+		file, err := filepath.Abs(o.EnvFile)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(file); err != nil {
+			return "", errors.Wrapf(err, "failed to read %s", o.EnvFile)
+		}
+		return file, nil
+	}
+
+	dir := o.WorkingDir
+	if dir == "" && len(o.ConfigPaths) > 0 && o.ConfigPaths[0] != "-" {
+		dir = filepath.Dir(o.ConfigPaths[0])
+	}
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = wd
+	}
+
+	file := filepath.Join(dir, ".env")
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return "", nil
+	}
+	return file, nil
+}
+
 // Command returns the compose command with its child commands
 func Command(contextType string) *cobra.Command {
+	var compatibility bool
 	command := &cobra.Command{
 		Short: "Docker Compose",
 		Use:   "compose",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if compatibility {
+				if err := os.Setenv(compatibilityEnv, "true"); err != nil {
+					return err
+				}
+			}
 			if contextType == store.DefaultContextType || contextType == store.LocalContextType {
 				fmt.Println("The new 'docker compose' command is currently experimental. To provide feedback or request new features please open issues at https://github.com/docker/compose-cli")
 			}
 			return nil
 		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return unknownCommandError(cmd, args[0])
+			}
+			return cmd.Help()
+		},
 	}
+	command.PersistentFlags().BoolVar(&compatibility, compatibilityFlag, false, "Run compose in backward compatibility mode, converting deploy resources into container limits")
 
 	command.AddCommand(
 		upCommand(contextType),
@@ -90,6 +187,9 @@ func Command(contextType string) *cobra.Command {
 		listCommand(),
 		logsCommand(),
 		convertCommand(),
+		translateCommand(),
+		execCommand(contextType),
+		runCommand(),
 	)
 
 	if contextType == store.LocalContextType || contextType == store.DefaultContextType {
@@ -97,6 +197,12 @@ func Command(contextType string) *cobra.Command {
 			buildCommand(),
 			pushCommand(),
 			pullCommand(),
+			portCommand(),
+			waitCommand(),
+			stopCommand(),
+			restartCommand(),
+			imagesCommand(),
+			alphaCommand(),
 		)
 	}
 
@@ -109,9 +215,13 @@ func filter(project *types.Project, services []string) error {
 		// All services
 		return nil
 	}
+	err := local_compose.EnableServices(project, services)
+	if err != nil {
+		return err
+	}
 
 	names := map[string]bool{}
-	err := addServiceNames(project, services, names)
+	err = addServiceNames(project, services, names)
 	if err != nil {
 		return err
 	}
@@ -125,6 +235,37 @@ func filter(project *types.Project, services []string) error {
 	return nil
 }
 
+// addDependents adds to the selected services all the services which depend on them, directly or not
+func addDependents(project *types.Project, services []string) []string {
+	if len(services) == 0 {
+		// All services
+		return services
+	}
+	selected := map[string]bool{}
+	for _, name := range services {
+		selected[name] = true
+	}
+	for {
+		added := false
+		for _, s := range project.Services {
+			if selected[s.Name] {
+				continue
+			}
+			for _, dep := range s.GetDependencies() {
+				if selected[dep] {
+					selected[s.Name] = true
+					services = append(services, s.Name)
+					added = true
+					break
+				}
+			}
+		}
+		if !added {
+			return services
+		}
+	}
+}
+
 func addServiceNames(project *types.Project, services []string, names map[string]bool) error {
 	for _, name := range services {
 		names[name] = true
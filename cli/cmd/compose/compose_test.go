@@ -19,8 +19,10 @@ package compose
 import (
 	"testing"
 
+	"github.com/compose-spec/compose-go/cli"
 	"github.com/compose-spec/compose-go/types"
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
 )
 
 func TestFilterServices(t *testing.T) {
@@ -51,3 +53,83 @@ func TestFilterServices(t *testing.T) {
 	_, err = p.GetService("zot")
 	assert.NilError(t, err)
 }
+
+func TestAddDependents(t *testing.T) {
+	p := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name: "web",
+				DependsOn: map[string]types.ServiceDependency{
+					"api": {},
+				},
+			},
+			{
+				Name:  "api",
+				Links: []string{"db"},
+			},
+			{
+				Name: "db",
+			},
+			{
+				Name: "cache",
+			},
+		},
+	}
+	assert.DeepEqual(t, addDependents(&p, []string{"db"}), []string{"db", "api", "web"})
+	assert.DeepEqual(t, addDependents(&p, []string{"cache"}), []string{"cache"})
+	assert.Equal(t, len(addDependents(&p, nil)), 0)
+}
+
+func TestDotEnvInterpolation(t *testing.T) {
+	dir := fs.NewDir(t, "dotenv",
+		fs.WithFile(".env", "HOST=backend\nPORT=8080\n"),
+		fs.WithFile("docker-compose.yml", `
+services:
+  app:
+    image: alpine
+    environment:
+      URL: "http://${HOST}:${PORT}"
+    labels:
+      upstream: "${HOST}"
+    ports:
+      - "${PORT}:80"
+`))
+	defer dir.Remove()
+
+	opts := composeOptions{
+		WorkingDir:  dir.Path(),
+		ConfigPaths: []string{dir.Join("docker-compose.yml")},
+		Environment: []string{"HOST=override"},
+	}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err := cli.ProjectFromOptions(options)
+	assert.NilError(t, err)
+
+	service, err := project.GetService("app")
+	assert.NilError(t, err)
+	assert.Equal(t, *service.Environment["URL"], "http://override:8080")
+	assert.Equal(t, service.Labels["upstream"], "override")
+	assert.Equal(t, service.Ports[0].Published, uint32(8080))
+}
+
+func TestEnvFile(t *testing.T) {
+	dir := fs.NewDir(t, "env-file",
+		fs.WithFile(".env", "HOST=default\n"),
+		fs.WithFile("prod.env", "HOST=prod\n"))
+	defer dir.Remove()
+
+	opts := composeOptions{WorkingDir: dir.Path()}
+	file, err := opts.envFile()
+	assert.NilError(t, err)
+	assert.Equal(t, file, dir.Join(".env"))
+
+	opts.EnvFile = dir.Join("prod.env")
+	env, err := opts.dotEnv()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, env, []string{"HOST=prod"})
+
+	opts.EnvFile = dir.Join("missing.env")
+	_, err = opts.envFile()
+	assert.ErrorContains(t, err, "failed to read")
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	apicontext "github.com/docker/compose-cli/context"
+)
+
+type contextCheckOptions struct {
+	composeOptions
+	IgnoreUnsupported bool
+}
+
+func contextCheckCommand() *cobra.Command {
+	opts := contextCheckOptions{}
+	cmd := &cobra.Command{
+		Use:   "context-check",
+		Short: "Report compose file features the backend of current context doesn't support, before running up",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runContextCheck(cmd.Context(), opts, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	cmd.Flags().BoolVar(&opts.IgnoreUnsupported, "ignore-unsupported", false, "Only report unsupported features, don't exit with error")
+	return cmd
+}
+
+func runContextCheck(ctx context.Context, opts contextCheckOptions, w io.Writer) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	unsupported, err := c.ComposeService().Unsupported(ctx, project)
+	if err != nil {
+		return err
+	}
+	return reportUnsupported(w, apicontext.CurrentContext(ctx), c.ContextType(), unsupported, opts.IgnoreUnsupported)
+}
+
+// reportUnsupported prints unsupported features, failing if there are any unless they're ignored
+func reportUnsupported(w io.Writer, contextName, contextType string, unsupported []string, ignore bool) error {
+	if len(unsupported) == 0 {
+		_, _ = fmt.Fprintf(w, "All features used are supported by context %q (%s)\n", contextName, contextType)
+		return nil
+	}
+	for _, feature := range unsupported {
+		_, _ = fmt.Fprintln(w, feature)
+	}
+	if ignore {
+		return nil
+	}
+	return fmt.Errorf("%d feature(s) unsupported by context %q (%s)", len(unsupported), contextName, contextType)
+}
@@ -0,0 +1,40 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReportUnsupported(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := reportUnsupported(out, "myaci", "aci", []string{"services.privileged: unsupported attribute"}, false)
+	assert.Error(t, err, `1 feature(s) unsupported by context "myaci" (aci)`)
+	assert.Equal(t, out.String(), "services.privileged: unsupported attribute\n")
+
+	out.Reset()
+	err = reportUnsupported(out, "myaci", "aci", []string{"services.privileged: unsupported attribute"}, true)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "services.privileged: unsupported attribute\n")
+
+	out.Reset()
+	assert.NilError(t, reportUnsupported(out, "default", "moby", nil, false))
+	assert.Equal(t, out.String(), "All features used are supported by context \"default\" (moby)\n")
+}
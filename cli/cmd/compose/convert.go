@@ -19,18 +19,33 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 
-	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	local_compose "github.com/docker/compose-cli/local/compose"
 )
 
+type convertOptions struct {
+	composeOptions
+	Lint        bool
+	Output      string
+	Parameters  string
+	ShowSecrets bool
+	// ListProfiles only prints the profiles services belong to
+	ListProfiles bool
+}
+
 func convertCommand() *cobra.Command {
-	opts := composeOptions{}
+	opts := convertOptions{}
 	convertCmd := &cobra.Command{
-		Use:   "convert",
-		Short: "Converts the compose file to a cloud format (default: cloudformation)",
+		Use:     "convert",
+		Aliases: []string{"config"},
+		Short:   "Converts the compose file to a cloud format (default: cloudformation)",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runConvert(cmd.Context(), opts)
 		},
@@ -38,13 +53,20 @@ func convertCommand() *cobra.Command {
 	convertCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	convertCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	convertCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	convertCmd.Flags().StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
 	convertCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
 	convertCmd.Flags().StringVar(&opts.Format, "format", "yaml", "Format the output. Values: [yaml | json]")
+	convertCmd.Flags().BoolVar(&opts.QuietLint, "quiet-lint", false, "Don't warn about unused resources declared by the compose file")
+	convertCmd.Flags().BoolVar(&opts.Lint, "lint", false, "Only check the compose file for unused resources, exit with error if any")
+	convertCmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Save to file (default to stdout)")
+	convertCmd.Flags().BoolVar(&opts.ShowSecrets, "show-secrets", false, "Show values of environment secrets where services or builds use them, instead of redacting them")
+	convertCmd.Flags().StringVar(&opts.Parameters, "parameters", "", "Also save to file the parameter values the converted model is deployed with")
+	convertCmd.Flags().BoolVar(&opts.ListProfiles, "profiles", false, "Print the profile names services belong to, one per line")
 
 	return convertCmd
 }
 
-func runConvert(ctx context.Context, opts composeOptions) error {
+func runConvert(ctx context.Context, opts convertOptions) error {
 	var json []byte
 	c, err := client.NewWithDefaultLocalBackend(ctx)
 	if err != nil {
@@ -56,16 +78,91 @@ func runConvert(ctx context.Context, opts composeOptions) error {
 		return err
 	}
 
-	project, err := cli.ProjectFromOptions(options)
+	project, err := projectFromOptions(options)
 	if err != nil {
 		return err
 	}
+	if opts.ListProfiles {
+		for _, profile := range local_compose.ProjectProfiles(project) {
+			fmt.Println(profile)
+		}
+		return nil
+	}
 
-	json, err = c.ComposeService().Convert(ctx, project, opts.Format)
+	warnings := lint(project)
+	if opts.Lint {
+		printLintWarnings(os.Stdout, warnings)
+		if len(warnings) > 0 {
+			return fmt.Errorf("%d warning(s) found in compose file", len(warnings))
+		}
+		return nil
+	}
+	if !opts.QuietLint {
+		printLintWarnings(os.Stderr, warnings)
+	}
+
+	if !opts.ShowSecrets {
+		redactSecrets(project)
+	}
+
+	json, err = c.ComposeService().Convert(ctx, project, compose.ConvertOptions{Format: opts.Format})
 	if err != nil {
 		return err
 	}
+	if opts.Parameters != "" {
+		parameters, err := c.ComposeService().Convert(ctx, project, compose.ConvertOptions{Format: opts.Format, Parameters: true})
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(opts.Parameters, append(parameters, '\n'), 0644)
+		if err != nil {
+			return err
+		}
+	}
 
-	fmt.Println(string(json))
-	return nil
+	if opts.Output == "" {
+		fmt.Println(string(json))
+		return nil
+	}
+	return ioutil.WriteFile(opts.Output, append(json, '\n'), 0644)
+}
+
+const redacted = "********"
+
+// redactSecrets hides values of environment secrets wherever services environment or build args hold them, as they
+// typically get interpolated from the same variable
+func redactSecrets(project *types.Project) {
+	values := map[string]bool{}
+	for _, secret := range project.Secrets {
+		if env := local_compose.SecretEnvironment(secret); env != "" {
+			if value, ok := os.LookupEnv(env); ok && value != "" {
+				values[value] = true
+			}
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	redact := func(mapping types.MappingWithEquals) types.MappingWithEquals {
+		if mapping == nil {
+			return nil
+		}
+		result := types.MappingWithEquals{}
+		for k, v := range mapping {
+			if v != nil && values[*v] {
+				r := redacted
+				v = &r
+			}
+			result[k] = v
+		}
+		return result
+	}
+	for i, service := range project.Services {
+		project.Services[i].Environment = redact(service.Environment)
+		if service.Build != nil {
+			build := *service.Build
+			build.Args = redact(build.Args)
+			project.Services[i].Build = &build
+		}
+	}
 }
@@ -0,0 +1,52 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	assert.NilError(t, os.Setenv("TEST_DB_PASS", "s3cr3t"))
+	defer os.Unsetenv("TEST_DB_PASS") //nolint:errcheck
+
+	pass := "s3cr3t"
+	user := "admin"
+	project := &types.Project{
+		Services: types.Services{
+			{
+				Name:        "db",
+				Environment: types.MappingWithEquals{"PASSWORD": &pass, "USER": &user, "UNSET": nil},
+				Build:       &types.BuildConfig{Context: ".", Args: types.MappingWithEquals{"PASS": &pass}},
+			},
+		},
+		Secrets: types.Secrets{
+			"db_pass": {Name: "db_pass", Extensions: map[string]interface{}{"x-environment": "TEST_DB_PASS"}},
+		},
+	}
+	redactSecrets(project)
+	environment := project.Services[0].Environment
+	assert.Equal(t, *environment["PASSWORD"], "********")
+	assert.Equal(t, *environment["USER"], "admin")
+	assert.Assert(t, environment["UNSET"] == nil)
+	assert.Equal(t, *project.Services[0].Build.Args["PASS"], "********")
+	assert.Equal(t, pass, "s3cr3t")
+}
@@ -0,0 +1,275 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/formatter"
+)
+
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+// resourceDiff is a service, network, volume, secret or config which differs between two compose files
+type resourceDiff struct {
+	Kind   string      `json:"kind"`
+	Name   string      `json:"name"`
+	Change string      `json:"change"`
+	Fields []fieldDiff `json:"fields,omitempty"`
+}
+
+// fieldDiff is an attribute of a resource, like `ports[0].published`, whose resolved value differs
+type fieldDiff struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+func diffFilesCommand() *cobra.Command {
+	opts := composeOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff-files -f OLD -f NEW",
+		Short: "Compare the resolved configuration of two compose files, rather than merging the second one as an override",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiffFiles(opts, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Old then new compose file")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
+	return cmd
+}
+
+func runDiffFiles(opts composeOptions, w io.Writer) error {
+	if len(opts.ConfigPaths) != 2 {
+		return fmt.Errorf("diff-files requires exactly two compose files, got %d", len(opts.ConfigPaths))
+	}
+	if opts.Format != "" && opts.Format != formatter.PRETTY && opts.Format != formatter.JSON {
+		return fmt.Errorf("unsupported format %q, must be one of pretty or json", opts.Format)
+	}
+	files := opts.ConfigPaths
+	opts.ConfigPaths = files[:1]
+	old, err := loadProject(opts)
+	if err != nil {
+		return err
+	}
+	// both files are resolved as the same project, so that resource names don't differ by project name
+	opts.Name = old.Name
+	opts.ConfigPaths = files[1:]
+	project, err := loadProject(opts)
+	if err != nil {
+		return err
+	}
+
+	diffs, err := diffProjects(old, project)
+	if err != nil {
+		return err
+	}
+	if opts.Format == formatter.JSON {
+		if diffs == nil {
+			diffs = []resourceDiff{}
+		}
+		out, err := formatter.ToStandardJSON(diffs)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(w, out)
+		return err
+	}
+	printDiffs(w, diffs)
+	return nil
+}
+
+func loadProject(opts composeOptions) (*types.Project, error) {
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return nil, err
+	}
+	return projectFromOptions(options)
+}
+
+// diffProjects compares services, then networks, volumes, secrets and configs of two projects, by name
+func diffProjects(before, after *types.Project) ([]resourceDiff, error) {
+	servicesByName := func(p *types.Project) map[string]interface{} {
+		services := map[string]interface{}{}
+		for _, s := range p.Services {
+			services[s.Name] = s
+		}
+		return services
+	}
+	sections := []struct {
+		kind          string
+		before, after interface{}
+	}{
+		{"service", servicesByName(before), servicesByName(after)},
+		{"network", before.Networks, after.Networks},
+		{"volume", before.Volumes, after.Volumes},
+		{"secret", before.Secrets, after.Secrets},
+		{"config", before.Configs, after.Configs},
+	}
+	var diffs []resourceDiff
+	for _, section := range sections {
+		beforeResources, err := toGeneric(section.before)
+		if err != nil {
+			return nil, err
+		}
+		afterResources, err := toGeneric(section.after)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diffResources(section.kind, asMap(beforeResources), asMap(afterResources))...)
+	}
+	return diffs, nil
+}
+
+// toGeneric converts o into maps, slices and scalars as its json representation, so that attributes are compared
+// with the names compose files use for them
+func toGeneric(o interface{}) (interface{}, error) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(b, &generic)
+	return generic, err
+}
+
+func asMap(o interface{}) map[string]interface{} {
+	m, _ := o.(map[string]interface{})
+	return m
+}
+
+func diffResources(kind string, before, after map[string]interface{}) []resourceDiff {
+	var names []string
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []resourceDiff
+	for _, name := range names {
+		o, inOld := before[name]
+		n, inNew := after[name]
+		switch {
+		case !inOld:
+			diffs = append(diffs, resourceDiff{Kind: kind, Name: name, Change: diffAdded})
+		case !inNew:
+			diffs = append(diffs, resourceDiff{Kind: kind, Name: name, Change: diffRemoved})
+		default:
+			if fields := diffFields("", o, n); len(fields) > 0 {
+				diffs = append(diffs, resourceDiff{Kind: kind, Name: name, Change: diffChanged, Fields: fields})
+			}
+		}
+	}
+	return diffs
+}
+
+// diffFields compares generic values recursively, down to the attributes which differ
+func diffFields(path string, before, after interface{}) []fieldDiff {
+	switch o := before.(type) {
+	case map[string]interface{}:
+		n, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var keys []string
+		for k := range o {
+			keys = append(keys, k)
+		}
+		for k := range n {
+			if _, ok := o[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var diffs []fieldDiff
+		for _, k := range keys {
+			diffs = append(diffs, diffFields(joinPath(path, k), o[k], n[k])...)
+		}
+		return diffs
+	case []interface{}:
+		n, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+		var diffs []fieldDiff
+		for i := 0; i < len(o) || i < len(n); i++ {
+			var oi, ni interface{}
+			if i < len(o) {
+				oi = o[i]
+			}
+			if i < len(n) {
+				ni = n[i]
+			}
+			diffs = append(diffs, diffFields(fmt.Sprintf("%s[%d]", path, i), oi, ni)...)
+		}
+		return diffs
+	}
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return []fieldDiff{{Path: path, Old: before, New: after}}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func printDiffs(w io.Writer, diffs []resourceDiff) {
+	if len(diffs) == 0 {
+		_, _ = fmt.Fprintln(w, "No difference")
+		return
+	}
+	for _, d := range diffs {
+		_, _ = fmt.Fprintf(w, "%s %s %s\n", d.Kind, d.Name, d.Change)
+		for _, f := range d.Fields {
+			_, _ = fmt.Fprintf(w, "  %s: %s -> %s\n", f.Path, diffValue(f.Old), diffValue(f.New))
+		}
+	}
+}
+
+func diffValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
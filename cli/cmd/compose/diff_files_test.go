@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestDiffFiles(t *testing.T) {
+	dir := fs.NewDir(t, "diff-files",
+		fs.WithFile("old.yaml", `
+services:
+  web:
+    image: nginx
+    ports:
+      - 8080:80
+  worker:
+    image: busybox
+volumes:
+  data: {}
+`),
+		fs.WithFile("new.yaml", `
+services:
+  web:
+    image: nginx
+    ports:
+      - 8081:80
+  cache:
+    image: redis
+volumes:
+  data: {}
+`),
+	)
+	defer dir.Remove()
+
+	opts := composeOptions{
+		Name:        "diff-files",
+		ConfigPaths: []string{dir.Join("old.yaml"), dir.Join("new.yaml")},
+	}
+	var out bytes.Buffer
+	assert.NilError(t, runDiffFiles(opts, &out))
+	assert.Equal(t, out.String(), `service cache added
+service web changed
+  ports[0].published: 8080 -> 8081
+service worker removed
+`)
+
+	opts.Format = "json"
+	out.Reset()
+	assert.NilError(t, runDiffFiles(opts, &out))
+	var diffs []resourceDiff
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &diffs))
+	assert.DeepEqual(t, diffs[1], resourceDiff{
+		Kind:   "service",
+		Name:   "web",
+		Change: diffChanged,
+		Fields: []fieldDiff{{Path: "ports[0].published", Old: float64(8080), New: float64(8081)}},
+	})
+}
+
+func TestDiffFilesSame(t *testing.T) {
+	dir := fs.NewDir(t, "diff-files", fs.WithFile("docker-compose.yml", "services:\n  web:\n    image: nginx\n"))
+	defer dir.Remove()
+
+	var out bytes.Buffer
+	file := dir.Join("docker-compose.yml")
+	assert.NilError(t, runDiffFiles(composeOptions{ConfigPaths: []string{file, file}}, &out))
+	assert.Equal(t, out.String(), "No difference\n")
+
+	err := runDiffFiles(composeOptions{ConfigPaths: []string{file}}, &out)
+	assert.Error(t, err, "diff-files requires exactly two compose files, got 1")
+}
+
+func TestDiffFields(t *testing.T) {
+	diffs := diffFields("", map[string]interface{}{
+		"environment": map[string]interface{}{"DEBUG": "1"},
+		"command":     []interface{}{"run", "--fast"},
+	}, map[string]interface{}{
+		"environment": map[string]interface{}{"DEBUG": "1", "LEVEL": "info"},
+		"command":     []interface{}{"run"},
+	})
+	assert.DeepEqual(t, diffs, []fieldDiff{
+		{Path: "command[1]", Old: "--fast", New: nil},
+		{Path: "environment.LEVEL", Old: nil, New: "info"},
+	})
+}
@@ -18,30 +18,55 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/progress"
 )
 
+type downOptions struct {
+	composeOptions
+	timeout    int
+	timeoutSet bool
+	images     string
+}
+
 func downCommand() *cobra.Command {
-	opts := composeOptions{}
+	opts := downOptions{}
 	downCmd := &cobra.Command{
 		Use:   "down",
 		Short: "Stop and remove containers, networks",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDown(cmd.Context(), opts)
+			opts.timeoutSet = cmd.Flags().Changed("timeout")
+			if err := checkSummaryFormat(opts.Format); err != nil {
+				return err
+			}
+			if opts.images != "" && opts.images != compose.RemoveImagesAll && opts.images != compose.RemoveImagesLocal {
+				return fmt.Errorf("invalid --rmi option %q, must be one of %q or %q", opts.images, compose.RemoveImagesAll, compose.RemoveImagesLocal)
+			}
+			return withSummary(cmd.Context(), opts.Format, os.Stdout, func(ctx context.Context) error {
+				return runDown(ctx, opts)
+			})
 		},
 	}
 	downCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	downCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	downCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	downCmd.Flags().StringVar(&opts.EnvFile, "env-file", "", "Specify an alternate environment file")
+
+	downCmd.Flags().StringVar(&opts.Format, "format", "", "Format the result. Values: [pretty | json]. json prints the resources touched once done. (Default: pretty)")
+	downCmd.Flags().IntVarP(&opts.timeout, "timeout", "t", 10, "Specify a shutdown timeout in seconds, 0 kills containers immediately")
+	downCmd.Flags().StringVar(&opts.images, "rmi", "", `Remove images used by services. "local" only removes images built without a custom tag, "all" removes any service image. Images already removed are skipped`)
 
 	return downCmd
 }
 
-func runDown(ctx context.Context, opts composeOptions) error {
+func runDown(ctx context.Context, opts downOptions) error {
 	c, err := client.NewWithDefaultLocalBackend(ctx)
 	if err != nil {
 		return err
@@ -52,7 +77,14 @@ func runDown(ctx context.Context, opts composeOptions) error {
 		if err != nil {
 			return "", err
 		}
-		return projectName, c.ComposeService().Down(ctx, projectName)
+		options := compose.DownOptions{
+			Images: opts.images,
+		}
+		if opts.timeoutSet {
+			timeout := time.Duration(opts.timeout) * time.Second
+			options.Timeout = &timeout
+		}
+		return projectName, c.ComposeService().Down(ctx, projectName, options)
 	})
 	return err
 }
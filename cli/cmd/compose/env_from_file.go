@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/envfile"
+	"github.com/compose-spec/compose-go/template"
+	"github.com/pkg/errors"
+)
+
+// withEnvFromFiles prepends KEY=VALUE entries loaded from env files to explicit environment entries, which take precedence.
+// `${VAR}` references in values are interpolated from the CLI environment, a KEY without value is also read from it
+func withEnvFromFiles(files []string, environment []string) ([]string, error) {
+	if len(files) == 0 {
+		return environment, nil
+	}
+	explicit := map[string]bool{}
+	for _, env := range environment {
+		explicit[strings.SplitN(env, "=", 2)[0]] = true
+	}
+	var merged []string
+	loaded := map[string]int{}
+	for _, file := range files {
+		vars, err := envfile.Parse(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read env file %s", file)
+		}
+		var keys []string
+		for k := range vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if explicit[k] {
+				continue
+			}
+			value, ok := os.LookupEnv(k)
+			if v := vars[k]; v != nil {
+				value, err = template.Substitute(*v, os.LookupEnv)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to interpolate %s from env file %s", k, file)
+				}
+			} else if !ok {
+				continue
+			}
+			entry := k + "=" + value
+			if i, ok := loaded[k]; ok {
+				// last env file wins
+				merged[i] = entry
+				continue
+			}
+			loaded[k] = len(merged)
+			merged = append(merged, entry)
+		}
+	}
+	return append(merged, environment...), nil
+}
@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWithEnvFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.env")
+	assert.NilError(t, ioutil.WriteFile(first, []byte(`# database
+DB_HOST=db
+DB_URL=postgres://${DB_USER}@db/app
+DB_USER
+PRICE=$$5
+LEVEL=info
+`), 0600))
+	second := filepath.Join(dir, "second.env")
+	assert.NilError(t, ioutil.WriteFile(second, []byte("DB_HOST=replica\nUNSET_IN_CLI\n"), 0600))
+
+	assert.NilError(t, os.Setenv("DB_USER", "admin"))
+	defer os.Unsetenv("DB_USER") //nolint:errcheck
+
+	env, err := withEnvFromFiles([]string{first, second}, []string{"LEVEL=debug"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, env, []string{
+		"DB_HOST=replica",
+		"DB_URL=postgres://admin@db/app",
+		"DB_USER=admin",
+		"PRICE=$5",
+		"LEVEL=debug",
+	})
+}
+
+func TestWithEnvFromMissingFile(t *testing.T) {
+	_, err := withEnvFromFiles([]string{filepath.Join(t.TempDir(), "missing.env")}, nil)
+	assert.ErrorContains(t, err, "failed to read env file")
+}
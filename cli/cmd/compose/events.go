@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type eventsOptions struct {
+	composeOptions
+	Filters []string
+}
+
+func eventsCommand() *cobra.Command {
+	opts := eventsOptions{}
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "Receive real time events from containers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEvents(cmd.Context(), opts)
+		},
+	}
+	eventsCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	eventsCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	eventsCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	eventsCmd.Flags().StringArrayVar(&opts.Filters, "filter", []string{}, "Filter events, e.g. \"type=container\", \"event=start,die\" or \"service=web\"")
+	return eventsCmd
+}
+
+func runEvents(ctx context.Context, opts eventsOptions) error {
+	filters, err := parseEventFilters(opts.Filters)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+
+	return c.ComposeService().Events(ctx, projectName, compose.EventsOptions{
+		Filters: filters,
+		Consumer: func(event compose.Event) error {
+			var attributes []string
+			for k, v := range event.Attributes {
+				attributes = append(attributes, fmt.Sprintf("%s=%s", k, v))
+			}
+			sort.Strings(attributes)
+			_, err := fmt.Fprintf(os.Stdout, "%s %s %s %s (%s)\n",
+				event.Timestamp.Format(time.RFC3339Nano), event.Type, event.Status, event.Container, strings.Join(attributes, ", "))
+			return err
+		},
+	})
+}
+
+// parseEventFilters parses `key=value[,value...]` filters, repeated keys accumulate values
+func parseEventFilters(filters []string) (map[string][]string, error) {
+	parsed := map[string][]string{}
+	for _, f := range filters {
+		parts := strings.SplitN(f, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid filter %q, expected key=value", f)
+		}
+		key := parts[0]
+		switch key {
+		case "type", "event", "service":
+		default:
+			return nil, fmt.Errorf("invalid filter key %q, must be one of type, event or service", key)
+		}
+		parsed[key] = append(parsed[key], strings.Split(parts[1], ",")...)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,39 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseEventFilters(t *testing.T) {
+	filters, err := parseEventFilters([]string{"type=container", "event=start,die", "service=web", "event=stop"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, filters, map[string][]string{
+		"type":    {"container"},
+		"event":   {"start", "die", "stop"},
+		"service": {"web"},
+	})
+
+	_, err = parseEventFilters([]string{"image=nginx"})
+	assert.ErrorContains(t, err, "invalid filter key \"image\"")
+
+	_, err = parseEventFilters([]string{"event"})
+	assert.ErrorContains(t, err, "invalid filter \"event\"")
+}
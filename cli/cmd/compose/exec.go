@@ -0,0 +1,164 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerd/console"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/context/store"
+)
+
+type execOptions struct {
+	composeOptions
+	Env          []string
+	EnvFromFiles []string
+	User         string
+	WorkDir      string
+	Index        int
+	Privileged   bool
+	NoTty        bool
+	DetachKeys   string
+}
+
+func execCommand(contextType string) *cobra.Command {
+	opts := execOptions{}
+	execCmd := &cobra.Command{
+		Use:   "exec [options] SERVICE COMMAND [ARGS...]",
+		Short: "Execute a command in a running service container",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Privileged && contextType != store.DefaultContextType && contextType != store.LocalContextType {
+				return fmt.Errorf("--privileged is not supported with %s context", contextType)
+			}
+			return runExec(cmd.Context(), opts, args[0], args[1:])
+		},
+	}
+	execCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	execCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	execCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	execCmd.Flags().StringArrayVarP(&opts.Env, "env", "e", []string{}, "Set environment variables")
+	execCmd.Flags().StringArrayVar(&opts.EnvFromFiles, "env-from-file", []string{}, "Set environment variables from a file of KEY=VALUE lines, --env values take precedence")
+	execCmd.Flags().StringVarP(&opts.User, "user", "u", "", "Run the command as this user")
+	execCmd.Flags().StringVarP(&opts.WorkDir, "working-dir", "w", "", "Path to run the command in, defaults to service working_dir")
+	execCmd.Flags().IntVar(&opts.Index, "index", 1, "Index of the container if service has multiple replicas")
+	execCmd.Flags().BoolVar(&opts.Privileged, "privileged", false, "Give extended privileges to the process")
+	execCmd.Flags().BoolVarP(&opts.NoTty, "no-TTY", "T", false, "Disable pseudo-TTY allocation")
+	execCmd.Flags().StringVar(&opts.DetachKeys, "detach-keys", "", "Override the key sequence for detaching from the command")
+	execCmd.Flags().SetInterspersed(false)
+	return execCmd
+}
+
+func runExec(ctx context.Context, opts execOptions, service string, command []string) error {
+	if opts.DetachKeys != "" {
+		if _, err := parseDetachKeys(opts.DetachKeys); err != nil {
+			return err
+		}
+	}
+	env, err := withEnvFromFiles(opts.EnvFromFiles, opts.Env)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	execOpts := compose.ExecOptions{
+		Service:     service,
+		Index:       opts.Index,
+		Command:     command,
+		Environment: env,
+		User:        opts.User,
+		WorkingDir:  opts.WorkDir,
+		Privileged:  opts.Privileged,
+		DetachKeys:  opts.DetachKeys,
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	}
+
+	// only allocate a pseudo-TTY when stdin is a terminal, so exec can be piped or run from CI
+	if con, err := console.ConsoleFromFile(os.Stdin); err == nil && !opts.NoTty {
+		execOpts.Tty = true
+		if err := con.SetRaw(); err != nil {
+			return err
+		}
+		defer func() {
+			if err := con.Reset(); err != nil {
+				fmt.Println("Unable to close the console")
+			}
+		}()
+
+		execOpts.Stdin = con
+		execOpts.Stdout = con
+		execOpts.Stderr = con
+	}
+
+	exitCode, err := c.ComposeService().Exec(ctx, project, execOpts)
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return ExitCodeError{ExitCode: exitCode}
+	}
+	return nil
+}
+
+// parseDetachKeys converts a comma separated key sequence, like `ctrl-p,ctrl-q`, into the matching bytes
+func parseDetachKeys(keys string) ([]byte, error) {
+	var codes []byte
+	for _, key := range strings.Split(keys, ",") {
+		if len(key) == 1 {
+			codes = append(codes, key[0])
+			continue
+		}
+		if !strings.HasPrefix(key, "ctrl-") || len(key) != len("ctrl-")+1 {
+			return nil, errors.Errorf("invalid detach keys %q: unknown character %q", keys, key)
+		}
+		c := key[len("ctrl-")]
+		switch {
+		case c >= 'a' && c <= 'z':
+			codes = append(codes, c-'a'+1)
+		case c == '@':
+			codes = append(codes, 0)
+		case c >= '[' && c <= '_':
+			codes = append(codes, c-'['+27)
+		default:
+			return nil, errors.Errorf("invalid detach keys %q: unknown character %q", keys, key)
+		}
+	}
+	return codes, nil
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseDetachKeys(t *testing.T) {
+	tests := []struct {
+		keys     string
+		expected []byte
+	}{
+		{keys: "ctrl-p,ctrl-q", expected: []byte{16, 17}},
+		{keys: "ctrl-@", expected: []byte{0}},
+		{keys: "ctrl-[,ctrl-\\,ctrl-],ctrl-^,ctrl-_", expected: []byte{27, 28, 29, 30, 31}},
+		{keys: "a,ctrl-a,Z", expected: []byte{'a', 1, 'Z'}},
+	}
+	for _, test := range tests {
+		codes, err := parseDetachKeys(test.keys)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, codes, test.expected)
+	}
+}
+
+func TestParseInvalidDetachKeys(t *testing.T) {
+	for _, keys := range []string{"ctrl-", "ctrl-A", "ctrl-ab", "shift-a", "ab", ""} {
+		_, err := parseDetachKeys(keys)
+		assert.ErrorContains(t, err, "invalid detach keys", keys)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+type exportEnvOptions struct {
+	composeOptions
+	out      string
+	services []string
+}
+
+func exportEnvCommand() *cobra.Command {
+	opts := exportEnvOptions{}
+	cmd := &cobra.Command{
+		Use:   "export-env --out DIR",
+		Short: "Write services resolved environment as dotenv files",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExportEnv(cmd.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	cmd.Flags().StringVar(&opts.out, "out", "", "Directory to write SERVICE.env files to")
+	cmd.Flags().StringArrayVar(&opts.services, "service", []string{}, "Only export environment for this service")
+	_ = cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+func runExportEnv(ctx context.Context, opts exportEnvOptions) error {
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	services := project.Services
+	if len(opts.services) > 0 {
+		services = nil
+		for _, name := range opts.services {
+			service, err := project.GetService(name)
+			if err != nil {
+				return err
+			}
+			services = append(services, service)
+		}
+	}
+
+	err = os.MkdirAll(opts.out, 0755)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		file := filepath.Join(opts.out, service.Name+".env")
+		err = writeEnvFile(file, service.Environment)
+		if err != nil {
+			return err
+		}
+		fmt.Println(file)
+	}
+	return nil
+}
+
+// writeEnvFile writes environment as a dotenv file, with values quoted and escaped. Variables without a value are skipped
+func writeEnvFile(file string, environment types.MappingWithEquals) error {
+	env := map[string]string{}
+	for k, v := range environment {
+		if v != nil {
+			env[k] = *v
+		}
+	}
+	content, err := godotenv.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, []byte(content+"\n"), 0644)
+}
@@ -0,0 +1,59 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/joho/godotenv"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestWriteEnvFileRoundtrip(t *testing.T) {
+	dir := fs.NewDir(t, "export-env")
+	defer dir.Remove()
+
+	simple := "value"
+	spaces := "some value with spaces"
+	quotes := `say "hello" it's me`
+	multiline := "first line\nsecond line"
+	equals := "a=b"
+	environment := types.MappingWithEquals{
+		"SIMPLE":    &simple,
+		"SPACES":    &spaces,
+		"QUOTES":    &quotes,
+		"MULTILINE": &multiline,
+		"EQUALS":    &equals,
+		"UNSET":     nil,
+	}
+
+	file := filepath.Join(dir.Path(), "web.env")
+	assert.NilError(t, writeEnvFile(file, environment))
+
+	env, err := godotenv.Read(file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, env, map[string]string{
+		"SIMPLE":    simple,
+		"SPACES":    spaces,
+		"QUOTES":    quotes,
+		"MULTILINE": multiline,
+		"EQUALS":    equals,
+	})
+}
@@ -0,0 +1,43 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"github.com/compose-spec/compose-go/types"
+)
+
+// mergeVolumesByTarget applies compose merge rules to volumes of services which extend or override another: environment
+// and labels are merged by key and ports appended by the loader, but volumes mounted on the same target must replace
+// each other, the last declared one winning while keeping the position of the first
+func mergeVolumesByTarget(project *types.Project) {
+	for i, service := range project.Services {
+		if len(service.Volumes) < 2 {
+			continue
+		}
+		var volumes []types.ServiceVolumeConfig
+		index := map[string]int{}
+		for _, volume := range service.Volumes {
+			if at, ok := index[volume.Target]; ok {
+				volumes[at] = volume
+				continue
+			}
+			index[volume.Target] = len(volumes)
+			volumes = append(volumes, volume)
+		}
+		project.Services[i].Volumes = volumes
+	}
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/cli"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestExtendsMergeRules(t *testing.T) {
+	dir := fs.NewDir(t, "extends", fs.WithFile("docker-compose.yml", `
+services:
+  base:
+    image: busybox
+    environment:
+      - A=parent
+      - B=parent
+    labels:
+      a: parent
+      b: parent
+    volumes:
+      - /data:/data
+      - /logs:/logs
+    ports:
+      - 8080:80
+  child:
+    extends: base
+    environment:
+      B: child
+      C: child
+    labels:
+      b: child
+    volumes:
+      - /other:/data
+    ports:
+      - 8443:443
+`))
+	defer dir.Remove()
+
+	options, err := cli.NewProjectOptions([]string{dir.Join("docker-compose.yml")}, cli.WithName("extends"))
+	assert.NilError(t, err)
+	project, err := projectFromOptions(options)
+	assert.NilError(t, err)
+	child, err := project.GetService("child")
+	assert.NilError(t, err)
+
+	environment := map[string]string{}
+	for k, v := range child.Environment {
+		environment[k] = *v
+	}
+	assert.DeepEqual(t, environment, map[string]string{"A": "parent", "B": "child", "C": "child"})
+	assert.DeepEqual(t, map[string]string(child.Labels), map[string]string{"a": "parent", "b": "child"})
+
+	assert.Equal(t, len(child.Volumes), 2)
+	assert.Equal(t, child.Volumes[0].Source, "/other")
+	assert.Equal(t, child.Volumes[0].Target, "/data")
+	assert.Equal(t, child.Volumes[1].Target, "/logs")
+
+	assert.Equal(t, len(child.Ports), 2)
+	assert.Equal(t, child.Ports[0].Target, uint32(80))
+	assert.Equal(t, child.Ports[1].Target, uint32(443))
+
+	base, err := project.GetService("base")
+	assert.NilError(t, err)
+	assert.Equal(t, len(base.Volumes), 2)
+	assert.Equal(t, base.Volumes[0].Source, "/data")
+}
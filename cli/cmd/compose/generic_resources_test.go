@@ -0,0 +1,106 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/docker/compose-cli/api/compose"
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+func TestGenericResourcesRoundTrip(t *testing.T) {
+	dir := fs.NewDir(t, "generic-resources",
+		fs.WithFile("docker-compose.yml", `
+services:
+  worker:
+    image: busybox
+    deploy:
+      resources:
+        reservations:
+          generic_resources:
+            - discrete_resource_spec:
+                kind: SSD
+                value: 2
+          x-generic_resources:
+            - named_resource_spec:
+                kind: FPGA
+                value: fpga-1
+`),
+	)
+	defer dir.Remove()
+
+	options, err := cli.NewProjectOptions([]string{dir.Join("docker-compose.yml")}, cli.WithName("generic-resources"))
+	assert.NilError(t, err)
+	project, err := projectFromOptions(options)
+	assert.NilError(t, err)
+	worker, err := project.GetService("worker")
+	assert.NilError(t, err)
+	reservations := worker.Deploy.Resources.Reservations
+	assert.Equal(t, len(reservations.GenericResources), 1)
+	assert.DeepEqual(t, *reservations.GenericResources[0].DiscreteResourceSpec, types.DiscreteGenericResource{Kind: "SSD", Value: 2})
+	assert.DeepEqual(t, reservations.Extensions, map[string]interface{}{
+		"x-generic_resources": []interface{}{
+			map[string]interface{}{
+				"named_resource_spec": map[string]interface{}{"kind": "FPGA", "value": "fpga-1"},
+			},
+		},
+	})
+
+	converted, err := local_compose.NewComposeService(nil).Convert(context.Background(), project, compose.ConvertOptions{Format: "yaml"})
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(converted), `
+        reservations:
+          generic_resources:
+          - discrete_resource_spec:
+              kind: SSD
+              value: 2
+          x-generic_resources:
+          - named_resource_spec:
+              kind: FPGA
+              value: fpga-1
+`), string(converted))
+}
+
+func TestGenericResourcesInvalid(t *testing.T) {
+	dir := fs.NewDir(t, "generic-resources",
+		fs.WithFile("docker-compose.yml", `
+services:
+  worker:
+    image: busybox
+    deploy:
+      resources:
+        reservations:
+          generic_resources:
+            - discrete_resource_spec:
+                kind: SSD
+`),
+	)
+	defer dir.Remove()
+
+	options, err := cli.NewProjectOptions([]string{dir.Join("docker-compose.yml")}, cli.WithName("generic-resources"))
+	assert.NilError(t, err)
+	_, err = projectFromOptions(options)
+	assert.Error(t, err, `service "worker": generic_resources[0]: discrete_resource_spec "SSD" requires a positive value`)
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+type graphOptions struct {
+	composeOptions
+	cycles bool
+}
+
+func graphCommand() *cobra.Command {
+	opts := graphOptions{}
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Print services dependency graph",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGraph(opts, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().BoolVar(&opts.cycles, "cycles", false, "Only report dependency cycles, as paths from a service back to itself")
+	return cmd
+}
+
+func runGraph(opts graphOptions, w io.Writer) error {
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	graph := local_compose.NewGraph(project.Services, local_compose.ServiceStopped)
+	if opts.cycles {
+		printCycles(w, graph.Cycles())
+		return nil
+	}
+	printDependencies(w, graph)
+	return nil
+}
+
+func printCycles(w io.Writer, cycles [][]string) {
+	if len(cycles) == 0 {
+		_, _ = fmt.Fprintln(w, "no cycles")
+		return
+	}
+	for _, cycle := range cycles {
+		_, _ = fmt.Fprintln(w, strings.Join(cycle, " -> "))
+	}
+}
+
+// printDependencies prints a `service -> dependency` line per dependency, and services without dependency alone
+func printDependencies(w io.Writer, graph *local_compose.Graph) {
+	var services []string
+	for name := range graph.Vertices {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		var dependencies []string
+		for name := range graph.Vertices[service].Children {
+			dependencies = append(dependencies, name)
+		}
+		if len(dependencies) == 0 {
+			_, _ = fmt.Fprintln(w, service)
+			continue
+		}
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			_, _ = fmt.Fprintf(w, "%s -> %s\n", service, dependency)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestGraphCycles(t *testing.T) {
+	dir := fs.NewDir(t, "graph", fs.WithFile("compose.yaml", `services:
+  web:
+    image: nginx
+    depends_on:
+      - api
+  api:
+    image: busybox
+    depends_on:
+      - db
+  db:
+    image: postgres
+    depends_on:
+      - web
+  cache:
+    image: redis
+`))
+	defer dir.Remove()
+	opts := graphOptions{
+		composeOptions: composeOptions{ConfigPaths: []string{dir.Join("compose.yaml")}},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, runGraph(opts, out))
+	assert.Equal(t, out.String(), "api -> db\ncache\ndb -> web\nweb -> api\n")
+
+	opts.cycles = true
+	out.Reset()
+	assert.NilError(t, runGraph(opts, out))
+	assert.Equal(t, out.String(), "api -> db -> web -> api\n")
+}
+
+func TestGraphNoCycles(t *testing.T) {
+	out := &bytes.Buffer{}
+	printCycles(out, nil)
+	assert.Equal(t, out.String(), "no cycles\n")
+}
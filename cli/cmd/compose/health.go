@@ -0,0 +1,112 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/formatter"
+)
+
+type healthOptions struct {
+	composeOptions
+	Index int
+	Last  int
+}
+
+func healthCommand() *cobra.Command {
+	opts := healthOptions{}
+	healthCmd := &cobra.Command{
+		Use:   "health SERVICE",
+		Short: "Display healthcheck results of a service's containers",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHealth(cmd.Context(), opts, args[0])
+		},
+	}
+	healthCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	healthCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	healthCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	healthCmd.Flags().StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
+	healthCmd.Flags().IntVar(&opts.Index, "index", 0, "Index of the container if service has multiple replicas")
+	healthCmd.Flags().IntVar(&opts.Last, "last", 0, "Only show the last N healthcheck results of each container, all when 0")
+	return healthCmd
+}
+
+func runHealth(ctx context.Context, opts healthOptions, service string) error {
+	if opts.Last < 0 {
+		return fmt.Errorf("invalid --last %d, can't be negative", opts.Last)
+	}
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	health, err := c.ComposeService().Health(ctx, projectName, service, opts.Index)
+	if err != nil {
+		return err
+	}
+
+	health = lastHealthResults(health, opts.Last)
+	return formatter.Print(health, opts.Format, os.Stdout,
+		func(w io.Writer) {
+			printHealth(w, health)
+		},
+		"NAME", "STATUS", "START", "EXIT CODE", "OUTPUT")
+}
+
+// printHealth prints a line per healthcheck result, or only the container status when no probe ran yet
+func printHealth(w io.Writer, health []compose.ContainerHealth) {
+	for _, container := range health {
+		if len(container.Log) == 0 {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t\t\t\n", container.Name, container.Status)
+		}
+		for _, result := range container.Log {
+			output := strings.ReplaceAll(strings.TrimSpace(result.Output), "\n", " ")
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", container.Name, container.Status,
+				result.Start.Format(time.RFC3339), result.ExitCode, output)
+		}
+	}
+}
+
+// lastHealthResults only keeps the last results of each container healthcheck log, all of them when last is 0
+func lastHealthResults(health []compose.ContainerHealth, last int) []compose.ContainerHealth {
+	if last == 0 {
+		return health
+	}
+	trimmed := make([]compose.ContainerHealth, len(health))
+	for i, container := range health {
+		if len(container.Log) > last {
+			container.Log = container.Log[len(container.Log)-last:]
+		}
+		trimmed[i] = container
+	}
+	return trimmed
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	// summaryProbes is the number of last healthcheck probes reported per container
+	summaryProbes = 3
+	// summaryLogLines is the number of last log lines reported per container
+	summaryLogLines = 10
+	// summaryLineWidth truncates probe outputs and log lines, so a chatty container doesn't flood the summary
+	summaryLineWidth = 200
+)
+
+// printHealthSummary reports containers of services which didn't get healthy, with their last healthcheck probes, last
+// log lines, and exit code if they died. Diagnostics which can't be collected are reported inline, as waiting already failed
+func printHealthSummary(ctx context.Context, w io.Writer, service compose.Service, projectName string, services []string) {
+	logs := &logCollector{lines: map[string][]string{}}
+	logsErr := service.Logs(ctx, projectName, logs, compose.LogOptions{
+		Services: services,
+		Tail:     strconv.Itoa(summaryLogLines),
+	})
+
+	for _, name := range services {
+		health, err := service.Health(ctx, projectName, name, 0)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "service %q: %s\n", name, err)
+			continue
+		}
+		for _, container := range health {
+			_, _ = fmt.Fprintf(w, "%s (%s):\n", container.Name, containerHealthState(container))
+
+			probes := container.Log
+			if len(probes) > summaryProbes {
+				probes = probes[len(probes)-summaryProbes:]
+			}
+			if len(probes) > 0 {
+				_, _ = fmt.Fprintln(w, "  last health probes:")
+			}
+			for _, probe := range probes {
+				_, _ = fmt.Fprintf(w, "    [exit %d] %s\n", probe.ExitCode, truncateLine(probe.Output))
+			}
+
+			if logsErr != nil {
+				_, _ = fmt.Fprintf(w, "  logs unavailable: %s\n", logsErr)
+				continue
+			}
+			lines := logs.get(container.ID)
+			if len(lines) > 0 {
+				_, _ = fmt.Fprintln(w, "  last logs:")
+			}
+			for _, line := range lines {
+				_, _ = fmt.Fprintf(w, "    %s\n", truncateLine(line))
+			}
+		}
+	}
+}
+
+func containerHealthState(container compose.ContainerHealth) string {
+	switch container.State {
+	case "exited", "dead":
+		return fmt.Sprintf("%s, %s with code %d", container.Status, container.State, container.ExitCode)
+	default:
+		return container.Status
+	}
+}
+
+// truncateLine joins a multi-line output on a single line, cut to summaryLineWidth characters
+func truncateLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= summaryLineWidth {
+		return s
+	}
+	return string(r[:summaryLineWidth-3]) + "..."
+}
+
+// logCollector is a LogConsumer keeping log lines per container
+type logCollector struct {
+	mu    sync.Mutex
+	lines map[string][]string
+}
+
+func (l *logCollector) Log(service, container, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines[container] = append(l.lines[container], message)
+}
+
+func (l *logCollector) get(container string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lines[container]
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type summaryService struct {
+	compose.Service
+	health map[string][]compose.ContainerHealth
+	logs   map[string][]string
+}
+
+func (s summaryService) Health(ctx context.Context, projectName string, service string, index int) ([]compose.ContainerHealth, error) {
+	return s.health[service], nil
+}
+
+func (s summaryService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	for container, lines := range s.logs {
+		for _, line := range lines {
+			consumer.Log("db", container, line)
+		}
+	}
+	return nil
+}
+
+func TestPrintHealthSummary(t *testing.T) {
+	service := summaryService{
+		health: map[string][]compose.ContainerHealth{
+			"db": {
+				{
+					ID:     "123",
+					Name:   "myproject_db_1",
+					Status: "unhealthy",
+					State:  "exited",
+					Log: []compose.HealthcheckResult{
+						{ExitCode: 1, Output: "first"},
+						{ExitCode: 1, Output: "second"},
+						{ExitCode: 1, Output: "third\n"},
+						{ExitCode: 1, Output: "connection\nrefused"},
+					},
+					ExitCode: 137,
+				},
+			},
+		},
+		logs: map[string][]string{
+			"123": {"starting", strings.Repeat("x", 300)},
+		},
+	}
+	out := &bytes.Buffer{}
+	printHealthSummary(context.Background(), out, service, "myproject", []string{"db"})
+	assert.Equal(t, out.String(), `myproject_db_1 (unhealthy, exited with code 137):
+  last health probes:
+    [exit 1] second
+    [exit 1] third
+    [exit 1] connection refused
+  last logs:
+    starting
+    `+strings.Repeat("x", 197)+`...
+`)
+}
@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestPrintHealth(t *testing.T) {
+	start := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	health := []compose.ContainerHealth{
+		{
+			Name:   "myproject_db_1",
+			Status: "starting",
+		},
+		{
+			Name:   "myproject_db_2",
+			Status: "unhealthy",
+			Log: []compose.HealthcheckResult{
+				{Start: start, ExitCode: 0, Output: "ok"},
+				{Start: start.Add(time.Second), ExitCode: 1, Output: "connection\nrefused\n"},
+			},
+		},
+	}
+	out := &bytes.Buffer{}
+	printHealth(out, health)
+	assert.Equal(t, out.String(), `myproject_db_1	starting			
+myproject_db_2	unhealthy	2020-12-01T10:00:00Z	0	ok
+myproject_db_2	unhealthy	2020-12-01T10:00:01Z	1	connection refused
+`)
+
+	out.Reset()
+	printHealth(out, lastHealthResults(health, 1))
+	assert.Equal(t, out.String(), `myproject_db_1	starting			
+myproject_db_2	unhealthy	2020-12-01T10:00:01Z	1	connection refused
+`)
+	assert.Equal(t, len(health[1].Log), 2)
+}
@@ -0,0 +1,122 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/formatter"
+)
+
+type imagesOptions struct {
+	composeOptions
+	Filter string
+}
+
+func imagesCommand() *cobra.Command {
+	opts := imagesOptions{}
+	imagesCmd := &cobra.Command{
+		Use:   "images",
+		Short: "List images used by the created containers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImages(cmd.Context(), opts)
+		},
+	}
+	imagesCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	imagesCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	imagesCmd.Flags().StringVar(&opts.Filter, "filter", "", "Filter images. Values: [mismatch=true] lists containers not running the image currently tagged for their service")
+	addComposeCommonFlags(imagesCmd.Flags(), &opts.composeOptions)
+	return imagesCmd
+}
+
+func runImages(ctx context.Context, opts imagesOptions) error {
+	mismatchOnly, err := parseImagesFilter(opts.Filter)
+	if err != nil {
+		return err
+	}
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	images, err := c.ComposeService().Images(ctx, project)
+	if err != nil {
+		return err
+	}
+	if mismatchOnly {
+		images = filterMismatchingImages(images)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].ContainerName < images[j].ContainerName
+	})
+	if opts.Quiet {
+		for _, image := range images {
+			fmt.Println(image.ImageID)
+		}
+		return nil
+	}
+	return formatter.Print(images, opts.Format, os.Stdout,
+		func(w io.Writer) {
+			for _, image := range images {
+				digest := image.Digest
+				if digest == "" {
+					digest = "<none>"
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", image.ContainerName, image.Service, image.Image, stringid.TruncateID(image.ImageID), digest)
+			}
+		},
+		"CONTAINER", "SERVICE", "IMAGE", "IMAGE ID", "DIGEST")
+}
+
+// parseImagesFilter parses a `--filter mismatch=true|false` value
+func parseImagesFilter(filter string) (bool, error) {
+	switch strings.ToLower(filter) {
+	case "", "mismatch=false":
+		return false, nil
+	case "mismatch=true":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid filter %q, supported filters are mismatch=true|false", filter)
+	}
+}
+
+func filterMismatchingImages(images []compose.ImageSummary) []compose.ImageSummary {
+	var filtered []compose.ImageSummary
+	for _, image := range images {
+		if image.Mismatch {
+			filtered = append(filtered, image)
+		}
+	}
+	return filtered
+}
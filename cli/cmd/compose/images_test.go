@@ -0,0 +1,46 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestParseImagesFilter(t *testing.T) {
+	mismatch, err := parseImagesFilter("")
+	assert.NilError(t, err)
+	assert.Assert(t, !mismatch)
+
+	mismatch, err = parseImagesFilter("mismatch=true")
+	assert.NilError(t, err)
+	assert.Assert(t, mismatch)
+
+	_, err = parseImagesFilter("dangling=true")
+	assert.Error(t, err, `invalid filter "dangling=true", supported filters are mismatch=true|false`)
+}
+
+func TestFilterMismatchingImages(t *testing.T) {
+	images := []compose.ImageSummary{
+		{ContainerName: "test_web_1", Mismatch: true},
+		{ContainerName: "test_db_1"},
+	}
+	assert.DeepEqual(t, filterMismatchingImages(images), []compose.ImageSummary{{ContainerName: "test_web_1", Mismatch: true}})
+}
@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/joho/godotenv"
+)
+
+// extLabelFile is the compose-spec `label_file` service attribute, not known yet by compose-go. It lists files of
+// KEY=VALUE entries, relative to the project working directory
+const extLabelFile = "x-label_file"
+
+// loadLabelFiles merges labels read from label files into services labels, inline labels winning. Later files override
+// earlier ones
+func loadLabelFiles(project *types.Project) error {
+	for i, service := range project.Services {
+		files, err := labelFiles(service)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		for j, file := range files {
+			if !filepath.IsAbs(file) {
+				files[j] = filepath.Join(project.WorkingDir, file)
+			}
+		}
+		labels, err := godotenv.Read(files...)
+		if err != nil {
+			return fmt.Errorf("service %q: %w", service.Name, err)
+		}
+		for k, v := range service.Labels {
+			labels[k] = v
+		}
+		project.Services[i].Labels = labels
+	}
+	return nil
+}
+
+// labelFiles lists label files of a service, declared as a single path or a list
+func labelFiles(service types.ServiceConfig) ([]string, error) {
+	switch value := service.Extensions[extLabelFile].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case []interface{}:
+		var files []string
+		for _, file := range value {
+			s, ok := file.(string)
+			if !ok {
+				return nil, fmt.Errorf("service %q: %s must be a path or a list of paths", service.Name, extLabelFile)
+			}
+			files = append(files, s)
+		}
+		return files, nil
+	default:
+		return nil, fmt.Errorf("service %q: %s must be a path or a list of paths", service.Name, extLabelFile)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestLoadLabelFiles(t *testing.T) {
+	dir := fs.NewDir(t, "label-file",
+		fs.WithFile("docker-compose.yml", `
+services:
+  web:
+    image: busybox
+    x-label_file:
+      - ./common.labels
+      - ./web.labels
+    labels:
+      team: inline
+  db:
+    image: busybox
+    x-label_file: ./common.labels
+`),
+		fs.WithFile("common.labels", "# shared labels\ncom.example.tier=backend\nteam=common\nowner=ops\n"),
+		fs.WithFile("web.labels", "owner=web\n"),
+	)
+	defer dir.Remove()
+
+	options, err := cli.NewProjectOptions([]string{dir.Join("docker-compose.yml")}, cli.WithName("label-file"))
+	assert.NilError(t, err)
+	project, err := projectFromOptions(options)
+	assert.NilError(t, err)
+
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, web.Labels, types.Labels{"com.example.tier": "backend", "team": "inline", "owner": "web"})
+	db, err := project.GetService("db")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, db.Labels, types.Labels{"com.example.tier": "backend", "team": "common", "owner": "ops"})
+}
+
+func TestLoadLabelFilesInvalid(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web", Extensions: map[string]interface{}{extLabelFile: 42}},
+		},
+	}
+	err := loadLabelFiles(project)
+	assert.Error(t, err, `service "web": x-label_file must be a path or a list of paths`)
+}
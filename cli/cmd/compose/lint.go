@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+// lint reports top-level resources declared by project but never used by a service
+func lint(project *types.Project) []string {
+	usedNetworks := map[string]bool{}
+	usedVolumes := map[string]bool{}
+	usedSecrets := map[string]bool{}
+	usedConfigs := map[string]bool{}
+	// services disabled by profiles still use the resources they declare
+	for _, service := range local_compose.DeclaredServices(project) {
+		for name := range service.Networks {
+			usedNetworks[name] = true
+		}
+		for _, volume := range service.Volumes {
+			if volume.Type == types.VolumeTypeVolume {
+				usedVolumes[volume.Source] = true
+			}
+		}
+		for _, secret := range service.Secrets {
+			usedSecrets[secret.Source] = true
+		}
+		for _, config := range service.Configs {
+			usedConfigs[config.Source] = true
+		}
+	}
+
+	var warnings []string
+	for name := range project.Networks {
+		// default network is implicitly declared, don't blame user for it
+		if name != "default" && !usedNetworks[name] {
+			warnings = append(warnings, fmt.Sprintf("network %q is declared but not used by any service", name))
+		}
+	}
+	for name := range project.Volumes {
+		if !usedVolumes[name] {
+			warnings = append(warnings, fmt.Sprintf("volume %q is declared but not used by any service", name))
+		}
+	}
+	for name := range project.Secrets {
+		if !usedSecrets[name] {
+			warnings = append(warnings, fmt.Sprintf("secret %q is declared but not used by any service", name))
+		}
+	}
+	for name := range project.Configs {
+		if !usedConfigs[name] {
+			warnings = append(warnings, fmt.Sprintf("config %q is declared but not used by any service", name))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+func printLintWarnings(w io.Writer, warnings []string) {
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintf(w, "WARNING: %s\n", warning)
+	}
+}
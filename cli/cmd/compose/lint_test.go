@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+func TestLintUnusedResources(t *testing.T) {
+	p := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name: "foo",
+				Networks: map[string]*types.ServiceNetworkConfig{
+					"front": nil,
+				},
+				Volumes: []types.ServiceVolumeConfig{
+					{Type: types.VolumeTypeVolume, Source: "data", Target: "/data"},
+				},
+			},
+		},
+		Networks: types.Networks{
+			"default": types.NetworkConfig{},
+			"front":   types.NetworkConfig{},
+			"back":    types.NetworkConfig{},
+		},
+		Volumes: types.Volumes{
+			"data":  types.VolumeConfig{},
+			"cache": types.VolumeConfig{},
+		},
+		Secrets: types.Secrets{
+			"token": types.SecretConfig{},
+		},
+	}
+	assert.DeepEqual(t, lint(&p), []string{
+		`network "back" is declared but not used by any service`,
+		`secret "token" is declared but not used by any service`,
+		`volume "cache" is declared but not used by any service`,
+	})
+}
+
+func TestLintResourcesOfDisabledServices(t *testing.T) {
+	p := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name: "debug",
+				Networks: map[string]*types.ServiceNetworkConfig{
+					"back": nil,
+				},
+				Extensions: map[string]interface{}{"x-profiles": []interface{}{"debug"}},
+			},
+		},
+		Networks: types.Networks{
+			"back": types.NetworkConfig{},
+		},
+	}
+	assert.NilError(t, local_compose.ApplyProfiles(&p, nil))
+	assert.Equal(t, len(p.Services), 0)
+	assert.Equal(t, len(lint(&p)), 0)
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -30,8 +31,17 @@ import (
 	"github.com/docker/compose-cli/formatter"
 )
 
+type listOptions struct {
+	composeOptions
+	Filter   string
+	Prunable time.Duration
+}
+
+// listStatusFilters are the container states projects can be filtered by
+var listStatusFilters = []string{"running", "exited", "dead"}
+
 func listCommand() *cobra.Command {
-	opts := composeOptions{}
+	opts := listOptions{}
 	lsCmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List running compose projects",
@@ -39,19 +49,33 @@ func listCommand() *cobra.Command {
 			return runList(cmd.Context(), opts)
 		},
 	}
-	addComposeCommonFlags(lsCmd.Flags(), &opts)
+	addComposeCommonFlags(lsCmd.Flags(), &opts.composeOptions)
+	lsCmd.Flags().StringVar(&opts.Filter, "filter", "", "Filter projects having containers in a state. Values: [status=running | status=exited | status=dead]")
+	lsCmd.Flags().DurationVar(&opts.Prunable, "prunable", 0, "List projects whose containers all exited for longer than a duration, and the commands to remove them")
 	return lsCmd
 }
 
-func runList(ctx context.Context, opts composeOptions) error {
+func runList(ctx context.Context, opts listOptions) error {
+	status, err := parseListFilter(opts.Filter)
+	if err != nil {
+		return err
+	}
 	c, err := client.NewWithDefaultLocalBackend(ctx)
 	if err != nil {
 		return err
 	}
-	stackList, err := c.ComposeService().List(ctx, opts.Name)
+	stackList, err := c.ComposeService().List(ctx, opts.Name, compose.ListOptions{
+		All: status != "" || opts.Prunable > 0,
+	})
 	if err != nil {
 		return err
 	}
+	if status != "" {
+		stackList = filterStacksByStatus(stackList, status)
+	}
+	if opts.Prunable > 0 {
+		stackList = prunableStacks(stackList, opts.Prunable, time.Now())
+	}
 	if opts.Quiet {
 		for _, s := range stackList {
 			fmt.Println(s.Name)
@@ -59,24 +83,85 @@ func runList(ctx context.Context, opts composeOptions) error {
 		return nil
 	}
 	view := viewFromStackList(stackList)
-	return formatter.Print(view, opts.Format, os.Stdout, func(w io.Writer) {
+	err = formatter.Print(view, opts.Format, os.Stdout, func(w io.Writer) {
 		for _, stack := range view {
 			_, _ = fmt.Fprintf(w, "%s\t%s\n", stack.Name, stack.Status)
 		}
 	}, "NAME", "STATUS")
+	if err != nil || opts.Prunable == 0 {
+		return err
+	}
+	if opts.Format == "" || opts.Format == formatter.PRETTY {
+		printPruneCommands(os.Stdout, stackList)
+	}
+	return nil
+}
+
+// parseListFilter parses a `--filter status=STATE` value and returns the state
+func parseListFilter(filter string) (string, error) {
+	if filter == "" {
+		return "", nil
+	}
+	kv := strings.SplitN(filter, "=", 2)
+	if len(kv) == 2 && kv[0] == "status" {
+		for _, status := range listStatusFilters {
+			if kv[1] == status {
+				return status, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("invalid filter %q, supported filters are status=%s", filter, strings.Join(listStatusFilters, "|"))
+}
+
+func filterStacksByStatus(stacks []compose.Stack, status string) []compose.Stack {
+	var filtered []compose.Stack
+	for _, s := range stacks {
+		if s.Containers[status] > 0 {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// prunableStacks selects stacks whose containers all exited for longer than a duration
+func prunableStacks(stacks []compose.Stack, exitedFor time.Duration, now time.Time) []compose.Stack {
+	var prunable []compose.Stack
+	for _, s := range stacks {
+		if !s.Exited.IsZero() && now.Sub(s.Exited) > exitedFor {
+			prunable = append(prunable, s)
+		}
+	}
+	return prunable
+}
+
+func printPruneCommands(w io.Writer, stacks []compose.Stack) {
+	if len(stacks) == 0 {
+		_, _ = fmt.Fprintln(w, "No prunable project")
+		return
+	}
+	_, _ = fmt.Fprintln(w, "\nRemove them with:")
+	for _, s := range stacks {
+		_, _ = fmt.Fprintf(w, "  docker compose down -p %s\n", s.Name)
+	}
 }
 
 type stackView struct {
-	Name   string
-	Status string
+	Name       string
+	Status     string
+	Containers map[string]int `json:",omitempty"`
+	Created    string         `json:",omitempty"`
 }
 
 func viewFromStackList(stackList []compose.Stack) []stackView {
 	retList := make([]stackView, len(stackList))
 	for i, s := range stackList {
 		retList[i] = stackView{
-			Name:   s.Name,
-			Status: strings.TrimSpace(fmt.Sprintf("%s %s", s.Status, s.Reason)),
+			Name:       s.Name,
+			Status:     strings.TrimSpace(fmt.Sprintf("%s %s", s.Status, s.Reason)),
+			Containers: s.Containers,
+		}
+		if !s.Created.IsZero() {
+			retList[i].Created = s.Created.Format(time.RFC3339)
 		}
 	}
 	return retList
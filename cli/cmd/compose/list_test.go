@@ -0,0 +1,96 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestParseListFilter(t *testing.T) {
+	status, err := parseListFilter("")
+	assert.NilError(t, err)
+	assert.Equal(t, status, "")
+
+	status, err = parseListFilter("status=exited")
+	assert.NilError(t, err)
+	assert.Equal(t, status, "exited")
+
+	_, err = parseListFilter("status=sleeping")
+	assert.Error(t, err, `invalid filter "status=sleeping", supported filters are status=running|exited|dead`)
+	_, err = parseListFilter("name=myproject")
+	assert.ErrorContains(t, err, "invalid filter")
+}
+
+func TestFilterStacksByStatus(t *testing.T) {
+	stacks := []compose.Stack{
+		{Name: "running", Containers: map[string]int{"running": 2}},
+		{Name: "mixed", Containers: map[string]int{"running": 1, "exited": 1}},
+		{Name: "dead", Containers: map[string]int{"dead": 1}},
+	}
+	assert.DeepEqual(t, stackNames(filterStacksByStatus(stacks, "running")), []string{"running", "mixed"})
+	assert.DeepEqual(t, stackNames(filterStacksByStatus(stacks, "exited")), []string{"mixed"})
+	assert.DeepEqual(t, stackNames(filterStacksByStatus(stacks, "dead")), []string{"dead"})
+}
+
+func TestPrunableStacks(t *testing.T) {
+	now := time.Date(2020, 12, 1, 12, 0, 0, 0, time.UTC)
+	stacks := []compose.Stack{
+		{Name: "running", Containers: map[string]int{"running": 1}},
+		{Name: "recent", Containers: map[string]int{"exited": 1}, Exited: now.Add(-time.Hour)},
+		{Name: "stale", Containers: map[string]int{"exited": 2}, Exited: now.Add(-48 * time.Hour)},
+	}
+	prunable := prunableStacks(stacks, 24*time.Hour, now)
+	assert.DeepEqual(t, stackNames(prunable), []string{"stale"})
+
+	var out bytes.Buffer
+	printPruneCommands(&out, prunable)
+	assert.Equal(t, out.String(), "\nRemove them with:\n  docker compose down -p stale\n")
+
+	out.Reset()
+	printPruneCommands(&out, nil)
+	assert.Equal(t, out.String(), "No prunable project\n")
+}
+
+func TestViewFromStackList(t *testing.T) {
+	view := viewFromStackList([]compose.Stack{
+		{
+			Name:       "myproject",
+			Status:     "exited(1), running(1)",
+			Containers: map[string]int{"running": 1, "exited": 1},
+			Created:    time.Date(2020, 12, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{Name: "remote", Status: "FAILED", Reason: "stack creation failed"},
+	})
+	assert.DeepEqual(t, view, []stackView{
+		{Name: "myproject", Status: "exited(1), running(1)", Containers: map[string]int{"running": 1, "exited": 1}, Created: "2020-12-01T12:00:00Z"},
+		{Name: "remote", Status: "FAILED stack creation failed"},
+	})
+}
+
+func stackNames(stacks []compose.Stack) []string {
+	var names []string
+	for _, s := range stacks {
+		names = append(names, s.Name)
+	}
+	return names
+}
@@ -18,40 +18,247 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/formatter"
+	local_compose "github.com/docker/compose-cli/local/compose"
+	"github.com/docker/compose-cli/utils"
 
+	"github.com/moby/term"
 	"github.com/spf13/cobra"
 )
 
+type logsOptions struct {
+	composeOptions
+	Follow     bool
+	Since      string
+	NoExited   bool
+	Tail       string
+	Timestamps bool
+}
+
 func logsCommand() *cobra.Command {
-	opts := composeOptions{}
+	opts := logsOptions{}
 	logsCmd := &cobra.Command{
-		Use:   "logs",
+		Use:   "logs [service...]",
 		Short: "View output from containers",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runLogs(cmd.Context(), opts)
+			color, err := useColors(ansiAuto, os.Stdout)
+			if err != nil {
+				return err
+			}
+			consumer := formatter.NewColoredLogConsumer(cmd.Context(), os.Stdout, formatter.ColorByService, color)
+			return runLogs(cmd.Context(), opts, args, consumer)
+		},
+	}
+	logsCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	logsCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	logsCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	logsCmd.Flags().BoolVar(&opts.Follow, "follow", true, "Follow log output, use --follow=false to only print logs emitted so far")
+	logsCmd.Flags().StringVar(&opts.Since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37), relative duration (e.g. 42m), or \"container-start\" for logs since containers last started")
+	logsCmd.Flags().BoolVar(&opts.NoExited, "no-exited", false, "Only show logs of running containers")
+	logsCmd.Flags().StringVar(&opts.Tail, "tail", "all", "Number of lines to show from the end of each container logs")
+	logsCmd.Flags().BoolVarP(&opts.Timestamps, "timestamps", "t", false, "Show timestamps")
+
+	return logsCmd
+}
+
+type alphaLogsOptions struct {
+	logsOptions
+	MergeStderr bool
+	ColorBy     string
+	Ansi        string
+	Save        string
+}
+
+func alphaLogsCommand() *cobra.Command {
+	opts := alphaLogsOptions{}
+	logsCmd := &cobra.Command{
+		Use:   "logs [service...]",
+		Short: "View output from containers, optionally telling stdout and stderr apart",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Save != "" {
+				if cmd.Flags().Changed("follow") && opts.Follow {
+					return fmt.Errorf("--save can't be combined with --follow")
+				}
+				// saved logs are a snapshot, written once containers logged so far
+				opts.Follow = false
+				return runSaveLogs(cmd.Context(), opts.logsOptions, args, opts.Save, os.Stdout)
+			}
+			consumer, err := alphaLogConsumer(cmd.Context(), opts, os.Stdout)
+			if err != nil {
+				return err
+			}
+			return runLogs(cmd.Context(), opts.logsOptions, args, consumer)
 		},
 	}
 	logsCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
 	logsCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	logsCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	logsCmd.Flags().BoolVar(&opts.Follow, "follow", true, "Follow log output, use --follow=false to only print logs emitted so far")
+	logsCmd.Flags().StringVar(&opts.Since, "since", "", "Show logs since timestamp (e.g. 2013-01-02T13:23:37), relative duration (e.g. 42m), or \"container-start\" for logs since containers last started")
+	logsCmd.Flags().BoolVar(&opts.NoExited, "no-exited", false, "Only show logs of running containers")
+	logsCmd.Flags().StringVar(&opts.Tail, "tail", "all", "Number of lines to show from the end of each container logs")
+	logsCmd.Flags().BoolVarP(&opts.Timestamps, "timestamps", "t", false, "Show timestamps")
+	logsCmd.Flags().StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
+	logsCmd.Flags().BoolVar(&opts.MergeStderr, "merge-stderr", true, "Combine stderr with stdout in emission order, otherwise mark lines with their stream. Ignored by json format which always reports the stream")
+	logsCmd.Flags().StringVar(&opts.ColorBy, "color-by", formatter.ColorByService, "Pick log colors per service or per container. Values: [service | container]")
+	logsCmd.Flags().StringVar(&opts.Ansi, "ansi", ansiAuto, "Control when to print ANSI control characters. Values: [never | always | auto]")
+	logsCmd.Flags().StringVar(&opts.Save, "save", "", "Save each container logs to a service-index.log file in this directory rather than printing them")
 
 	return logsCmd
 }
 
-func runLogs(ctx context.Context, opts composeOptions) error {
+const (
+	ansiNever  = "never"
+	ansiAlways = "always"
+	ansiAuto   = "auto"
+)
+
+// alphaLogConsumer selects the LogConsumer for output format, stream being always reported in json format
+func alphaLogConsumer(ctx context.Context, opts alphaLogsOptions, w io.Writer) (compose.LogConsumer, error) {
+	switch opts.ColorBy {
+	case "", formatter.ColorByService, formatter.ColorByContainer:
+	default:
+		return nil, fmt.Errorf("unsupported color-by %q, must be one of service or container", opts.ColorBy)
+	}
+	color, err := useColors(opts.Ansi, w)
+	if err != nil {
+		return nil, err
+	}
+	switch opts.Format {
+	case "", formatter.PRETTY:
+		if opts.MergeStderr {
+			return formatter.NewColoredLogConsumer(ctx, w, opts.ColorBy, color), nil
+		}
+		return formatter.NewStreamLogConsumer(ctx, w, opts.ColorBy, color), nil
+	case formatter.JSON:
+		return formatter.NewJSONLogConsumer(ctx, w), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q, must be one of pretty or json", opts.Format)
+	}
+}
+
+// useColors tells if log prefixes can be colored by ANSI codes, in auto mode only when writing to a terminal
+func useColors(ansi string, w io.Writer) (bool, error) {
+	switch ansi {
+	case ansiNever:
+		return false, nil
+	case ansiAlways:
+		return true, nil
+	case "", ansiAuto:
+		_, isTerminal := term.GetFdInfo(w)
+		return isTerminal, nil
+	default:
+		return false, fmt.Errorf("unsupported ansi %q, must be one of never, always or auto", ansi)
+	}
+}
+
+func runLogs(ctx context.Context, opts logsOptions, services []string, consumer compose.LogConsumer) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := opts.withRecordedProject(ctx, c.ComposeService()); err != nil {
+		return err
+	}
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		var detached bool
+		services, detached = opts.attachedServices()
+		if detached && len(services) == 0 {
+			return nil
+		}
+	}
+	return c.ComposeService().Logs(ctx, projectName, consumer, opts.toLogOptions(services))
+}
+
+// attachedServices lists services to show logs of when none is requested, leaving out the ones declaring
+// `attach: false`. It returns no service, meaning all of them, unless compose file excludes some
+func (opts logsOptions) attachedServices() ([]string, bool) {
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return nil, false
+	}
+	// logs don't require a compose file when project name is set, so logs of the whole project are shown without one
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return nil, false
+	}
+	var (
+		attached []string
+		detached bool
+	)
+	for _, service := range project.Services {
+		if local_compose.IsAttached(service) {
+			attached = append(attached, service.Name)
+		} else {
+			detached = true
+		}
+	}
+	if !detached {
+		return nil, false
+	}
+	return attached, true
+}
+
+func (opts logsOptions) toLogOptions(services []string) compose.LogOptions {
+	return compose.LogOptions{
+		Follow:     opts.Follow,
+		Since:      opts.Since,
+		Services:   services,
+		NoExited:   opts.NoExited,
+		Tail:       opts.Tail,
+		Timestamps: opts.Timestamps,
+	}
+}
+
+// runSaveLogs writes logs of each container into its own file in dir, then prints a summary of files written
+func runSaveLogs(ctx context.Context, opts logsOptions, services []string, dir string, w io.Writer) error {
 	c, err := client.NewWithDefaultLocalBackend(ctx)
 	if err != nil {
 		return err
 	}
 
+	if err := opts.withRecordedProject(ctx, c.ComposeService()); err != nil {
+		return err
+	}
 	projectName, err := opts.toProjectName()
 	if err != nil {
 		return err
 	}
-	consumer := formatter.NewLogConsumer(ctx, os.Stdout)
-	return c.ComposeService().Logs(ctx, projectName, consumer)
+	containers, err := c.ComposeService().Ps(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	if len(services) > 0 {
+		var selected []compose.ContainerSummary
+		for _, c := range containers {
+			if utils.StringContains(services, c.Service) {
+				selected = append(selected, c)
+			}
+		}
+		containers = selected
+	}
+	consumer, err := newSaveLogConsumer(dir, containers)
+	if err != nil {
+		return err
+	}
+	err = c.ComposeService().Logs(ctx, projectName, consumer, opts.toLogOptions(services))
+	if closeErr := consumer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	consumer.printSummary(w)
+	return nil
 }
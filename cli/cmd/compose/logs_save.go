@@ -0,0 +1,178 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// saveLogConsumer writes each container logs into its own `service-index.log` file
+type saveLogConsumer struct {
+	mu      sync.Mutex
+	dir     string
+	indexes map[string]string
+	next    map[string]int
+	files   map[string]*savedLog
+	err     error
+}
+
+type savedLog struct {
+	path  string
+	file  *os.File
+	lines int
+}
+
+// newSaveLogConsumer creates a LogConsumer writing logs into dir, container indexes being resolved from their names.
+// Files are created upfront, so that containers which didn't log anything get an empty one
+func newSaveLogConsumer(dir string, containers []compose.ContainerSummary) (*saveLogConsumer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	l := &saveLogConsumer{
+		dir:     dir,
+		indexes: map[string]string{},
+		next:    map[string]int{},
+		files:   map[string]*savedLog{},
+	}
+	for _, c := range containers {
+		if index := replicaIndex(c.Name); index != "" {
+			l.indexes[c.ID] = index
+		}
+	}
+	for _, c := range containers {
+		if _, err := l.fileFor(c.Service, c.ID); err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// replicaIndex extracts the replica number ending a container name, as `project_service_1` or `service-1`
+func replicaIndex(name string) string {
+	index := name[strings.LastIndexAny(name, "_-")+1:]
+	if _, err := strconv.Atoi(index); err != nil {
+		return ""
+	}
+	return index
+}
+
+// ContainerExited records the replica number of an exited container, unknown to ps, and creates its file
+func (l *saveLogConsumer) ContainerExited(container, name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if index := replicaIndex(name); index != "" {
+		l.indexes[container] = index
+	}
+	if l.err != nil {
+		return
+	}
+	service := name
+	if index := replicaIndex(name); index != "" {
+		service = name[:len(name)-len(index)-1]
+	}
+	if _, err := l.fileFor(service, container); err != nil {
+		l.err = err
+	}
+}
+
+// Log appends message to the file of container
+func (l *saveLogConsumer) Log(service, container, message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return
+	}
+	saved, err := l.fileFor(service, container)
+	if err == nil {
+		saved.lines++
+		_, err = io.WriteString(saved.file, message+"\n")
+	}
+	if err != nil {
+		l.err = err
+	}
+}
+
+func (l *saveLogConsumer) fileFor(service, container string) (*savedLog, error) {
+	if saved, ok := l.files[container]; ok {
+		return saved, nil
+	}
+	index, ok := l.indexes[container]
+	path := filepath.Join(l.dir, fmt.Sprintf("%s-%s.log", service, index))
+	// containers we don't know the index of get the next one available
+	for !ok || l.used(path) {
+		l.next[service]++
+		index, ok = strconv.Itoa(l.next[service]), true
+		path = filepath.Join(l.dir, fmt.Sprintf("%s-%s.log", service, index))
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	saved := &savedLog{path: path, file: file}
+	l.files[container] = saved
+	return saved, nil
+}
+
+func (l *saveLogConsumer) used(path string) bool {
+	for _, saved := range l.files {
+		if saved.path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes log files, and returns the first error met writing them
+func (l *saveLogConsumer) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, saved := range l.files {
+		if err := saved.file.Close(); err != nil && l.err == nil {
+			l.err = err
+		}
+	}
+	return l.err
+}
+
+// printSummary lists files written, with the number of log lines each got
+func (l *saveLogConsumer) printSummary(w io.Writer) {
+	var saved []*savedLog
+	for _, s := range l.files {
+		saved = append(saved, s)
+	}
+	sort.Slice(saved, func(i, j int) bool {
+		return saved[i].path < saved[j].path
+	})
+	if len(saved) == 0 {
+		_, _ = fmt.Fprintf(w, "No logs saved to %s\n", l.dir)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Saved logs of %d containers to %s\n", len(saved), l.dir)
+	for _, s := range saved {
+		_, _ = fmt.Fprintf(w, "  %s (%d lines)\n", s.path, s.lines)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestReplicaIndex(t *testing.T) {
+	assert.Equal(t, replicaIndex("project_web_2"), "2")
+	assert.Equal(t, replicaIndex("project-web-12"), "12")
+	assert.Equal(t, replicaIndex("web_1"), "1")
+	assert.Equal(t, replicaIndex("web"), "")
+	assert.Equal(t, replicaIndex("project_web_run_abc"), "")
+}
+
+func TestSaveLogConsumer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	consumer, err := newSaveLogConsumer(dir, []compose.ContainerSummary{
+		{ID: "aaa", Name: "project_web_2", Service: "web"},
+		{ID: "ddd", Name: "project_cache_1", Service: "cache"},
+	})
+	assert.NilError(t, err)
+
+	consumer.ContainerExited("bbb", "db_1")
+	consumer.ContainerExited("eee", "worker_1")
+	consumer.Log("web", "aaa", "hello")
+	consumer.Log("db", "bbb", "ready")
+	consumer.Log("web", "aaa", "world")
+	consumer.Log("web", "ccc", "unknown replica")
+	assert.NilError(t, consumer.Close())
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "web-2.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "hello\nworld\n")
+
+	content, err = ioutil.ReadFile(filepath.Join(dir, "db-1.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "ready\n")
+
+	content, err = ioutil.ReadFile(filepath.Join(dir, "web-1.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "unknown replica\n")
+
+	// silent containers get an empty file
+	for _, file := range []string{"cache-1.log", "worker-1.log"} {
+		content, err = ioutil.ReadFile(filepath.Join(dir, file))
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), "")
+	}
+
+	out := &bytes.Buffer{}
+	consumer.printSummary(out)
+	assert.Equal(t, out.String(), "Saved logs of 5 containers to "+dir+"\n"+
+		"  "+filepath.Join(dir, "cache-1.log")+" (0 lines)\n"+
+		"  "+filepath.Join(dir, "db-1.log")+" (1 lines)\n"+
+		"  "+filepath.Join(dir, "web-1.log")+" (1 lines)\n"+
+		"  "+filepath.Join(dir, "web-2.log")+" (2 lines)\n"+
+		"  "+filepath.Join(dir, "worker-1.log")+" (0 lines)\n")
+}
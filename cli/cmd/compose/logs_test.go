@@ -0,0 +1,87 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestAlphaLogConsumer(t *testing.T) {
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+
+	merged, err := alphaLogConsumer(ctx, alphaLogsOptions{MergeStderr: true}, out)
+	assert.NilError(t, err)
+	_, ok := merged.(compose.StreamLogConsumer)
+	assert.Assert(t, !ok, "merged output should not tell streams apart")
+
+	separate, err := alphaLogConsumer(ctx, alphaLogsOptions{MergeStderr: false}, out)
+	assert.NilError(t, err)
+	_, ok = separate.(compose.StreamLogConsumer)
+	assert.Assert(t, ok)
+
+	opts := alphaLogsOptions{MergeStderr: true}
+	opts.Format = "json"
+	json, err := alphaLogConsumer(ctx, opts, out)
+	assert.NilError(t, err)
+	_, ok = json.(compose.StreamLogConsumer)
+	assert.Assert(t, ok, "json output always reports stream")
+
+	opts.Format = "yaml"
+	_, err = alphaLogConsumer(ctx, opts, out)
+	assert.Error(t, err, `unsupported format "yaml", must be one of pretty or json`)
+}
+
+func TestAlphaLogConsumerColors(t *testing.T) {
+	ctx := context.Background()
+
+	out := &bytes.Buffer{}
+	consumer, err := alphaLogConsumer(ctx, alphaLogsOptions{MergeStderr: true, ColorBy: "container", Ansi: "always"}, out)
+	assert.NilError(t, err)
+	consumer.Log("web", "web_1", "hello")
+	consumer.Log("web", "web_2", "hello")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.HasPrefix(lines[0], "\033["), lines[0])
+	assert.Assert(t, lines[0] != lines[1], "replicas should get distinct colors: %q", out.String())
+
+	out.Reset()
+	consumer, err = alphaLogConsumer(ctx, alphaLogsOptions{MergeStderr: true, ColorBy: "container", Ansi: "never"}, out)
+	assert.NilError(t, err)
+	consumer.Log("web", "web_1", "hello")
+	consumer.Log("web", "web_2", "hello")
+	assert.Assert(t, !strings.Contains(out.String(), "\033["), out.String())
+
+	out.Reset()
+	consumer, err = alphaLogConsumer(ctx, alphaLogsOptions{MergeStderr: true, ColorBy: "container"}, out)
+	assert.NilError(t, err)
+	consumer.Log("web", "web_1", "hello")
+	assert.Assert(t, !strings.Contains(out.String(), "\033["), "auto mode should not color output to a non-terminal: %q", out.String())
+
+	_, err = alphaLogConsumer(ctx, alphaLogsOptions{ColorBy: "project"}, out)
+	assert.Error(t, err, `unsupported color-by "project", must be one of service or container`)
+
+	_, err = alphaLogConsumer(ctx, alphaLogsOptions{Ansi: "sometimes"}, out)
+	assert.Error(t, err, `unsupported ansi "sometimes", must be one of never, always or auto`)
+}
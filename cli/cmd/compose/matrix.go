@@ -0,0 +1,173 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/formatter"
+)
+
+type matrixOptions struct {
+	runOptions
+	Matrix   string
+	Parallel bool
+}
+
+func matrixRunCommand() *cobra.Command {
+	opts := matrixOptions{}
+	runCmd := &cobra.Command{
+		Use:   "run --matrix VAR=a,b,c [options] SERVICE [COMMAND] [ARGS...]",
+		Short: "Run a one-off command on a service once per matrix value",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMatrix(cmd.Context(), opts, args[0], args[1:])
+		},
+	}
+	runCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	runCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	runCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	runCmd.Flags().StringArrayVarP(&opts.Env, "env", "e", []string{}, "Set environment variables")
+	runCmd.Flags().StringVar(&opts.Matrix, "matrix", "", "Variable and comma separated values to run command with, e.g. \"VAR=a,b,c\"")
+	runCmd.Flags().BoolVar(&opts.Parallel, "parallel", false, "Run command for all matrix values in parallel")
+	runCmd.Flags().BoolVar(&opts.Keep, "keep", false, "Keep the one-off containers once command completed")
+	runCmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, "Stop waiting for each command completion after this duration, e.g. \"30m\"")
+	runCmd.Flags().SetInterspersed(false)
+	_ = runCmd.MarkFlagRequired("matrix")
+	return runCmd
+}
+
+func runMatrix(ctx context.Context, opts matrixOptions, service string, command []string) error {
+	variable, values, err := parseMatrix(opts.Matrix)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	consumer := formatter.NewLogConsumer(ctx, os.Stdout)
+	var mu sync.Mutex
+	exitCodes, err := runForEachValue(ctx, variable, values, opts.Parallel, func(ctx context.Context, env string) (int, error) {
+		return c.ComposeService().RunOneOffContainer(ctx, project, compose.RunOptions{
+			Service:     service,
+			Command:     command,
+			Environment: append(append([]string{}, opts.Env...), env),
+			AutoRemove:  !opts.Keep,
+			Timeout:     opts.Timeout,
+			Writer: matrixWriter{
+				prefix:   env,
+				consumer: consumer,
+				mu:       &mu,
+			},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for i, value := range values {
+		fmt.Printf("%s=%s exited with code %d\n", variable, value, exitCodes[i])
+		if exitCodes[i] != 0 && failed == 0 {
+			failed = exitCodes[i]
+		}
+	}
+	if failed != 0 {
+		return ExitCodeError{ExitCode: failed}
+	}
+	return nil
+}
+
+// parseMatrix parses a VAR=a,b,c matrix definition
+func parseMatrix(matrix string) (string, []string, error) {
+	kv := strings.SplitN(matrix, "=", 2)
+	if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+		return "", nil, errors.Errorf("invalid matrix %q, expected VAR=value1,value2", matrix)
+	}
+	values := strings.Split(kv[1], ",")
+	for _, v := range values {
+		if v == "" {
+			return "", nil, errors.Errorf("invalid matrix %q, values must not be empty", matrix)
+		}
+	}
+	return kv[0], values, nil
+}
+
+// runForEachValue invokes run once per value with the matching VAR=value environment entry, and collects
+// exit codes in values order
+func runForEachValue(ctx context.Context, variable string, values []string, parallel bool, run func(ctx context.Context, env string) (int, error)) ([]int, error) {
+	exitCodes := make([]int, len(values))
+	if !parallel {
+		for i, value := range values {
+			code, err := run(ctx, fmt.Sprintf("%s=%s", variable, value))
+			if err != nil {
+				return nil, err
+			}
+			exitCodes[i] = code
+		}
+		return exitCodes, nil
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	for i, value := range values {
+		i, value := i, value
+		eg.Go(func() error {
+			code, err := run(ctx, fmt.Sprintf("%s=%s", variable, value))
+			exitCodes[i] = code
+			return err
+		})
+	}
+	return exitCodes, eg.Wait()
+}
+
+// matrixWriter prefixes each line of output with the matrix value it was produced for
+type matrixWriter struct {
+	prefix   string
+	consumer compose.LogConsumer
+	mu       *sync.Mutex
+}
+
+func (w matrixWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+		w.consumer.Log(w.prefix, "", line)
+	}
+	return len(b), nil
+}
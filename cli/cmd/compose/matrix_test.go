@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseMatrix(t *testing.T) {
+	variable, values, err := parseMatrix("VAR=a,b,c")
+	assert.NilError(t, err)
+	assert.Equal(t, variable, "VAR")
+	assert.DeepEqual(t, values, []string{"a", "b", "c"})
+
+	_, _, err = parseMatrix("VAR")
+	assert.ErrorContains(t, err, "invalid matrix")
+	_, _, err = parseMatrix("VAR=")
+	assert.ErrorContains(t, err, "invalid matrix")
+	_, _, err = parseMatrix("VAR=a,,c")
+	assert.ErrorContains(t, err, "values must not be empty")
+}
+
+func TestRunForEachValue(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		var mu sync.Mutex
+		var envs []string
+		exitCodes, err := runForEachValue(context.Background(), "VAR", []string{"a", "b", "c"}, parallel, func(ctx context.Context, env string) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			envs = append(envs, env)
+			if env == "VAR=b" {
+				return 3, nil
+			}
+			return 0, nil
+		})
+		assert.NilError(t, err)
+		sort.Strings(envs)
+		assert.DeepEqual(t, envs, []string{"VAR=a", "VAR=b", "VAR=c"})
+		assert.DeepEqual(t, exitCodes, []int{0, 3, 0})
+	}
+}
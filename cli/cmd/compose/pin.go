@@ -0,0 +1,230 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/docker/compose-cli/api/client"
+)
+
+type pinOptions struct {
+	composeOptions
+	output string
+	dryRun bool
+}
+
+func imageDigestPinCommand() *cobra.Command {
+	opts := pinOptions{}
+	cmd := &cobra.Command{
+		Use:   "image-digest-pin",
+		Short: "Rewrite compose file with service images pinned to their current digest",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImageDigestPin(cmd.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration file")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Save to file (default to stdout)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Only display images that would be pinned")
+	return cmd
+}
+
+func runImageDigestPin(ctx context.Context, opts pinOptions) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	if len(project.ComposeFiles) != 1 || project.ComposeFiles[0] == "-" {
+		return errors.New("image-digest-pin requires a single compose file")
+	}
+
+	pinned, err := c.ComposeService().ResolveImageDigests(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		var services []string
+		for service := range pinned {
+			services = append(services, service)
+		}
+		sort.Strings(services)
+		for _, service := range services {
+			s, err := project.GetService(service)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: %s -> %s\n", service, s.Image, pinned[service])
+		}
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(project.ComposeFiles[0])
+	if err != nil {
+		return err
+	}
+	content, err = pinImages(content, pinned)
+	if err != nil {
+		return err
+	}
+	if opts.output == "" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+	return ioutil.WriteFile(opts.output, content, 0644)
+}
+
+// pinImages replaces image of services in compose file content by the pinned reference. Only the `image` values are
+// edited in place, so that the rest of the file keeps its formatting and comments
+func pinImages(content []byte, pinned map[string]string) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return content, nil
+	}
+	services := mappingValue(document.Content[0], "services")
+	if services == nil {
+		return content, nil
+	}
+	if services.Kind != yaml.MappingNode {
+		return nil, errors.New("invalid compose file: services must be a mapping")
+	}
+	type pin struct {
+		image *yaml.Node
+		ref   string
+	}
+	var pins []pin
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		ref, ok := pinned[services.Content[i].Value]
+		if !ok {
+			continue
+		}
+		image := mappingValue(services.Content[i+1], "image")
+		if image == nil || image.Kind != yaml.ScalarNode {
+			continue
+		}
+		pins = append(pins, pin{image: image, ref: ref})
+	}
+	// images are replaced from the end of file, so that offsets of the ones before are left unchanged
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].image.Line > pins[j].image.Line
+	})
+	for _, p := range pins {
+		start, end, err := scalarRange(content, p.image)
+		if err != nil {
+			return nil, err
+		}
+		var replaced []byte
+		replaced = append(replaced, content[:start]...)
+		replaced = append(replaced, quoteLike(p.image, p.ref)...)
+		replaced = append(replaced, content[end:]...)
+		content = replaced
+	}
+	return content, nil
+}
+
+// mappingValue returns the value of key in a mapping node, nil if node isn't a mapping or has no such key
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarRange locates the bytes of a single line scalar node in content, quotes included
+func scalarRange(content []byte, node *yaml.Node) (int, int, error) {
+	start := 0
+	for line := 1; line < node.Line; line++ {
+		next := bytes.IndexByte(content[start:], '\n')
+		if next < 0 {
+			return 0, 0, fmt.Errorf("line %d: image out of file", node.Line)
+		}
+		start += next + 1
+	}
+	// columns count characters
+	for column := 1; column < node.Column && start < len(content); column++ {
+		_, size := utf8.DecodeRune(content[start:])
+		start += size
+	}
+	lineEnd := bytes.IndexByte(content[start:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content) - start
+	}
+	text := content[start : start+lineEnd]
+	switch node.Style {
+	case 0, yaml.TaggedStyle:
+		if !bytes.HasPrefix(text, []byte(node.Value)) {
+			return 0, 0, fmt.Errorf("line %d: multi-line image isn't supported", node.Line)
+		}
+		return start, start + len(node.Value), nil
+	case yaml.SingleQuotedStyle, yaml.DoubleQuotedStyle:
+		quote := text[0]
+		for i := 1; i < len(text); i++ {
+			switch {
+			case quote == '"' && text[i] == '\\':
+				i++
+			case quote == '\'' && text[i] == '\'' && i+1 < len(text) && text[i+1] == '\'':
+				i++
+			case text[i] == quote:
+				return start, start + i + 1, nil
+			}
+		}
+		return 0, 0, fmt.Errorf("line %d: multi-line image isn't supported", node.Line)
+	default:
+		return 0, 0, fmt.Errorf("line %d: image must be a single line value to be pinned", node.Line)
+	}
+}
+
+// quoteLike quotes a reference as the node it replaces is, which references never need escaping for
+func quoteLike(node *yaml.Node, ref string) string {
+	switch node.Style {
+	case yaml.SingleQuotedStyle:
+		return "'" + ref + "'"
+	case yaml.DoubleQuotedStyle:
+		return `"` + ref + `"`
+	default:
+		return ref
+	}
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPinImages(t *testing.T) {
+	content := []byte(`# production stack
+services:
+  web:
+    image: nginx:1.19 # front
+    ports:
+      - "80:80"
+  app:
+    build: .
+    image: myapp:latest
+  db: {image: 'postgres:13', environment: [POSTGRES_PASSWORD=secret]}
+volumes:
+  data: {}
+`)
+	pinned, err := pinImages(content, map[string]string{
+		"web": "nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		"db":  "postgres@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, string(pinned), `# production stack
+services:
+  web:
+    image: nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000 # front
+    ports:
+      - "80:80"
+  app:
+    build: .
+    image: myapp:latest
+  db: {image: 'postgres@sha256:1111111111111111111111111111111111111111111111111111111111111111', environment: [POSTGRES_PASSWORD=secret]}
+volumes:
+  data: {}
+`)
+}
+
+func TestPinImagesMultiLine(t *testing.T) {
+	_, err := pinImages([]byte("services:\n  web:\n    image: >\n      nginx:1.19\n"), map[string]string{"web": "nginx@sha256:0"})
+	assert.ErrorContains(t, err, "line 3: image must be a single line value to be pinned")
+}
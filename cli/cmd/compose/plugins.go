@@ -0,0 +1,215 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/config"
+	apicontext "github.com/docker/compose-cli/context"
+)
+
+const (
+	// pluginPrefix is the executable name prefix of compose plugins, `docker-compose-<name>` running as
+	// `docker compose <name>`
+	pluginPrefix = "docker-compose-"
+	// pluginsDir is the directory, in docker config directory, compose plugins are looked up in first
+	pluginsDir = "compose-plugins"
+	// pluginAnnotation is set on plugin commands with the path of the plugin executable
+	pluginAnnotation = "com.docker.compose.plugin"
+)
+
+// AddPluginCommands adds a subcommand to compose command for each plugin found in the compose plugins directory of
+// configDir, in the directories set by cliPluginsExtraDirs in docker config, then in PATH. Built-in commands win
+// over plugins of the same name
+func AddPluginCommands(command *cobra.Command, configDir string) {
+	dirs := []string{filepath.Join(configDir, pluginsDir)}
+	conf, err := config.LoadFile(configDir)
+	if err != nil {
+		logrus.Warnf("Can't look up compose plugins in extra directories: %v", err)
+	} else {
+		dirs = append(dirs, conf.CLIPluginsExtraDirs...)
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+
+	for _, plugin := range findPlugins(dirs) {
+		if c, _, err := command.Find([]string{plugin.name}); err == nil && c != command {
+			logrus.Debugf("Ignoring plugin %s, conflicting with compose %s command", plugin.path, plugin.name)
+			continue
+		}
+		command.AddCommand(pluginCommand(plugin))
+	}
+}
+
+type plugin struct {
+	name string
+	path string
+}
+
+// findPlugins lists the plugin executables found in dirs, the first directory a plugin is found in masking the next
+// ones
+func findPlugins(dirs []string) []plugin {
+	var plugins []plugin
+	found := map[string]bool{}
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			name := f.Name()
+			if runtime.GOOS == "windows" {
+				if !strings.EqualFold(filepath.Ext(name), ".exe") {
+					continue
+				}
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if !strings.HasPrefix(name, pluginPrefix) || len(name) == len(pluginPrefix) || found[name] {
+				continue
+			}
+			path := filepath.Join(dir, f.Name())
+			if info, err := os.Stat(path); err != nil || info.IsDir() || (runtime.GOOS != "windows" && info.Mode()&0111 == 0) {
+				continue
+			}
+			found[name] = true
+			plugins = append(plugins, plugin{name: strings.TrimPrefix(name, pluginPrefix), path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].name < plugins[j].name
+	})
+	return plugins
+}
+
+func pluginCommand(p plugin) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                p.name,
+		Short:              fmt.Sprintf("Run plugin %s", p.path),
+		DisableFlagParsing: true,
+		Annotations:        map[string]string{pluginAnnotation: p.path},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlugin(cmd.Context(), p.path, args)
+		},
+	}
+	// flags are parsed by splitPluginArgs, declaring them documents them and lets v1 invocations pass them
+	cmd.Flags().StringArrayP("file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().StringP("project-name", "p", "", "Project name")
+	cmd.Flags().String("workdir", "", "Work dir")
+	cmd.Flags().String("env-file", "", "Specify an alternate environment file")
+	return cmd
+}
+
+// runPlugin runs a plugin with the resolved project as JSON on its stdin, and the current docker context and compose
+// project name in its environment. Project flags set before plugin own arguments select the project, like for
+// built-in commands
+func runPlugin(ctx context.Context, path string, args []string) error {
+	opts, args, err := splitPluginArgs(args)
+	if err != nil {
+		return err
+	}
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(project)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"DOCKER_CONTEXT="+apicontext.CurrentContext(ctx),
+		"COMPOSE_PROJECT_NAME="+project.Name,
+	)
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return ExitCodeError{ExitCode: exitErr.ExitCode()}
+	}
+	return err
+}
+
+// splitPluginArgs extracts the leading project flags from plugin arguments, the other ones being passed to plugin
+func splitPluginArgs(args []string) (composeOptions, []string, error) {
+	var opts composeOptions
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := args[i], "", false
+		if idx := strings.Index(name, "="); strings.HasPrefix(name, "--") && idx > 0 {
+			name, value, hasValue = name[:idx], name[idx+1:], true
+		}
+		var target *string
+		switch name {
+		case "-f", "--file":
+			opts.ConfigPaths = append(opts.ConfigPaths, "")
+			target = &opts.ConfigPaths[len(opts.ConfigPaths)-1]
+		case "-p", "--project-name":
+			target = &opts.Name
+		case "--workdir":
+			target = &opts.WorkingDir
+		case "--env-file":
+			target = &opts.EnvFile
+		case "--":
+			return opts, args[i+1:], nil
+		default:
+			return opts, args[i:], nil
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return opts, nil, fmt.Errorf("flag needs an argument: %s", name)
+			}
+			i++
+			value = args[i]
+		}
+		*target = value
+	}
+	return opts, nil, nil
+}
+
+// unknownCommandError reports a compose subcommand neither built-in nor provided by a plugin, listing available plugins
+func unknownCommandError(command *cobra.Command, name string) error {
+	var plugins []string
+	for _, c := range command.Commands() {
+		if path, ok := c.Annotations[pluginAnnotation]; ok {
+			plugins = append(plugins, fmt.Sprintf("  %s\t%s", c.Name(), path))
+		}
+	}
+	if len(plugins) == 0 {
+		return fmt.Errorf("unknown command %q for \"docker compose\", and no %s%s plugin found", name, pluginPrefix, name)
+	}
+	return fmt.Errorf("unknown command %q for \"docker compose\", available plugins:\n%s", name, strings.Join(plugins, "\n"))
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+)
+
+func writePlugin(t *testing.T, dir, name string, mode uint32) string {
+	path := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(path, []byte("#!/bin/sh\n"), 0644))
+	assert.NilError(t, os.Chmod(path, os.FileMode(mode)))
+	return path
+}
+
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are looked up by .exe extension on windows")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	notes := writePlugin(t, first, "docker-compose-deploy-notes", 0755)
+	writePlugin(t, second, "docker-compose-deploy-notes", 0755)
+	lint := writePlugin(t, second, "docker-compose-lint", 0755)
+	writePlugin(t, second, "docker-compose-not-executable", 0644)
+	writePlugin(t, second, "docker-compose-", 0755)
+	writePlugin(t, second, "docker-buildx", 0755)
+
+	plugins := findPlugins([]string{first, filepath.Join(first, "missing"), second})
+	assert.DeepEqual(t, plugins, []plugin{
+		{name: "deploy-notes", path: notes},
+		{name: "lint", path: lint},
+	}, cmp.AllowUnexported(plugin{}))
+}
+
+func TestAddPluginCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are looked up by .exe extension on windows")
+	}
+	configDir := t.TempDir()
+	pluginDir := filepath.Join(configDir, pluginsDir)
+	extraDir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(pluginDir, 0755))
+	writePlugin(t, pluginDir, "docker-compose-deploy-notes", 0755)
+	writePlugin(t, pluginDir, "docker-compose-up", 0755)
+	writePlugin(t, extraDir, "docker-compose-lint", 0755)
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"cliPluginsExtraDirs": ["`+extraDir+`"]}`), 0644))
+
+	command := &cobra.Command{Use: "compose"}
+	command.AddCommand(&cobra.Command{Use: "up", Run: func(*cobra.Command, []string) {}})
+	AddPluginCommands(command, configDir)
+
+	var names []string
+	for _, c := range command.Commands() {
+		names = append(names, c.Name())
+	}
+	assert.DeepEqual(t, names, []string{"deploy-notes", "lint", "up"})
+	up, _, err := command.Find([]string{"up"})
+	assert.NilError(t, err)
+	assert.Equal(t, up.Annotations[pluginAnnotation], "", "built-in command wins over plugin")
+
+	assert.Error(t, unknownCommandError(command, "deploy"), "unknown command \"deploy\" for \"docker compose\", available plugins:\n"+
+		"  deploy-notes\t"+filepath.Join(pluginDir, "docker-compose-deploy-notes")+"\n"+
+		"  lint\t"+filepath.Join(extraDir, "docker-compose-lint"))
+	assert.Error(t, unknownCommandError(&cobra.Command{Use: "compose"}, "deploy"),
+		`unknown command "deploy" for "docker compose", and no docker-compose-deploy plugin found`)
+}
+
+func TestSplitPluginArgs(t *testing.T) {
+	opts, args, err := splitPluginArgs([]string{"-f", "a.yml", "--file=b.yml", "-p", "demo", "--workdir", "/src", "--env-file", "prod.env", "--since", "v1", "-f", "c"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, opts.ConfigPaths, []string{"a.yml", "b.yml"})
+	assert.Equal(t, opts.Name, "demo")
+	assert.Equal(t, opts.WorkingDir, "/src")
+	assert.Equal(t, opts.EnvFile, "prod.env")
+	assert.DeepEqual(t, args, []string{"--since", "v1", "-f", "c"})
+
+	opts, args, err = splitPluginArgs([]string{"--project-name=demo", "--", "-p", "other"})
+	assert.NilError(t, err)
+	assert.Equal(t, opts.Name, "demo")
+	assert.DeepEqual(t, args, []string{"-p", "other"})
+
+	_, args, err = splitPluginArgs(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(args), 0)
+
+	_, _, err = splitPluginArgs([]string{"--workdir"})
+	assert.Error(t, err, "flag needs an argument: --workdir")
+}
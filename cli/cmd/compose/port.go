@@ -0,0 +1,215 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/formatter"
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+type portOptions struct {
+	composeOptions
+	protocol string
+	index    int
+}
+
+func portCommand() *cobra.Command {
+	opts := portOptions{}
+	cmd := &cobra.Command{
+		Use:   "port [options] SERVICE PRIVATE_PORT",
+		Short: "Print the public address and port for a service container port",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			port, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid private port %q", args[1])
+			}
+			return runPort(cmd.Context(), opts, args[0], port)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().StringVar(&opts.protocol, "protocol", "tcp", "tcp or udp")
+	cmd.Flags().IntVar(&opts.index, "index", 1, "Index of the container if the service is scaled")
+	return cmd
+}
+
+func runPort(ctx context.Context, opts portOptions, service string, port int) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	containers, err := c.ComposeService().Ps(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	name := compose.ResourceName(projectName, service, strconv.Itoa(opts.index))
+	address, err := publishedAddress(containers, name, port, opts.protocol)
+	if err != nil {
+		return err
+	}
+	fmt.Println(address)
+	return nil
+}
+
+// publishedAddress returns the host address container publishes its private port on
+func publishedAddress(containers []compose.ContainerSummary, name string, port int, protocol string) (string, error) {
+	for _, container := range containers {
+		if container.Name != name {
+			continue
+		}
+		for _, p := range container.Publishers {
+			if p.TargetPort == port && p.Protocol == protocol && p.PublishedPort != 0 {
+				return p.URL, nil
+			}
+		}
+		return "", fmt.Errorf("container %s doesn't publish port %d/%s", name, port, protocol)
+	}
+	return "", fmt.Errorf("no running container %s", name)
+}
+
+// printPublishedPorts prints the host ports containers publish their ports on, nothing if no port is published
+func printPublishedPorts(w io.Writer, containers []compose.ContainerSummary) error {
+	type published struct {
+		service   string
+		container string
+		port      string
+		address   string
+	}
+	var ports []published
+	for _, container := range containers {
+		for _, p := range container.Publishers {
+			if p.PublishedPort == 0 {
+				continue
+			}
+			ports = append(ports, published{
+				service:   container.Service,
+				container: container.Name,
+				port:      fmt.Sprintf("%d/%s", p.TargetPort, p.Protocol),
+				address:   p.URL,
+			})
+		}
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+	sort.SliceStable(ports, func(i, j int) bool {
+		if ports[i].container != ports[j].container {
+			return ports[i].container < ports[j].container
+		}
+		return ports[i].port < ports[j].port
+	})
+	return formatter.PrintPrettySection(w, func(w io.Writer) {
+		for _, p := range ports {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.service, p.container, p.port, p.address)
+		}
+	}, "SERVICE", "CONTAINER", "PORT", "PUBLISHED")
+}
+
+// alphaPortCommand lists the ports services declare, with the metadata service discovery tooling relies on
+func alphaPortCommand() *cobra.Command {
+	opts := composeOptions{}
+	cmd := &cobra.Command{
+		Use:   "port [SERVICE...]",
+		Short: "List the ports services declare, with their name and application protocol",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAlphaPort(opts, args, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Format the output. Values: [pretty | json]. (Default: pretty)")
+	return cmd
+}
+
+func runAlphaPort(opts composeOptions, services []string, w io.Writer) error {
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	err = filter(project, services)
+	if err != nil {
+		return err
+	}
+
+	view := viewFromServicePorts(project.Services)
+	return formatter.Print(view, opts.Format, w, func(w io.Writer) {
+		for _, p := range view {
+			published := ""
+			if p.Published != 0 {
+				published = strconv.Itoa(int(p.Published))
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d/%s\t%s\t%s\n", p.Service, p.Name, p.Target, p.Protocol, published, p.AppProtocol)
+		}
+	}, "SERVICE", "NAME", "PORT", "PUBLISHED", "APP PROTOCOL")
+}
+
+type portView struct {
+	Service     string
+	Name        string
+	Target      uint32
+	Published   uint32
+	Protocol    string
+	AppProtocol string
+}
+
+func viewFromServicePorts(services types.Services) []portView {
+	var view []portView
+	for _, service := range services {
+		for _, port := range service.Ports {
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = "tcp"
+			}
+			view = append(view, portView{
+				Service:     service.Name,
+				Name:        local_compose.PortName(port),
+				Target:      port.Target,
+				Published:   port.Published,
+				Protocol:    protocol,
+				AppProtocol: local_compose.PortAppProtocol(port),
+			})
+		}
+	}
+	sort.SliceStable(view, func(i, j int) bool {
+		return view[i].Service < view[j].Service
+	})
+	return view
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/formatter"
+)
+
+var publishingContainers = []compose.ContainerSummary{
+	{
+		Name:    "myproject_web_1",
+		Service: "web",
+		Publishers: []compose.PortPublisher{
+			{URL: "0.0.0.0:49153", TargetPort: 80, PublishedPort: 49153, Protocol: "tcp"},
+			{TargetPort: 443, Protocol: "tcp"},
+		},
+	},
+	{
+		Name:    "myproject_db_1",
+		Service: "db",
+		Publishers: []compose.PortPublisher{
+			{URL: "127.0.0.1:5432", TargetPort: 5432, PublishedPort: 5432, Protocol: "tcp"},
+		},
+	},
+	{
+		Name:    "myproject_worker_1",
+		Service: "worker",
+	},
+}
+
+func TestPublishedAddress(t *testing.T) {
+	address, err := publishedAddress(publishingContainers, "myproject_web_1", 80, "tcp")
+	assert.NilError(t, err)
+	assert.Equal(t, address, "0.0.0.0:49153")
+
+	_, err = publishedAddress(publishingContainers, "myproject_web_1", 443, "tcp")
+	assert.Error(t, err, "container myproject_web_1 doesn't publish port 443/tcp")
+
+	_, err = publishedAddress(publishingContainers, "myproject_web_2", 80, "tcp")
+	assert.Error(t, err, "no running container myproject_web_2")
+}
+
+func TestPrintPublishedPorts(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NilError(t, printPublishedPorts(out, publishingContainers))
+	assert.Equal(t, out.String(), `SERVICE             CONTAINER           PORT                PUBLISHED
+db                  myproject_db_1      5432/tcp            127.0.0.1:5432
+web                 myproject_web_1     80/tcp              0.0.0.0:49153
+`)
+
+	out.Reset()
+	assert.NilError(t, printPublishedPorts(out, publishingContainers[2:]))
+	assert.Equal(t, out.String(), "")
+}
+
+func TestViewFromServicePorts(t *testing.T) {
+	services := types.Services{
+		{
+			Name: "web",
+			Ports: []types.ServicePortConfig{
+				{
+					Target:     80,
+					Published:  8080,
+					Protocol:   "tcp",
+					Extensions: map[string]interface{}{"x-name": "http", "x-app_protocol": "http"},
+				},
+			},
+		},
+		{
+			Name:  "db",
+			Ports: []types.ServicePortConfig{{Target: 5432}},
+		},
+	}
+	view := viewFromServicePorts(services)
+	assert.DeepEqual(t, view, []portView{
+		{Service: "db", Target: 5432, Protocol: "tcp"},
+		{Service: "web", Name: "http", Target: 80, Published: 8080, Protocol: "tcp", AppProtocol: "http"},
+	})
+
+	var out bytes.Buffer
+	assert.NilError(t, formatter.Print(view, formatter.JSON, &out, nil))
+	assert.Assert(t, strings.Contains(out.String(), `"AppProtocol":"http"`), out.String())
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+const profilesComposeFile = `
+services:
+  web:
+    image: nginx
+  debug:
+    image: busybox
+    profiles: [debug]
+  adminer:
+    image: adminer
+    profiles: [tools]
+`
+
+func TestProfiles(t *testing.T) {
+	dir := fs.NewDir(t, "profiles", fs.WithFile("docker-compose.yml", profilesComposeFile))
+	defer dir.Remove()
+
+	load := func(opts composeOptions) (*types.Project, error) {
+		opts.Name = "profiles"
+		opts.ConfigPaths = []string{dir.Join("docker-compose.yml")}
+		options, err := opts.toProjectOptions()
+		assert.NilError(t, err)
+		return projectFromOptions(options)
+	}
+
+	project, err := load(composeOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, serviceNames(project), []string{"web"})
+	assert.DeepEqual(t, local_compose.ProjectProfiles(project), []string{"debug", "tools"})
+
+	project, err = load(composeOptions{Profiles: []string{"debug"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, serviceNames(project), []string{"debug", "web"})
+
+	project, err = load(composeOptions{Environment: []string{"COMPOSE_PROFILES=debug, tools"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, serviceNames(project), []string{"adminer", "debug", "web"})
+
+	project, err = load(composeOptions{Environment: []string{"COMPOSE_PROFILES=debug"}, Profiles: []string{"tools"}})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, serviceNames(project), []string{"adminer", "web"})
+
+	_, err = load(composeOptions{Profiles: []string{"bogus"}})
+	assert.Error(t, err, `no such profile "bogus", valid profiles are: debug, tools`)
+}
+
+func TestFilterEnablesTargetedService(t *testing.T) {
+	dir := fs.NewDir(t, "profiles", fs.WithFile("docker-compose.yml", profilesComposeFile))
+	defer dir.Remove()
+
+	opts := composeOptions{Name: "targeted", ConfigPaths: []string{dir.Join("docker-compose.yml")}}
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err := projectFromOptions(options)
+	assert.NilError(t, err)
+
+	assert.NilError(t, filter(project, []string{"adminer"}))
+	assert.DeepEqual(t, serviceNames(project), []string{"adminer"})
+}
+
+func serviceNames(project *types.Project) []string {
+	names := project.ServiceNames()
+	sort.Strings(names)
+	return names
+}
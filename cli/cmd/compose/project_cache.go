@@ -0,0 +1,116 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/compose-spec/compose-go/types"
+)
+
+// loadedProjects keeps the projects this CLI invocation loaded, so compose files are parsed and interpolated once, and
+// all phases of a command, e.g. build then convergence for `up`, get the same snapshot of the model even if files
+// change meanwhile
+var loadedProjects = projectCache{projects: map[string]*types.Project{}}
+
+type projectCache struct {
+	mu       sync.Mutex
+	projects map[string]*types.Project
+}
+
+// projectCacheKey identifies a loaded project by the options and environment it has been loaded with
+type projectCacheKey struct {
+	Options       *cli.ProjectOptions
+	Compatibility bool
+}
+
+// load returns a copy of the project already loaded for the same options, or loads it. Callers get their own copy, as
+// commands and backends amend the model they're given
+func (c *projectCache) load(options *cli.ProjectOptions, load func() (*types.Project, error)) (*types.Project, error) {
+	key, err := json.Marshal(projectCacheKey{Options: options, Compatibility: compatibilityMode()})
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if project, ok := c.projects[string(key)]; ok {
+		return copyProject(project), nil
+	}
+	project, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.projects[string(key)] = project
+	return copyProject(project), nil
+}
+
+func copyProject(project *types.Project) *types.Project {
+	return deepCopy(reflect.ValueOf(project)).Interface().(*types.Project)
+}
+
+// deepCopy copies a value of the compose model, along with the pointers, slices, maps and interfaces it holds
+func deepCopy(in reflect.Value) reflect.Value {
+	switch in.Kind() {
+	case reflect.Ptr:
+		if in.IsNil() {
+			return in
+		}
+		out := reflect.New(in.Type().Elem())
+		out.Elem().Set(deepCopy(in.Elem()))
+		return out
+	case reflect.Interface:
+		if in.IsNil() {
+			return in
+		}
+		out := reflect.New(in.Type()).Elem()
+		out.Set(deepCopy(in.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(in.Type()).Elem()
+		out.Set(in)
+		for i := 0; i < in.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(deepCopy(in.Field(i)))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if in.IsNil() {
+			return in
+		}
+		out := reflect.MakeSlice(in.Type(), in.Len(), in.Len())
+		for i := 0; i < in.Len(); i++ {
+			out.Index(i).Set(deepCopy(in.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if in.IsNil() {
+			return in
+		}
+		out := reflect.MakeMapWithSize(in.Type(), in.Len())
+		iter := in.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return out
+	default:
+		return in
+	}
+}
@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProjectLoadedOnce(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "docker-compose.yml")
+	assert.NilError(t, ioutil.WriteFile(file, []byte("services:\n  web:\n    image: nginx:1.19\n"), 0600))
+	opts := composeOptions{WorkingDir: dir, ConfigPaths: []string{file}}
+
+	options, err := opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err := projectFromOptions(options)
+	assert.NilError(t, err)
+
+	// compose file changes while the command runs, e.g. between build and convergence
+	assert.NilError(t, ioutil.WriteFile(file, []byte("services:\n  web:\n    image: nginx:1.20\n"), 0600))
+
+	options, err = opts.toProjectOptions()
+	assert.NilError(t, err)
+	snapshot, err := projectFromOptions(options)
+	assert.NilError(t, err)
+	assert.Equal(t, snapshot.Services[0].Image, "nginx:1.19")
+
+	// commands amending their project don't alter the cached one
+	snapshot.Services[0].Image = "httpd"
+	snapshot.Services[0].Environment["DEBUG"] = nil
+	options, err = opts.toProjectOptions()
+	assert.NilError(t, err)
+	project, err = projectFromOptions(options)
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services[0].Image, "nginx:1.19")
+	_, ok := project.Services[0].Environment["DEBUG"]
+	assert.Assert(t, !ok)
+
+	name, err := opts.toProjectName()
+	assert.NilError(t, err)
+	assert.Equal(t, name, project.Name)
+
+	// another set of options is another project
+	other := composeOptions{Name: "other", WorkingDir: dir, ConfigPaths: []string{file}}
+	options, err = other.toProjectOptions()
+	assert.NilError(t, err)
+	reloaded, err := projectFromOptions(options)
+	assert.NilError(t, err)
+	assert.Equal(t, reloaded.Services[0].Image, "nginx:1.20")
+
+	// so is the same project loaded in compatibility mode
+	defer os.Unsetenv(compatibilityEnv) //nolint:errcheck
+	assert.NilError(t, os.Setenv(compatibilityEnv, "true"))
+	options, err = opts.toProjectOptions()
+	assert.NilError(t, err)
+	reloaded, err = projectFromOptions(options)
+	assert.NilError(t, err)
+	assert.Equal(t, reloaded.Services[0].Image, "nginx:1.20")
+}
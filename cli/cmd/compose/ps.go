@@ -22,16 +22,30 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/formatter"
+	local_compose "github.com/docker/compose-cli/local/compose"
 )
 
+const noTruncHelp = "Don't truncate output, also listing full container IDs and commands"
+
+type psOptions struct {
+	composeOptions
+	Orphans bool
+	Stats   bool
+	Tree    bool
+	NoTrunc bool
+}
+
 func psCommand() *cobra.Command {
-	opts := composeOptions{}
+	opts := psOptions{}
 	psCmd := &cobra.Command{
 		Use:   "ps",
 		Short: "List containers",
@@ -41,15 +55,45 @@ func psCommand() *cobra.Command {
 	}
 	psCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	psCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
-	addComposeCommonFlags(psCmd.Flags(), &opts)
+	psCmd.Flags().StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
+	psCmd.Flags().StringVar(&opts.EnvFile, "env-file", "", "Specify an alternate environment file")
+	psCmd.Flags().BoolVar(&opts.Orphans, "orphans", false, "List containers which diverged from compose file, and what up would do about them")
+	psCmd.Flags().BoolVar(&opts.Stats, "stats", false, "Show CPU and memory usage, sampled once per running container")
+	psCmd.Flags().BoolVar(&opts.NoTrunc, "no-trunc", false, noTruncHelp)
+	addComposeCommonFlags(psCmd.Flags(), &opts.composeOptions)
 	return psCmd
 }
 
-func runPs(ctx context.Context, opts composeOptions) error {
+func alphaPsCommand() *cobra.Command {
+	opts := psOptions{}
+	psCmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List containers, optionally arranged as the services dependency tree",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPs(cmd.Context(), opts)
+		},
+	}
+	psCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	psCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	psCmd.Flags().StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
+	psCmd.Flags().StringVar(&opts.EnvFile, "env-file", "", "Specify an alternate environment file")
+	psCmd.Flags().BoolVar(&opts.Tree, "tree", false, "Indent services under the services they depend on, with their state and health")
+	psCmd.Flags().BoolVar(&opts.NoTrunc, "no-trunc", false, noTruncHelp)
+	addComposeCommonFlags(psCmd.Flags(), &opts.composeOptions)
+	return psCmd
+}
+
+func runPs(ctx context.Context, opts psOptions) error {
 	c, err := client.NewWithDefaultLocalBackend(ctx)
 	if err != nil {
 		return err
 	}
+	if err := opts.withRecordedProject(ctx, c.ComposeService()); err != nil {
+		return err
+	}
+	if opts.Orphans {
+		return runPsOrphans(ctx, c, opts)
+	}
 
 	projectName, err := opts.toProjectName()
 	if err != nil {
@@ -69,8 +113,35 @@ func runPs(ctx context.Context, opts composeOptions) error {
 	sort.Slice(containers, func(i, j int) bool {
 		return containers[i].Name < containers[j].Name
 	})
+	if opts.Stats {
+		stats, err := c.ComposeService().Stats(ctx, projectName)
+		if err != nil {
+			return err
+		}
+		return printPsStats(os.Stdout, opts.Format, withStats(containers, stats))
+	}
+	if opts.Tree {
+		options, err := opts.toProjectOptions()
+		if err != nil {
+			return err
+		}
+		project, err := projectFromOptions(options)
+		if err != nil {
+			return err
+		}
+		return printPsTree(os.Stdout, local_compose.NewGraph(project.Services, local_compose.ServiceStopped), containers)
+	}
+
+	return printPs(os.Stdout, opts.Format, containers, opts.NoTrunc)
+}
 
-	return formatter.Print(containers, opts.Format, os.Stdout,
+// printPs lists containers. Untruncated output also lists full container IDs and commands, as scripts may need them
+func printPs(out io.Writer, format string, containers []compose.ContainerSummary, noTrunc bool) error {
+	headers := []string{"NAME", "SERVICE", "STATE", "PORTS"}
+	if noTrunc {
+		headers = []string{"CONTAINER ID", "NAME", "SERVICE", "COMMAND", "STATE", "PORTS"}
+	}
+	return formatter.Print(containers, format, out,
 		func(w io.Writer) {
 			for _, container := range containers {
 				var ports []string
@@ -81,8 +152,135 @@ func runPs(ctx context.Context, opts composeOptions) error {
 						ports = append(ports, fmt.Sprintf("%s->%d/%s", p.URL, p.TargetPort, p.Protocol))
 					}
 				}
+				if noTrunc {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", container.ID, container.Name, container.Service,
+						strconv.Quote(container.Command), container.State, strings.Join(ports, ", "))
+					continue
+				}
 				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", container.Name, container.Service, container.State, strings.Join(ports, ", "))
 			}
 		},
-		"NAME", "SERVICE", "STATE", "PORTS")
+		headers...)
+}
+
+// printPsTree prints services starting from the ones without dependency, each indented under the services it depends
+// on, so a service depending on several others is listed under each of them
+func printPsTree(out io.Writer, graph *local_compose.Graph, containers []compose.ContainerSummary) error {
+	states := map[string][]string{}
+	health := map[string][]string{}
+	for _, c := range containers {
+		states[c.Service] = append(states[c.Service], c.State)
+		if c.Health != "" {
+			health[c.Service] = append(health[c.Service], c.Health)
+		}
+	}
+
+	printed := map[string]bool{}
+	var printService func(w io.Writer, service string, depth int, path map[string]bool)
+	printService = func(w io.Writer, service string, depth int, path map[string]bool) {
+		name := strings.Repeat("  ", depth) + service
+		if path[service] {
+			name += " (cycle)"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", name, joinOrDash(states[service]), joinOrDash(health[service]))
+		if path[service] {
+			return
+		}
+		printed[service] = true
+		path[service] = true
+		for _, dependent := range local_compose.SortedVertexKeys(graph.Vertices[service].Parents) {
+			printService(w, dependent, depth+1, path)
+		}
+		delete(path, service)
+	}
+
+	return formatter.PrintPrettySection(out, func(w io.Writer) {
+		var leaves []string
+		for _, v := range graph.Leaves() {
+			leaves = append(leaves, v.Key)
+		}
+		sort.Strings(leaves)
+		for _, service := range leaves {
+			printService(w, service, 0, map[string]bool{})
+		}
+		// services only reachable through a cycle have no leaf to start from
+		for _, service := range local_compose.SortedVertexKeys(graph.Vertices) {
+			if !printed[service] {
+				printService(w, service, 0, map[string]bool{})
+			}
+		}
+	}, "SERVICE", "STATE", "HEALTH")
+}
+
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
+
+// containerWithStats adds resources usage to a container summary, if it could be sampled
+type containerWithStats struct {
+	compose.ContainerSummary
+	*compose.ContainerStats
+}
+
+func withStats(containers []compose.ContainerSummary, stats map[string]compose.ContainerStats) []containerWithStats {
+	var entries []containerWithStats
+	for _, container := range containers {
+		entry := containerWithStats{ContainerSummary: container}
+		if s, ok := stats[container.ID]; ok {
+			entry.ContainerStats = &s
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func printPsStats(out io.Writer, format string, entries []containerWithStats) error {
+	return formatter.Print(entries, format, out,
+		func(w io.Writer) {
+			for _, e := range entries {
+				cpu, memory := "-", "-"
+				if e.ContainerStats != nil {
+					cpu = fmt.Sprintf("%.2f%%", e.CPUPercent)
+					memory = fmt.Sprintf("%s / %s", units.BytesSize(float64(e.MemoryUsage)), units.BytesSize(float64(e.MemoryLimit)))
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Name, e.Service, e.State, cpu, memory)
+			}
+		},
+		"NAME", "SERVICE", "STATE", "CPU %", "MEM USAGE / LIMIT")
+}
+
+func runPsOrphans(ctx context.Context, c *client.Client, opts psOptions) error {
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	divergences, err := c.ComposeService().Divergences(ctx, project)
+	if err != nil {
+		return err
+	}
+	if opts.Quiet {
+		for _, d := range divergences {
+			fmt.Println(d.ID)
+		}
+		return nil
+	}
+
+	sort.Slice(divergences, func(i, j int) bool {
+		return divergences[i].Name < divergences[j].Name
+	})
+
+	return formatter.Print(divergences, opts.Format, os.Stdout,
+		func(w io.Writer) {
+			for _, d := range divergences {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Name, d.Service, d.State, d.Reason, d.Action)
+			}
+		},
+		"NAME", "SERVICE", "STATE", "REASON", "UP ACTION")
 }
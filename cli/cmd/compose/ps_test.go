@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+func TestPrintPsStats(t *testing.T) {
+	entries := withStats([]compose.ContainerSummary{
+		{ID: "123", Name: "myproject_db_1", Service: "db", State: "running"},
+		{ID: "456", Name: "myproject_web_1", Service: "web", State: "running"},
+	}, map[string]compose.ContainerStats{
+		"123": {CPUPercent: 12.5, MemoryUsage: 200 * 1024 * 1024, MemoryLimit: 1024 * 1024 * 1024},
+	})
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, printPsStats(out, "", entries))
+	assert.Equal(t, out.String(), `NAME                SERVICE             STATE               CPU %               MEM USAGE / LIMIT
+myproject_db_1      db                  running             12.50%              200MiB / 1GiB
+myproject_web_1     web                 running             -                   -
+`)
+
+	out.Reset()
+	assert.NilError(t, printPsStats(out, "json", entries))
+	assert.Equal(t, out.String(), `[{"ID":"123","Name":"myproject_db_1","Project":"","Service":"db","State":"running","Health":"","Publishers":null,"CPUPercent":12.5,"MemoryUsage":209715200,"MemoryLimit":1073741824},{"ID":"456","Name":"myproject_web_1","Project":"","Service":"web","State":"running","Health":"","Publishers":null}]
+`)
+}
+
+func TestPrintPsNoTrunc(t *testing.T) {
+	const id = "f0e3b9b8d1e44d8b9f2a6c4e1b7d3a5c9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b"
+	containers := []compose.ContainerSummary{
+		{ID: id, Name: "myproject_web_1", Service: "web", State: "running", Command: "nginx -g 'daemon off;'",
+			Publishers: []compose.PortPublisher{{URL: "0.0.0.0:80", TargetPort: 80, Protocol: "tcp"}, {TargetPort: 443, Protocol: "tcp"}}},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, printPs(out, "", containers, false))
+	assert.Equal(t, out.String(), `NAME                SERVICE             STATE               PORTS
+myproject_web_1     web                 running             0.0.0.0:80->80/tcp, 443/tcp
+`)
+
+	out.Reset()
+	assert.NilError(t, printPs(out, "", containers, true))
+	assert.Equal(t, out.String(), `CONTAINER ID                                                       NAME                SERVICE             COMMAND                    STATE               PORTS
+`+id+`   myproject_web_1     web                 "nginx -g 'daemon off;'"   running             0.0.0.0:80->80/tcp, 443/tcp
+`)
+
+	out.Reset()
+	assert.NilError(t, printPs(out, "json", containers, true))
+	assert.Assert(t, strings.Contains(out.String(), `"ID":"`+id+`"`), out.String())
+	assert.Assert(t, strings.Contains(out.String(), `"Command":"nginx -g 'daemon off;'"`), out.String())
+}
+
+func TestPrintPsTree(t *testing.T) {
+	services := types.Services{
+		{Name: "db"},
+		{Name: "cache"},
+		{Name: "api", DependsOn: types.DependsOnConfig{"db": {}, "cache": {}}},
+		{Name: "web", DependsOn: types.DependsOnConfig{"api": {}}},
+	}
+	containers := []compose.ContainerSummary{
+		{Name: "myproject_db_1", Service: "db", State: "running", Health: "healthy"},
+		{Name: "myproject_api_1", Service: "api", State: "running"},
+		{Name: "myproject_web_1", Service: "web", State: "exited"},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, printPsTree(out, local_compose.NewGraph(services, local_compose.ServiceStopped), containers))
+	assert.Equal(t, out.String(), `SERVICE             STATE               HEALTH
+cache               -                   -
+  api               running             -
+    web             exited              -
+db                  running             healthy
+  api               running             -
+    web             exited              -
+`)
+}
+
+func TestPrintPsTreeCycle(t *testing.T) {
+	services := types.Services{
+		{Name: "a", DependsOn: types.DependsOnConfig{"b": {}}},
+		{Name: "b", DependsOn: types.DependsOnConfig{"a": {}}},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, printPsTree(out, local_compose.NewGraph(services, local_compose.ServiceStopped), nil))
+	assert.Equal(t, out.String(), `SERVICE             STATE               HEALTH
+a                   -                   -
+  b                 -                   -
+    a (cycle)       -                   -
+`)
+}
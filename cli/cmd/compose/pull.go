@@ -18,16 +18,20 @@ package compose
 
 import (
 	"context"
+	"os"
 
-	"github.com/compose-spec/compose-go/cli"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/progress"
 )
 
 type pullOptions struct {
 	composeOptions
+	Verify          bool
+	Verbose         bool
+	IgnoreBuildable bool
 }
 
 func pullCommand() *cobra.Command {
@@ -42,6 +46,11 @@ func pullCommand() *cobra.Command {
 
 	pullCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	pullCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	pullCmd.Flags().StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
+	pullCmd.Flags().BoolVar(&opts.Verify, "verify-signatures", false, "Only pull signed images, same as setting DOCKER_CONTENT_TRUST=1.")
+
+	pullCmd.Flags().BoolVar(&opts.Verbose, "verbose", false, "Show progress for each image layer being pulled.")
+	pullCmd.Flags().BoolVar(&opts.IgnoreBuildable, "ignore-buildable", false, "Skip services declaring a build, their image gets built anyway")
 
 	return pullCmd
 }
@@ -52,12 +61,13 @@ func runPull(ctx context.Context, opts pullOptions, services []string) error {
 		return err
 	}
 
+	var summary compose.TransferSummary
 	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
 		options, err := opts.toProjectOptions()
 		if err != nil {
 			return "", err
 		}
-		project, err := cli.ProjectFromOptions(options)
+		project, err := projectFromOptions(options)
 		if err != nil {
 			return "", err
 		}
@@ -66,7 +76,13 @@ func runPull(ctx context.Context, opts pullOptions, services []string) error {
 		if err != nil {
 			return "", err
 		}
-		return "", c.ComposeService().Pull(ctx, project)
+		summary, err = c.ComposeService().Pull(ctx, project, compose.PullOptions{
+			VerifySignatures: verifySignatures(opts.Verify),
+			Verbose:          opts.Verbose,
+			IgnoreBuildable:  opts.IgnoreBuildable,
+		})
+		return "", err
 	})
+	printTransferSummary(os.Stdout, "Pulled", summary)
 	return err
 }
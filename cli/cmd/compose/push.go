@@ -18,16 +18,19 @@ package compose
 
 import (
 	"context"
+	"os"
 
-	"github.com/compose-spec/compose-go/cli"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/progress"
 )
 
 type pushOptions struct {
 	composeOptions
+	scanOptions
+	IgnoreFailures bool
 }
 
 func pushCommand() *cobra.Command {
@@ -42,31 +45,50 @@ func pushCommand() *cobra.Command {
 
 	pushCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
 	pushCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	pushCmd.Flags().StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
+	pushCmd.Flags().BoolVar(&opts.IgnoreFailures, "ignore-push-failures", false, "Push what it can, only reporting services whose image failed to push")
+	addScanFlags(pushCmd.Flags(), &opts.scanOptions)
 
 	return pushCmd
 }
 
 func runPush(ctx context.Context, opts pushOptions, services []string) error {
+	err := opts.scanOptions.validate()
+	if err != nil {
+		return err
+	}
 	c, err := client.NewWithDefaultLocalBackend(ctx)
 	if err != nil {
 		return err
 	}
 
-	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
-		options, err := opts.toProjectOptions()
-		if err != nil {
-			return "", err
-		}
-		project, err := cli.ProjectFromOptions(options)
-		if err != nil {
-			return "", err
-		}
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	err = filter(project, services)
+	if err != nil {
+		return err
+	}
 
-		err = filter(project, services)
+	if opts.check {
+		err = runImageChecks(ctx, project, newImageScanner(), opts.severityThreshold, os.Stdout)
 		if err != nil {
-			return "", err
+			return err
 		}
-		return "", c.ComposeService().Push(ctx, project)
+	}
+	var summary compose.TransferSummary
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		var err error
+		summary, err = c.ComposeService().Push(ctx, project, compose.PushOptions{
+			IgnoreFailures: opts.IgnoreFailures,
+		})
+		return "", err
 	})
+	printTransferSummary(os.Stdout, "Pushed", summary)
 	return err
 }
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// withRecordedProject defaults the options flags don't set to the ones project containers were created with, as down
+// does: the env file set by --env-file, and the compose files when only a project name is given. A recorded env file
+// which doesn't exist anymore is skipped with a warning
+func (o *composeOptions) withRecordedProject(ctx context.Context, service compose.Service) error {
+	withoutFiles := o.Name != "" && len(o.ConfigPaths) == 0 && o.WorkingDir == ""
+	if o.EnvFile != "" && !withoutFiles {
+		return nil
+	}
+	projectName, err := o.toProjectName()
+	if err != nil {
+		return err
+	}
+	stacks, err := service.List(ctx, projectName, compose.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+	for _, stack := range stacks {
+		if stack.Name != projectName {
+			continue
+		}
+		if withoutFiles && len(stack.ConfigFiles) > 0 && stack.ConfigFiles[0] != "-" {
+			o.WorkingDir = stack.WorkingDir
+			o.ConfigPaths = stack.ConfigFiles
+		}
+		if o.EnvFile != "" || stack.EnvFile == "" {
+			return nil
+		}
+		if _, err := os.Stat(stack.EnvFile); err != nil {
+			logrus.Warnf("env file %s used to create project %q doesn't exist anymore", stack.EnvFile, projectName)
+			return nil
+		}
+		o.EnvFile = stack.EnvFile
+		return nil
+	}
+	return nil
+}
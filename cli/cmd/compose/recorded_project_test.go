@@ -0,0 +1,68 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type stacksService struct {
+	compose.Service
+	stacks []compose.Stack
+}
+
+func (s stacksService) List(ctx context.Context, projectName string, options compose.ListOptions) ([]compose.Stack, error) {
+	return s.stacks, nil
+}
+
+func TestWithRecordedProject(t *testing.T) {
+	dir := fs.NewDir(t, "recorded-project",
+		fs.WithFile("docker-compose.yml", "services:\n  web:\n    image: nginx\n"),
+		fs.WithFile("prod.env", "TAG=1.0\n"))
+	defer dir.Remove()
+	service := stacksService{stacks: []compose.Stack{
+		{Name: "other", EnvFile: dir.Join("other.env")},
+		{
+			Name:        "demo",
+			WorkingDir:  dir.Path(),
+			ConfigFiles: []string{dir.Join("docker-compose.yml")},
+			EnvFile:     dir.Join("prod.env"),
+		},
+	}}
+
+	opts := composeOptions{Name: "demo"}
+	assert.NilError(t, opts.withRecordedProject(context.Background(), service))
+	assert.Equal(t, opts.WorkingDir, dir.Path())
+	assert.DeepEqual(t, opts.ConfigPaths, []string{dir.Join("docker-compose.yml")})
+	assert.Equal(t, opts.EnvFile, dir.Join("prod.env"))
+
+	opts = composeOptions{Name: "demo", EnvFile: "staging.env", ConfigPaths: []string{"local.yml"}}
+	assert.NilError(t, opts.withRecordedProject(context.Background(), service))
+	assert.DeepEqual(t, opts.ConfigPaths, []string{"local.yml"})
+	assert.Equal(t, opts.EnvFile, "staging.env")
+
+	opts = composeOptions{Name: "demo", ConfigPaths: []string{dir.Join("docker-compose.yml")}}
+	service.stacks[1].EnvFile = dir.Join("removed.env")
+	assert.NilError(t, opts.withRecordedProject(context.Background(), service))
+	assert.Equal(t, opts.EnvFile, "")
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+// divergenceRecreate is the ContainerDivergence action of containers `up` recreates
+const divergenceRecreate = "recreate"
+
+func recreateIfChangedCommand() *cobra.Command {
+	opts := composeOptions{}
+	cmd := &cobra.Command{
+		Use:   "recreate-if-changed",
+		Short: "Recreate services whose running containers don't match the compose file configuration anymore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRecreateIfChanged(cmd.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	return cmd
+}
+
+func runRecreateIfChanged(ctx context.Context, opts composeOptions) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+
+	divergences, err := c.ComposeService().Divergences(ctx, project)
+	if err != nil {
+		return err
+	}
+	services := printChanged(os.Stdout, divergences)
+	if len(services) == 0 {
+		return nil
+	}
+	err = filter(project, services)
+	if err != nil {
+		return err
+	}
+
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		return "", c.ComposeService().Create(ctx, project, compose.CreateOptions{
+			Recreate: compose.RecreateDiverged,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return c.ComposeService().Start(ctx, project, nil)
+}
+
+// printChanged prints containers whose configuration changed since they were created, and returns their services
+func printChanged(w io.Writer, divergences []compose.ContainerDivergence) []string {
+	sort.Slice(divergences, func(i, j int) bool {
+		return divergences[i].Name < divergences[j].Name
+	})
+	seen := map[string]bool{}
+	var services []string
+	for _, d := range divergences {
+		if d.Action != divergenceRecreate {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s: %s, recreating %s\n", d.Service, d.Reason, d.Name)
+		if !seen[d.Service] {
+			seen[d.Service] = true
+			services = append(services, d.Service)
+		}
+	}
+	if len(services) == 0 {
+		_, _ = fmt.Fprintln(w, "No service changed")
+	}
+	sort.Strings(services)
+	return services
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestPrintChanged(t *testing.T) {
+	divergences := []compose.ContainerDivergence{
+		{
+			ContainerSummary: compose.ContainerSummary{Name: "myproject_web_2", Service: "web"},
+			Reason:           "service configuration changed",
+			Action:           "recreate",
+		},
+		{
+			ContainerSummary: compose.ContainerSummary{Name: "myproject_db_1", Service: "db"},
+			Reason:           "image tag points to a newer image",
+			Action:           "none, use --force-recreate to recreate",
+		},
+		{
+			ContainerSummary: compose.ContainerSummary{Name: "myproject_web_1", Service: "web"},
+			Reason:           "service configuration changed",
+			Action:           "recreate",
+		},
+	}
+	var out bytes.Buffer
+	services := printChanged(&out, divergences)
+	assert.DeepEqual(t, services, []string{"web"})
+	assert.Equal(t, out.String(), `web: service configuration changed, recreating myproject_web_1
+web: service configuration changed, recreating myproject_web_2
+`)
+}
+
+func TestPrintChangedNoop(t *testing.T) {
+	divergences := []compose.ContainerDivergence{
+		{
+			ContainerSummary: compose.ContainerSummary{Name: "myproject_old_1", Service: "old"},
+			Reason:           "orphan, service is not defined in compose file",
+			Action:           "none, use down to remove it",
+		},
+	}
+	var out bytes.Buffer
+	services := printChanged(&out, divergences)
+	assert.Equal(t, len(services), 0)
+	assert.Equal(t, out.String(), "No service changed\n")
+
+	out.Reset()
+	assert.Equal(t, len(printChanged(&out, nil)), 0)
+	assert.Equal(t, out.String(), "No service changed\n")
+}
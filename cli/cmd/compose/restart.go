@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+type restartOptions struct {
+	composeOptions
+	NoDeps bool
+}
+
+func restartCommand() *cobra.Command {
+	opts := restartOptions{}
+	restartCmd := &cobra.Command{
+		Use:   "restart [SERVICE...]",
+		Short: "Restart services, services depending on them being restarted after",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestart(cmd.Context(), opts, args)
+		},
+	}
+	restartCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	restartCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	restartCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	restartCmd.Flags().BoolVar(&opts.NoDeps, "no-deps", false, "Only restart the selected services, not services depending on them")
+	return restartCmd
+}
+
+func runRestart(ctx context.Context, opts restartOptions, services []string) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		projectName, err := opts.toProjectName()
+		if err != nil {
+			return "", err
+		}
+		return "", c.ComposeService().Restart(ctx, projectName, compose.RestartOptions{
+			Services: services,
+			NoDeps:   opts.NoDeps,
+		})
+	})
+	return err
+}
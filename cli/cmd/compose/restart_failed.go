@@ -0,0 +1,90 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+type restartFailedOptions struct {
+	composeOptions
+	DryRun bool
+}
+
+func restartFailedCommand() *cobra.Command {
+	opts := restartFailedOptions{}
+	restartCmd := &cobra.Command{
+		Use:   "restart-failed",
+		Short: "Restart containers which exited with a non-zero code or are unhealthy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestartFailed(cmd.Context(), opts)
+		},
+	}
+	restartCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	restartCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	restartCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	restartCmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Only list containers which would be restarted")
+	return restartCmd
+}
+
+func runRestartFailed(ctx context.Context, opts restartFailedOptions) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+
+	var restarted []string
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		restarted, err = c.ComposeService().RestartFailed(ctx, projectName, compose.RestartFailedOptions{
+			DryRun: opts.DryRun,
+		})
+		return "", err
+	})
+	if err != nil {
+		return err
+	}
+	printRestarted(os.Stdout, restarted, opts.DryRun)
+	return nil
+}
+
+func printRestarted(w io.Writer, restarted []string, dryRun bool) {
+	if len(restarted) == 0 {
+		_, _ = fmt.Fprintln(w, "No failed container")
+		return
+	}
+	for _, name := range restarted {
+		if dryRun {
+			_, _ = fmt.Fprintf(w, "Would restart %s\n", name)
+		} else {
+			_, _ = fmt.Fprintf(w, "Restarted %s\n", name)
+		}
+	}
+}
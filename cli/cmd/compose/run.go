@@ -0,0 +1,137 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	local_compose "github.com/docker/compose-cli/local/compose"
+)
+
+type runOptions struct {
+	composeOptions
+	Env           []string
+	EnvFromFiles  []string
+	User          string
+	WorkDir       string
+	Keep          bool
+	KeepVolumes   bool
+	Timeout       time.Duration
+	QuietPull     bool
+	RemoveOrphans bool
+}
+
+// ExitCodeError reports a command completed with a non-zero exit code, the CLI should exit with
+type ExitCodeError struct {
+	ExitCode int
+}
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.ExitCode)
+}
+
+func runCommand() *cobra.Command {
+	opts := runOptions{}
+	runCmd := &cobra.Command{
+		Use:   "run [options] SERVICE [COMMAND] [ARGS...]",
+		Short: "Run a one-off command on a service",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRun(cmd.Context(), opts, args[0], args[1:])
+		},
+	}
+	runCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	runCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	runCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	runCmd.Flags().StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
+	runCmd.Flags().StringArrayVarP(&opts.Env, "env", "e", []string{}, "Set environment variables")
+	runCmd.Flags().StringArrayVar(&opts.EnvFromFiles, "env-from-file", []string{}, "Set environment variables from a file of KEY=VALUE lines, --env values take precedence")
+	runCmd.Flags().StringVarP(&opts.User, "user", "u", "", "Run as this user, defaults to service user")
+	runCmd.Flags().StringVarP(&opts.WorkDir, "working-dir", "w", "", "Path to run the command in, defaults to service working_dir")
+	runCmd.Flags().BoolVar(&opts.Keep, "keep", false, "Keep the one-off container once command completed")
+	runCmd.Flags().BoolVar(&opts.KeepVolumes, "keep-volumes", false, "Keep anonymous volumes of the one-off container when removing it, and print their names")
+	runCmd.Flags().DurationVar(&opts.Timeout, "timeout", 0, "Stop waiting for command completion after this duration, e.g. \"30m\"")
+	runCmd.Flags().BoolVar(&opts.Build, "build", false, "Build images before running the command.")
+	runCmd.Flags().BoolVar(&opts.QuietPull, "quiet-pull", false, "Only show pull output when a pull fails")
+	runCmd.Flags().BoolVar(&opts.RemoveOrphans, "remove-orphans", false, "Remove containers of services no longer declared by the compose file")
+	runCmd.Flags().SetInterspersed(false)
+	return runCmd
+}
+
+func runRun(ctx context.Context, opts runOptions, service string, command []string) error {
+	env, err := withEnvFromFiles(opts.EnvFromFiles, opts.Env)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	project, err := projectFromOptions(options)
+	if err != nil {
+		return err
+	}
+	// running a service enables it, as targeting a service activates its profiles
+	if err := local_compose.EnableServices(project, []string{service}); err != nil {
+		return err
+	}
+	if opts.Build {
+		forceBuild(project)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	exitCode, err := c.ComposeService().RunOneOffContainer(ctx, project, compose.RunOptions{
+		Service:     service,
+		Command:     command,
+		Environment: env,
+		User:        opts.User,
+		WorkingDir:  opts.WorkDir,
+		AutoRemove:  !opts.Keep,
+		KeepVolumes: opts.KeepVolumes,
+		Timeout:     opts.Timeout,
+		Writer:      os.Stdout,
+		Signals:     signals,
+
+		QuietPull:     opts.QuietPull,
+		RemoveOrphans: opts.RemoveOrphans,
+	})
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return ExitCodeError{ExitCode: exitCode}
+	}
+	return nil
+}
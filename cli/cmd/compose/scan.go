@@ -0,0 +1,188 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/formatter"
+)
+
+// scanCommandEnv sets the command used to scan images, invoked with image name as last argument
+const scanCommandEnv = "COMPOSE_SCAN_COMMAND"
+
+var severities = []string{"low", "medium", "high", "critical"}
+
+type scanOptions struct {
+	check             bool
+	severityThreshold string
+}
+
+func addScanFlags(f *pflag.FlagSet, opts *scanOptions) {
+	f.BoolVar(&opts.check, "check", false, "Scan service images for vulnerabilities, using \"docker scan\" or the command set by "+scanCommandEnv)
+	f.StringVar(&opts.severityThreshold, "severity-threshold", "critical", "Fail --check when vulnerabilities of this severity or higher are found. Values: [low | medium | high | critical]")
+}
+
+func (o scanOptions) validate() error {
+	if severityRank(o.severityThreshold) < 0 {
+		return fmt.Errorf("invalid --severity-threshold %q, must be one of %s", o.severityThreshold, strings.Join(severities, ", "))
+	}
+	return nil
+}
+
+// imageScanner scans an image for vulnerabilities
+type imageScanner interface {
+	Scan(ctx context.Context, image string) (scanReport, error)
+}
+
+// scanReport is the subset of `docker scan --json` output we rely on. Scanner plugins must output the same format
+type scanReport struct {
+	Vulnerabilities []struct {
+		Severity string `json:"severity"`
+	} `json:"vulnerabilities"`
+}
+
+// scanSummary counts vulnerabilities found in a service image by severity
+type scanSummary struct {
+	Service  string
+	Image    string
+	Critical int
+	High     int
+	Medium   int
+	Low      int
+}
+
+// execScanner runs an external scanner binary, so we don't depend on a specific vendor
+type execScanner struct {
+	command []string
+}
+
+func newImageScanner() imageScanner {
+	if command := strings.Fields(os.Getenv(scanCommandEnv)); len(command) > 0 {
+		return execScanner{command: command}
+	}
+	return execScanner{command: []string{"docker", "scan", "--json"}}
+}
+
+func (s execScanner) Scan(ctx context.Context, image string) (scanReport, error) {
+	var stdout, stderr bytes.Buffer
+	args := append(append([]string{}, s.command[1:]...), image)
+	cmd := exec.CommandContext(ctx, s.command[0], args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// scanners exit with non-zero status when vulnerabilities are found, rely on report being parsable
+	runErr := cmd.Run()
+
+	var report scanReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		if runErr != nil {
+			return report, errors.Wrapf(runErr, "failed to scan image %s: %s", image, strings.TrimSpace(stderr.String()))
+		}
+		return report, errors.Wrapf(err, "failed to parse %s report for image %s", s.command[0], image)
+	}
+	return report, nil
+}
+
+// runImageChecks scans images built for services and prints a summary, failing if vulnerabilities reach severity threshold
+func runImageChecks(ctx context.Context, project *types.Project, scanner imageScanner, threshold string, out io.Writer) error {
+	var summaries []scanSummary
+	var failed []string
+	for _, service := range project.Services {
+		if service.Build == nil {
+			continue
+		}
+		image := service.Image
+		if image == "" {
+			image = compose.ResourceName(project.Name, service.Name)
+		}
+		report, err := scanner.Scan(ctx, image)
+		if err != nil {
+			return err
+		}
+		summary := summarize(service.Name, image, report)
+		summaries = append(summaries, summary)
+		if summary.exceeds(threshold) {
+			failed = append(failed, service.Name)
+		}
+	}
+
+	err := formatter.Print(summaries, formatter.PRETTY, out,
+		func(w io.Writer) {
+			for _, s := range summaries {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", s.Service, s.Image, s.Critical, s.High, s.Medium, s.Low)
+			}
+		},
+		"SERVICE", "IMAGE", "CRITICAL", "HIGH", "MEDIUM", "LOW")
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("vulnerabilities with %s severity or higher found in images for services: %s", threshold, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func summarize(service string, image string, report scanReport) scanSummary {
+	summary := scanSummary{
+		Service: service,
+		Image:   image,
+	}
+	for _, v := range report.Vulnerabilities {
+		switch strings.ToLower(v.Severity) {
+		case "critical":
+			summary.Critical++
+		case "high":
+			summary.High++
+		case "medium":
+			summary.Medium++
+		case "low":
+			summary.Low++
+		}
+	}
+	return summary
+}
+
+func (s scanSummary) exceeds(threshold string) bool {
+	counts := []int{s.Low, s.Medium, s.High, s.Critical}
+	for i := severityRank(threshold); i >= 0 && i < len(counts); i++ {
+		if counts[i] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == strings.ToLower(severity) {
+			return i
+		}
+	}
+	return -1
+}
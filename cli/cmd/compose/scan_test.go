@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+type fakeScanner struct {
+	reports map[string]string
+}
+
+func (s fakeScanner) Scan(ctx context.Context, image string) (scanReport, error) {
+	var report scanReport
+	err := json.Unmarshal([]byte(s.reports[image]), &report)
+	return report, err
+}
+
+func TestRunImageChecks(t *testing.T) {
+	project := &types.Project{
+		Name: "test",
+		Services: types.Services{
+			{Name: "front", Build: &types.BuildConfig{Context: "."}},
+			{Name: "back", Image: "back:latest", Build: &types.BuildConfig{Context: "."}},
+			{Name: "db", Image: "mysql"},
+		},
+	}
+	scanner := fakeScanner{reports: map[string]string{
+		"test_front":  `{"vulnerabilities": [{"severity": "high"}, {"severity": "low"}, {"severity": "low"}]}`,
+		"back:latest": `{"vulnerabilities": [{"severity": "critical"}, {"severity": "medium"}]}`,
+	}}
+
+	out := &bytes.Buffer{}
+	err := runImageChecks(context.Background(), project, scanner, "critical", out)
+	assert.Error(t, err, "vulnerabilities with critical severity or higher found in images for services: back")
+	assert.Equal(t, out.String(), `SERVICE             IMAGE               CRITICAL            HIGH                MEDIUM              LOW
+front               test_front          0                   1                   0                   2
+back                back:latest         1                   0                   1                   0
+`)
+
+	err = runImageChecks(context.Background(), project, scanner, "high", &bytes.Buffer{})
+	assert.Error(t, err, "vulnerabilities with high severity or higher found in images for services: front, back")
+}
+
+func TestSeverityThreshold(t *testing.T) {
+	summary := scanSummary{Medium: 1}
+	assert.Assert(t, summary.exceeds("low"))
+	assert.Assert(t, summary.exceeds("medium"))
+	assert.Assert(t, !summary.exceeds("high"))
+
+	assert.NilError(t, scanOptions{severityThreshold: "HIGH"}.validate())
+	assert.ErrorContains(t, scanOptions{severityThreshold: "bogus"}.validate(), "invalid --severity-threshold")
+}
@@ -0,0 +1,117 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+type snapshotOptions struct {
+	composeOptions
+	Dir string
+}
+
+func snapshotCommand() *cobra.Command {
+	opts := snapshotOptions{}
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Archive project named volumes to a directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSnapshot(cmd.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().StringVar(&opts.Dir, "out", "", "Directory volumes archives are written to")
+	_ = cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+func runSnapshot(ctx context.Context, opts snapshotOptions) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+
+	var archives []string
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		archives, err = c.ComposeService().Snapshot(ctx, projectName, compose.SnapshotOptions{
+			Dir: opts.Dir,
+		})
+		return "", err
+	})
+	if err != nil {
+		return err
+	}
+	for _, archive := range archives {
+		fmt.Println(archive)
+	}
+	return nil
+}
+
+type restoreOptions struct {
+	Dir string
+}
+
+func restoreCommand() *cobra.Command {
+	opts := restoreOptions{}
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Create volumes from a directory written by snapshot",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRestore(cmd.Context(), opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Dir, "in", "", "Directory volumes archives are read from")
+	_ = cmd.MarkFlagRequired("in")
+	return cmd
+}
+
+func runRestore(ctx context.Context, opts restoreOptions) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	var restored []string
+	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
+		restored, err = c.ComposeService().Restore(ctx, compose.RestoreOptions{
+			Dir: opts.Dir,
+		})
+		return "", err
+	})
+	if err != nil {
+		return err
+	}
+	for _, volume := range restored {
+		fmt.Println(volume)
+	}
+	return nil
+}
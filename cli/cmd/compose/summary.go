@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/compose-cli/formatter"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	summarySuccess = "success"
+	summaryFailure = "failure"
+)
+
+type operationSummary struct {
+	Status    string            `json:"status"`
+	Error     string            `json:"error,omitempty"`
+	Resources []resourceSummary `json:"resources"`
+}
+
+type resourceSummary struct {
+	Kind     string   `json:"kind,omitempty"`
+	Name     string   `json:"name"`
+	Action   string   `json:"action"`
+	Duration string   `json:"duration"`
+	Ports    []string `json:"ports,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// checkSummaryFormat validates --format for commands which summarize the resources they touched
+func checkSummaryFormat(format string) error {
+	switch format {
+	case "", formatter.PRETTY, formatter.JSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of pretty or json", format)
+	}
+}
+
+// withSummary runs fn, and in json format prints the resources it touched once done, even if it failed
+func withSummary(ctx context.Context, format string, w io.Writer, fn func(context.Context) error) error {
+	if format != formatter.JSON {
+		return fn(ctx)
+	}
+	recorder := progress.NewRecorder()
+	err := fn(progress.WithRecorder(ctx, recorder))
+	if printErr := printSummary(w, recorder.Resources(), err); err == nil {
+		return printErr
+	}
+	return err
+}
+
+func printSummary(w io.Writer, resources []progress.Resource, err error) error {
+	summary := operationSummary{
+		Status:    summarySuccess,
+		Resources: []resourceSummary{},
+	}
+	if err != nil {
+		summary.Status = summaryFailure
+		summary.Error = err.Error()
+	}
+	for _, r := range resources {
+		// resources reported without a kind are only known by the ID they are displayed with
+		name := r.Name
+		if r.Kind == "" {
+			name = r.ID
+		}
+		summary.Resources = append(summary.Resources, resourceSummary{
+			Kind:     r.Kind,
+			Name:     name,
+			Action:   r.Action,
+			Duration: r.Duration().Round(time.Millisecond).String(),
+			Ports:    r.Ports,
+			Error:    r.Error,
+		})
+	}
+	out, err := formatter.ToStandardJSON(summary)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(w, out)
+	return err
+}
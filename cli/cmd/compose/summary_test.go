@@ -0,0 +1,92 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/progress"
+)
+
+func TestPrintSummary(t *testing.T) {
+	start := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	resources := []progress.Resource{
+		{ID: `Network "demo_default"`, Kind: progress.NetworkKind, Name: "demo_default", Action: "Created", Status: progress.Done, Start: start, End: start.Add(120 * time.Millisecond)},
+		{ID: "web", Kind: progress.ImageKind, Name: "nginx", Action: "Pulled", Status: progress.Done, Start: start, End: start.Add(2 * time.Second)},
+		{ID: "demo_db_1", Kind: progress.ContainerKind, Name: "demo_db_1", Action: "Started", Status: progress.Done, Ports: []string{"0.0.0.0:5432->5432/tcp"}, Start: start, End: start.Add(time.Second)},
+		{ID: "demo_web_1", Kind: progress.ContainerKind, Name: "demo_web_1", Action: "Starting", Status: progress.Error, Error: "port is already allocated", Start: start, End: start.Add(time.Second)},
+		{ID: "Pushing web", Action: "Pushed", Status: progress.Done, Start: start, End: start.Add(time.Second)},
+	}
+
+	out := &bytes.Buffer{}
+	assert.NilError(t, printSummary(out, resources, errors.New("up failed")))
+	assert.Equal(t, out.String(), `{
+    "status": "failure",
+    "error": "up failed",
+    "resources": [
+        {
+            "kind": "network",
+            "name": "demo_default",
+            "action": "Created",
+            "duration": "120ms"
+        },
+        {
+            "kind": "image",
+            "name": "nginx",
+            "action": "Pulled",
+            "duration": "2s"
+        },
+        {
+            "kind": "container",
+            "name": "demo_db_1",
+            "action": "Started",
+            "duration": "1s",
+            "ports": [
+                "0.0.0.0:5432->5432/tcp"
+            ]
+        },
+        {
+            "kind": "container",
+            "name": "demo_web_1",
+            "action": "Starting",
+            "duration": "1s",
+            "error": "port is already allocated"
+        },
+        {
+            "name": "Pushing web",
+            "action": "Pushed",
+            "duration": "1s"
+        }
+    ]
+}
+`)
+
+	out.Reset()
+	assert.NilError(t, printSummary(out, nil, nil))
+	assert.Equal(t, out.String(), "{\n    \"status\": \"success\",\n    \"resources\": []\n}\n")
+}
+
+func TestCheckSummaryFormat(t *testing.T) {
+	assert.NilError(t, checkSummaryFormat(""))
+	assert.NilError(t, checkSummaryFormat("json"))
+	assert.Error(t, checkSummaryFormat("yaml"), `unsupported format "yaml", must be one of pretty or json`)
+}
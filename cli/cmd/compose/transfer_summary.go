@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// printTransferSummary reports services whose image got pushed or pulled, then the skipped ones and why, then the
+// failed ones with their error
+func printTransferSummary(w io.Writer, transferred string, summary compose.TransferSummary) {
+	if len(summary.Succeeded) > 0 {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", transferred, strings.Join(summary.Succeeded, ", "))
+	}
+	if len(summary.Skipped) > 0 {
+		var skipped []string
+		for service, reason := range summary.Skipped {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", service, reason))
+		}
+		sort.Strings(skipped)
+		_, _ = fmt.Fprintf(w, "Skipped: %s\n", strings.Join(skipped, ", "))
+	}
+	if len(summary.Failed) > 0 {
+		var failed []string
+		for service := range summary.Failed {
+			failed = append(failed, service)
+		}
+		sort.Strings(failed)
+		_, _ = fmt.Fprintln(w, "Failed:")
+		for _, service := range failed {
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", service, summary.Failed[service])
+		}
+	}
+}
@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestPrintTransferSummary(t *testing.T) {
+	var out bytes.Buffer
+	printTransferSummary(&out, "Pushed", compose.TransferSummary{
+		Succeeded: []string{"app", "worker"},
+		Skipped:   map[string]string{"redis": "no build section", "db": "no build section"},
+		Failed:    map[string]error{"api": errors.New("denied: requested access to the resource is denied")},
+	})
+	assert.Equal(t, out.String(), `Pushed: app, worker
+Skipped: db (no build section), redis (no build section)
+Failed:
+  api: denied: requested access to the resource is denied
+`)
+
+	out.Reset()
+	printTransferSummary(&out, "Pulled", compose.TransferSummary{})
+	assert.Equal(t, out.String(), "")
+}
@@ -0,0 +1,394 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sanathkr/go-yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// docker run flags we know about but can't translate into the compose model
+var (
+	unsupportedRunBoolFlags  = []string{"publish-all", "sig-proxy", "oom-kill-disable", "no-healthcheck"}
+	unsupportedRunValueFlags = []string{"attach", "cidfile", "device", "gpus", "health-cmd", "health-interval", "health-retries",
+		"health-timeout", "log-driver", "log-opt", "mount", "platform", "pull", "security-opt", "shm-size", "sysctl", "tmpfs", "ulimit"}
+)
+
+type runFlags struct {
+	detach      bool
+	remove      bool
+	interactive bool
+	tty         bool
+	privileged  bool
+	init        bool
+	name        string
+	hostname    string
+	user        string
+	workdir     string
+	entrypoint  string
+	restart     string
+	network     string
+	memory      string
+	cpus        string
+	publish     []string
+	volumes     []string
+	env         []string
+	envFile     []string
+	labels      []string
+	capAdd      []string
+	capDrop     []string
+}
+
+func translateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "translate -- docker run [OPTIONS] IMAGE [COMMAND] [ARG...]",
+		Short: "Translate a docker run command into the equivalent compose service",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTranslate(args)
+		},
+	}
+}
+
+func runTranslate(args []string) error {
+	service, unsupported, err := translateRunCommand(args)
+	if err != nil {
+		return err
+	}
+	out, err := marshalTranslatedService(service)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	if len(unsupported) > 0 {
+		fmt.Fprintf(os.Stderr, "WARNING: the following flags could not be translated: %s\n", strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// translateRunCommand parses a `docker run` command line and returns the equivalent service, as well as flags which
+// could not be translated
+func translateRunCommand(args []string) (types.ServiceConfig, []string, error) {
+	if len(args) > 0 && args[0] == "docker" {
+		args = args[1:]
+	}
+	if len(args) > 0 && args[0] == "container" {
+		args = args[1:]
+	}
+	if len(args) > 0 && args[0] == "run" {
+		args = args[1:]
+	}
+
+	var opts runFlags
+	fs := pflag.NewFlagSet("run", pflag.ContinueOnError)
+	fs.SetInterspersed(false)
+	fs.BoolVarP(&opts.detach, "detach", "d", false, "")
+	fs.BoolVar(&opts.remove, "rm", false, "")
+	fs.BoolVarP(&opts.interactive, "interactive", "i", false, "")
+	fs.BoolVarP(&opts.tty, "tty", "t", false, "")
+	fs.BoolVar(&opts.privileged, "privileged", false, "")
+	fs.BoolVar(&opts.init, "init", false, "")
+	fs.StringVar(&opts.name, "name", "", "")
+	fs.StringVarP(&opts.hostname, "hostname", "h", "", "")
+	fs.StringVarP(&opts.user, "user", "u", "", "")
+	fs.StringVarP(&opts.workdir, "workdir", "w", "", "")
+	fs.StringVar(&opts.entrypoint, "entrypoint", "", "")
+	fs.StringVar(&opts.restart, "restart", "", "")
+	fs.StringVar(&opts.network, "network", "", "")
+	fs.StringVar(&opts.network, "net", "", "")
+	fs.StringVarP(&opts.memory, "memory", "m", "", "")
+	fs.StringVar(&opts.cpus, "cpus", "", "")
+	fs.StringArrayVarP(&opts.publish, "publish", "p", nil, "")
+	fs.StringArrayVarP(&opts.volumes, "volume", "v", nil, "")
+	fs.StringArrayVarP(&opts.env, "env", "e", nil, "")
+	fs.StringArrayVar(&opts.envFile, "env-file", nil, "")
+	fs.StringArrayVarP(&opts.labels, "label", "l", nil, "")
+	fs.StringArrayVar(&opts.capAdd, "cap-add", nil, "")
+	fs.StringArrayVar(&opts.capDrop, "cap-drop", nil, "")
+
+	unsupportedFlags := map[string]bool{}
+	for _, name := range unsupportedRunBoolFlags {
+		fs.Bool(name, false, "")
+		unsupportedFlags[name] = true
+	}
+	for _, name := range unsupportedRunValueFlags {
+		fs.StringArray(name, nil, "")
+		unsupportedFlags[name] = true
+	}
+
+	args, unknown := stripUnknownRunFlags(fs, args)
+	if err := fs.Parse(args); err != nil {
+		return types.ServiceConfig{}, nil, err
+	}
+	if fs.NArg() == 0 {
+		return types.ServiceConfig{}, nil, errors.New("docker run command requires an image name")
+	}
+
+	var unsupported []string
+	fs.Visit(func(f *pflag.Flag) {
+		if unsupportedFlags[f.Name] {
+			unsupported = append(unsupported, "--"+f.Name)
+		}
+	})
+	unsupported = append(unsupported, unknown...)
+
+	image := fs.Arg(0)
+	service := types.ServiceConfig{
+		Name:          serviceNameFromImage(image),
+		ContainerName: opts.name,
+		Image:         image,
+		Hostname:      opts.hostname,
+		User:          opts.user,
+		WorkingDir:    opts.workdir,
+		Restart:       opts.restart,
+		StdinOpen:     opts.interactive,
+		Tty:           opts.tty,
+		Privileged:    opts.privileged,
+		CapAdd:        opts.capAdd,
+		CapDrop:       opts.capDrop,
+		EnvFile:       opts.envFile,
+	}
+	if opts.name != "" {
+		service.Name = opts.name
+	}
+	if fs.NArg() > 1 {
+		service.Command = fs.Args()[1:]
+	}
+	if opts.entrypoint != "" {
+		service.Entrypoint = types.ShellCommand{opts.entrypoint}
+	}
+	if opts.init {
+		service.Init = &opts.init
+	}
+	if len(opts.env) > 0 {
+		service.Environment = types.MappingWithEquals{}
+		for _, e := range opts.env {
+			parts := strings.SplitN(e, "=", 2)
+			if len(parts) == 1 {
+				service.Environment[parts[0]] = nil
+			} else {
+				value := parts[1]
+				service.Environment[parts[0]] = &value
+			}
+		}
+	}
+	if len(opts.labels) > 0 {
+		service.Labels = types.Labels{}
+		for _, l := range opts.labels {
+			parts := strings.SplitN(l, "=", 2)
+			if len(parts) == 1 {
+				service.Labels[parts[0]] = ""
+			} else {
+				service.Labels[parts[0]] = parts[1]
+			}
+		}
+	}
+	switch {
+	case opts.network == "":
+	case opts.network == "host" || opts.network == "none" || strings.HasPrefix(opts.network, "container:"):
+		service.NetworkMode = opts.network
+	default:
+		service.Networks = map[string]*types.ServiceNetworkConfig{
+			opts.network: nil,
+		}
+	}
+
+	ports, err := toServicePorts(opts.publish)
+	if err != nil {
+		return types.ServiceConfig{}, nil, err
+	}
+	service.Ports = ports
+
+	for _, v := range opts.volumes {
+		volume, err := loader.ParseVolume(v)
+		if err != nil {
+			return types.ServiceConfig{}, nil, err
+		}
+		service.Volumes = append(service.Volumes, volume)
+	}
+
+	if opts.memory != "" || opts.cpus != "" {
+		limits := &types.Resource{}
+		if opts.memory != "" {
+			memory, err := units.RAMInBytes(opts.memory)
+			if err != nil {
+				return types.ServiceConfig{}, nil, errors.Wrapf(err, "invalid memory limit %q", opts.memory)
+			}
+			limits.MemoryBytes = types.UnitBytes(memory)
+		}
+		if opts.cpus != "" {
+			if _, err := strconv.ParseFloat(opts.cpus, 64); err != nil {
+				return types.ServiceConfig{}, nil, errors.Wrapf(err, "invalid cpus limit %q", opts.cpus)
+			}
+			limits.NanoCPUs = opts.cpus
+		}
+		service.Deploy = &types.DeployConfig{
+			Resources: types.Resources{
+				Limits: limits,
+			},
+		}
+	}
+	return service, unsupported, nil
+}
+
+// stripUnknownRunFlags removes from args the flags fs doesn't declare, and returns them to be reported, so translating
+// a command using a flag we don't know about yet still works. An unknown long flag is assumed to take a value, unless
+// this would leave no image name, while an unknown shorthand is assumed to be a boolean
+func stripUnknownRunFlags(fs *pflag.FlagSet, args []string) ([]string, []string) {
+	var known, unknown []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-"):
+			// flags aren't interspersed, this is the image name
+			return append(known, args[i:]...), unknown
+		case strings.HasPrefix(arg, "--"):
+			name := strings.SplitN(arg[2:], "=", 2)[0]
+			hasValue := strings.Contains(arg, "=")
+			flag := fs.Lookup(name)
+			if flag == nil {
+				unknown = append(unknown, "--"+name)
+				if !hasValue && i+2 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+					i++
+				}
+				continue
+			}
+			known = append(known, arg)
+			if !hasValue && flag.NoOptDefVal == "" && i+1 < len(args) {
+				i++
+				known = append(known, args[i])
+			}
+		default:
+			// shorthands can be grouped, like -it, the last one possibly taking a value
+			shorthands := "-"
+			takesValue := false
+			for j := 1; j < len(arg); j++ {
+				c := arg[j : j+1]
+				flag := fs.ShorthandLookup(c)
+				if flag == nil {
+					unknown = append(unknown, "-"+c)
+					continue
+				}
+				if flag.NoOptDefVal == "" {
+					shorthands += arg[j:]
+					takesValue = j == len(arg)-1
+					break
+				}
+				shorthands += c
+			}
+			if shorthands != "-" {
+				known = append(known, shorthands)
+			}
+			if takesValue && i+1 < len(args) {
+				i++
+				known = append(known, args[i])
+			}
+		}
+	}
+	return known, unknown
+}
+
+// marshalTranslatedService renders service as a compose file. Ports bound to a host IP are written with the short
+// syntax, as the long one compose-go loads can't express host_ip
+func marshalTranslatedService(service types.ServiceConfig) ([]byte, error) {
+	out, err := yaml.Marshal(service)
+	if err != nil {
+		return nil, err
+	}
+	var config yaml.MapSlice
+	if err := yaml.Unmarshal(out, &config); err != nil {
+		return nil, err
+	}
+	for i, item := range config {
+		if item.Key != "ports" {
+			continue
+		}
+		var ports []interface{}
+		for _, port := range service.Ports {
+			if port.HostIP == "" {
+				ports = append(ports, port)
+				continue
+			}
+			published := ""
+			if port.Published != 0 {
+				published = strconv.Itoa(int(port.Published))
+			}
+			ports = append(ports, fmt.Sprintf("%s:%s:%d/%s", port.HostIP, published, port.Target, port.Protocol))
+		}
+		config[i].Value = ports
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"services": yaml.MapSlice{{Key: service.Name, Value: config}},
+	})
+}
+
+// serviceNameFromImage computes a service name from image reference, i.e. `nginx` for `docker.io/library/nginx:latest`
+func serviceNameFromImage(image string) string {
+	name := path.Base(image)
+	if i := strings.IndexAny(name, ":@"); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func toServicePorts(specs []string) ([]types.ServicePortConfig, error) {
+	_, bindings, err := nat.ParsePortSpecs(specs)
+	if err != nil {
+		return nil, err
+	}
+	var ports []types.ServicePortConfig
+	for port, binds := range bindings {
+		for _, bind := range binds {
+			config := types.ServicePortConfig{
+				HostIP:   bind.HostIP,
+				Target:   uint32(port.Int()),
+				Protocol: port.Proto(),
+			}
+			if bind.HostPort != "" {
+				published, err := strconv.ParseUint(bind.HostPort, 10, 32)
+				if err != nil {
+					return nil, errors.Wrapf(err, "unsupported published port %q", bind.HostPort)
+				}
+				config.Published = uint32(published)
+			}
+			ports = append(ports, config)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Target == ports[j].Target {
+			if ports[i].Published == ports[j].Published {
+				return ports[i].HostIP < ports[j].HostIP
+			}
+			return ports[i].Published < ports[j].Published
+		}
+		return ports[i].Target < ports[j].Target
+	})
+	return ports, nil
+}
@@ -0,0 +1,208 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestTranslateRunCommand(t *testing.T) {
+	value := "bar"
+	tests := []struct {
+		name        string
+		args        []string
+		expected    types.ServiceConfig
+		unsupported []string
+	}{
+		{
+			name: "image only",
+			args: []string{"docker", "run", "nginx"},
+			expected: types.ServiceConfig{
+				Name:  "nginx",
+				Image: "nginx",
+			},
+		},
+		{
+			name: "name and command",
+			args: []string{"run", "--rm", "--name", "worker", "example/app:1.2", "sh", "-c", "echo hello"},
+			expected: types.ServiceConfig{
+				Name:          "worker",
+				ContainerName: "worker",
+				Image:         "example/app:1.2",
+				Command:       types.ShellCommand{"sh", "-c", "echo hello"},
+			},
+		},
+		{
+			name: "ports",
+			args: []string{"run", "-d", "-p", "8080:80", "-p", "443", "-p", "53:53/udp", "nginx"},
+			expected: types.ServiceConfig{
+				Name:  "nginx",
+				Image: "nginx",
+				Ports: []types.ServicePortConfig{
+					{Target: 53, Published: 53, Protocol: "udp"},
+					{Target: 80, Published: 8080, Protocol: "tcp"},
+					{Target: 443, Protocol: "tcp"},
+				},
+			},
+		},
+		{
+			name: "ports bound to a host IP",
+			args: []string{"run", "-p", "127.0.0.1:8080:80", "-p", "127.0.0.1::443", "nginx"},
+			expected: types.ServiceConfig{
+				Name:  "nginx",
+				Image: "nginx",
+				Ports: []types.ServicePortConfig{
+					{HostIP: "127.0.0.1", Target: 80, Published: 8080, Protocol: "tcp"},
+					{HostIP: "127.0.0.1", Target: 443, Protocol: "tcp"},
+				},
+			},
+		},
+		{
+			name: "environment and labels",
+			args: []string{"run", "-e", "FOO=bar", "--env", "QIX", "-l", "com.example=test", "nginx"},
+			expected: types.ServiceConfig{
+				Name:        "nginx",
+				Image:       "nginx",
+				Environment: types.MappingWithEquals{"FOO": &value, "QIX": nil},
+				Labels:      types.Labels{"com.example": "test"},
+			},
+		},
+		{
+			name: "restart policy and network",
+			args: []string{"run", "--restart", "always", "--network", "backend", "nginx"},
+			expected: types.ServiceConfig{
+				Name:     "nginx",
+				Image:    "nginx",
+				Restart:  "always",
+				Networks: map[string]*types.ServiceNetworkConfig{"backend": nil},
+			},
+		},
+		{
+			name: "network mode",
+			args: []string{"run", "--net", "host", "nginx"},
+			expected: types.ServiceConfig{
+				Name:        "nginx",
+				Image:       "nginx",
+				NetworkMode: "host",
+			},
+		},
+		{
+			name: "resource limits",
+			args: []string{"run", "-m", "512m", "--cpus", "1.5", "nginx"},
+			expected: types.ServiceConfig{
+				Name:  "nginx",
+				Image: "nginx",
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{
+						Limits: &types.Resource{
+							MemoryBytes: 512 * 1024 * 1024,
+							NanoCPUs:    "1.5",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unsupported flags",
+			args: []string{"run", "--gpus", "all", "--ulimit", "nofile=1024", "--publish-all", "nginx"},
+			expected: types.ServiceConfig{
+				Name:  "nginx",
+				Image: "nginx",
+			},
+			unsupported: []string{"--gpus", "--publish-all", "--ulimit"},
+		},
+		{
+			name: "unknown flags",
+			args: []string{"run", "--storage-opt", "size=10G", "-itX", "--isolation=hyperv", "-d", "--unknown", "nginx"},
+			expected: types.ServiceConfig{
+				Name:      "nginx",
+				Image:     "nginx",
+				StdinOpen: true,
+				Tty:       true,
+			},
+			unsupported: []string{"--storage-opt", "-X", "--isolation", "--unknown"},
+		},
+		{
+			name: "unknown shorthand grouped with a value flag",
+			args: []string{"run", "-Xe", "FOO=bar", "nginx", "env"},
+			expected: types.ServiceConfig{
+				Name:        "nginx",
+				Image:       "nginx",
+				Command:     types.ShellCommand{"env"},
+				Environment: types.MappingWithEquals{"FOO": &value},
+			},
+			unsupported: []string{"-X"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, unsupported, err := translateRunCommand(tt.args)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, service, tt.expected)
+			assert.DeepEqual(t, unsupported, tt.unsupported)
+		})
+	}
+}
+
+func TestTranslateRunCommandVolumes(t *testing.T) {
+	service, _, err := translateRunCommand([]string{"run", "-v", "data:/var/lib/data:ro", "-v", "/host:/container", "nginx"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(service.Volumes), 2)
+	assert.Equal(t, service.Volumes[0].Type, types.VolumeTypeVolume)
+	assert.Equal(t, service.Volumes[0].Source, "data")
+	assert.Equal(t, service.Volumes[0].Target, "/var/lib/data")
+	assert.Equal(t, service.Volumes[0].ReadOnly, true)
+	assert.Equal(t, service.Volumes[1].Type, types.VolumeTypeBind)
+	assert.Equal(t, service.Volumes[1].Source, "/host")
+	assert.Equal(t, service.Volumes[1].Target, "/container")
+}
+
+func TestTranslateRunCommandErrors(t *testing.T) {
+	_, _, err := translateRunCommand([]string{"docker", "run", "-d"})
+	assert.Error(t, err, "docker run command requires an image name")
+
+	_, _, err = translateRunCommand([]string{"docker", "run", "-m", "lots", "nginx"})
+	assert.ErrorContains(t, err, `invalid memory limit "lots"`)
+}
+
+func TestMarshalTranslatedService(t *testing.T) {
+	service, _, err := translateRunCommand([]string{"run", "-p", "127.0.0.1:8080:80", "-p", "443", "nginx"})
+	assert.NilError(t, err)
+	out, err := marshalTranslatedService(service)
+	assert.NilError(t, err)
+	assert.Equal(t, string(out), `services:
+  nginx:
+    image: nginx
+    ports:
+    - 127.0.0.1:8080:80/tcp
+    - target: 443
+      protocol: tcp
+`)
+
+	config, err := loader.ParseYAML(out)
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  ".",
+		ConfigFiles: []types.ConfigFile{{Filename: "docker-compose.yml", Config: config}},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services[0].Ports[0].HostIP, "127.0.0.1")
+}
@@ -20,47 +20,157 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/docker/compose-cli/api/client"
 	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/formatter"
+	local_compose "github.com/docker/compose-cli/local/compose"
 	"github.com/docker/compose-cli/progress"
 
-	"github.com/compose-spec/compose-go/cli"
 	"github.com/compose-spec/compose-go/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+type upOptions struct {
+	composeOptions
+	ForceRecreate  bool
+	RecreateDeps   bool
+	Pull           string
+	Offline        bool
+	Verify         bool
+	Verbose        bool
+	OnFailure      string
+	HealthInterval time.Duration
+	HealthTimeout  time.Duration
+	Attach         []string
+	QuietBuild     bool
+	QuietPull      bool
+	Secrets        []string
+	ReplaceImage   bool
+	SinceBuild     bool
+}
+
+const (
+	// onFailureRollback removes containers and networks created by the failed up
+	onFailureRollback = "rollback"
+	// onFailureLeave keeps whatever up created or started, for debugging
+	onFailureLeave = "leave"
+	// onFailureDown tears the whole project down
+	onFailureDown = "down"
+)
+
+func (o upOptions) recreateStrategy() string {
+	if o.ForceRecreate {
+		return compose.RecreateForce
+	}
+	return compose.RecreateDiverged
+}
+
 func upCommand(contextType string) *cobra.Command {
-	opts := composeOptions{}
+	opts := upOptions{}
 	upCmd := &cobra.Command{
 		Use:   "up [SERVICE...]",
 		Short: "Create and start containers",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			switch contextType {
-			case store.LocalContextType, store.DefaultContextType:
-				return runCreateStart(cmd.Context(), opts, args)
-			default:
-				return runUp(cmd.Context(), opts, args)
+			if err := checkSummaryFormat(opts.Format); err != nil {
+				return err
 			}
+			return withSummary(cmd.Context(), opts.Format, os.Stdout, func(ctx context.Context) error {
+				switch contextType {
+				case store.LocalContextType, store.DefaultContextType:
+					if err := checkDetachedJSON(opts); err != nil {
+						return err
+					}
+					return runCreateStart(ctx, opts, args)
+				default:
+					return runUp(ctx, opts.composeOptions, args)
+				}
+			})
 		},
 	}
-	upCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
-	upCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
-	upCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
-	upCmd.Flags().StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
-	upCmd.Flags().BoolVarP(&opts.Detach, "detach", "d", false, "Detached mode: Run containers in the background")
-	upCmd.Flags().BoolVar(&opts.Build, "build", false, "Build images before starting containers.")
+	addUpFlags(upCmd.Flags(), &opts)
 
 	if contextType == store.AciContextType {
 		upCmd.Flags().StringVar(&opts.DomainName, "domainname", "", "Container NIS domain name")
+		upCmd.Flags().StringToStringVar(&opts.Tags, "tags", nil, "Tags set on the container group, overriding the ones of the context, e.g. team=payments,env=dev")
+	}
+	if contextType == store.EcsContextType {
+		upCmd.Flags().BoolVar(&opts.SkipPreflight, "skip-preflight", false, "Skip IAM permissions check before deployment")
 	}
 
 	return upCmd
 }
 
+func alphaUpCommand() *cobra.Command {
+	opts := upOptions{}
+	cmd := &cobra.Command{
+		Use:   "up [SERVICE...]",
+		Short: "Create and start containers, choosing what happens to them when up fails",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSummaryFormat(opts.Format); err != nil {
+				return err
+			}
+			switch opts.OnFailure {
+			case onFailureRollback, onFailureLeave, onFailureDown:
+			default:
+				return fmt.Errorf("invalid --on-failure option %q, must be one of %s, %s or %s", opts.OnFailure, onFailureRollback, onFailureLeave, onFailureDown)
+			}
+			if err := checkDetachedJSON(opts); err != nil {
+				return err
+			}
+			return withSummary(cmd.Context(), opts.Format, os.Stdout, func(ctx context.Context) error {
+				return runCreateStart(ctx, opts, args)
+			})
+		},
+	}
+	addUpFlags(cmd.Flags(), &opts)
+	cmd.Flags().BoolVar(&opts.QuietBuild, "quiet-build", false, "Only show build output when a build fails, and a line per service built otherwise")
+	cmd.Flags().BoolVar(&opts.ReplaceImage, "replace-image-on-tag-change", false, "Pull images and recreate containers whose image tag now points to another image.")
+	cmd.Flags().StringVar(&opts.OnFailure, "on-failure", onFailureRollback, "What to do when up fails: \"rollback\" removes containers and networks this up created, \"leave\" keeps them for debugging, \"down\" removes the whole project")
+	return cmd
+}
+
+// addUpFlags registers the flags up and alpha up share
+func addUpFlags(f *pflag.FlagSet, opts *upOptions) {
+	f.StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	f.StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	f.StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	f.StringArrayVar(&opts.Profiles, "profile", []string{}, profileHelp)
+	f.StringArrayVarP(&opts.Environment, "environment", "e", []string{}, "Environment variables")
+	f.StringVar(&opts.EnvFile, "env-file", "", "Specify an alternate environment file")
+	f.BoolVarP(&opts.Detach, "detach", "d", false, "Detached mode: Run containers in the background")
+	f.StringArrayVar(&opts.Attach, "attach", []string{}, "Attach to output of these services only, overriding their attach attribute")
+	f.BoolVar(&opts.Build, "build", false, "Build images before starting containers.")
+	f.BoolVar(&opts.ForceRecreate, "force-recreate", false, "Recreate containers even if their configuration and image haven't changed.")
+	f.BoolVar(&opts.RecreateDeps, "recreate-deps-on-force", false, "With --force-recreate, also recreate services depending on the selected ones.")
+	f.StringVar(&opts.Pull, "pull", "", "Check registry for newer images of tags containers use: \"missing\" only warns, \"always\" pulls and recreates containers. Registry isn't checked by default")
+	f.BoolVar(&opts.Offline, "offline", false, "Don't check registry for updated images.")
+	f.BoolVar(&opts.Verify, "verify-signatures", false, "Only pull signed images, same as setting DOCKER_CONTENT_TRUST=1.")
+	f.BoolVar(&opts.Verbose, "verbose", false, "Show progress for each image layer being pulled.")
+	f.BoolVar(&opts.QuietPull, "quiet-pull", false, "Only show pull output when a pull fails")
+	f.BoolVar(&opts.SinceBuild, "since-build", false, sinceBuildHelp)
+	f.BoolVar(&opts.QuietLint, "quiet-lint", false, "Don't warn about unused resources declared by the compose file")
+	f.DurationVar(&opts.HealthInterval, "health-interval", 0, "Override the interval of healthchecks services declare, e.g. to detect readiness faster in CI")
+	f.DurationVar(&opts.HealthTimeout, "health-timeout", 0, "Override the timeout of healthchecks services declare")
+	f.StringArrayVar(&opts.Secrets, "secret", []string{}, "Set where a secret gets its value from, as NAME=env:VARIABLE or NAME=file:PATH")
+	f.StringVar(&opts.Format, "format", "", "Format the result. Values: [pretty | json]. json prints the resources touched once done. (Default: pretty)")
+}
+
+// checkDetachedJSON rejects a json result for attached up, as container logs would be mixed with it
+func checkDetachedJSON(opts upOptions) error {
+	if opts.Format == formatter.JSON && !opts.Detach {
+		return errors.New("--format json requires --detach, container logs would be mixed with the result")
+	}
+	return nil
+}
+
 func runUp(ctx context.Context, opts composeOptions, services []string) error {
 	c, project, err := setup(ctx, opts, services)
 	if err != nil {
@@ -68,22 +178,70 @@ func runUp(ctx context.Context, opts composeOptions, services []string) error {
 	}
 
 	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
-		return "", c.ComposeService().Up(ctx, project, opts.Detach)
+		return "", c.ComposeService().Up(ctx, project, compose.UpOptions{
+			Detach:        opts.Detach,
+			SkipPreflight: opts.SkipPreflight,
+			Tags:          opts.Tags,
+		})
 	})
 	return err
 }
 
-func runCreateStart(ctx context.Context, opts composeOptions, services []string) error {
-	c, project, err := setup(ctx, opts, services)
+func runCreateStart(ctx context.Context, opts upOptions, services []string) error {
+	if opts.RecreateDeps && !opts.ForceRecreate {
+		return errors.New("--recreate-deps-on-force requires --force-recreate")
+	}
+	if opts.Pull != "" && opts.Pull != compose.PullMissing && opts.Pull != compose.PullAlways {
+		return fmt.Errorf("invalid --pull option %q, must be one of %q or %q", opts.Pull, compose.PullMissing, compose.PullAlways)
+	}
+	c, project, err := setup(ctx, opts.composeOptions, nil)
+	if err != nil {
+		return err
+	}
+	envFile := ""
+	if opts.EnvFile != "" {
+		envFile, err = opts.envFile()
+		if err != nil {
+			return err
+		}
+	}
+	if err := applyHealthOverrides(project, opts.HealthInterval, opts.HealthTimeout); err != nil {
+		return err
+	}
+	if err := applySecretSources(project, opts.Secrets); err != nil {
+		return err
+	}
+	if len(opts.Attach) > 0 {
+		if err := local_compose.AttachOnly(project, opts.Attach); err != nil {
+			return err
+		}
+	}
+	if opts.RecreateDeps {
+		services = addDependents(project, services)
+	}
+	err = filter(project, services)
 	if err != nil {
 		return err
 	}
 
+	since := time.Now()
 	_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
-		return "", c.ComposeService().Create(ctx, project)
+		return "", c.ComposeService().Create(ctx, project, compose.CreateOptions{
+			Recreate:         opts.recreateStrategy(),
+			Pull:             opts.Pull,
+			Offline:          opts.Offline,
+			VerifySignatures: verifySignatures(opts.Verify),
+			Verbose:          opts.Verbose,
+			EnvFile:          envFile,
+			QuietBuild:       opts.QuietBuild,
+			QuietPull:        opts.QuietPull,
+
+			ReplaceImageOnTagChange: opts.ReplaceImage,
+			SinceBuild:              opts.SinceBuild,
+		})
 	})
 	if err != nil {
-		return err
+		return onUpFailure(ctx, c, project.Name, opts.OnFailure, since, err)
 	}
 
 	var consumer compose.LogConsumer
@@ -96,10 +254,113 @@ func runCreateStart(ctx context.Context, opts composeOptions, services []string)
 		fmt.Println("Gracefully stopping...")
 		ctx = context.Background()
 		_, err = progress.Run(ctx, func(ctx context.Context) (string, error) {
-			return "", c.ComposeService().Down(ctx, project.Name)
+			return "", c.ComposeService().Down(ctx, project.Name, compose.DownOptions{})
 		})
+	} else if err != nil {
+		return onUpFailure(ctx, c, project.Name, opts.OnFailure, since, err)
+	}
+	if err != nil || !opts.Detach || opts.Format == formatter.JSON {
+		return err
+	}
+	// ports published as 0 get an ephemeral host port assigned by the engine, report them so they don't have to be looked up
+	containers, err := c.ComposeService().Ps(ctx, project.Name)
+	if err != nil {
+		return err
+	}
+	return printPublishedPorts(os.Stdout, containers)
+}
+
+// applyHealthOverrides replaces interval and timeout of the healthchecks services declare
+func applyHealthOverrides(project *types.Project, interval, timeout time.Duration) error {
+	if interval < 0 || timeout < 0 {
+		return errors.New("--health-interval and --health-timeout must be positive durations")
+	}
+	if interval == 0 && timeout == 0 {
+		return nil
+	}
+	for i, service := range project.Services {
+		if service.HealthCheck == nil || service.HealthCheck.Disable {
+			continue
+		}
+		check := *service.HealthCheck
+		if interval != 0 {
+			d := types.Duration(interval)
+			check.Interval = &d
+		}
+		if timeout != 0 {
+			d := types.Duration(timeout)
+			check.Timeout = &d
+		}
+		project.Services[i].HealthCheck = &check
+	}
+	return nil
+}
+
+// applySecretSources overrides where top-level secrets get their value from, declaring them if needed. Values are
+// only read when containers are created, so they don't show up in the model
+func applySecretSources(project *types.Project, sources []string) error {
+	for _, source := range sources {
+		name, from := splitSecretSource(source, "=")
+		kind, value := splitSecretSource(from, ":")
+		if name == "" || value == "" {
+			return fmt.Errorf("invalid --secret %q, must be NAME=env:VARIABLE or NAME=file:PATH", source)
+		}
+		switch kind {
+		case "env":
+			local_compose.SetSecretEnvironment(project, name, value)
+		case "file":
+			file, err := filepath.Abs(value)
+			if err != nil {
+				return err
+			}
+			local_compose.SetSecretFile(project, name, file)
+		default:
+			return fmt.Errorf("invalid --secret %q, must be NAME=env:VARIABLE or NAME=file:PATH", source)
+		}
+	}
+	return nil
+}
+
+func splitSecretSource(s, sep string) (string, string) {
+	parts := strings.SplitN(s, sep, 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// onUpFailure applies the --on-failure policy once up failed, resources created since up started being the ones rollback removes
+func onUpFailure(ctx context.Context, c *client.Client, projectName, policy string, since time.Time, upErr error) error {
+	if policy == "" {
+		return upErr
+	}
+	printOnFailure(os.Stderr, policy)
+	var options compose.DownOptions
+	switch policy {
+	case onFailureLeave:
+		return upErr
+	case onFailureRollback:
+		options.CreatedSince = since
+	}
+	_, err := progress.Run(ctx, func(ctx context.Context) (string, error) {
+		return "", c.ComposeService().Down(ctx, projectName, options)
+	})
+	if err != nil {
+		return fmt.Errorf("%w, then --on-failure=%s failed: %v", upErr, policy, err)
+	}
+	return upErr
+}
+
+// printOnFailure tells which --on-failure policy runs
+func printOnFailure(w io.Writer, policy string) {
+	switch policy {
+	case onFailureRollback:
+		fmt.Fprintln(w, "Up failed, rolling back containers and networks it created (--on-failure=rollback)")
+	case onFailureLeave:
+		fmt.Fprintln(w, "Up failed, leaving containers in place for debugging (--on-failure=leave)")
+	case onFailureDown:
+		fmt.Fprintln(w, "Up failed, removing the whole project (--on-failure=down)")
 	}
-	return err
 }
 
 func setup(ctx context.Context, opts composeOptions, services []string) (*client.Client, *types.Project, error) {
@@ -112,18 +373,19 @@ func setup(ctx context.Context, opts composeOptions, services []string) (*client
 	if err != nil {
 		return nil, nil, err
 	}
-	project, err := cli.ProjectFromOptions(options)
+	project, err := projectFromOptions(options)
 	if err != nil {
 		return nil, nil, err
 	}
+	if !opts.QuietLint {
+		printLintWarnings(os.Stderr, lint(project))
+	}
 	if opts.DomainName != "" {
 		// arbitrarily set the domain name on the first service ; ACI backend will expose the entire project
 		project.Services[0].DomainName = opts.DomainName
 	}
 	if opts.Build {
-		for _, service := range project.Services {
-			service.PullPolicy = types.PullPolicyBuild
-		}
+		forceBuild(project)
 	}
 
 	err = filter(project, services)
@@ -132,3 +394,12 @@ func setup(ctx context.Context, opts composeOptions, services []string) (*client
 	}
 	return c, project, nil
 }
+
+// forceBuild makes services declaring a build get their image rebuilt, even if present, as --build requests
+func forceBuild(project *types.Project) {
+	for i, service := range project.Services {
+		if service.Build != nil {
+			project.Services[i].PullPolicy = types.PullPolicyBuild
+		}
+	}
+}
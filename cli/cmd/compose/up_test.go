@@ -0,0 +1,101 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestAlphaUpInvalidOnFailure(t *testing.T) {
+	cmd := alphaUpCommand()
+	cmd.SetArgs([]string{"--on-failure", "keep"})
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+	err := cmd.Execute()
+	assert.Error(t, err, `invalid --on-failure option "keep", must be one of rollback, leave or down`)
+}
+
+func TestPrintOnFailure(t *testing.T) {
+	tests := map[string]string{
+		onFailureRollback: "Up failed, rolling back containers and networks it created (--on-failure=rollback)\n",
+		onFailureLeave:    "Up failed, leaving containers in place for debugging (--on-failure=leave)\n",
+		onFailureDown:     "Up failed, removing the whole project (--on-failure=down)\n",
+	}
+	for policy, expected := range tests {
+		out := &bytes.Buffer{}
+		printOnFailure(out, policy)
+		assert.Equal(t, out.String(), expected)
+	}
+}
+
+func TestApplyHealthOverrides(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web", HealthCheck: &types.HealthCheckConfig{Test: types.HealthCheckTest{"CMD", "true"}}},
+			{Name: "disabled", HealthCheck: &types.HealthCheckConfig{Disable: true}},
+			{Name: "none"},
+		},
+	}
+	assert.NilError(t, applyHealthOverrides(project, 2*time.Second, 0))
+	assert.Equal(t, *project.Services[0].HealthCheck.Interval, types.Duration(2*time.Second))
+	assert.Assert(t, project.Services[0].HealthCheck.Timeout == nil)
+	assert.Assert(t, project.Services[1].HealthCheck.Interval == nil)
+	assert.Assert(t, project.Services[2].HealthCheck == nil)
+
+	err := applyHealthOverrides(project, 0, -time.Second)
+	assert.Error(t, err, "--health-interval and --health-timeout must be positive durations")
+}
+
+func TestApplySecretSources(t *testing.T) {
+	project := &types.Project{
+		Secrets: types.Secrets{
+			"db_pass": {Name: "db_pass", File: "/run/db_pass"},
+			"token":   {Name: "token", Extensions: map[string]interface{}{"x-environment": "TOKEN"}},
+		},
+	}
+	assert.NilError(t, applySecretSources(project, []string{"db_pass=env:DB_PASS", "token=file:/tmp/token", "api_key=env:API_KEY"}))
+	assert.Equal(t, project.Secrets["db_pass"].File, "")
+	assert.Equal(t, project.Secrets["db_pass"].Extensions["x-environment"], "DB_PASS")
+	assert.Equal(t, project.Secrets["token"].File, "/tmp/token")
+	assert.Equal(t, len(project.Secrets["token"].Extensions), 0)
+	assert.Equal(t, project.Secrets["api_key"].Extensions["x-environment"], "API_KEY")
+
+	for _, source := range []string{"db_pass", "db_pass=DB_PASS", "db_pass=vault:DB_PASS", "=env:DB_PASS", "db_pass=env:"} {
+		err := applySecretSources(project, []string{source})
+		assert.Error(t, err, `invalid --secret "`+source+`", must be NAME=env:VARIABLE or NAME=file:PATH`)
+	}
+}
+
+func TestForceBuild(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "app", Build: &types.BuildConfig{Context: "."}},
+			{Name: "db", Image: "postgres"},
+			{Name: "web", Image: "myweb", Build: &types.BuildConfig{Context: "."}},
+		},
+	}
+	forceBuild(project)
+	assert.Equal(t, project.Services[0].PullPolicy, types.PullPolicyBuild)
+	assert.Equal(t, project.Services[1].PullPolicy, "")
+	assert.Equal(t, project.Services[2].PullPolicy, types.PullPolicyBuild)
+}
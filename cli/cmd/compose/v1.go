@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// v1ExecutableName is the name of the standalone docker-compose binary
+	v1ExecutableName = "docker-compose"
+	// CompatibilityV1Flag makes the CLI accept docker-compose command lines, whatever the executable name
+	CompatibilityV1Flag = "--compatibility-v1"
+)
+
+// v1GlobalFlags maps docker-compose global flags to the equivalent compose command flags
+var v1GlobalFlags = map[string]string{
+	"-f":                  "file",
+	"--file":              "file",
+	"-p":                  "project-name",
+	"--project-name":      "project-name",
+	"--project-directory": "workdir",
+}
+
+// rootValueFlags are the root command flags which take a value
+var rootValueFlags = map[string]bool{
+	"-c":          true,
+	"--context":   true,
+	"--config":    true,
+	"-H":          true,
+	"--host":      true,
+	"-l":          true,
+	"--log-level": true,
+}
+
+// IsV1Invocation tells if the CLI has been invoked as a drop-in replacement for docker-compose
+func IsV1Invocation(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	// strip both kinds of path separators, filepath.Base only knows about the current OS one
+	executable := args[0][strings.LastIndexAny(args[0], `/\`)+1:]
+	if strings.TrimSuffix(executable, ".exe") == v1ExecutableName {
+		return true
+	}
+	// only global flags enable compatibility, the same flag given to a command or its arguments is left alone
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == CompatibilityV1Flag {
+			return true
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "--" {
+			return false
+		}
+		if !strings.Contains(arg, "=") && (rootValueFlags[arg] || v1GlobalFlags[arg] != "") {
+			i++
+		}
+	}
+	return false
+}
+
+// ConvertV1Args converts a `docker-compose [global options] COMMAND [options]` command line into
+// the equivalent `compose COMMAND [options]` one. Global flags the target command doesn't support are dropped.
+func ConvertV1Args(composeCmd *cobra.Command, args []string) []string {
+	var (
+		global  []string
+		compose []string
+		flags   []string
+		command []string
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == CompatibilityV1Flag {
+			continue
+		}
+		if arg == "--"+compatibilityFlag {
+			compose = append(compose, arg)
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "--" {
+			command = args[i:]
+			break
+		}
+		name, value, hasValue := arg, "", false
+		if idx := strings.Index(arg, "="); idx > 0 {
+			name, value, hasValue = arg[:idx], arg[idx+1:], true
+		}
+		if mapped, ok := v1GlobalFlags[name]; ok {
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			flags = append(flags, mapped, value)
+			continue
+		}
+		global = append(global, arg)
+		if !hasValue && rootValueFlags[name] && i+1 < len(args) {
+			i++
+			global = append(global, args[i])
+		}
+	}
+
+	converted := append(append(global, "compose"), compose...)
+	if len(command) == 0 {
+		return converted
+	}
+
+	converted = append(converted, command[0])
+	sub, _, err := composeCmd.Find(command[:1])
+	for i := 0; i < len(flags); i += 2 {
+		if err == nil && sub != composeCmd && sub.Flags().Lookup(flags[i]) == nil {
+			continue
+		}
+		converted = append(converted, "--"+flags[i]+"="+flags[i+1])
+	}
+	for _, arg := range command[1:] {
+		if arg != CompatibilityV1Flag {
+			converted = append(converted, arg)
+		}
+	}
+	return converted
+}
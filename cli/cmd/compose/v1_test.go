@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/context/store"
+)
+
+func TestIsV1Invocation(t *testing.T) {
+	assert.Assert(t, IsV1Invocation([]string{"/usr/local/bin/docker-compose", "up"}))
+	assert.Assert(t, IsV1Invocation([]string{"C:\\bin\\docker-compose.exe", "up"}))
+	assert.Assert(t, IsV1Invocation([]string{"docker", "--compatibility-v1", "up"}))
+	assert.Assert(t, !IsV1Invocation([]string{"docker", "compose", "up"}))
+	assert.Assert(t, !IsV1Invocation([]string{"docker", "run", "--", "--compatibility-v1"}))
+	assert.Assert(t, IsV1Invocation([]string{"docker", "-c", "mycontext", "-f", "docker-compose.yaml", "--compatibility-v1", "up"}))
+	assert.Assert(t, !IsV1Invocation([]string{"docker", "run", "alpine", "echo", "--compatibility-v1"}))
+	assert.Assert(t, !IsV1Invocation([]string{"docker", "compose", "exec", "web", "--compatibility-v1"}))
+}
+
+func TestConvertV1Args(t *testing.T) {
+	cmd := Command(store.DefaultContextType)
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"-f", "docker-compose.yaml", "-p", "demo", "up", "-d"},
+			expected: []string{"compose", "up", "--file=docker-compose.yaml", "--project-name=demo", "-d"},
+		},
+		{
+			args:     []string{"--context", "mycontext", "--project-directory=./app", "--compatibility-v1", "down"},
+			expected: []string{"--context", "mycontext", "compose", "down", "--workdir=./app"},
+		},
+		{
+			args:     []string{"--compatibility", "-p", "demo", "up"},
+			expected: []string{"compose", "--compatibility", "up", "--project-name=demo"},
+		},
+		{
+			// ls doesn't support --file
+			args:     []string{"-f", "docker-compose.yaml", "-p", "demo", "ls"},
+			expected: []string{"compose", "ls", "--project-name=demo"},
+		},
+		{
+			args:     []string{"exec", "web", "ls", "-f"},
+			expected: []string{"compose", "exec", "web", "ls", "-f"},
+		},
+		{
+			args:     []string{},
+			expected: []string{"compose"},
+		},
+	}
+	for _, test := range tests {
+		assert.DeepEqual(t, ConvertV1Args(cmd, test.args), test.expected)
+	}
+}
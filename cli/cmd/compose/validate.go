@@ -0,0 +1,342 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/compose-spec/compose-go/cli"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type validateOptions struct {
+	composeOptions
+	Strict bool
+}
+
+func validateCommand() *cobra.Command {
+	opts := validateOptions{}
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate compose files, strict mode reporting unknown and deprecated keys with their location",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runValidate(opts, os.Stdout)
+		},
+	}
+	validateCmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	validateCmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	validateCmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	validateCmd.Flags().BoolVar(&opts.Strict, "strict", false, "Report unknown or misspelled keys and deprecated fields")
+	return validateCmd
+}
+
+func runValidate(opts validateOptions, w io.Writer) error {
+	issues := 0
+	if opts.Strict {
+		files, err := opts.configFiles()
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			found, err := validateStrict(file)
+			if err != nil {
+				return err
+			}
+			for _, issue := range found {
+				_, _ = fmt.Fprintln(w, issue)
+			}
+			issues += len(found)
+		}
+	}
+
+	options, err := opts.toProjectOptions()
+	if err != nil {
+		return err
+	}
+	if _, err := projectFromOptions(options); err != nil {
+		if issues > 0 {
+			_, _ = fmt.Fprintln(w, err)
+			issues++
+		} else {
+			return err
+		}
+	}
+	if issues > 0 {
+		return fmt.Errorf("%d issue(s) found in compose files", issues)
+	}
+	return nil
+}
+
+// configFiles lists the compose files to validate, looking for default ones in working directory if none is set
+func (o *composeOptions) configFiles() ([]string, error) {
+	if len(o.ConfigPaths) > 0 {
+		return o.ConfigPaths, nil
+	}
+	dir := o.WorkingDir
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = wd
+	}
+	for _, name := range cli.DefaultFileNames {
+		file := filepath.Join(dir, name)
+		if _, err := os.Stat(file); err == nil {
+			return []string{file}, nil
+		}
+	}
+	return nil, fmt.Errorf("no compose file found in %s", dir)
+}
+
+// validationIssue is a problem found by strict validation, located in compose file
+type validationIssue struct {
+	file    string
+	line    int
+	column  int
+	message string
+}
+
+func (i validationIssue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.file, i.line, i.column, i.message)
+}
+
+// deprecatedKeys maps key paths, `*` matching any name, to the reason they are deprecated
+var deprecatedKeys = map[string]string{
+	"version":                  "version is informative only, compose files are always validated against the latest schema",
+	"services.*.volumes_from":  "volumes_from is deprecated, declare named volumes shared by services instead",
+	"networks.*.external.name": "external.name is deprecated, use name instead",
+	"volumes.*.external.name":  "external.name is deprecated, use name instead",
+	"secrets.*.external.name":  "external.name is deprecated, use name instead",
+	"configs.*.external.name":  "external.name is deprecated, use name instead",
+}
+
+// validateStrict reports unknown and deprecated keys of a compose file, comparing them to the compose specification
+// schema
+func validateStrict(file string) ([]validationIssue, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	var schema jsonSchema
+	if err := json.Unmarshal([]byte(composeSpecSchema), &schema); err != nil {
+		return nil, err
+	}
+	definitions, _ := schema["definitions"].(map[string]interface{})
+	v := strictValidator{file: file, definitions: definitions}
+	v.walk(document.Content[0], schema, nil)
+	return v.issues, nil
+}
+
+// jsonSchema is a JSON schema node, as decoded from compose specification schema
+type jsonSchema map[string]interface{}
+
+type strictValidator struct {
+	file        string
+	definitions map[string]interface{}
+	issues      []validationIssue
+}
+
+func (v *strictValidator) report(node *yaml.Node, format string, args ...interface{}) {
+	v.issues = append(v.issues, validationIssue{
+		file:    v.file,
+		line:    node.Line,
+		column:  node.Column,
+		message: fmt.Sprintf(format, args...),
+	})
+}
+
+// walk checks node keys against the properties schema declares for it
+func (v *strictValidator) walk(node *yaml.Node, schema jsonSchema, path []string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		schema = v.alternative(schema, "object")
+		if schema == nil {
+			return
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			keyPath := append(append([]string{}, path...), key.Value)
+			if reason, ok := deprecated(keyPath); ok {
+				v.report(key, "%s: %s", strings.Join(keyPath, "."), reason)
+			}
+			property, ok := schema.property(key.Value)
+			if !ok {
+				if _, known := deprecated(keyPath); !known {
+					v.report(key, "%s", unknownKeyMessage(keyPath, properties))
+				}
+				continue
+			}
+			v.walk(value, property, keyPath)
+		}
+	case yaml.SequenceNode:
+		schema = v.alternative(schema, "array")
+		if schema == nil {
+			return
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for _, item := range node.Content {
+			v.walk(item, items, path)
+		}
+	}
+	// scalars and short syntaxes are validated by compose-go while loading the project
+}
+
+// alternative resolves the schema a node of JSON type kind is validated against, following references and oneOf or
+// anyOf alternatives. It returns nil if schema doesn't accept such a node
+func (v *strictValidator) alternative(schema jsonSchema, kind string) jsonSchema {
+	if ref, ok := schema["$ref"].(string); ok {
+		definition, _ := v.definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{})
+		return v.alternative(definition, kind)
+	}
+	for _, combination := range []string{"oneOf", "anyOf"} {
+		alternatives, _ := schema[combination].([]interface{})
+		for _, alternative := range alternatives {
+			alternative, _ := alternative.(map[string]interface{})
+			if resolved := v.alternative(alternative, kind); resolved != nil {
+				return resolved
+			}
+		}
+	}
+	switch t := schema["type"].(type) {
+	case string:
+		if t == kind {
+			return schema
+		}
+	case []interface{}:
+		for _, name := range t {
+			if name == kind {
+				return schema
+			}
+		}
+	}
+	return nil
+}
+
+// property returns the schema of a mapping key, from properties, then patternProperties, then additionalProperties
+func (s jsonSchema) property(key string) (jsonSchema, bool) {
+	properties, _ := s["properties"].(map[string]interface{})
+	if property, ok := properties[key].(map[string]interface{}); ok {
+		return property, true
+	}
+	patterns, _ := s["patternProperties"].(map[string]interface{})
+	for pattern, property := range patterns {
+		if matched, _ := regexp.MatchString(pattern, key); matched {
+			property, _ := property.(map[string]interface{})
+			return property, true
+		}
+	}
+	switch additional := s["additionalProperties"].(type) {
+	case bool:
+		return jsonSchema{}, additional
+	case map[string]interface{}:
+		return additional, true
+	}
+	return jsonSchema{}, true
+}
+
+func deprecated(path []string) (string, bool) {
+	for pattern, reason := range deprecatedKeys {
+		if matchKeyPath(strings.Split(pattern, "."), path) {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+func matchKeyPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func unknownKeyMessage(path []string, fields map[string]interface{}) string {
+	key := path[len(path)-1]
+	message := fmt.Sprintf("unknown key %q in %s", key, strings.Join(path[:len(path)-1], "."))
+	if len(path) == 1 {
+		message = fmt.Sprintf("unknown top-level key %q", key)
+	}
+	best, distance := "", len(key)/2+1
+	for name := range fields {
+		if d := levenshtein(key, name); d < distance || d == distance && name < best {
+			best, distance = name, d
+		}
+	}
+	if best != "" {
+		message += fmt.Sprintf(", did you mean %q?", best)
+	}
+	return message
+}
+
+// levenshtein computes the edit distance between two strings
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestValidateStrict(t *testing.T) {
+	dir := fs.NewDir(t, "validate", fs.WithFile("compose.yaml", `version: "3.8"
+services:
+  web:
+    image: nginx
+    enviroment:
+      - DEBUG=1
+    x-custom: value
+    deploy:
+      resources:
+        limits:
+          memroy: 64M
+    volumes_from:
+      - data
+  data:
+    image: busybox
+volumes:
+  cache:
+    external:
+      name: legacy_cache
+`))
+	defer dir.Remove()
+
+	file := dir.Join("compose.yaml")
+	issues, err := validateStrict(file)
+	assert.NilError(t, err)
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	assert.DeepEqual(t, messages, []string{
+		file + `:1:1: version: version is informative only, compose files are always validated against the latest schema`,
+		file + `:5:5: unknown key "enviroment" in services.web, did you mean "environment"?`,
+		file + `:11:11: unknown key "memroy" in services.web.deploy.resources.limits, did you mean "memory"?`,
+		file + `:12:5: services.web.volumes_from: volumes_from is deprecated, declare named volumes shared by services instead`,
+		file + `:19:7: volumes.cache.external.name: external.name is deprecated, use name instead`,
+	})
+}
+
+func TestValidateStrictValidFile(t *testing.T) {
+	dir := fs.NewDir(t, "validate", fs.WithFile("compose.yaml", `services:
+  web:
+    image: nginx
+    environment:
+      - DEBUG=1
+    ports:
+      - 8080:80
+      - target: 443
+        published: 8443
+    profiles: [frontend]
+    pids_limit: 100
+    blkio_config:
+      weight: 300
+    healthcheck:
+      test: ["CMD", "true"]
+      start_period: 10s
+      x-start_interval: 1s
+    deploy:
+      resources:
+        limits:
+          memory: 64M
+networks:
+  front:
+`))
+	defer dir.Remove()
+
+	issues, err := validateStrict(dir.Join("compose.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(issues), 0)
+}
+
+func TestValidateStrictAlternatives(t *testing.T) {
+	dir := fs.NewDir(t, "validate", fs.WithFile("compose.yaml", `services:
+  web:
+    build:
+      contxt: .
+    ports:
+      - target: 80
+        publshed: 8080
+    x-custom:
+      anything: goes
+`))
+	defer dir.Remove()
+
+	file := dir.Join("compose.yaml")
+	issues, err := validateStrict(file)
+	assert.NilError(t, err)
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	assert.DeepEqual(t, messages, []string{
+		file + `:4:7: unknown key "contxt" in services.web.build, did you mean "context"?`,
+		file + `:7:9: unknown key "publshed" in services.web.ports, did you mean "published"?`,
+	})
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, levenshtein("enviroment", "environment"), 1)
+	assert.Equal(t, levenshtein("image", "image"), 0)
+	assert.Equal(t, levenshtein("", "abc"), 3)
+}
@@ -0,0 +1,129 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type waitOptions struct {
+	composeOptions
+	health  bool
+	timeout time.Duration
+}
+
+func waitCommand() *cobra.Command {
+	opts := waitOptions{}
+	cmd := &cobra.Command{
+		Use:   "wait [SERVICE...]",
+		Short: "Wait for containers of a running project to be running, and healthy with --health",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWait(cmd.Context(), opts, args, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().BoolVar(&opts.health, "health", false, "Also wait for containers declaring a healthcheck to be healthy")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Maximum duration to wait for, e.g. \"120s\". Wait forever by default")
+	return cmd
+}
+
+// runWait only relies on containers labels, so it works with a project name alone, whatever started the project
+func runWait(ctx context.Context, opts waitOptions, services []string, w io.Writer) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		services, err = runningServices(ctx, c.ComposeService(), projectName)
+		if err != nil {
+			return err
+		}
+	}
+
+	deadline := time.Now().Add(opts.timeout)
+	err = waitHealthy(ctx, services, opts.timeout, time.Second, func(ctx context.Context, service string) (string, error) {
+		containers, err := c.ComposeService().Ps(ctx, projectName)
+		if err != nil {
+			return "", err
+		}
+		status := serviceReadiness(containers, service, opts.health)
+		if status != moby.Healthy {
+			printCountdown(w, service, status, opts.timeout, time.Until(deadline))
+		}
+		return status, nil
+	})
+	if timeout, ok := err.(*healthTimeoutError); ok {
+		printHealthSummary(ctx, os.Stderr, c.ComposeService(), projectName, timeout.services)
+	}
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		_, _ = fmt.Fprintf(w, "service %q is ready\n", service)
+	}
+	return nil
+}
+
+// serviceReadiness returns moby.Healthy once all service containers are running and, when health is required, healthy
+// if they declare a healthcheck. Otherwise, it returns the status of the first container which isn't ready
+func serviceReadiness(containers []compose.ContainerSummary, service string, health bool) string {
+	found := false
+	for _, container := range containers {
+		if container.Service != service {
+			continue
+		}
+		found = true
+		if container.State != "running" {
+			return container.State
+		}
+		if health && container.Health != "" && container.Health != moby.Healthy {
+			return container.Health
+		}
+	}
+	if !found {
+		return "not running"
+	}
+	return moby.Healthy
+}
+
+func printCountdown(w io.Writer, service string, status string, timeout time.Duration, left time.Duration) {
+	if timeout == 0 {
+		_, _ = fmt.Fprintf(w, "service %q is %s, waiting\n", service, status)
+		return
+	}
+	if left < 0 {
+		left = 0
+	}
+	_, _ = fmt.Fprintf(w, "service %q is %s, %s left\n", service, status, left.Round(time.Second))
+}
@@ -0,0 +1,180 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/compose-cli/api/client"
+	"github.com/docker/compose-cli/api/compose"
+)
+
+type waitHealthyOptions struct {
+	composeOptions
+	timeout time.Duration
+}
+
+func waitHealthyCommand() *cobra.Command {
+	opts := waitHealthyOptions{}
+	cmd := &cobra.Command{
+		Use:   "wait-healthy [SERVICE...]",
+		Short: "Wait for services of a running project to be healthy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWaitHealthy(cmd.Context(), opts, args)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Name, "project-name", "p", "", "Project name")
+	cmd.Flags().StringVar(&opts.WorkingDir, "workdir", "", "Work dir")
+	cmd.Flags().StringArrayVarP(&opts.ConfigPaths, "file", "f", []string{}, "Compose configuration files")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Maximum duration to wait for, e.g. \"30s\". Wait forever by default")
+	return cmd
+}
+
+func runWaitHealthy(ctx context.Context, opts waitHealthyOptions, services []string) error {
+	c, err := client.NewWithDefaultLocalBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	projectName, err := opts.toProjectName()
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		containers, err := c.ComposeService().Ps(ctx, projectName)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			return fmt.Errorf("no container found for project %q", projectName)
+		}
+		services = healthcheckedServices(containers)
+		if len(services) == 0 {
+			return fmt.Errorf("no running service of project %q has a healthcheck", projectName)
+		}
+	}
+
+	err = waitHealthy(ctx, services, opts.timeout, time.Second, func(ctx context.Context, service string) (string, error) {
+		health, err := c.ComposeService().Health(ctx, projectName, service, 0)
+		if err != nil {
+			return "", err
+		}
+		for _, container := range health {
+			if container.Status != moby.Healthy {
+				return container.Status, nil
+			}
+		}
+		return moby.Healthy, nil
+	})
+	if timeout, ok := err.(*healthTimeoutError); ok {
+		printHealthSummary(ctx, os.Stderr, c.ComposeService(), projectName, timeout.services)
+	}
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		fmt.Printf("service %q is healthy\n", service)
+	}
+	return nil
+}
+
+// runningServices lists services of a project which have running containers
+func runningServices(ctx context.Context, service compose.Service, projectName string) ([]string, error) {
+	containers, err := service.Ps(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	seen := map[string]bool{}
+	for _, container := range containers {
+		if !seen[container.Service] {
+			seen[container.Service] = true
+			services = append(services, container.Service)
+		}
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no container found for project %q", projectName)
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// healthcheckedServices lists services whose running containers have a healthcheck, others can't get healthy
+func healthcheckedServices(containers []compose.ContainerSummary) []string {
+	var services []string
+	seen := map[string]bool{}
+	for _, container := range containers {
+		if container.Health != "" && !seen[container.Service] {
+			seen[container.Service] = true
+			services = append(services, container.Service)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// healthTimeoutError reports the services which were still not healthy once wait timed out
+type healthTimeoutError struct {
+	timeout  time.Duration
+	services []string
+	statuses []string
+}
+
+func (e *healthTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for services to be healthy: %s", e.timeout, strings.Join(e.statuses, ", "))
+}
+
+// waitHealthy polls services health status until they are all healthy, or timeout expires
+func waitHealthy(ctx context.Context, services []string, timeout time.Duration, interval time.Duration, status func(ctx context.Context, service string) (string, error)) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		unhealthy := &healthTimeoutError{timeout: timeout}
+		for _, service := range services {
+			s, err := status(ctx, service)
+			if err != nil {
+				return err
+			}
+			if s != moby.Healthy {
+				unhealthy.services = append(unhealthy.services, service)
+				unhealthy.statuses = append(unhealthy.statuses, fmt.Sprintf("service %q is %s", service, s))
+			}
+		}
+		if len(unhealthy.services) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-expired:
+			return unhealthy
+		case <-time.After(interval):
+		}
+	}
+}
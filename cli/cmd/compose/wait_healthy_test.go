@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestWaitHealthy(t *testing.T) {
+	polls := map[string]int{}
+	err := waitHealthy(context.Background(), []string{"db", "web"}, time.Second, time.Millisecond, func(ctx context.Context, service string) (string, error) {
+		polls[service]++
+		if service == "db" && polls[service] < 3 {
+			return "starting", nil
+		}
+		return "healthy", nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, polls["db"], 3)
+}
+
+func TestWaitHealthyTimeout(t *testing.T) {
+	err := waitHealthy(context.Background(), []string{"db", "web"}, 20*time.Millisecond, time.Millisecond, func(ctx context.Context, service string) (string, error) {
+		if service == "web" {
+			return "unhealthy", nil
+		}
+		return "healthy", nil
+	})
+	assert.Error(t, err, `timed out after 20ms waiting for services to be healthy: service "web" is unhealthy`)
+}
+
+func TestHealthcheckedServices(t *testing.T) {
+	services := healthcheckedServices([]compose.ContainerSummary{
+		{Service: "web", Health: "starting"},
+		{Service: "worker"},
+		{Service: "db", Health: "healthy"},
+		{Service: "web", Health: "healthy"},
+	})
+	assert.DeepEqual(t, services, []string{"db", "web"})
+}
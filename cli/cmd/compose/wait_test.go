@@ -0,0 +1,54 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestServiceReadiness(t *testing.T) {
+	containers := []compose.ContainerSummary{
+		{Service: "db", State: "running", Health: "healthy"},
+		{Service: "web", State: "running", Health: "starting"},
+		{Service: "worker", State: "running"},
+		{Service: "worker", State: "restarting"},
+		{Service: "cache", State: "running"},
+	}
+	assert.Equal(t, serviceReadiness(containers, "db", true), "healthy")
+	assert.Equal(t, serviceReadiness(containers, "web", true), "starting")
+	assert.Equal(t, serviceReadiness(containers, "web", false), "healthy")
+	assert.Equal(t, serviceReadiness(containers, "worker", false), "restarting")
+	assert.Equal(t, serviceReadiness(containers, "cache", true), "healthy")
+	assert.Equal(t, serviceReadiness(containers, "proxy", false), "not running")
+}
+
+func TestPrintCountdown(t *testing.T) {
+	out := &bytes.Buffer{}
+	printCountdown(out, "web", "starting", 2*time.Minute, 95*time.Second+300*time.Millisecond)
+	printCountdown(out, "web", "starting", 2*time.Minute, -time.Second)
+	printCountdown(out, "web", "starting", 0, 0)
+	assert.Equal(t, out.String(), `service "web" is starting, 1m35s left
+service "web" is starting, 0s left
+service "web" is starting, waiting
+`)
+}
@@ -52,6 +52,7 @@ func createAciCommand() *cobra.Command {
 	cmd.Flags().StringVar(&opts.Location, "location", "eastus", "Location")
 	cmd.Flags().StringVar(&opts.SubscriptionID, "subscription-id", "", "Location")
 	cmd.Flags().StringVar(&opts.ResourceGroup, "resource-group", "", "Resource group")
+	cmd.Flags().StringToStringVar(&opts.Tags, "tags", nil, "Tags set on the container groups created in this context, e.g. team=payments,env=dev")
 
 	return cmd
 }
@@ -188,18 +188,32 @@ func main() {
 		ctype = cc.Type()
 	}
 
+	composeCmd := compose.Command(ctype)
 	root.AddCommand(
 		run.Command(ctype),
-		compose.Command(ctype),
+		composeCmd,
 		volume.Command(ctype),
 	)
 
+	// plugins are only looked up when they could be the requested command, not to slow down other ones
+	if c, _, err := root.Find(os.Args[1:]); (err == nil && c == composeCmd) || compose.IsV1Invocation(os.Args) {
+		compose.AddPluginCommands(composeCmd, configDir)
+	}
+
+	// when used as a drop-in replacement for docker-compose, run compose subcommands without the `compose` prefix
+	if compose.IsV1Invocation(os.Args) {
+		os.Args = append([]string{os.Args[0]}, compose.ConvertV1Args(composeCmd, os.Args[1:])...)
+		root.SetArgs(os.Args[1:])
+	}
+
 	ctx = apicontext.WithCurrentContext(ctx, currentContext)
 	ctx = store.WithContextStore(ctx, s)
 
 	if err = root.ExecuteContext(ctx); err != nil {
 		// if user canceled request, simply exit without any error message
-		if errdefs.IsErrCanceled(err) || errors.Is(ctx.Err(), context.Canceled) {
+		// unless command reports the exit code of a process it forwarded the interrupt to
+		var exitCodeErr compose.ExitCodeError
+		if (errdefs.IsErrCanceled(err) || errors.Is(ctx.Err(), context.Canceled)) && !errors.As(err, &exitCodeErr) {
 			metrics.Track(ctype, os.Args[1:], metrics.CanceledStatus)
 			os.Exit(130)
 		}
@@ -229,6 +243,10 @@ func exit(ctx string, err error, ctype string) {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(errdefs.ExitCodeLoginRequired)
 	}
+	var exitCodeErr compose.ExitCodeError
+	if errors.As(err, &exitCodeErr) {
+		os.Exit(exitCodeErr.ExitCode)
+	}
 	if errors.Is(err, errdefs.ErrNotImplemented) {
 		name := metrics.GetCommand(os.Args[1:])
 		fmt.Fprintf(os.Stderr, "Command %q not available in current context (%s)\n", name, ctx)
@@ -98,4 +98,6 @@ func configFilePath(dir string) string {
 // File contains the current context from the docker configuration file
 type File struct {
 	CurrentContext string `json:"currentContext,omitempty"`
+	// CLIPluginsExtraDirs lists directories plugins are looked up in, besides the default one
+	CLIPluginsExtraDirs []string `json:"cliPluginsExtraDirs,omitempty"`
 }
@@ -47,6 +47,8 @@ type AciContext struct {
 	SubscriptionID string `json:",omitempty"`
 	Location       string `json:",omitempty"`
 	ResourceGroup  string `json:",omitempty"`
+	// Tags are set on the container groups created in this context
+	Tags map[string]string `json:",omitempty"`
 }
 
 // EcsContext is the context for the AWS backend
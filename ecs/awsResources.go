@@ -19,6 +19,7 @@ package ecs
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -52,6 +53,7 @@ func (r *awsResources) serviceSecurityGroups(service types.ServiceConfig) []stri
 	for net := range service.Networks {
 		groups = append(groups, r.securityGroups[net])
 	}
+	sort.Strings(groups)
 	return groups
 }
 
@@ -60,6 +62,7 @@ func (r *awsResources) allSecurityGroups() []string {
 	for _, r := range r.securityGroups {
 		securityGroups = append(securityGroups, r)
 	}
+	sort.Strings(securityGroups)
 	return securityGroups
 }
 
@@ -250,8 +253,8 @@ func (b *ecsAPIService) parseExternalVolumes(ctx context.Context, project *types
 
 		logrus.Debugf("searching for existing filesystem as volume %q", name)
 		tags := map[string]string{
-			compose.ProjectTag: project.Name,
-			compose.VolumeTag:  name,
+			compose.ProjectTag(): project.Name,
+			compose.VolumeTag():  name,
 		}
 		previous, err := b.aws.ListFileSystems(ctx, tags)
 		if err != nil {
@@ -358,11 +361,11 @@ func (b *ecsAPIService) ensureVolumes(r *awsResources, project *types.Project, t
 			FileSystemPolicy: nil,
 			FileSystemTags: []efs.FileSystem_ElasticFileSystemTag{
 				{
-					Key:   compose.ProjectTag,
+					Key:   compose.ProjectTag(),
 					Value: project.Name,
 				},
 				{
-					Key:   compose.VolumeTag,
+					Key:   compose.VolumeTag(),
 					Value: name,
 				},
 				{
@@ -433,6 +436,7 @@ func (r *awsResources) getLoadBalancerSecurityGroups(project *types.Project) []s
 			securityGroups = append(securityGroups, r.securityGroups[name])
 		}
 	}
+	sort.Strings(securityGroups)
 	return securityGroups
 }
 
@@ -51,6 +51,21 @@ func (mr *MockAPIMockRecorder) CheckRequirements(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRequirements", reflect.TypeOf((*MockAPI)(nil).CheckRequirements), arg0, arg1)
 }
 
+// CheckPermissions mocks base method
+func (m *MockAPI) CheckPermissions(arg0 context.Context, arg1 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPermissions", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermissions indicates an expected call of CheckPermissions
+func (mr *MockAPIMockRecorder) CheckPermissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermissions", reflect.TypeOf((*MockAPI)(nil).CheckPermissions), arg0, arg1)
+}
+
 // CheckVPC mocks base method
 func (m *MockAPI) CheckVPC(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -30,6 +30,7 @@ import (
 	"github.com/docker/compose-cli/context/cloud"
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
+	"github.com/docker/compose-cli/utils/httpclient"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -99,7 +100,8 @@ func getEcsAPIService(ecsCtx store.EcsContext) (*ecsAPIService, error) {
 		Profile:           profile,
 		SharedConfigState: session.SharedConfigEnable,
 		Config: aws.Config{
-			Region: aws.String(region),
+			Region:     aws.String(region),
+			HTTPClient: httpclient.New(),
 		},
 	})
 	if err != nil {
@@ -51,6 +51,43 @@ func TestSimpleConvert(t *testing.T) {
 	golden.Assert(t, result, expected)
 }
 
+func TestConvertIsStable(t *testing.T) {
+	bytes, err := ioutil.ReadFile("testdata/input/multi-services.yaml")
+	assert.NilError(t, err)
+	for _, format := range []string{"yaml", "json"} {
+		template := convertYaml(t, string(bytes), useDefaultVPC)
+		result, err := marshall(template, format)
+		assert.NilError(t, err)
+		golden.Assert(t, fmt.Sprintf("%s\n", string(result)), "multi/multi-services-conversion."+format+".golden")
+	}
+}
+
+func TestMarshallParameters(t *testing.T) {
+	template := convertYaml(t, `
+services:
+  test:
+    image: nginx
+`, useDefaultVPC)
+	parameters, err := marshallParameters(template, "json")
+	assert.NilError(t, err)
+	assert.Equal(t, string(parameters), "[]")
+
+	template.Parameters["Replicas"] = cloudformation.Parameter{Type: "Number", Default: 2}
+	template.Parameters["Environment"] = cloudformation.Parameter{Type: "String"}
+	parameters, err = marshallParameters(template, "json")
+	assert.NilError(t, err)
+	assert.Equal(t, string(parameters), `[
+  {
+    "ParameterKey": "Environment",
+    "ParameterValue": ""
+  },
+  {
+    "ParameterKey": "Replicas",
+    "ParameterValue": "2"
+  }
+]`)
+}
+
 func TestLogging(t *testing.T) {
 	template := convertYaml(t, `
 services:
@@ -406,8 +443,8 @@ volumes:
         provisioned_throughput: 1024
 `, useDefaultVPC, func(m *MockAPIMockRecorder) {
 		m.ListFileSystems(gomock.Any(), map[string]string{
-			compose.ProjectTag: t.Name(),
-			compose.VolumeTag:  "db-data",
+			compose.ProjectTag(): t.Name(),
+			compose.VolumeTag():  "db-data",
 		}).Return(nil, nil)
 	})
 	n := volumeResourceName("db-data")
@@ -452,8 +489,8 @@ volumes:
   db-data: {}
 `, useDefaultVPC, func(m *MockAPIMockRecorder) {
 		m.ListFileSystems(gomock.Any(), map[string]string{
-			compose.ProjectTag: t.Name(),
-			compose.VolumeTag:  "db-data",
+			compose.ProjectTag(): t.Name(),
+			compose.VolumeTag():  "db-data",
 		}).Return([]awsResource{
 			existingAWSResource{
 				id: "fs-123abc",
@@ -521,7 +558,7 @@ services:
 		for i := 0; i < tags.Len(); i++ {
 			k := tags.Index(i).FieldByName("Key").String()
 			v := tags.Index(i).FieldByName("Value").String()
-			if k == compose.ProjectTag {
+			if k == compose.ProjectTag() {
 				assert.Equal(t, v, t.Name())
 			}
 		}
@@ -17,6 +17,7 @@
 package ecs
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/compose-spec/compose-go/compatibility"
@@ -26,12 +27,7 @@ import (
 )
 
 func (b *ecsAPIService) checkCompatibility(project *types.Project) error {
-	var checker compatibility.Checker = &fargateCompatibilityChecker{
-		AllowList: compatibility.AllowList{
-			Supported: compatibleComposeAttributes,
-		},
-		projet: project,
-	}
+	checker := newFargateCompatibilityChecker(project)
 	compatibility.Check(project, checker)
 	for _, err := range checker.Errors() {
 		if errdefs.IsIncompatibleError(err) {
@@ -45,11 +41,30 @@ func (b *ecsAPIService) checkCompatibility(project *types.Project) error {
 	return nil
 }
 
+func (b *ecsAPIService) Unsupported(ctx context.Context, project *types.Project) ([]string, error) {
+	checker := newFargateCompatibilityChecker(project)
+	compatibility.Check(project, checker)
+	var unsupported []string
+	for _, err := range checker.Errors() {
+		unsupported = append(unsupported, err.Error())
+	}
+	return unsupported, nil
+}
+
 type fargateCompatibilityChecker struct {
 	compatibility.AllowList
 	projet *types.Project
 }
 
+func newFargateCompatibilityChecker(project *types.Project) *fargateCompatibilityChecker {
+	return &fargateCompatibilityChecker{
+		AllowList: compatibility.AllowList{
+			Supported: compatibleComposeAttributes,
+		},
+		projet: project,
+	}
+}
+
 var compatibleComposeAttributes = []string{
 	"services.command",
 	"services.container_name",
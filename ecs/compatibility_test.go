@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestUnsupportedFeatures(t *testing.T) {
+	project := loadConfig(t, `
+services:
+  test:
+    image: nginx
+    privileged: true
+    ports:
+      - 8080:80
+`)
+	backend := &ecsAPIService{}
+	unsupported, err := backend.Unsupported(context.TODO(), project)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, unsupported, []string{
+		"published port can't be set to a distinct value than container port: incompatible attribute",
+		"services.privileged: unsupported attribute",
+	})
+}
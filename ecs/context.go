@@ -27,6 +27,7 @@ import (
 	"github.com/docker/compose-cli/context/store"
 	"github.com/docker/compose-cli/errdefs"
 	"github.com/docker/compose-cli/prompt"
+	"github.com/docker/compose-cli/utils/httpclient"
 
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/aws/aws-sdk-go/aws"
@@ -357,6 +358,7 @@ func listAvailableRegions(opts *ContextParams) ([]string, error) {
 		Config: aws.Config{
 			Credentials: credentials.NewStaticCredentials(opts.AccessKey, opts.SecretKey, ""),
 			Region:      aws.String("us-east-1"),
+			HTTPClient:  httpclient.New(),
 		},
 	})
 	if err != nil {
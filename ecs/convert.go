@@ -174,7 +174,7 @@ func toTaskResourceRequirements(reservations *types.Resource) []ecs.TaskDefiniti
 	}
 	var requirements []ecs.TaskDefinition_ResourceRequirement
 	for _, r := range reservations.GenericResources {
-		if r.DiscreteResourceSpec.Kind == "gpus" {
+		if r.DiscreteResourceSpec != nil && r.DiscreteResourceSpec.Kind == "gpus" {
 			requirements = append(requirements, ecs.TaskDefinition_ResourceRequirement{
 				Type:  ecsapi.ResourceTypeGpu,
 				Value: fmt.Sprint(r.DiscreteResourceSpec.Value),
@@ -326,6 +326,9 @@ func toSystemControls(sysctls types.Mapping) []ecs.TaskDefinition_SystemControl
 			Value:     v,
 		})
 	}
+	sort.Slice(sys, func(i, j int) bool {
+		return sys[i].Namespace < sys[j].Namespace
+	})
 	return sys
 }
 
@@ -463,6 +466,9 @@ func toUlimits(ulimits map[string]*types.UlimitsConfig) []ecs.TaskDefinition_Uli
 			HardLimit: v.Hard,
 		})
 	}
+	sort.Slice(u, func(i, j int) bool {
+		return u[i].Name < u[j].Name
+	})
 	return u
 }
 
@@ -558,7 +564,7 @@ func gpuRequirements(s types.ServiceConfig) int64 {
 	if deploy := s.Deploy; deploy != nil {
 		if reservations := deploy.Resources.Reservations; reservations != nil {
 			for _, resource := range reservations.GenericResources {
-				if resource.DiscreteResourceSpec.Kind == "gpus" {
+				if resource.DiscreteResourceSpec != nil && resource.DiscreteResourceSpec.Kind == "gpus" {
 					return resource.DiscreteResourceSpec.Value
 				}
 			}
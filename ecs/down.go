@@ -19,10 +19,17 @@ package ecs
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
 	"github.com/docker/compose-cli/progress"
 )
 
-func (b *ecsAPIService) Down(ctx context.Context, project string) error {
+func (b *ecsAPIService) Down(ctx context.Context, project string, options compose.DownOptions) error {
+	if !options.CreatedSince.IsZero() {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ECS can only delete the whole stack")
+	}
 	resources, err := b.aws.ListStackResources(ctx, project)
 	if err != nil {
 		return err
@@ -50,6 +57,14 @@ func (b *ecsAPIService) Down(ctx context.Context, project string) error {
 	return b.WaitStackCompletion(ctx, project, stackDelete, previousEvents...)
 }
 
+func (b *ecsAPIService) Stop(ctx context.Context, projectName string, options compose.StopOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Restart(ctx context.Context, projectName string, options compose.RestartOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
 func (b *ecsAPIService) previousStackEvents(ctx context.Context, project string) ([]string, error) {
 	events, err := b.aws.DescribeStackEvents(ctx, project)
 	if err != nil {
@@ -178,7 +178,7 @@ func toResourceRequirements(service types.ServiceConfig) (*resourceRequirements,
 
 	var requiredGPUs int64
 	for _, r := range reservations.GenericResources {
-		if r.DiscreteResourceSpec.Kind == "gpus" {
+		if r.DiscreteResourceSpec != nil && r.DiscreteResourceSpec.Kind == "gpus" {
 			requiredGPUs = r.DiscreteResourceSpec.Value
 			break
 		}
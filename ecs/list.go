@@ -23,7 +23,7 @@ import (
 	"github.com/docker/compose-cli/api/compose"
 )
 
-func (b *ecsAPIService) List(ctx context.Context, project string) ([]compose.Stack, error) {
+func (b *ecsAPIService) List(ctx context.Context, project string, options compose.ListOptions) ([]compose.Stack, error) {
 	stacks, err := b.aws.ListStacks(ctx, project)
 	if err != nil {
 		return nil, err
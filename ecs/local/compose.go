@@ -39,19 +39,19 @@ import (
 	"github.com/docker/compose-cli/errdefs"
 )
 
-func (e ecsLocalSimulation) Build(ctx context.Context, project *types.Project) error {
+func (e ecsLocalSimulation) Build(ctx context.Context, project *types.Project, opts compose.BuildOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (e ecsLocalSimulation) Push(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (e ecsLocalSimulation) Push(ctx context.Context, project *types.Project, opts compose.PushOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (e ecsLocalSimulation) Pull(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (e ecsLocalSimulation) Pull(ctx context.Context, project *types.Project, opts compose.PullOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (e ecsLocalSimulation) Create(ctx context.Context, project *types.Project) error {
+func (e ecsLocalSimulation) Create(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
@@ -59,7 +59,7 @@ func (e ecsLocalSimulation) Start(ctx context.Context, project *types.Project, c
 	return errdefs.ErrNotImplemented
 }
 
-func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project, detach bool) error {
+func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 
 	cmd := exec.Command("docker-compose", "version", "--short")
 	b := bytes.Buffer{}
@@ -77,7 +77,7 @@ func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project, deta
 		return fmt.Errorf("ECS simulation mode require Docker-compose 1.27, found %s", version)
 	}
 
-	converted, err := e.Convert(ctx, project, "json")
+	converted, err := e.Convert(ctx, project, compose.ConvertOptions{Format: "json"})
 	if err != nil {
 		return err
 	}
@@ -89,7 +89,10 @@ func (e ecsLocalSimulation) Up(ctx context.Context, project *types.Project, deta
 	return cmd.Run()
 }
 
-func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
+func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project, options compose.ConvertOptions) ([]byte, error) {
+	if options.Parameters {
+		return nil, errors.Wrap(errdefs.ErrNotImplemented, "ECS simulation mode doesn't deploy with parameters")
+	}
 	project.Networks["credentials_network"] = types.NetworkConfig{
 		Driver: "bridge",
 		Ipam: types.IPAMConfig{
@@ -157,18 +160,21 @@ func (e ecsLocalSimulation) Convert(ctx context.Context, project *types.Project,
 		"secrets":  project.Secrets,
 		"configs":  project.Configs,
 	}
-	switch format {
+	switch options.Format {
 	case "json":
 		return json.MarshalIndent(config, "", "  ")
 	case "yaml":
 		return yaml.Marshal(config)
 	default:
-		return nil, fmt.Errorf("unsupported format %q", format)
+		return nil, fmt.Errorf("unsupported format %q", options.Format)
 	}
 
 }
 
-func (e ecsLocalSimulation) Down(ctx context.Context, projectName string) error {
+func (e ecsLocalSimulation) Down(ctx context.Context, projectName string, options compose.DownOptions) error {
+	if !options.CreatedSince.IsZero() {
+		return errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose down")
+	}
 	cmd := exec.Command("docker-compose", "--context", "default", "--project-name", projectName, "-f", "-", "down", "--remove-orphans")
 	cmd.Stdin = strings.NewReader(string(`
 services:
@@ -180,16 +186,27 @@ services:
 	return cmd.Run()
 }
 
-func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer) error {
+func (e ecsLocalSimulation) Stop(ctx context.Context, projectName string, options compose.StopOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Restart(ctx context.Context, projectName string, options compose.RestartOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	if !options.Follow || options.Since != "" {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ECS simulation mode only follows logs, use docker-compose logs")
+	}
 	list, err := e.moby.ContainerList(ctx, types2.ContainerListOptions{
-		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+projectName)),
+		Filters: filters.NewArgs(filters.Arg("label", compose.ProjectTag()+"="+projectName)),
 	})
 	if err != nil {
 		return err
 	}
 	services := map[string]types.ServiceConfig{}
 	for _, c := range list {
-		services[c.Labels["com.docker.compose.service"]] = types.ServiceConfig{
+		services[c.Labels[compose.ServiceTag()]] = types.ServiceConfig{
 			Image: "unused",
 		}
 	}
@@ -210,6 +227,58 @@ func (e ecsLocalSimulation) Logs(ctx context.Context, projectName string, consum
 func (e ecsLocalSimulation) Ps(ctx context.Context, projectName string) ([]compose.ContainerSummary, error) {
 	return nil, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose ps")
 }
-func (e ecsLocalSimulation) List(ctx context.Context, projectName string) ([]compose.Stack, error) {
+func (e ecsLocalSimulation) List(ctx context.Context, projectName string, options compose.ListOptions) ([]compose.Stack, error) {
 	return nil, errors.Wrap(errdefs.ErrNotImplemented, "use docker-compose ls")
 }
+
+func (e ecsLocalSimulation) Health(ctx context.Context, projectName string, service string, index int) ([]compose.ContainerHealth, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Exec(ctx context.Context, project *types.Project, opts compose.ExecOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Events(ctx context.Context, projectName string, options compose.EventsOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) ResolveImageDigests(ctx context.Context, project *types.Project) (map[string]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Divergences(ctx context.Context, project *types.Project) ([]compose.ContainerDivergence, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Images(ctx context.Context, project *types.Project) ([]compose.ImageSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) RestartFailed(ctx context.Context, projectName string, opts compose.RestartFailedOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Stats(ctx context.Context, projectName string) (map[string]compose.ContainerStats, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Snapshot(ctx context.Context, projectName string, opts compose.SnapshotOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Restore(ctx context.Context, opts compose.RestoreOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Commit(ctx context.Context, projectName string, opts compose.CommitOptions) (string, error) {
+	return "", errdefs.ErrNotImplemented
+}
+
+func (e ecsLocalSimulation) Unsupported(ctx context.Context, project *types.Project) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
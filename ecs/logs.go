@@ -19,10 +19,27 @@ package ecs
 import (
 	"context"
 
+	"github.com/pkg/errors"
+
 	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
 )
 
-func (b *ecsAPIService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer) error {
+func (b *ecsAPIService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	if err := checkLogOptions(options); err != nil {
+		return err
+	}
 	err := b.aws.GetLogs(ctx, projectName, consumer.Log)
 	return err
 }
+
+// checkLogOptions rejects log options ECS can't honor, as logs are always streamed from CloudWatch as they come
+func checkLogOptions(options compose.LogOptions) error {
+	if !options.Follow {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ECS can only follow logs, --follow=false is not supported")
+	}
+	if options.Since != "" {
+		return errors.Wrap(errdefs.ErrNotImplemented, "ECS doesn't support --since")
+	}
+	return nil
+}
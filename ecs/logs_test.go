@@ -0,0 +1,38 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
+)
+
+func TestCheckLogOptions(t *testing.T) {
+	assert.NilError(t, checkLogOptions(compose.LogOptions{Follow: true}))
+
+	err := checkLogOptions(compose.LogOptions{})
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "--follow=false is not supported")
+
+	err = checkLogOptions(compose.LogOptions{Follow: true, Since: "42m"})
+	assert.Assert(t, errdefs.IsErrNotImplemented(err))
+	assert.ErrorContains(t, err, "doesn't support --since")
+}
@@ -19,6 +19,7 @@ package ecs
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/awslabs/goformation/v4/cloudformation"
@@ -56,23 +57,74 @@ func marshall(template *cloudformation.Template, format string) ([]byte, error)
 		return nil, fmt.Errorf("invalid JSON: %s", err)
 	}
 
-	if input, ok := unmarshalled.(map[interface{}]interface{}); ok {
-		if resources, ok := input["Resources"]; ok {
-			for _, uresource := range resources.(map[interface{}]interface{}) {
-				if resource, ok := uresource.(map[interface{}]interface{}); ok {
-					if resource["Type"] == "AWS::ECS::TaskDefinition" {
-						properties := resource["Properties"].(map[interface{}]interface{})
-						for _, def := range properties["ContainerDefinitions"].([]interface{}) {
-							containerDefinition := def.(map[interface{}]interface{})
-							if strings.HasSuffix(containerDefinition["Name"].(string), "_InitContainer") {
-								containerDefinition["Essential"] = false
-							}
-						}
-					}
-				}
+	// goformation omits false booleans, so init containers have to be marked as non essential once marshalled
+	for _, resource := range entries(entries(unmarshalled)["Resources"]) {
+		resource := entries(resource)
+		if resource["Type"] != "AWS::ECS::TaskDefinition" {
+			continue
+		}
+		definitions, _ := entries(resource["Properties"])["ContainerDefinitions"].([]interface{})
+		for _, definition := range definitions {
+			if name, ok := entries(definition)["Name"].(string); ok && strings.HasSuffix(name, "_InitContainer") {
+				setEntry(definition, "Essential", false)
 			}
 		}
 	}
 
 	return marshal(unmarshalled)
 }
+
+// stackParameter is a CloudFormation stack parameter value, in the format `aws cloudformation` commands accept
+type stackParameter struct {
+	ParameterKey   string
+	ParameterValue string
+}
+
+// marshallParameters marshalls the parameter values a stack is deployed with. As no value is passed on deployment,
+// those are the template parameters defaults
+func marshallParameters(template *cloudformation.Template, format string) ([]byte, error) {
+	parameters := []stackParameter{}
+	for key, parameter := range template.Parameters {
+		value := ""
+		if parameter.Default != nil {
+			value = fmt.Sprint(parameter.Default)
+		}
+		parameters = append(parameters, stackParameter{ParameterKey: key, ParameterValue: value})
+	}
+	sort.Slice(parameters, func(i, j int) bool {
+		return parameters[i].ParameterKey < parameters[j].ParameterKey
+	})
+	switch format {
+	case "yaml":
+		return yaml.Marshal(parameters)
+	case "json":
+		return json.MarshalIndent(parameters, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// entries returns the entries of a map unmarshalled from JSON or YAML, indexed by their string key
+func entries(in interface{}) map[string]interface{} {
+	switch m := in.(type) {
+	case map[string]interface{}:
+		return m
+	case map[interface{}]interface{}:
+		out := map[string]interface{}{}
+		for k, v := range m {
+			out[fmt.Sprint(k)] = v
+		}
+		return out
+	}
+	return nil
+}
+
+// setEntry sets an entry of a map unmarshalled from JSON or YAML
+func setEntry(in interface{}, key string, value interface{}) {
+	switch m := in.(type) {
+	case map[string]interface{}:
+		m[key] = value
+	case map[interface{}]interface{}:
+		m[key] = value
+	}
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/pkg/errors"
+)
+
+// deploymentActions are the IAM actions a user needs to deploy a compose application as a CloudFormation stack
+var deploymentActions = []string{
+	"cloudformation:CreateStack",
+	"cloudformation:CreateChangeSet",
+	"cloudformation:DescribeStackEvents",
+	"ecs:CreateCluster",
+	"ecs:CreateService",
+	"ecs:RegisterTaskDefinition",
+	"ec2:CreateNetworkInterface",
+	"ec2:CreateSecurityGroup",
+	"ec2:AuthorizeSecurityGroupIngress",
+	"elasticloadbalancing:CreateLoadBalancer",
+	"elasticloadbalancing:CreateTargetGroup",
+	"elasticloadbalancing:CreateListener",
+	"logs:CreateLogGroup",
+	"iam:CreateRole",
+	"iam:PassRole",
+}
+
+func (b *ecsAPIService) checkPermissions(ctx context.Context) error {
+	denied, err := b.aws.CheckPermissions(ctx, deploymentActions)
+	if err != nil {
+		return errors.Wrap(err, "failed to check IAM permissions, use --skip-preflight to bypass this check")
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("missing IAM permissions to deploy application: %s", strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// principalArn converts the caller identity into an IAM principal which can be used to simulate policies.
+// Assumed roles (arn:aws:sts::123456789012:assumed-role/role/session) are converted into the IAM role ARN.
+func principalArn(identity string) (string, error) {
+	parsed, err := arn.Parse(identity)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Service != "sts" {
+		return identity, nil
+	}
+	parts := strings.Split(parsed.Resource, "/")
+	if len(parts) < 2 || parts[0] != "assumed-role" {
+		return "", fmt.Errorf("unsupported caller identity %q", identity)
+	}
+	return arn.ARN{
+		Partition: parsed.Partition,
+		Service:   "iam",
+		AccountID: parsed.AccountID,
+		Resource:  "role/" + parts[1],
+	}.String(), nil
+}
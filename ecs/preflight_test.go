@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+)
+
+func TestPrincipalArn(t *testing.T) {
+	principal, err := principalArn("arn:aws:iam::123456789012:user/jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, principal, "arn:aws:iam::123456789012:user/jdoe")
+
+	principal, err = principalArn("arn:aws:sts::123456789012:assumed-role/developer/session")
+	assert.NilError(t, err)
+	assert.Equal(t, principal, "arn:aws:iam::123456789012:role/developer")
+
+	_, err = principalArn("arn:aws:sts::123456789012:federated-user/jdoe")
+	assert.ErrorContains(t, err, "unsupported caller identity")
+}
+
+func TestCheckPermissionsReportsDeniedActions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := NewMockAPI(ctrl)
+	m.EXPECT().CheckPermissions(gomock.Any(), deploymentActions).Return([]string{"iam:PassRole", "logs:CreateLogGroup"}, nil)
+	backend := &ecsAPIService{
+		aws: m,
+	}
+	err := backend.checkPermissions(context.TODO())
+	assert.Error(t, err, "missing IAM permissions to deploy application: iam:PassRole, logs:CreateLogGroup")
+}
@@ -19,7 +19,10 @@ package ecs
 import (
 	"context"
 
+	"github.com/compose-spec/compose-go/types"
+
 	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/errdefs"
 )
 
 func (b *ecsAPIService) Ps(ctx context.Context, project string) ([]compose.ContainerSummary, error) {
@@ -56,3 +59,35 @@ func (b *ecsAPIService) Ps(ctx context.Context, project string) ([]compose.Conta
 	}
 	return summary, nil
 }
+
+func (b *ecsAPIService) Health(ctx context.Context, projectName string, service string, index int) ([]compose.ContainerHealth, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Divergences(ctx context.Context, project *types.Project) ([]compose.ContainerDivergence, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Images(ctx context.Context, project *types.Project) ([]compose.ImageSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) RestartFailed(ctx context.Context, projectName string, opts compose.RestartFailedOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Stats(ctx context.Context, projectName string) (map[string]compose.ContainerStats, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Snapshot(ctx context.Context, projectName string, opts compose.SnapshotOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Restore(ctx context.Context, opts compose.RestoreOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Commit(ctx context.Context, projectName string, opts compose.CommitOptions) (string, error) {
+	return "", errdefs.ErrNotImplemented
+}
@@ -57,6 +57,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-uuid"
 	"github.com/pkg/errors"
@@ -75,6 +77,7 @@ type sdk struct {
 	SSM      ssmiface.SSMAPI
 	AG       autoscalingiface.AutoScalingAPI
 	S3       s3iface.S3API
+	STS      stsiface.STSAPI
 	uploader *s3manager.Uploader
 }
 
@@ -97,6 +100,7 @@ func newSDK(sess *session.Session) sdk {
 		SSM:      ssm.New(sess),
 		AG:       autoscaling.New(sess),
 		S3:       s3.New(sess),
+		STS:      sts.New(sess),
 		uploader: s3manager.NewUploader(sess),
 	}
 }
@@ -118,6 +122,31 @@ func (s sdk) CheckRequirements(ctx context.Context, region string) error {
 	return nil
 }
 
+func (s sdk) CheckPermissions(ctx context.Context, actions []string) ([]string, error) {
+	identity, err := s.STS.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, err
+	}
+	principal, err := principalArn(aws.StringValue(identity.Arn))
+	if err != nil {
+		return nil, err
+	}
+	logrus.Debugf("Simulate IAM policies for %s", principal)
+	var denied []string
+	err = s.IAM.SimulatePrincipalPolicyPagesWithContext(ctx, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principal),
+		ActionNames:     aws.StringSlice(actions),
+	}, func(response *iam.SimulatePolicyResponse, lastPage bool) bool {
+		for _, result := range response.EvaluationResults {
+			if aws.StringValue(result.EvalDecision) != iam.PolicyEvaluationDecisionTypeAllowed {
+				denied = append(denied, aws.StringValue(result.EvalActionName))
+			}
+		}
+		return true
+	})
+	return denied, err
+}
+
 func (s sdk) ResolveCluster(ctx context.Context, nameOrArn string) (awsResource, error) {
 	logrus.Debug("CheckRequirements if cluster was already created: ", nameOrArn)
 	clusters, err := s.ECS.DescribeClustersWithContext(ctx, &ecs.DescribeClustersInput{
@@ -314,7 +343,7 @@ func (s sdk) CreateStack(ctx context.Context, name string, region string, templa
 			},
 			Tags: []*cloudformation.Tag{
 				{
-					Key:   aws.String(compose.ProjectTag),
+					Key:   aws.String(compose.ProjectTag()),
 					Value: aws.String(name),
 				},
 			},
@@ -429,7 +458,7 @@ func (s sdk) ListStacks(ctx context.Context, name string) ([]compose.Stack, erro
 	stacks := []compose.Stack{}
 	for _, stack := range cfStacks.Stacks {
 		for _, t := range stack.Tags {
-			if *t.Key == compose.ProjectTag {
+			if *t.Key == compose.ProjectTag() {
 				status := compose.RUNNING
 				switch aws.StringValue(stack.StackStatus) {
 				case "CREATE_IN_PROGRESS":
@@ -793,12 +822,12 @@ func (s sdk) DescribeService(ctx context.Context, cluster string, arn string) (c
 	service := services.Services[0]
 	var name string
 	for _, t := range service.Tags {
-		if *t.Key == compose.ServiceTag {
+		if *t.Key == compose.ServiceTag() {
 			name = aws.StringValue(t.Value)
 		}
 	}
 	if name == "" {
-		return compose.ServiceStatus{}, fmt.Errorf("service %s doesn't have a %s tag", *service.ServiceArn, compose.ServiceTag)
+		return compose.ServiceStatus{}, fmt.Errorf("service %s doesn't have a %s tag", *service.ServiceArn, compose.ServiceTag())
 	}
 	targetGroupArns := []string{}
 	for _, lb := range service.LoadBalancers {
@@ -852,9 +881,9 @@ func (s sdk) DescribeServiceTasks(ctx context.Context, cluster string, project s
 			var service string
 			for _, tag := range t.Tags {
 				switch aws.StringValue(tag.Key) {
-				case compose.ProjectTag:
+				case compose.ProjectTag():
 					project = aws.StringValue(tag.Value)
-				case compose.ServiceTag:
+				case compose.ServiceTag():
 					service = aws.StringValue(tag.Value)
 				}
 			}
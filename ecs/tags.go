@@ -25,7 +25,7 @@ import (
 func projectTags(project *types.Project) []tags.Tag {
 	return []tags.Tag{
 		{
-			Key:   compose.ProjectTag,
+			Key:   compose.ProjectTag(),
 			Value: project.Name,
 		},
 	}
@@ -34,11 +34,11 @@ func projectTags(project *types.Project) []tags.Tag {
 func serviceTags(project *types.Project, service types.ServiceConfig) []tags.Tag {
 	return []tags.Tag{
 		{
-			Key:   compose.ProjectTag,
+			Key:   compose.ProjectTag(),
 			Value: project.Name,
 		},
 		{
-			Key:   compose.ServiceTag,
+			Key:   compose.ServiceTag(),
 			Value: service.Name,
 		},
 	}
@@ -47,11 +47,11 @@ func serviceTags(project *types.Project, service types.ServiceConfig) []tags.Tag
 func networkTags(project *types.Project, net types.NetworkConfig) []tags.Tag {
 	return []tags.Tag{
 		{
-			Key:   compose.ProjectTag,
+			Key:   compose.ProjectTag(),
 			Value: project.Name,
 		},
 		{
-			Key:   compose.NetworkTag,
+			Key:   compose.NetworkTag(),
 			Value: net.Name,
 		},
 	}
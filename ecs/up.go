@@ -29,19 +29,19 @@ import (
 	"github.com/compose-spec/compose-go/types"
 )
 
-func (b *ecsAPIService) Build(ctx context.Context, project *types.Project) error {
+func (b *ecsAPIService) Build(ctx context.Context, project *types.Project, opts compose.BuildOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (b *ecsAPIService) Push(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (b *ecsAPIService) Push(ctx context.Context, project *types.Project, opts compose.PushOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (b *ecsAPIService) Pull(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (b *ecsAPIService) Pull(ctx context.Context, project *types.Project, opts compose.PullOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (b *ecsAPIService) Create(ctx context.Context, project *types.Project) error {
+func (b *ecsAPIService) Create(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
@@ -49,14 +49,21 @@ func (b *ecsAPIService) Start(ctx context.Context, project *types.Project, consu
 	return errdefs.ErrNotImplemented
 }
 
-func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, detach bool) error {
+func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 
 	err := b.aws.CheckRequirements(ctx, b.Region)
 	if err != nil {
 		return err
 	}
 
-	template, err := b.Convert(ctx, project, "yaml")
+	if !options.SkipPreflight {
+		err = b.checkPermissions(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	template, err := b.Convert(ctx, project, compose.ConvertOptions{Format: "yaml"})
 	if err != nil {
 		return err
 	}
@@ -82,7 +89,7 @@ func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, detach b
 			return err
 		}
 	}
-	if detach {
+	if options.Detach {
 		return nil
 	}
 	signalChan := make(chan os.Signal, 1)
@@ -90,9 +97,25 @@ func (b *ecsAPIService) Up(ctx context.Context, project *types.Project, detach b
 	go func() {
 		<-signalChan
 		fmt.Println("user interrupted deployment. Deleting stack...")
-		b.Down(ctx, project.Name) // nolint:errcheck
+		b.Down(ctx, project.Name, compose.DownOptions{}) // nolint:errcheck
 	}()
 
 	err = b.WaitStackCompletion(ctx, project.Name, operation)
 	return err
 }
+
+func (b *ecsAPIService) Exec(ctx context.Context, project *types.Project, opts compose.ExecOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) Events(ctx context.Context, projectName string, options compose.EventsOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (b *ecsAPIService) ResolveImageDigests(ctx context.Context, project *types.Project) (map[string]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
@@ -63,11 +63,11 @@ func (b *ecsAPIService) createAccessPoints(project *types.Project, r awsResource
 		ap := efs.AccessPoint{
 			AccessPointTags: []efs.AccessPoint_AccessPointTag{
 				{
-					Key:   compose.ProjectTag,
+					Key:   compose.ProjectTag(),
 					Value: project.Name,
 				},
 				{
-					Key:   compose.VolumeTag,
+					Key:   compose.VolumeTag(),
 					Value: name,
 				},
 				{
@@ -137,20 +137,20 @@ func (cs *containerService) Delete(ctx context.Context, id string, request conta
 
 type composeService struct{}
 
-func (cs *composeService) Build(ctx context.Context, project *types.Project) error {
+func (cs *composeService) Build(ctx context.Context, project *types.Project, opts compose.BuildOptions) error {
 	fmt.Printf("Build command on project %q", project.Name)
 	return nil
 }
 
-func (cs *composeService) Push(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (cs *composeService) Push(ctx context.Context, project *types.Project, opts compose.PushOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (cs *composeService) Pull(ctx context.Context, project *types.Project) error {
-	return errdefs.ErrNotImplemented
+func (cs *composeService) Pull(ctx context.Context, project *types.Project, opts compose.PullOptions) (compose.TransferSummary, error) {
+	return compose.TransferSummary{}, errdefs.ErrNotImplemented
 }
 
-func (cs *composeService) Create(ctx context.Context, project *types.Project) error {
+func (cs *composeService) Create(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
@@ -158,27 +158,87 @@ func (cs *composeService) Start(ctx context.Context, project *types.Project, con
 	return errdefs.ErrNotImplemented
 }
 
-func (cs *composeService) Up(ctx context.Context, project *types.Project, detach bool) error {
+func (cs *composeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 
 	fmt.Printf("Up command on project %q", project.Name)
 	return nil
 }
 
-func (cs *composeService) Down(ctx context.Context, project string) error {
+func (cs *composeService) Down(ctx context.Context, project string, options compose.DownOptions) error {
 	fmt.Printf("Down command on project %q", project)
 	return nil
 }
 
+func (cs *composeService) Stop(ctx context.Context, projectName string, options compose.StopOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Restart(ctx context.Context, projectName string, options compose.RestartOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
 func (cs *composeService) Ps(ctx context.Context, projectName string) ([]compose.ContainerSummary, error) {
 	return nil, errdefs.ErrNotImplemented
 }
-func (cs *composeService) List(ctx context.Context, project string) ([]compose.Stack, error) {
+func (cs *composeService) List(ctx context.Context, project string, options compose.ListOptions) ([]compose.Stack, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+func (cs *composeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
+	return errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Convert(ctx context.Context, project *types.Project, options compose.ConvertOptions) ([]byte, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Health(ctx context.Context, projectName string, service string, index int) ([]compose.ContainerHealth, error) {
 	return nil, errdefs.ErrNotImplemented
 }
-func (cs *composeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer) error {
+
+func (cs *composeService) Exec(ctx context.Context, project *types.Project, opts compose.ExecOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Events(ctx context.Context, projectName string, options compose.EventsOptions) error {
 	return errdefs.ErrNotImplemented
 }
 
-func (cs *composeService) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
+func (cs *composeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	return 0, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) ResolveImageDigests(ctx context.Context, project *types.Project) (map[string]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Divergences(ctx context.Context, project *types.Project) ([]compose.ContainerDivergence, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Images(ctx context.Context, project *types.Project) ([]compose.ImageSummary, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) RestartFailed(ctx context.Context, projectName string, opts compose.RestartFailedOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Stats(ctx context.Context, projectName string) (map[string]compose.ContainerStats, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Snapshot(ctx context.Context, projectName string, opts compose.SnapshotOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Restore(ctx context.Context, opts compose.RestoreOptions) ([]string, error) {
+	return nil, errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Commit(ctx context.Context, projectName string, opts compose.CommitOptions) (string, error) {
+	return "", errdefs.ErrNotImplemented
+}
+
+func (cs *composeService) Unsupported(ctx context.Context, project *types.Project) ([]string, error) {
 	return nil, errdefs.ErrNotImplemented
 }
@@ -0,0 +1,60 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Command docker-compose-summary is an example of compose plugin. Once built as `docker-compose-summary` in PATH or in
+// the compose-plugins directory of docker config directory, `docker compose summary` runs it. It gets the resolved
+// project as JSON on stdin, and prints its services with their image.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// project is the part of the resolved project this plugin reads, services being keyed by name
+type project struct {
+	Name     string
+	Services map[string]types.ServiceConfig `json:"services"`
+}
+
+func main() {
+	var project project
+	if err := json.NewDecoder(os.Stdin).Decode(&project); err != nil {
+		fmt.Fprintf(os.Stderr, "can't read project: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Project %s in context %s\n", project.Name, os.Getenv("DOCKER_CONTEXT"))
+	if len(os.Args) > 1 {
+		fmt.Printf("Arguments: %s\n", strings.Join(os.Args[1:], " "))
+	}
+	var names []string
+	for name := range project.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		image := project.Services[name].Image
+		if image == "" {
+			image = "(built)"
+		}
+		fmt.Printf("%s\t%s\n", name, image)
+	}
+}
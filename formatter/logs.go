@@ -19,6 +19,7 @@ package formatter
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -27,13 +28,34 @@ import (
 	"github.com/docker/compose-cli/api/compose"
 )
 
+const (
+	// ColorByService gives all containers of a service the same color
+	ColorByService = "service"
+	// ColorByContainer gives each container its own color, so service replicas can be told apart
+	ColorByContainer = "container"
+)
+
 // NewLogConsumer creates a new LogConsumer
 func NewLogConsumer(ctx context.Context, w io.Writer) compose.LogConsumer {
+	return NewColoredLogConsumer(ctx, w, ColorByService, true)
+}
+
+// NewColoredLogConsumer creates a new LogConsumer picking a color per service or per container, or no color at all
+func NewColoredLogConsumer(ctx context.Context, w io.Writer, colorBy string, color bool) compose.LogConsumer {
+	return newLogConsumer(ctx, w, colorBy, color)
+}
+
+func newLogConsumer(ctx context.Context, w io.Writer, colorBy string, color bool) *logConsumer {
 	return &logConsumer{
-		ctx:    ctx,
-		colors: map[string]colorFunc{},
-		width:  0,
-		writer: w,
+		ctx:      ctx,
+		colors:   map[string]colorFunc{},
+		services: map[string]bool{},
+		replicas: map[string]string{},
+		exited:   map[string]string{},
+		colorBy:  colorBy,
+		noColor:  !color,
+		width:    0,
+		writer:   w,
 	}
 }
 
@@ -42,13 +64,19 @@ func (l *logConsumer) Log(service, container, message string) {
 	if l.ctx.Err() != nil {
 		return
 	}
-	cf, ok := l.colors[service]
-	if !ok {
-		cf = <-loop
-		l.colors[service] = cf
+	name := service
+	if replica, ok := l.replicas[container]; ok {
+		name = replica
+	}
+	if exited, ok := l.exited[container]; ok {
+		name = exited
+	}
+	if !l.services[name] {
+		l.services[name] = true
 		l.computeWidth()
 	}
-	prefix := fmt.Sprintf("%-"+strconv.Itoa(l.width)+"s |", service)
+	cf := l.colorFor(service, container)
+	prefix := fmt.Sprintf("%-"+strconv.Itoa(l.width)+"s |", name)
 
 	for _, line := range strings.Split(message, "\n") {
 		buf := bytes.NewBufferString(fmt.Sprintf("%s %s\n", cf(prefix), line))
@@ -56,9 +84,79 @@ func (l *logConsumer) Log(service, container, message string) {
 	}
 }
 
+// ContainerReplica marks logs of container with the service replica name it is
+func (l *logConsumer) ContainerReplica(container, name string) {
+	l.replicas[container] = name
+	l.services[name] = true
+	l.computeWidth()
+}
+
+// ContainerExited marks logs of container with the service replica name it was and its exited state
+func (l *logConsumer) ContainerExited(container, name string) {
+	name = fmt.Sprintf("%s (exited)", name)
+	l.exited[container] = name
+	l.services[name] = true
+	l.computeWidth()
+}
+
+// NewStreamLogConsumer creates a new LogConsumer marking each message with the stream it was written to
+func NewStreamLogConsumer(ctx context.Context, w io.Writer, colorBy string, color bool) compose.StreamLogConsumer {
+	return &streamLogConsumer{
+		logConsumer: newLogConsumer(ctx, w, colorBy, color),
+	}
+}
+
+// LogStream formats a log message as received from service/container, prefixed by stream
+func (l *streamLogConsumer) LogStream(service, container, stream, message string) {
+	l.Log(service, container, fmt.Sprintf("%s | %s", stream, message))
+}
+
+// NewJSONLogConsumer creates a new LogConsumer writing messages as JSON lines
+func NewJSONLogConsumer(ctx context.Context, w io.Writer) compose.StreamLogConsumer {
+	return &jsonLogConsumer{
+		ctx:     ctx,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Log writes a log message as received from service/container, with unknown stream
+func (l *jsonLogConsumer) Log(service, container, message string) {
+	l.LogStream(service, container, "", message)
+}
+
+// LogStream writes a log message as received from service/container stream
+func (l *jsonLogConsumer) LogStream(service, container, stream, message string) {
+	if l.ctx.Err() != nil {
+		return
+	}
+	_ = l.encoder.Encode(logEntry{
+		Service:   service,
+		Container: container,
+		Stream:    stream,
+		Message:   message,
+	})
+}
+
+// colorFor returns the color assigned to service, or to container when coloring by container
+func (l *logConsumer) colorFor(service, container string) colorFunc {
+	if l.noColor {
+		return noColor
+	}
+	key := service
+	if l.colorBy == ColorByContainer {
+		key = container
+	}
+	cf, ok := l.colors[key]
+	if !ok {
+		cf = <-loop
+		l.colors[key] = cf
+	}
+	return cf
+}
+
 func (l *logConsumer) computeWidth() {
 	width := 0
-	for n := range l.colors {
+	for n := range l.services {
 		if len(n) > width {
 			width = len(n)
 		}
@@ -68,8 +166,29 @@ func (l *logConsumer) computeWidth() {
 
 // LogConsumer consume logs from services and format them
 type logConsumer struct {
-	ctx    context.Context
-	colors map[string]colorFunc
-	width  int
-	writer io.Writer
+	ctx      context.Context
+	colors   map[string]colorFunc
+	services map[string]bool
+	replicas map[string]string
+	exited   map[string]string
+	colorBy  string
+	noColor  bool
+	width    int
+	writer   io.Writer
+}
+
+type streamLogConsumer struct {
+	*logConsumer
+}
+
+type jsonLogConsumer struct {
+	ctx     context.Context
+	encoder *json.Encoder
+}
+
+type logEntry struct {
+	Service   string `json:"service"`
+	Container string `json:"container"`
+	Stream    string `json:"stream,omitempty"`
+	Message   string `json:"message"`
 }
@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package formatter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestStreamLogConsumer(t *testing.T) {
+	out := &bytes.Buffer{}
+	consumer := NewStreamLogConsumer(context.Background(), out, ColorByService, true)
+	consumer.LogStream("web", "123", "stdout", "listening")
+	consumer.LogStream("web", "123", "stderr", "failure")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.HasSuffix(lines[0], " stdout | listening"), lines[0])
+	assert.Assert(t, strings.HasSuffix(lines[1], " stderr | failure"), lines[1])
+}
+
+func TestJSONLogConsumer(t *testing.T) {
+	out := &bytes.Buffer{}
+	consumer := NewJSONLogConsumer(context.Background(), out)
+	consumer.LogStream("web", "123", "stderr", `{"level":"warn"}`)
+	consumer.Log("web", "123", "no stream")
+
+	assert.Equal(t, out.String(), `{"service":"web","container":"123","stream":"stderr","message":"{\"level\":\"warn\"}"}
+{"service":"web","container":"123","message":"no stream"}
+`)
+}
+
+func TestColorByContainer(t *testing.T) {
+	out := &bytes.Buffer{}
+	consumer := NewColoredLogConsumer(context.Background(), out, ColorByContainer, true)
+	consumer.Log("web", "web_1", "hello")
+	consumer.Log("web", "web_2", "hello")
+	consumer.Log("web", "web_1", "again")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 3)
+	first, second := colorCode(lines[0]), colorCode(lines[1])
+	assert.Assert(t, first != "", lines[0])
+	assert.Assert(t, first != second, "replicas should get distinct colors: %q", out.String())
+	assert.Equal(t, colorCode(lines[2]), first)
+}
+
+func TestColorByService(t *testing.T) {
+	out := &bytes.Buffer{}
+	consumer := NewColoredLogConsumer(context.Background(), out, ColorByService, true)
+	consumer.Log("web", "web_1", "hello")
+	consumer.Log("web", "web_2", "hello")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Equal(t, colorCode(lines[0]), colorCode(lines[1]))
+}
+
+func TestNoColor(t *testing.T) {
+	out := &bytes.Buffer{}
+	consumer := NewColoredLogConsumer(context.Background(), out, ColorByContainer, false)
+	consumer.Log("web", "web_1", "hello")
+	consumer.Log("web", "web_2", "hello")
+
+	assert.Equal(t, out.String(), "web    | hello\nweb    | hello\n")
+}
+
+func TestExitedContainer(t *testing.T) {
+	out := &bytes.Buffer{}
+	consumer := NewColoredLogConsumer(context.Background(), out, ColorByService, false)
+	consumer.(compose.ExitedLogConsumer).ContainerExited("456", "web_1")
+	consumer.Log("web", "123", "running")
+	consumer.Log("web", "456", "stopped")
+
+	assert.Equal(t, out.String(), "web               | running\nweb_1 (exited)    | stopped\n")
+}
+
+func TestContainerReplica(t *testing.T) {
+	out := &bytes.Buffer{}
+	consumer := NewColoredLogConsumer(context.Background(), out, ColorByService, false)
+	rc := consumer.(compose.ReplicaLogConsumer)
+	rc.ContainerReplica("123", "web_1")
+	rc.ContainerReplica("456", "web_2")
+	rc.ContainerReplica("789", "db_1")
+	consumer.(compose.ExitedLogConsumer).ContainerExited("789", "db_1")
+	consumer.Log("web", "123", "first")
+	consumer.Log("web", "456", "second")
+	consumer.Log("db", "789", "stopped")
+
+	assert.Equal(t, out.String(), "web_1            | first\nweb_2            | second\ndb_1 (exited)    | stopped\n")
+}
+
+// colorCode extracts the ANSI color escape sequence a log line starts with
+func colorCode(line string) string {
+	if !strings.HasPrefix(line, "\033[") {
+		return ""
+	}
+	return line[:strings.Index(line, "m")+1]
+}
@@ -42,7 +42,8 @@ func init() {
 }
 
 func service(ctx context.Context) (backend.Service, error) {
-	apiClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	apiClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation(), local_compose.WithHTTPTimeout(),
+		local_compose.WithHealthStartInterval())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,130 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+)
+
+// apiFeature is an optional container configuration which requires a minimal engine API version
+type apiFeature struct {
+	name    string
+	version string
+	used    func(*container.Config, *container.HostConfig) bool
+	disable func(*container.Config, *container.HostConfig)
+}
+
+var apiFeatures = []apiFeature{
+	{
+		name:    "stop_grace_period",
+		version: "1.25",
+		used: func(c *container.Config, h *container.HostConfig) bool {
+			return c.StopTimeout != nil
+		},
+		disable: func(c *container.Config, h *container.HostConfig) {
+			c.StopTimeout = nil
+		},
+	},
+	{
+		name:    "init",
+		version: "1.25",
+		used: func(c *container.Config, h *container.HostConfig) bool {
+			return h.Init != nil
+		},
+		disable: func(c *container.Config, h *container.HostConfig) {
+			h.Init = nil
+		},
+	},
+	{
+		name:    "healthcheck start_period",
+		version: "1.29",
+		used: func(c *container.Config, h *container.HostConfig) bool {
+			return c.Healthcheck != nil && c.Healthcheck.StartPeriod != 0
+		},
+		disable: func(c *container.Config, h *container.HostConfig) {
+			c.Healthcheck.StartPeriod = 0
+		},
+	},
+	{
+		name:    "cpus",
+		version: "1.25",
+		used: func(c *container.Config, h *container.HostConfig) bool {
+			return h.NanoCPUs != 0
+		},
+		disable: func(c *container.Config, h *container.HostConfig) {
+			h.NanoCPUs = 0
+		},
+	},
+	{
+		name:    "pids_limit",
+		version: "1.23",
+		used: func(c *container.Config, h *container.HostConfig) bool {
+			return h.PidsLimit != nil
+		},
+		disable: func(c *container.Config, h *container.HostConfig) {
+			h.PidsLimit = nil
+		},
+	},
+}
+
+// downgradeForAPIVersion removes from container configuration the features engine API version doesn't support,
+// and returns warnings about those being ignored
+func downgradeForAPIVersion(version string, service string, config *container.Config, hostConfig *container.HostConfig) []string {
+	var warnings []string
+	for _, feature := range apiFeatures {
+		if !feature.used(config, hostConfig) || !versions.LessThan(version, feature.version) {
+			continue
+		}
+		feature.disable(config, hostConfig)
+		warnings = append(warnings, fmt.Sprintf("service %q: %s requires API %s, ignoring", service, feature.name, feature.version))
+	}
+	return warnings
+}
+
+// healthStartIntervalVersion is the first engine API version supporting healthcheck start_interval
+const healthStartIntervalVersion = "1.44"
+
+// dropHealthStartInterval removes healthcheck start_interval from services when engine API doesn't support it, so that
+// it neither makes containers diverge nor gets sent, and explains it's ignored
+func dropHealthStartInterval(version string, services types.Services) []string {
+	if !versions.LessThan(version, healthStartIntervalVersion) {
+		return nil
+	}
+	var warnings []string
+	for i, s := range services {
+		if s.HealthCheck == nil {
+			continue
+		}
+		if _, ok := s.HealthCheck.Extensions[extHealthStartInterval]; !ok {
+			continue
+		}
+		healthCheck := *s.HealthCheck
+		healthCheck.Extensions = map[string]interface{}{}
+		for name, value := range s.HealthCheck.Extensions {
+			if name != extHealthStartInterval {
+				healthCheck.Extensions[name] = value
+			}
+		}
+		services[i].HealthCheck = &healthCheck
+		warnings = append(warnings, fmt.Sprintf("service %q: healthcheck start_interval requires API %s, ignoring", s.Name, healthStartIntervalVersion))
+	}
+	return warnings
+}
@@ -0,0 +1,128 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+)
+
+func TestDowngradeForAPIVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		warnings []string
+	}{
+		{
+			version: "1.41",
+		},
+		{
+			version: "1.29",
+		},
+		{
+			version: "1.28",
+			warnings: []string{
+				`service "web": healthcheck start_period requires API 1.29, ignoring`,
+			},
+		},
+		{
+			version: "1.24",
+			warnings: []string{
+				`service "web": stop_grace_period requires API 1.25, ignoring`,
+				`service "web": init requires API 1.25, ignoring`,
+				`service "web": healthcheck start_period requires API 1.29, ignoring`,
+				`service "web": cpus requires API 1.25, ignoring`,
+			},
+		},
+		{
+			version: "1.22",
+			warnings: []string{
+				`service "web": stop_grace_period requires API 1.25, ignoring`,
+				`service "web": init requires API 1.25, ignoring`,
+				`service "web": healthcheck start_period requires API 1.29, ignoring`,
+				`service "web": cpus requires API 1.25, ignoring`,
+				`service "web": pids_limit requires API 1.23, ignoring`,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			stopTimeout := 10
+			init := true
+			pidsLimit := int64(100)
+			config := &container.Config{
+				StopTimeout: &stopTimeout,
+				Healthcheck: &container.HealthConfig{
+					Test:        []string{"CMD", "true"},
+					StartPeriod: time.Minute,
+				},
+			}
+			hostConfig := &container.HostConfig{
+				Init: &init,
+				Resources: container.Resources{
+					NanoCPUs:  500000000,
+					PidsLimit: &pidsLimit,
+				},
+			}
+
+			warnings := downgradeForAPIVersion(test.version, "web", config, hostConfig)
+			assert.DeepEqual(t, warnings, test.warnings)
+			for _, feature := range apiFeatures {
+				warning := fmt.Sprintf("service %q: %s requires API %s, ignoring", "web", feature.name, feature.version)
+				assert.Equal(t, feature.used(config, hostConfig), !contains(test.warnings, warning), feature.name)
+			}
+		})
+	}
+}
+
+func TestDowngradeForAPIVersionUnusedFeatures(t *testing.T) {
+	warnings := downgradeForAPIVersion("1.22", "web", &container.Config{}, &container.HostConfig{})
+	assert.Equal(t, len(warnings), 0)
+}
+
+func TestDropHealthStartInterval(t *testing.T) {
+	services := func() types.Services {
+		return types.Services{
+			{
+				Name: "web",
+				HealthCheck: &types.HealthCheckConfig{
+					Extensions: map[string]interface{}{extHealthStartInterval: "2s", "x-other": "value"},
+				},
+			},
+			{Name: "db"},
+		}
+	}
+	older := services()
+	assert.DeepEqual(t, dropHealthStartInterval("1.41", older), []string{`service "web": healthcheck start_interval requires API 1.44, ignoring`})
+	assert.DeepEqual(t, older[0].HealthCheck.Extensions, map[string]interface{}{"x-other": "value"})
+	dropped, err := serviceHash(older[0], "")
+	assert.NilError(t, err)
+	expected, err := serviceHash(types.ServiceConfig{
+		Name:        "web",
+		HealthCheck: &types.HealthCheckConfig{Extensions: map[string]interface{}{"x-other": "value"}},
+	}, "")
+	assert.NilError(t, err)
+	assert.Equal(t, dropped, expected)
+
+	supported := services()
+	assert.Assert(t, dropHealthStartInterval("1.44", supported) == nil)
+	assert.DeepEqual(t, supported, services())
+}
@@ -32,6 +32,32 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+// extAttach is the compose-spec `attach` service attribute, not known yet by compose-go. When false, service output
+// is excluded from attached up and from logs of the whole project
+const extAttach = "x-attach"
+
+// IsAttached tells if service output is shown by attached up and by logs of the whole project
+func IsAttached(service types.ServiceConfig) bool {
+	attach, ok := service.Extensions[extAttach].(bool)
+	return !ok || attach
+}
+
+// AttachOnly attaches output of the listed services only, regardless of their attach attribute
+func AttachOnly(project *types.Project, services []string) error {
+	for _, name := range services {
+		if _, err := project.GetService(name); err != nil {
+			return err
+		}
+	}
+	for i, service := range project.Services {
+		if service.Extensions == nil {
+			project.Services[i].Extensions = map[string]interface{}{}
+		}
+		project.Services[i].Extensions[extAttach] = contains(services, service.Name)
+	}
+	return nil
+}
+
 func (s *composeService) attach(ctx context.Context, project *types.Project, consumer compose.LogConsumer) (*errgroup.Group, error) {
 	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
 		Filters: filters.NewArgs(
@@ -43,14 +69,21 @@ func (s *composeService) attach(ctx context.Context, project *types.Project, con
 		return nil, err
 	}
 
-	var names []string
+	var (
+		attached []moby.Container
+		names    []string
+	)
 	for _, c := range containers {
+		if service, err := project.GetService(c.Labels[serviceLabel()]); err == nil && !IsAttached(service) {
+			continue
+		}
+		attached = append(attached, c)
 		names = append(names, getContainerName(c))
 	}
-	fmt.Printf("Attaching to %s\n", strings.Join(names, ", "))
+	fmt.Println(attachingMessage(names, project))
 
 	eg, ctx := errgroup.WithContext(ctx)
-	for _, c := range containers {
+	for _, c := range attached {
 		container := c
 		eg.Go(func() error {
 			return s.attachContainer(ctx, container, consumer, project)
@@ -59,8 +92,28 @@ func (s *composeService) attach(ctx context.Context, project *types.Project, con
 	return eg, nil
 }
 
+// attachingMessage lists containers up attaches to, and tells how many services are not attached so their missing
+// output doesn't come as a surprise
+func attachingMessage(names []string, project *types.Project) string {
+	message := fmt.Sprintf("Attaching to %s", strings.Join(names, ", "))
+	detached := 0
+	for _, service := range project.Services {
+		if !IsAttached(service) {
+			detached++
+		}
+	}
+	switch detached {
+	case 0:
+		return message
+	case 1:
+		return message + " (1 service not attached)"
+	default:
+		return fmt.Sprintf("%s (%d services not attached)", message, detached)
+	}
+}
+
 func (s *composeService) attachContainer(ctx context.Context, container moby.Container, consumer compose.LogConsumer, project *types.Project) error {
-	serviceName := container.Labels[serviceLabel]
+	serviceName := container.Labels[serviceLabel()]
 	w := getWriter(serviceName, container.ID, consumer)
 
 	service, err := project.GetService(serviceName)
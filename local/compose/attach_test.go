@@ -0,0 +1,45 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestAttachOnly(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web"},
+			{Name: "db", Extensions: map[string]interface{}{extAttach: false}},
+			{Name: "cache"},
+		},
+	}
+	assert.Assert(t, IsAttached(project.Services[0]))
+	assert.Assert(t, !IsAttached(project.Services[1]))
+	assert.Equal(t, attachingMessage([]string{"web_1", "cache_1"}, project), "Attaching to web_1, cache_1 (1 service not attached)")
+
+	assert.NilError(t, AttachOnly(project, []string{"db"}))
+	assert.Assert(t, !IsAttached(project.Services[0]))
+	assert.Assert(t, IsAttached(project.Services[1]))
+	assert.Assert(t, !IsAttached(project.Services[2]))
+	assert.Equal(t, attachingMessage([]string{"db_1"}, project), "Attaching to db_1 (2 services not attached)")
+
+	assert.ErrorContains(t, AttachOnly(project, []string{"unknown"}), "unknown")
+}
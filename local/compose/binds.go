@@ -0,0 +1,146 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+)
+
+// extCreateHostPath is the compose-spec `create_host_path` bind attribute, not known yet by compose-go. When true, a
+// missing bind source is created as a directory rather than being reported as an error. Short syntax binds don't set
+// it, as their host path is always created
+const extCreateHostPath = "x-create_host_path"
+
+// checkBindSources reports all bind mounts whose source doesn't exist on host at once, as engine would only fail on the
+// first one once containers get created. Missing sources allowing their creation are only created if there's no
+// error. Sources are only checked when engine runs locally, as they don't have to exist on client otherwise
+func (s *composeService) checkBindSources(services types.Services) error {
+	if !isLocalHost(s.apiClient.DaemonHost()) {
+		return nil
+	}
+	return checkBindSources(services)
+}
+
+func checkBindSources(services types.Services) error {
+	var (
+		problems []string
+		create   []string
+	)
+	for _, service := range services {
+		for _, volume := range service.Volumes {
+			if volume.Type != types.VolumeTypeBind {
+				continue
+			}
+			source, err := bindSource(volume)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stat(source)
+			if err == nil {
+				continue
+			}
+			if !os.IsNotExist(err) {
+				problems = append(problems, fmt.Sprintf("service %q: %s", service.Name, err))
+				continue
+			}
+			if createHostPath(volume) {
+				logrus.Warnf("service %q: bind source %s doesn't exist, creating it", service.Name, source)
+				create = append(create, source)
+				continue
+			}
+			problems = append(problems, fmt.Sprintf("service %q: bind source %s doesn't exist", service.Name, source))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d invalid bind mount(s):\n%s", len(problems), strings.Join(problems, "\n"))
+	}
+	for _, source := range create {
+		if err := os.MkdirAll(source, 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bindSource(volume types.ServiceVolumeConfig) (string, error) {
+	if filepath.IsAbs(volume.Source) {
+		return volume.Source, nil
+	}
+	// volume source has already been prefixed with workdir if required, by compose-go project loader
+	return filepath.Abs(volume.Source)
+}
+
+// createHostPath tells if a missing bind source gets created. Only long syntax binds, read by ApplySpecAttributes,
+// can disable it
+func createHostPath(volume types.ServiceVolumeConfig) bool {
+	if volume.Bind == nil {
+		return true
+	}
+	create, ok := volume.Bind.Extensions[extCreateHostPath].(bool)
+	return create || !ok
+}
+
+// isLocalHost tells if engine is reached through a local socket, sharing client filesystem
+func isLocalHost(host string) bool {
+	return strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://")
+}
+
+// bindOverDirectoryWarnings reports files bind mounted over a path image declares as a directory, being a volume or
+// its working directory. Image filesystem isn't inspected, so other paths aren't checked
+func (s *composeService) bindOverDirectoryWarnings(ctx context.Context, project *types.Project) ([]string, error) {
+	var warnings []string
+	for _, service := range project.Services {
+		var files []types.ServiceVolumeConfig
+		for _, volume := range service.Volumes {
+			if volume.Type != types.VolumeTypeBind {
+				continue
+			}
+			source, err := bindSource(volume)
+			if err != nil {
+				return nil, err
+			}
+			if info, err := os.Stat(source); err == nil && !info.IsDir() {
+				files = append(files, volume)
+			}
+		}
+		if len(files) == 0 {
+			continue
+		}
+		image := getImageName(service, project)
+		inspect, _, err := s.apiClient.ImageInspectWithRaw(ctx, image)
+		if err != nil {
+			return nil, err
+		}
+		if inspect.Config == nil {
+			continue
+		}
+		for _, volume := range files {
+			_, isVolume := inspect.Config.Volumes[volume.Target]
+			if isVolume || volume.Target == inspect.Config.WorkingDir {
+				warnings = append(warnings, fmt.Sprintf("service %q: file %s is bind mounted over directory %s of image %s", service.Name, volume.Source, volume.Target, image))
+			}
+		}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckBindSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "binds")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "config"), 0755))
+
+	// long syntax binds, as read by ApplySpecAttributes
+	bind := func(source string) types.ServiceVolumeConfig {
+		return types.ServiceVolumeConfig{Type: types.VolumeTypeBind, Source: filepath.Join(dir, source), Target: "/etc/app",
+			Bind: &types.ServiceVolumeBind{Extensions: map[string]interface{}{extCreateHostPath: false}}}
+	}
+	create := bind("data")
+	create.Bind = &types.ServiceVolumeBind{Extensions: map[string]interface{}{extCreateHostPath: true}}
+	short := types.ServiceVolumeConfig{Type: types.VolumeTypeBind, Source: filepath.Join(dir, "logs"), Target: "/var/log/app"}
+
+	services := types.Services{
+		{Name: "web", Volumes: []types.ServiceVolumeConfig{bind("config"), bind("confg"), create}},
+		{Name: "db", Volumes: []types.ServiceVolumeConfig{bind("db"), {Type: types.VolumeTypeVolume, Source: "data", Target: "/data"}}},
+	}
+	err = checkBindSources(services)
+	assert.Error(t, err, "2 invalid bind mount(s):\n"+
+		`service "web": bind source `+filepath.Join(dir, "confg")+" doesn't exist\n"+
+		`service "db": bind source `+filepath.Join(dir, "db")+" doesn't exist")
+
+	_, err = os.Stat(filepath.Join(dir, "data"))
+	assert.Assert(t, os.IsNotExist(err))
+
+	valid := types.Services{{Name: "web", Volumes: []types.ServiceVolumeConfig{bind("config"), create, short}}}
+	assert.NilError(t, checkBindSources(valid))
+	for _, source := range []string{"data", "logs"} {
+		info, err := os.Stat(filepath.Join(dir, source))
+		assert.NilError(t, err)
+		assert.Assert(t, info.IsDir())
+	}
+}
+
+func TestIsLocalHost(t *testing.T) {
+	assert.Assert(t, isLocalHost("unix:///var/run/docker.sock"))
+	assert.Assert(t, isLocalHost("npipe:////./pipe/docker_engine"))
+	assert.Assert(t, !isLocalHost("tcp://192.168.1.10:2376"))
+	assert.Assert(t, !isLocalHost("ssh://user@remote"))
+}
@@ -19,8 +19,12 @@ package compose
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/compose-spec/compose-go/types"
@@ -28,31 +32,136 @@ import (
 	"github.com/docker/buildx/driver"
 	_ "github.com/docker/buildx/driver/docker" // required to get default driver registered
 	"github.com/docker/buildx/util/progress"
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/api/compose"
 )
 
-func (s *composeService) Build(ctx context.Context, project *types.Project) error {
+func (s *composeService) Build(ctx context.Context, project *types.Project, options compose.BuildOptions) error {
 	opts := map[string]build.Options{}
+	shmSizes := map[string]int64{}
+	shmSizesByService := map[string]int64{}
+	byService := map[string]build.Options{}
 	for _, service := range project.Services {
 		if service.Build != nil {
 			imageName := getImageName(service, project)
-			opts[imageName] = s.toBuildOptions(service, project.WorkingDir, imageName)
+			shmSize, err := buildShmSize(service)
+			if err != nil {
+				return err
+			}
+			buildOptions, err := s.toBuildOptions(service, project, imageName)
+			if err != nil {
+				return err
+			}
+			tags, err := additionalTags(imageName, options.Tags)
+			if err != nil {
+				return err
+			}
+			buildOptions.Tags = append(buildOptions.Tags, tags...)
+			for key, value := range options.Labels {
+				if strings.HasPrefix(key, compose.LabelPrefix()+".") {
+					return fmt.Errorf("label %q is reserved to compose", key)
+				}
+				buildOptions.Labels[key] = value
+			}
+			if options.SinceBuild {
+				upToDate, err := s.buildUpToDate(ctx, service, project, buildOptions, shmSize)
+				if err != nil {
+					return err
+				}
+				if upToDate {
+					continue
+				}
+			}
+			opts[imageName] = buildOptions
+			byService[service.Name] = buildOptions
+			if shmSize > 0 {
+				shmSizes[imageName] = shmSize
+				shmSizesByService[service.Name] = shmSize
+			}
 		}
 	}
 
-	return s.build(ctx, project, opts)
+	if options.LogDir != "" {
+		return s.buildWithLogs(ctx, project, byService, shmSizesByService, options.LogDir)
+	}
+	return s.build(ctx, project, opts, shmSizes, false)
 }
 
 func getImageName(service types.ServiceConfig, project *types.Project) string {
 	imageName := service.Image
 	if imageName == "" {
-		imageName = project.Name + "_" + service.Name
+		imageName = canonicalImageName(service, project)
 	}
 	return imageName
 }
 
-func (s *composeService) ensureImagesExists(ctx context.Context, project *types.Project) error {
-	opts := map[string]build.Options{}
+// canonicalImageName is the `project_service` name a built image gets, even if service also declares an image name
+func canonicalImageName(service types.ServiceConfig, project *types.Project) string {
+	return compose.ResourceName(project.Name, service.Name)
+}
+
+// buildTags lists the names a service image is tagged with once built, primary name being the first one
+func buildTags(service types.ServiceConfig, project *types.Project, imageTag string) []string {
+	tags := []string{imageTag}
+	if canonical := canonicalImageName(service, project); canonical != imageTag {
+		tags = append(tags, canonical)
+	}
+	return tags
+}
+
+// additionalTags applies tags to the repository of image
+func additionalTags(image string, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, tag := range tags {
+		tagged, err := reference.WithTag(reference.TrimNamed(named), tag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid build tag %q", tag)
+		}
+		refs = append(refs, reference.FamiliarString(tagged))
+	}
+	return refs, nil
+}
+
+// imagesOptions control how service images are made available, the same way for create and run
+type imagesOptions struct {
+	// quietBuild only reports build output when a build fails
+	quietBuild bool
+	// quietPull only reports pull output when a pull fails
+	quietPull bool
+	// verifySignatures first pulls images by a digest resolved from signed trust data
+	verifySignatures bool
+	// verbose reports progress for each image layer pulled by trusted digest
+	verbose bool
+	// sinceBuild rebuilds service images whose build context changed since they were built, and only those
+	sinceBuild bool
+}
+
+// ensureImages makes images of project services available: missing images are pulled or built, and services with
+// build pull policy, as `--build` sets, are rebuilt. With sinceBuild, images are rebuilt if and only if their build
+// context changed
+func (s *composeService) ensureImages(ctx context.Context, project *types.Project, opts imagesOptions) error {
+	if opts.verifySignatures {
+		err := s.pullTrustedImages(ctx, project, notaryVerifier{}, opts.verbose)
+		if err != nil {
+			return err
+		}
+	}
+
+	builds := map[string]build.Options{}
+	shmSizes := map[string]int64{}
+	pulls := map[string]build.Options{}
+	var built []string
 	for _, service := range project.Services {
 		if service.Image == "" && service.Build == nil {
 			return fmt.Errorf("invalid service %q. Must specify either image or build", service.Name)
@@ -64,34 +173,87 @@ func (s *composeService) ensureImagesExists(ctx context.Context, project *types.
 			return err
 		}
 		// TODO build vs pull should be controlled by pull policy, see https://github.com/compose-spec/compose-spec/issues/26
-		if service.Image != "" {
-			if localImagePresent {
-				continue
-			}
+		if localImagePresent && !rebuildRequested(service, opts) {
+			continue
 		}
 		if service.Build != nil {
-			if localImagePresent && service.PullPolicy != types.PullPolicyBuild {
-				continue
+			shmSize, err := buildShmSize(service)
+			if err != nil {
+				return err
+			}
+			if shmSize > 0 {
+				shmSizes[imageName] = shmSize
 			}
-			opts[imageName] = s.toBuildOptions(service, project.WorkingDir, imageName)
+			buildOptions, err := s.toBuildOptions(service, project, imageName)
+			if err != nil {
+				return err
+			}
+			if opts.sinceBuild {
+				upToDate, err := s.buildUpToDate(ctx, service, project, buildOptions, shmSize)
+				if err != nil {
+					return err
+				}
+				if upToDate {
+					continue
+				}
+			}
+			builds[imageName] = buildOptions
+			built = append(built, service.Name)
 			continue
 		}
 
-		// Buildx has no command to "just pull", see
-		// so we bake a temporary dockerfile that will just pull and export pulled image
-		opts[service.Name] = build.Options{
-			Inputs: build.Inputs{
-				ContextPath:    ".",
-				DockerfilePath: "-",
-				InStream:       strings.NewReader("FROM " + service.Image),
-			},
-			Tags: []string{service.Image},
-			Pull: true,
+		pulls[service.Name] = pullOptions(service.Image)
+	}
+
+	for _, image := range imageVolumeSources(project) {
+		present, err := s.localImagePresent(ctx, image)
+		if err != nil {
+			return err
+		}
+		if !present {
+			pulls[image] = pullOptions(image)
 		}
+	}
 
+	// pulls and builds get their own build session, so that their output can be quieted independently
+	if opts.quietPull != opts.quietBuild {
+		err := s.build(ctx, project, pulls, nil, opts.quietPull)
+		if err != nil {
+			return err
+		}
+	} else {
+		for name, pull := range pulls {
+			builds[name] = pull
+		}
+	}
+	err := s.build(ctx, project, builds, shmSizes, opts.quietBuild)
+	if err != nil || !opts.quietBuild {
+		return err
 	}
+	for _, name := range built {
+		fmt.Printf("Built service %s\n", name)
+	}
+	return nil
+}
+
+// rebuildRequested tells if a service image has to be built even if present, as service declares a build and either
+// the build pull policy, as `--build` sets, or sinceBuild applies. This holds for services also declaring an image name
+func rebuildRequested(service types.ServiceConfig, opts imagesOptions) bool {
+	return service.Build != nil && (service.PullPolicy == types.PullPolicyBuild || opts.sinceBuild)
+}
 
-	return s.build(ctx, project, opts)
+// pullOptions are the build options to pull an image. Buildx has no command to "just pull", so we bake a temporary
+// dockerfile that will just pull and export pulled image
+func pullOptions(image string) build.Options {
+	return build.Options{
+		Inputs: build.Inputs{
+			ContextPath:    ".",
+			DockerfilePath: "-",
+			InStream:       strings.NewReader("FROM " + image),
+		},
+		Tags: []string{image},
+		Pull: true,
+	}
 }
 
 func (s *composeService) localImagePresent(ctx context.Context, imageName string) (bool, error) {
@@ -105,52 +267,176 @@ func (s *composeService) localImagePresent(ctx context.Context, imageName string
 	return true, nil
 }
 
-func (s *composeService) build(ctx context.Context, project *types.Project, opts map[string]build.Options) error {
+// build runs builds with buildx, but the ones shmSizes sets a build shm size for, run with engine classic builder. When
+// quiet, build output is only shown if a build fails, so the failing step output still comes with the error
+func (s *composeService) build(ctx context.Context, project *types.Project, opts map[string]build.Options, shmSizes map[string]int64, quiet bool) error {
 	if len(opts) == 0 {
 		return nil
 	}
-	const drivername = "default"
-	d, err := driver.GetDriver(ctx, drivername, nil, s.apiClient, nil, nil, "", nil, project.WorkingDir)
+	driverInfo, err := s.buildDrivers(ctx, project)
 	if err != nil {
 		return err
 	}
-	driverInfo := []build.DriverInfo{
-		{
-			Name:   "default",
-			Driver: d,
-		},
-	}
 
 	// Progress needs its own context that lives longer than the
 	// build one otherwise it won't read all the messages from
 	// build and will lock
 	progressCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	w := progress.NewPrinter(progressCtx, os.Stdout, "auto")
+	out, mode := os.Stdout, "auto"
+	if quiet {
+		out, err = ioutil.TempFile("", "compose-build-*.log")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(out.Name()) //nolint:errcheck
+		defer out.Close()           //nolint:errcheck
+		mode = "plain"
+	}
+	w := progress.NewPrinter(progressCtx, out, mode)
 
-	// We rely on buildx "docker" builder integrated in docker engine, so don't need a DockerAPI here
-	_, err = build.Build(ctx, driverInfo, opts, nil, nil, w)
+	buildxOpts := map[string]build.Options{}
+	for name, opt := range opts {
+		shmSize, ok := shmSizes[name]
+		if !ok {
+			buildxOpts[name] = opt
+			continue
+		}
+		if err = s.classicBuild(ctx, opt, shmSize, out); err != nil {
+			break
+		}
+	}
+	if err == nil && len(buildxOpts) > 0 {
+		// We rely on buildx "docker" builder integrated in docker engine, so don't need a DockerAPI here
+		_, err = build.Build(ctx, driverInfo, buildxOpts, nil, nil, w)
+	}
+	if err != nil && quiet {
+		if _, seekErr := out.Seek(0, io.SeekStart); seekErr == nil {
+			io.Copy(os.Stderr, out) //nolint:errcheck
+		}
+	}
 	return err
 }
 
-func (s *composeService) toBuildOptions(service types.ServiceConfig, contextPath string, imageTag string) build.Options {
-	var tags []string
-	tags = append(tags, imageTag)
+// buildWithLogs builds services concurrently, each one writing its complete build output in plain mode to
+// `<service>.log` in logDir. Console only gets a line per service once all builds are done, with the log file of the
+// failed ones. Services shmSizes sets a build shm size for are built with engine classic builder
+func (s *composeService) buildWithLogs(ctx context.Context, project *types.Project, opts map[string]build.Options, shmSizes map[string]int64, logDir string) error {
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		return errors.Wrap(err, "can't create build log directory")
+	}
+	var services []string
+	for name := range opts {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	// log files are all created before building, so an unwritable directory fails before any build starts
+	logs := make([]*os.File, len(services))
+	for i, name := range services {
+		f, err := os.Create(filepath.Join(logDir, name+".log"))
+		if err != nil {
+			return errors.Wrap(err, "can't write build log")
+		}
+		defer f.Close() //nolint:errcheck
+		logs[i] = f
+	}
+
+	driverInfo, err := s.buildDrivers(ctx, project)
+	if err != nil {
+		return err
+	}
+	// Progress needs its own context that lives longer than the
+	// build one otherwise it won't read all the messages from
+	// build and will lock
+	progressCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	buildErrs := make([]error, len(services))
+	var eg errgroup.Group
+	for i, name := range services {
+		i, name := i, name
+		eg.Go(func() error {
+			if shmSize, ok := shmSizes[name]; ok {
+				buildErrs[i] = s.classicBuild(ctx, opts[name], shmSize, logs[i])
+				return nil
+			}
+			w := progress.NewPrinter(progressCtx, logs[i], "plain")
+			_, buildErrs[i] = build.Build(ctx, driverInfo, map[string]build.Options{name: opts[name]}, nil, nil, w)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	var failed []string
+	for i, name := range services {
+		if buildErrs[i] != nil {
+			failed = append(failed, name)
+			fmt.Printf("Failed to build service %s, see %s\n", name, logs[i].Name())
+		} else {
+			fmt.Printf("Built service %s\n", name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to build service(s) %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// buildDrivers returns the buildx driver builds run with
+func (s *composeService) buildDrivers(ctx context.Context, project *types.Project) ([]build.DriverInfo, error) {
+	const drivername = "default"
+	d, err := driver.GetDriver(ctx, drivername, nil, s.apiClient, nil, nil, "", nil, project.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+	return []build.DriverInfo{
+		{
+			Name:   "default",
+			Driver: d,
+		},
+	}, nil
+}
 
+func (s *composeService) toBuildOptions(service types.ServiceConfig, project *types.Project, imageTag string) (build.Options, error) {
 	if service.Build.Dockerfile == "" {
 		service.Build.Dockerfile = "Dockerfile"
 	}
 	var buildArgs map[string]string
 
-	return build.Options{
+	opts := build.Options{
 		Inputs: build.Inputs{
-			ContextPath:    path.Join(contextPath, service.Build.Context),
-			DockerfilePath: path.Join(contextPath, service.Build.Context, service.Build.Dockerfile),
+			ContextPath:    path.Join(project.WorkingDir, service.Build.Context),
+			DockerfilePath: path.Join(project.WorkingDir, service.Build.Context, service.Build.Dockerfile),
 		},
 		BuildArgs: flatten(mergeArgs(service.Build.Args, buildArgs)),
-		Tags:      tags,
+		Tags:      buildTags(service, project, imageTag),
+		Labels:    buildLabels(service, project, imageTag),
 		Target:    service.Build.Target,
 	}
+	secrets, err := buildSecrets(service, project)
+	if err != nil {
+		return build.Options{}, err
+	}
+	if secrets != nil {
+		opts.Session = append(opts.Session, secrets)
+	}
+	return opts, nil
+}
+
+// buildLabels lists labels set on a service image: the ones declared by build section, then the ones compose relies
+// on, which can't be overridden. Containers inherit image labels, so project and service ones are image specific not
+// to make containers created from the image by other means look like project containers
+func buildLabels(service types.ServiceConfig, project *types.Project, imageTag string) map[string]string {
+	labels := map[string]string{}
+	for key, value := range service.Build.Labels {
+		labels[key] = value
+	}
+	labels[imageProjectLabel()] = project.Name
+	labels[imageServiceLabel()] = service.Name
+	labels[versionLabel()] = ComposeVersion
+	labels[imagePrimaryLabel()] = imageTag
+	return labels
 }
 
 func flatten(in types.MappingWithEquals) map[string]string {
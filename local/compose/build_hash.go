@@ -0,0 +1,164 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/buildx/build"
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/pkg/errors"
+
+	"github.com/docker/compose-cli/progress"
+)
+
+// buildUpToDate tells if the primary image of a service build was built from the same context, as recorded by its
+// build hash label, and sets this label for the image to be built otherwise
+func (s *composeService) buildUpToDate(ctx context.Context, service types.ServiceConfig, project *types.Project, opts build.Options, shmSize int64) (bool, error) {
+	secrets, err := buildSecretSources(service, project)
+	if err != nil {
+		return false, err
+	}
+	hash, err := buildContextHash(opts, shmSize, secrets)
+	if err != nil {
+		return false, errors.Wrapf(err, "service %q: can't hash build context", service.Name)
+	}
+	opts.Labels[buildHashLabel()] = hash
+
+	image, _, err := s.apiClient.ImageInspectWithRaw(ctx, opts.Tags[0])
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if image.Config == nil || image.Config.Labels[buildHashLabel()] != hash {
+		return false, nil
+	}
+	progress.ContextWriter(ctx).Event(progress.NewEvent(fmt.Sprintf("Service %s", service.Name), progress.Done, "Build context unchanged, skipping build").WithResource(progress.ServiceKind, service.Name))
+	return true, nil
+}
+
+// buildContextHash digests what a build depends on: Dockerfile, build args, target, shm size, build secrets, and the
+// build context files .dockerignore doesn't exclude
+func buildContextHash(opts build.Options, shmSize int64, secrets buildSecretStore) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "target %s\n", opts.Target)
+	_, _ = fmt.Fprintf(h, "shm_size %d\n", shmSize)
+	var names []string
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// secret values are left out, not to leak any digest of them into image labels
+		_, _ = fmt.Fprintf(h, "secret %s file=%s env=%s\n", name, secrets[name].file, secrets[name].env)
+	}
+	var args []string
+	for key := range opts.BuildArgs {
+		args = append(args, key)
+	}
+	sort.Strings(args)
+	for _, key := range args {
+		_, _ = fmt.Fprintf(h, "arg %s=%s\n", key, opts.BuildArgs[key])
+	}
+	// Dockerfile may live out of the build context, or be excluded from it
+	_, _ = fmt.Fprintf(h, "dockerfile %s\n", filepath.Base(opts.Inputs.DockerfilePath))
+	if err := hashFile(h, opts.Inputs.DockerfilePath); err != nil {
+		return "", err
+	}
+
+	contextPath := opts.Inputs.ContextPath
+	excludes, err := readDockerignore(contextPath)
+	if err != nil {
+		return "", err
+	}
+	matcher, err := fileutils.NewPatternMatcher(excludes)
+	if err != nil {
+		return "", err
+	}
+	err = filepath.Walk(contextPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(contextPath, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		excluded, err := matcher.Matches(rel)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			// an exclusion pattern could re-include a file from an excluded directory
+			if info.IsDir() && !matcher.Exclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(h, "link %s %s\n", rel, target)
+		case info.IsDir():
+			_, _ = fmt.Fprintf(h, "dir %s %o\n", rel, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			_, _ = fmt.Fprintf(h, "file %s %o %d\n", rel, info.Mode().Perm(), info.Size())
+			return hashFile(h, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+func readDockerignore(contextPath string) ([]string, error) {
+	f, err := os.Open(filepath.Join(contextPath, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+	return dockerignore.ReadAll(f)
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/buildx/build"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildContextHash(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NilError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	write("Dockerfile", "FROM alpine\nCOPY . /src\n")
+	write("src/main.go", "package main\n")
+	write("logs/debug.log", "started\n")
+	write(".dockerignore", "logs\n")
+
+	opts := build.Options{
+		Inputs: build.Inputs{
+			ContextPath:    dir,
+			DockerfilePath: filepath.Join(dir, "Dockerfile"),
+		},
+		BuildArgs: map[string]string{"VERSION": "1"},
+	}
+	shmSize := int64(0)
+	secrets := buildSecretStore{}
+	hash := func() string {
+		h, err := buildContextHash(opts, shmSize, secrets)
+		assert.NilError(t, err)
+		return h
+	}
+	initial := hash()
+	assert.Equal(t, hash(), initial)
+
+	write("logs/debug.log", "restarted\n")
+	assert.Equal(t, hash(), initial, "files excluded by .dockerignore don't change the hash")
+
+	write("src/main.go", "package main\n\nfunc main() {}\n")
+	changed := hash()
+	assert.Assert(t, changed != initial)
+
+	write("Dockerfile", "FROM alpine:3.12\nCOPY . /src\n")
+	assert.Assert(t, hash() != changed)
+	changed = hash()
+
+	opts.BuildArgs["VERSION"] = "2"
+	assert.Assert(t, hash() != changed)
+	changed = hash()
+
+	opts.Target = "dev"
+	assert.Assert(t, hash() != changed)
+	changed = hash()
+
+	shmSize = 1 << 30
+	assert.Assert(t, hash() != changed)
+	changed = hash()
+
+	secrets["token"] = buildSecretSource{env: "TOKEN"}
+	assert.Assert(t, hash() != changed)
+	changed = hash()
+
+	secrets["token"] = buildSecretSource{file: filepath.Join(dir, "token")}
+	assert.Assert(t, hash() != changed)
+}
@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	"github.com/pkg/errors"
+)
+
+const (
+	// extBuildSecrets lists the top-level secrets a service build can mount. compose-go doesn't support build.secrets yet
+	extBuildSecrets = "x-secrets"
+	// extSecretEnvironment makes a top-level secret get its value from an environment variable rather than a file
+	extSecretEnvironment = "x-environment"
+)
+
+// buildSecrets resolves the top-level secrets referenced by service build, so they can be mounted by
+// `RUN --mount=type=secret,id=<secret>` instructions. Returns nil if service build doesn't use any secret
+func buildSecrets(service types.ServiceConfig, project *types.Project) (session.Attachable, error) {
+	store, err := buildSecretSources(service, project)
+	if err != nil || store == nil {
+		return nil, err
+	}
+	return secretsprovider.NewSecretProvider(store), nil
+}
+
+// buildSecretSources resolves where the values of the top-level secrets referenced by service build are read from.
+// Returns nil if service build doesn't use any secret
+func buildSecretSources(service types.ServiceConfig, project *types.Project) (buildSecretStore, error) {
+	names, err := buildSecretNames(service)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	store := buildSecretStore{}
+	for _, name := range names {
+		secret, ok := project.Secrets[name]
+		if !ok {
+			return nil, fmt.Errorf("service %q: build secret %q is not defined in top-level secrets", service.Name, name)
+		}
+		if secret.External.External {
+			return nil, fmt.Errorf("service %q: build secret %q is external, only file or environment secrets can be used to build", service.Name, name)
+		}
+		if secret.File != "" {
+			store[name] = buildSecretSource{file: secret.File}
+			continue
+		}
+		env, ok := secret.Extensions[extSecretEnvironment].(string)
+		if !ok || env == "" {
+			return nil, fmt.Errorf("service %q: build secret %q must set either file or %s", service.Name, name, extSecretEnvironment)
+		}
+		store[name] = buildSecretSource{env: env}
+	}
+	return store, nil
+}
+
+func buildSecretNames(service types.ServiceConfig) ([]string, error) {
+	value, ok := service.Build.Extensions[extBuildSecrets]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("service %q: build %s must be a list of secret names", service.Name, extBuildSecrets)
+	}
+	var names []string
+	for _, item := range list {
+		name, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("service %q: build %s must be a list of secret names", service.Name, extBuildSecrets)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+type buildSecretSource struct {
+	file string
+	env  string
+}
+
+// buildSecretStore reads secrets values only when buildkit requests them, so they are never written anywhere else
+type buildSecretStore map[string]buildSecretSource
+
+func (s buildSecretStore) GetSecret(ctx context.Context, id string) ([]byte, error) {
+	source, ok := s[id]
+	if !ok {
+		return nil, errors.WithStack(secrets.ErrNotFound)
+	}
+	if source.file != "" {
+		return ioutil.ReadFile(source.file)
+	}
+	value, ok := os.LookupEnv(source.env)
+	if !ok {
+		return nil, fmt.Errorf("build secret %q: environment variable %s is not set", id, source.env)
+	}
+	return []byte(value), nil
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+	file := filepath.Join(dir, "npmrc")
+	assert.NilError(t, ioutil.WriteFile(file, []byte("registry-token"), 0600))
+	assert.NilError(t, os.Setenv("TEST_BUILD_SECRET", "from-env"))
+	defer os.Unsetenv("TEST_BUILD_SECRET") //nolint:errcheck
+
+	project := &types.Project{
+		Secrets: types.Secrets{
+			"npmrc": types.SecretConfig{File: file},
+			"token": types.SecretConfig{Extensions: map[string]interface{}{extSecretEnvironment: "TEST_BUILD_SECRET"}},
+			"vault": types.SecretConfig{External: types.External{External: true}},
+		},
+	}
+	service := types.ServiceConfig{
+		Name: "web",
+		Build: &types.BuildConfig{
+			Extensions: map[string]interface{}{extBuildSecrets: []interface{}{"npmrc", "token"}},
+		},
+	}
+	attachable, err := buildSecrets(service, project)
+	assert.NilError(t, err)
+	assert.Assert(t, attachable != nil)
+
+	store := buildSecretStore{"npmrc": {file: file}, "token": {env: "TEST_BUILD_SECRET"}}
+	value, err := store.GetSecret(context.Background(), "npmrc")
+	assert.NilError(t, err)
+	assert.Equal(t, string(value), "registry-token")
+	value, err = store.GetSecret(context.Background(), "token")
+	assert.NilError(t, err)
+	assert.Equal(t, string(value), "from-env")
+	_, err = store.GetSecret(context.Background(), "unknown")
+	assert.ErrorContains(t, err, "not found")
+
+	service.Build.Extensions[extBuildSecrets] = []interface{}{"missing"}
+	_, err = buildSecrets(service, project)
+	assert.Error(t, err, `service "web": build secret "missing" is not defined in top-level secrets`)
+
+	service.Build.Extensions[extBuildSecrets] = []interface{}{"vault"}
+	_, err = buildSecrets(service, project)
+	assert.ErrorContains(t, err, `build secret "vault" is external`)
+
+	service.Build.Extensions = nil
+	attachable, err = buildSecrets(service, project)
+	assert.NilError(t, err)
+	assert.Assert(t, attachable == nil)
+}
@@ -0,0 +1,183 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/buildx/build"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// extShmSize holds build shm_size, which the compose-go version in use drops while loading, see ApplySpecAttributes
+const extShmSize = "x-shm_size"
+
+// buildShmSize is the size of /dev/shm the build of service requires
+func buildShmSize(service types.ServiceConfig) (int64, error) {
+	value, ok := service.Build.Extensions[extShmSize]
+	if !ok {
+		return 0, nil
+	}
+	shmSize, err := units.RAMInBytes(fmt.Sprint(value))
+	if err != nil || shmSize <= 0 {
+		return 0, fmt.Errorf("service %q: invalid build shm_size %v", service.Name, value)
+	}
+	return shmSize, nil
+}
+
+// SetBuildShmSize sets the size in bytes of /dev/shm during builds of project services, overriding the build shm_size
+// they declare
+func SetBuildShmSize(project *types.Project, size int64) {
+	for _, service := range project.Services {
+		if service.Build != nil {
+			service.Build.Extensions = setExtension(service.Build.Extensions, extShmSize, size)
+		}
+	}
+}
+
+// classicBuild builds with engine classic builder, as the buildx version in use can't size build /dev/shm. Build
+// output is written to out
+func (s *composeService) classicBuild(ctx context.Context, opts build.Options, shmSize int64, out io.Writer) error {
+	if len(opts.Session) > 0 {
+		return errors.New("build secrets require BuildKit, they can't be used with build shm_size")
+	}
+	buildOptions, err := classicBuildOptions(opts, shmSize)
+	if err != nil {
+		return err
+	}
+	buildContext := tarBuildContext(opts.Inputs.ContextPath, buildOptions.Dockerfile)
+	defer buildContext.Close() //nolint:errcheck
+
+	response, err := s.apiClient.ImageBuild(ctx, buildContext, buildOptions)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close() //nolint:errcheck
+	return jsonmessage.DisplayJSONMessagesStream(response.Body, out, 0, false, nil)
+}
+
+// classicBuildOptions converts buildx options to engine build options
+func classicBuildOptions(opts build.Options, shmSize int64) (moby.ImageBuildOptions, error) {
+	dockerfile, err := filepath.Rel(opts.Inputs.ContextPath, opts.Inputs.DockerfilePath)
+	if err != nil {
+		return moby.ImageBuildOptions{}, err
+	}
+	if strings.HasPrefix(dockerfile, "..") {
+		return moby.ImageBuildOptions{}, fmt.Errorf("Dockerfile %s must be in build context to build with build shm_size", opts.Inputs.DockerfilePath)
+	}
+	buildArgs := map[string]*string{}
+	for key, value := range opts.BuildArgs {
+		value := value
+		buildArgs[key] = &value
+	}
+	return moby.ImageBuildOptions{
+		Tags:       opts.Tags,
+		Labels:     opts.Labels,
+		BuildArgs:  buildArgs,
+		Target:     opts.Target,
+		Dockerfile: filepath.ToSlash(dockerfile),
+		PullParent: opts.Pull,
+		NoCache:    opts.NoCache,
+		Remove:     true,
+		ShmSize:    shmSize,
+	}, nil
+}
+
+// tarBuildContext archives the build context files .dockerignore doesn't exclude, engine classic builder reading
+// Dockerfile and .dockerignore from build context anyway
+func tarBuildContext(contextPath string, dockerfile string) io.ReadCloser {
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(writeBuildContext(w, contextPath, filepath.ToSlash(dockerfile))) //nolint:errcheck
+	}()
+	return r
+}
+
+func writeBuildContext(w io.Writer, contextPath string, dockerfile string) error {
+	excludes, err := readDockerignore(contextPath)
+	if err != nil {
+		return err
+	}
+	matcher, err := fileutils.NewPatternMatcher(excludes)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(w)
+	err = filepath.Walk(contextPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(contextPath, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		excluded, err := matcher.Matches(rel)
+		if err != nil {
+			return err
+		}
+		if excluded && rel != dockerfile && rel != ".dockerignore" && !strings.HasPrefix(dockerfile, rel+"/") {
+			// an exclusion pattern could re-include a file from an excluded directory
+			if info.IsDir() && !matcher.Exclusions() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
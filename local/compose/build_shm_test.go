@@ -0,0 +1,120 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/buildx/build"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestBuildShmSize(t *testing.T) {
+	service := types.ServiceConfig{Name: "web", Build: &types.BuildConfig{}}
+	size, err := buildShmSize(service)
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(0))
+
+	service.Build.Extensions = map[string]interface{}{extShmSize: "2gb"}
+	size, err = buildShmSize(service)
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(2*1024*1024*1024))
+
+	service.Build.Extensions[extShmSize] = 1024
+	size, err = buildShmSize(service)
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(1024))
+
+	service.Build.Extensions[extShmSize] = "lots"
+	_, err = buildShmSize(service)
+	assert.Error(t, err, `service "web": invalid build shm_size lots`)
+}
+
+func TestSetBuildShmSize(t *testing.T) {
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web", Build: &types.BuildConfig{Extensions: map[string]interface{}{extShmSize: "2gb"}}},
+			{Name: "db", Image: "postgres"},
+		},
+	}
+	SetBuildShmSize(project, 64)
+	size, err := buildShmSize(project.Services[0])
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(64))
+	assert.Assert(t, project.Services[1].Build == nil)
+}
+
+func TestClassicBuildForwardsShmSize(t *testing.T) {
+	dir := fs.NewDir(t, "build-shm",
+		fs.WithFile(".dockerignore", "docker\nsecret.txt\n"),
+		fs.WithFile("secret.txt", "s3cr3t"),
+		fs.WithFile("main.go", "package main\n"),
+		fs.WithDir("docker",
+			fs.WithFile("Dockerfile.dev", "FROM busybox\n"),
+			fs.WithFile("notes.txt", "")))
+	defer dir.Remove()
+
+	var (
+		query url.Values
+		files []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/build") {
+			query = r.URL.Query()
+			tr := tar.NewReader(r.Body)
+			for {
+				header, err := tr.Next()
+				if err != nil {
+					break
+				}
+				files = append(files, header.Name)
+			}
+		}
+		_, _ = w.Write([]byte(`{"stream":"Successfully built 0123456789ab\n"}`))
+	}))
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(server.URL, "http://")), client.WithVersion("1.40"))
+	assert.NilError(t, err)
+
+	s := composeService{apiClient: apiClient}
+	var out bytes.Buffer
+	err = s.classicBuild(context.Background(), build.Options{
+		Inputs: build.Inputs{
+			ContextPath:    dir.Path(),
+			DockerfilePath: dir.Join("docker", "Dockerfile.dev"),
+		},
+		Tags:      []string{"myproject_web"},
+		BuildArgs: map[string]string{"VERSION": "1.0"},
+	}, 2*1024*1024*1024, &out)
+	assert.NilError(t, err)
+	assert.Equal(t, query.Get("shmsize"), "2147483648")
+	assert.Equal(t, query.Get("dockerfile"), "docker/Dockerfile.dev")
+	assert.Equal(t, query.Get("t"), "myproject_web")
+	assert.Equal(t, query.Get("buildargs"), `{"VERSION":"1.0"}`)
+	assert.DeepEqual(t, files, []string{".dockerignore", "docker/", "docker/Dockerfile.dev", "main.go"})
+	assert.Equal(t, out.String(), "Successfully built 0123456789ab\n")
+}
@@ -0,0 +1,101 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/buildx/build"
+	"gotest.tools/v3/assert"
+)
+
+func TestBuildTags(t *testing.T) {
+	project := &types.Project{Name: "myproject"}
+
+	unnamed := types.ServiceConfig{Name: "web"}
+	assert.DeepEqual(t, buildTags(unnamed, project, getImageName(unnamed, project)), []string{"myproject_web"})
+
+	named := types.ServiceConfig{Name: "web", Image: "custom-web"}
+	assert.DeepEqual(t, buildTags(named, project, getImageName(named, project)), []string{"custom-web", "myproject_web"})
+}
+
+func TestAdditionalTags(t *testing.T) {
+	tags, err := additionalTags("myproject_web", []string{"latest", "3f2a1c9"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tags, []string{"myproject_web:latest", "myproject_web:3f2a1c9"})
+
+	tags, err = additionalTags("registry.example.com:5000/org/web:1.0", []string{"3f2a1c9"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tags, []string{"registry.example.com:5000/org/web:3f2a1c9"})
+
+	tags, err = additionalTags("web", nil)
+	assert.NilError(t, err)
+	assert.Assert(t, tags == nil)
+
+	_, err = additionalTags("web", []string{"not/a tag"})
+	assert.ErrorContains(t, err, `invalid build tag "not/a tag"`)
+}
+
+func TestBuildLabels(t *testing.T) {
+	project := &types.Project{Name: "myproject"}
+	service := types.ServiceConfig{
+		Name: "web",
+		Build: &types.BuildConfig{
+			Labels: types.Labels{
+				"org.opencontainers.image.source": "https://github.com/example/web",
+				imageProjectLabel():               "spoofed",
+			},
+		},
+	}
+	assert.DeepEqual(t, buildLabels(service, project, "myproject_web"), map[string]string{
+		"org.opencontainers.image.source": "https://github.com/example/web",
+		imageProjectLabel():               "myproject",
+		imageServiceLabel():               "web",
+		versionLabel():                    ComposeVersion,
+		imagePrimaryLabel():               "myproject_web",
+	})
+}
+
+func TestBuildWithLogsUnwritableDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "build-logs")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+	file := filepath.Join(dir, "file")
+	assert.NilError(t, ioutil.WriteFile(file, nil, 0600))
+
+	s := &composeService{}
+	err = s.buildWithLogs(context.Background(), &types.Project{}, map[string]build.Options{"web": {}}, nil, filepath.Join(file, "logs"))
+	assert.Assert(t, err != nil)
+	assert.Assert(t, strings.HasPrefix(err.Error(), "can't create build log directory"), err.Error())
+}
+
+func TestRebuildRequested(t *testing.T) {
+	build := &types.BuildConfig{Context: "."}
+	assert.Assert(t, !rebuildRequested(types.ServiceConfig{Name: "db", Image: "postgres"}, imagesOptions{}))
+	assert.Assert(t, !rebuildRequested(types.ServiceConfig{Name: "db", Image: "postgres", PullPolicy: types.PullPolicyBuild}, imagesOptions{}))
+	assert.Assert(t, !rebuildRequested(types.ServiceConfig{Name: "app", Build: build}, imagesOptions{}))
+	assert.Assert(t, !rebuildRequested(types.ServiceConfig{Name: "app", Image: "myapp", Build: build}, imagesOptions{}))
+	assert.Assert(t, rebuildRequested(types.ServiceConfig{Name: "app", Build: build, PullPolicy: types.PullPolicyBuild}, imagesOptions{}))
+	assert.Assert(t, rebuildRequested(types.ServiceConfig{Name: "app", Image: "myapp", Build: build, PullPolicy: types.PullPolicyBuild}, imagesOptions{}))
+	assert.Assert(t, rebuildRequested(types.ServiceConfig{Name: "app", Image: "myapp", Build: build}, imagesOptions{sinceBuild: true}))
+}
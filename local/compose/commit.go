@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func (s *composeService) Commit(ctx context.Context, projectName string, options compose.CommitOptions) (string, error) {
+	args := filters.NewArgs(
+		projectFilter(projectName),
+		serviceFilter(options.Service),
+		filters.Arg("label", fmt.Sprintf("%s=%s", oneoffLabel(), "False")),
+	)
+	if options.Index > 0 {
+		args.Add("label", fmt.Sprintf("%s=%d", containerNumberLabel(), options.Index))
+	}
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: args,
+		All:     true,
+	})
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case len(containers) == 0 && options.Index > 0:
+		return "", fmt.Errorf("service %q has no container #%d", options.Service, options.Index)
+	case len(containers) == 0:
+		return "", fmt.Errorf("no container found for service %q", options.Service)
+	case len(containers) > 1:
+		return "", fmt.Errorf("service %q has %d containers, select the one to commit by its index", options.Service, len(containers))
+	}
+
+	created, err := s.apiClient.ContainerCommit(ctx, containers[0].ID, moby.ContainerCommitOptions{
+		Reference: options.Reference,
+		Comment:   options.Message,
+		Changes:   options.Changes,
+		Pause:     options.Pause,
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
@@ -28,6 +28,7 @@ import (
 	errdefs2 "github.com/docker/compose-cli/errdefs"
 	moby "github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
 	"github.com/sanathkr/go-yaml"
 )
 
@@ -40,7 +41,7 @@ type composeService struct {
 	apiClient *client.Client
 }
 
-func (s *composeService) Up(ctx context.Context, project *types.Project, detach bool) error {
+func (s *composeService) Up(ctx context.Context, project *types.Project, options compose.UpOptions) error {
 	return errdefs2.ErrNotImplemented
 }
 
@@ -54,13 +55,34 @@ func getContainerName(c moby.Container) string {
 	return c.Names[0][1:]
 }
 
-func (s *composeService) Convert(ctx context.Context, project *types.Project, format string) ([]byte, error) {
-	switch format {
+func (s *composeService) Convert(ctx context.Context, project *types.Project, options compose.ConvertOptions) ([]byte, error) {
+	if options.Parameters {
+		return nil, errors.Wrap(errdefs2.ErrNotImplemented, "local backend doesn't deploy with parameters")
+	}
+	// services disabled by profiles are held by project so that their containers aren't orphans, they aren't part of
+	// the model
+	if _, ok := project.Extensions[extDisabledServices]; ok {
+		converted := *project
+		converted.Extensions = map[string]interface{}{}
+		for key, value := range project.Extensions {
+			if key != extDisabledServices {
+				converted.Extensions[key] = value
+			}
+		}
+		project = &converted
+	}
+	switch options.Format {
 	case "json":
 		return json.MarshalIndent(project, "", "  ")
 	case "yaml":
 		return yaml.Marshal(project)
 	default:
-		return nil, fmt.Errorf("unsupported format %q", format)
+		return nil, fmt.Errorf("unsupported format %q", options.Format)
 	}
 }
+
+// Unsupported lists the features local backend ignores, which engine only supports for swarm services
+func (s *composeService) Unsupported(ctx context.Context, project *types.Project) ([]string, error) {
+	unsupported := append(templateDriverWarnings(project), externalSecretWarnings(project)...)
+	return append(unsupported, genericResourcesWarnings(project)...), nil
+}
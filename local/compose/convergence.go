@@ -20,21 +20,27 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/compose-spec/compose-go/types"
 	moby "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/docker/compose-cli/api/compose"
 	status "github.com/docker/compose-cli/local/moby"
 	"github.com/docker/compose-cli/progress"
 )
 
 const (
 	extLifecycle  = "x-lifecycle"
+	extEnvFile    = "x-env-file"
+	extImageID    = "x-image-id"
 	forceRecreate = "force_recreate"
+	restartOnly   = "restart"
 )
 
 func (s *composeService) ensureService(ctx context.Context, project *types.Project, service types.ServiceConfig) error {
@@ -42,6 +48,7 @@ func (s *composeService) ensureService(ctx context.Context, project *types.Proje
 		Filters: filters.NewArgs(
 			projectFilter(project.Name),
 			serviceFilter(service.Name),
+			filters.Arg("label", fmt.Sprintf("%s=%s", oneoffLabel(), "False")),
 		),
 		All: true,
 	})
@@ -60,7 +67,8 @@ func (s *composeService) ensureService(ctx context.Context, project *types.Proje
 		missing := scale - len(actual)
 		for i := 0; i < missing; i++ {
 			number := next + i
-			name := fmt.Sprintf("%s_%s_%d", project.Name, service.Name, number)
+			// container names keep the docker-compose v1 `_` separator unless COMPOSE_NAME_SEPARATOR is set, with or without compatibility mode
+			name := compose.ResourceName(project.Name, service.Name, strconv.Itoa(number))
 			eg.Go(func() error {
 				return s.createContainer(ctx, project, service, name, number)
 			})
@@ -81,16 +89,19 @@ func (s *composeService) ensureService(ctx context.Context, project *types.Proje
 		actual = actual[:scale]
 	}
 
-	expected, err := jsonHash(service)
-	if err != nil {
-		return err
-	}
-
 	for _, container := range actual {
 		container := container
 		name := getContainerName(container)
 
-		diverged := container.Labels[configHashLabel] != expected
+		imageID := container.ImageID
+		if id, ok := service.Extensions[extImageID].(string); ok {
+			imageID = id
+		}
+		expected, err := serviceHash(service, imageID)
+		if err != nil {
+			return err
+		}
+		diverged := container.Labels[configHashLabel()] != expected
 		if diverged || service.Extensions[extLifecycle] == forceRecreate {
 			eg.Go(func() error {
 				return s.recreateContainer(ctx, project, service, container)
@@ -101,10 +112,18 @@ func (s *composeService) ensureService(ctx context.Context, project *types.Proje
 		w := progress.ContextWriter(ctx)
 		switch container.State {
 		case status.ContainerRunning:
-			w.Event(progress.RunningEvent(name))
+			if service.Extensions[extLifecycle] == restartOnly {
+				eg.Go(func() error {
+					return s.restartRunningContainer(ctx, container)
+				})
+				continue
+			}
+			event := progress.RunningEvent(name).WithResource(progress.ContainerKind, name)
+			event.Ports = publishedPorts(container.Ports)
+			w.Event(event)
 		case status.ContainerCreated:
 		case status.ContainerRestarting:
-			w.Event(progress.CreatedEvent(name))
+			w.Event(progress.CreatedEvent(name).WithResource(progress.ContainerKind, name))
 		default:
 			eg.Go(func() error {
 				return s.restartContainer(ctx, container)
@@ -141,7 +160,7 @@ func (s *composeService) waitDependencies(ctx context.Context, project *types.Pr
 func nextContainerNumber(containers []moby.Container) (int, error) {
 	max := 0
 	for _, c := range containers {
-		n, err := strconv.Atoi(c.Labels[containerNumberLabel])
+		n, err := strconv.Atoi(c.Labels[containerNumberLabel()])
 		if err != nil {
 			return 0, err
 		}
@@ -165,29 +184,29 @@ func getScale(config types.ServiceConfig) int {
 
 func (s *composeService) createContainer(ctx context.Context, project *types.Project, service types.ServiceConfig, name string, number int) error {
 	w := progress.ContextWriter(ctx)
-	w.Event(progress.CreatingEvent(name))
+	w.Event(progress.CreatingEvent(name).WithResource(progress.ContainerKind, name))
 	err := s.runContainer(ctx, project, service, name, number, nil)
 	if err != nil {
 		return err
 	}
-	w.Event(progress.CreatedEvent(name))
+	w.Event(progress.CreatedEvent(name).WithResource(progress.ContainerKind, name))
 	return nil
 }
 
 func (s *composeService) recreateContainer(ctx context.Context, project *types.Project, service types.ServiceConfig, container moby.Container) error {
 	w := progress.ContextWriter(ctx)
-	w.Event(progress.NewEvent(getContainerName(container), progress.Working, "Recreate"))
+	name := getContainerName(container)
+	w.Event(progress.NewEvent(name, progress.Working, "Recreate").WithResource(progress.ContainerKind, name))
 	err := s.apiClient.ContainerStop(ctx, container.ID, nil)
 	if err != nil {
 		return err
 	}
-	name := getContainerName(container)
 	tmpName := fmt.Sprintf("%s_%s", container.ID[:12], name)
 	err = s.apiClient.ContainerRename(ctx, container.ID, tmpName)
 	if err != nil {
 		return err
 	}
-	number, err := strconv.Atoi(container.Labels[containerNumberLabel])
+	number, err := strconv.Atoi(container.Labels[containerNumberLabel()])
 	if err != nil {
 		return err
 	}
@@ -195,36 +214,106 @@ func (s *composeService) recreateContainer(ctx context.Context, project *types.P
 	if err != nil {
 		return err
 	}
+	// logs of the replaced container are the ones needed to understand why it had to be
+	if err := s.saveReplacedLogs(ctx, project.Name, container); err != nil {
+		logrus.Warnf("failed to save logs of container %s: %v", name, err)
+	}
 	err = s.apiClient.ContainerRemove(ctx, container.ID, moby.ContainerRemoveOptions{})
 	if err != nil {
 		return err
 	}
-	w.Event(progress.NewEvent(getContainerName(container), progress.Done, "Recreated"))
-	setDependentLifecycle(project, service.Name, forceRecreate)
+	w.Event(progress.NewEvent(name, progress.Done, "Recreated").WithResource(progress.ContainerKind, name))
+	setDependentLifecycle(project, service.Name)
 	return nil
 }
 
-// setDependentLifecycle define the Lifecycle strategy for all services to depend on specified service
-func setDependentLifecycle(project *types.Project, service string, strategy string) {
+// dependentLifecycleMutex serializes updates of dependents lifecycle, as the replicas of a service, or services
+// sharing a dependent, get recreated concurrently
+var dependentLifecycleMutex sync.Mutex
+
+// setDependentLifecycle define the Lifecycle strategy for all services to depend on specified service once it has
+// been recreated
+func setDependentLifecycle(project *types.Project, service string) {
+	dependentLifecycleMutex.Lock()
+	defer dependentLifecycleMutex.Unlock()
 	for i, s := range project.Services {
-		if contains(s.GetDependencies(), service) {
-			if s.Extensions == nil {
-				s.Extensions = map[string]interface{}{}
-			}
-			s.Extensions[extLifecycle] = strategy
-			project.Services[i] = s
+		strategy := dependentLifecycle(s, service)
+		if strategy == "" || s.Extensions[extLifecycle] == forceRecreate {
+			continue
+		}
+		if s.Extensions == nil {
+			s.Extensions = map[string]interface{}{}
+		}
+		s.Extensions[extLifecycle] = strategy
+		project.Services[i] = s
+	}
+}
+
+// dependentLifecycle tells how dependent has to be updated after dependency has been recreated. Dependents are
+// recreated, but those only declaring dependency in depends_on, and listing it in x-depends_on_restart, which don't
+// refer to its container and just need to reconnect, are restarted
+func dependentLifecycle(dependent types.ServiceConfig, dependency string) string {
+	if !contains(getDependencies(dependent), dependency) {
+		return ""
+	}
+	withoutDependsOn := dependent
+	withoutDependsOn.DependsOn = nil
+	if contains(getDependencies(withoutDependsOn), dependency) {
+		return forceRecreate
+	}
+	restart, _ := getDependsOnRestart(dependent)
+	if contains(restart, dependency) {
+		return restartOnly
+	}
+	return forceRecreate
+}
+
+// extDependsOnRestart lists the dependencies whose recreation restarts service, as depends_on `restart: true` does.
+// The compose-go version in use only accepts condition in depends_on entries
+const extDependsOnRestart = "x-depends_on_restart"
+
+// getDependsOnRestart validates service x-depends_on_restart, which must only list services declared in depends_on
+func getDependsOnRestart(s types.ServiceConfig) ([]string, error) {
+	value, ok := s.Extensions[extDependsOnRestart]
+	if !ok {
+		return nil, nil
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("service %q: invalid %s %v, must be a list of services", s.Name, extDependsOnRestart, value)
+	}
+	var dependencies []string
+	for _, v := range values {
+		name := fmt.Sprint(v)
+		if _, ok := s.DependsOn[name]; !ok {
+			return nil, fmt.Errorf("service %q: %s lists %q, which isn't declared in depends_on", s.Name, extDependsOnRestart, name)
 		}
+		dependencies = append(dependencies, name)
 	}
+	return dependencies, nil
 }
 
 func (s *composeService) restartContainer(ctx context.Context, container moby.Container) error {
 	w := progress.ContextWriter(ctx)
-	w.Event(progress.NewEvent(getContainerName(container), progress.Working, "Restart"))
+	name := getContainerName(container)
+	w.Event(progress.NewEvent(name, progress.Working, "Restart").WithResource(progress.ContainerKind, name))
 	err := s.apiClient.ContainerStart(ctx, container.ID, moby.ContainerStartOptions{})
 	if err != nil {
 		return err
 	}
-	w.Event(progress.NewEvent(getContainerName(container), progress.Done, "Restarted"))
+	w.Event(progress.NewEvent(name, progress.Done, "Restarted").WithResource(progress.ContainerKind, name))
+	return nil
+}
+
+func (s *composeService) restartRunningContainer(ctx context.Context, container moby.Container) error {
+	w := progress.ContextWriter(ctx)
+	name := getContainerName(container)
+	w.Event(progress.NewEvent(name, progress.Working, "Restart").WithResource(progress.ContainerKind, name))
+	err := s.apiClient.ContainerRestart(ctx, container.ID, nil)
+	if err != nil {
+		return err
+	}
+	w.Event(progress.NewEvent(name, progress.Done, "Restarted").WithResource(progress.ContainerKind, name))
 	return nil
 }
 
@@ -233,12 +322,41 @@ func (s *composeService) runContainer(ctx context.Context, project *types.Projec
 	if err != nil {
 		return err
 	}
+	if err := s.applyVolumesFrom(ctx, project, service, hostConfig); err != nil {
+		return err
+	}
+	for _, warning := range downgradeForAPIVersion(s.apiClient.ClientVersion(), service.Name, containerConfig, hostConfig) {
+		logrus.Warn(warning)
+	}
+	if interval, err := getHealthStartInterval(service); err == nil && interval > 0 {
+		ctx = withHealthStartInterval(ctx, interval)
+	}
+	imageID, digest, err := s.imageIdentity(ctx, containerConfig.Image)
+	if err != nil {
+		return err
+	}
+	if digest != "" {
+		containerConfig.Labels[imageDigestLabel()] = digest
+	}
+	hash, err := serviceHash(service, imageID)
+	if err != nil {
+		return err
+	}
+	containerConfig.Labels[configHashLabel()] = hash
 	created, err := s.apiClient.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, nil, name)
 	if err != nil {
 		return err
 	}
 	id := created.ID
-	for netName := range service.Networks {
+	networks, err := getNetworksByPriority(service)
+	if err != nil {
+		return err
+	}
+	for _, netName := range networks {
+		if _, ok := service.Networks[netName]; !ok {
+			// service without networks only joins the default network it was created on
+			continue
+		}
 		network := project.Networks[netName]
 		err = s.connectContainerToNetwork(ctx, id, service.Name, network.Name)
 		if err != nil {
@@ -261,8 +379,8 @@ func (s *composeService) connectContainerToNetwork(ctx context.Context, id strin
 func (s *composeService) isServiceHealthy(ctx context.Context, project *types.Project, service string) (bool, error) {
 	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
 		Filters: filters.NewArgs(
-			filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, project.Name)),
-			filters.Arg("label", fmt.Sprintf("%s=%s", serviceLabel, service)),
+			filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel(), project.Name)),
+			filters.Arg("label", fmt.Sprintf("%s=%s", serviceLabel(), service)),
 		),
 	})
 	if err != nil {
@@ -296,6 +414,7 @@ func (s *composeService) startService(ctx context.Context, project *types.Projec
 		Filters: filters.NewArgs(
 			projectFilter(project.Name),
 			serviceFilter(service.Name),
+			filters.Arg("label", fmt.Sprintf("%s=%s", oneoffLabel(), "False")),
 		),
 		All: true,
 	})
@@ -310,12 +429,19 @@ func (s *composeService) startService(ctx context.Context, project *types.Projec
 		}
 		eg.Go(func() error {
 			w := progress.ContextWriter(ctx)
-			w.Event(progress.StartingEvent(getContainerName(container)))
+			name := getContainerName(container)
+			w.Event(progress.StartingEvent(name).WithResource(progress.ContainerKind, name))
 			err := s.apiClient.ContainerStart(ctx, container.ID, moby.ContainerStartOptions{})
-			if err == nil {
-				w.Event(progress.StartedEvent(getContainerName(container)))
+			if err != nil {
+				return err
 			}
-			return err
+			event := progress.StartedEvent(name).WithResource(progress.ContainerKind, name)
+			// ports get published by the engine once the container is started
+			if inspected, err := s.apiClient.ContainerInspect(ctx, container.ID); err == nil && inspected.NetworkSettings != nil {
+				event.Ports = bindingPorts(inspected.NetworkSettings.Ports)
+			}
+			w.Event(event)
+			return nil
 		})
 	}
 	return eg.Wait()
@@ -0,0 +1,208 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestSetDependentLifecycle(t *testing.T) {
+	restartOnDB := func() map[string]interface{} {
+		return map[string]interface{}{extDependsOnRestart: []interface{}{"db"}}
+	}
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name: "db",
+			},
+			{
+				Name: "api",
+				DependsOn: types.DependsOnConfig{
+					"db": types.ServiceDependency{Condition: types.ServiceConditionStarted},
+				},
+				Extensions: restartOnDB(),
+			},
+			{
+				Name: "worker",
+				DependsOn: types.DependsOnConfig{
+					"db": types.ServiceDependency{Condition: types.ServiceConditionStarted},
+				},
+			},
+			{
+				Name:        "proxy",
+				NetworkMode: "service:db",
+				DependsOn: types.DependsOnConfig{
+					"db": types.ServiceDependency{Condition: types.ServiceConditionStarted},
+				},
+				Extensions: restartOnDB(),
+			},
+			{
+				Name:  "admin",
+				Links: []string{"db:database"},
+			},
+			{
+				Name: "cache",
+			},
+		},
+	}
+
+	// replicas of db get recreated concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			setDependentLifecycle(&project, "db")
+		}()
+	}
+	wg.Wait()
+
+	lifecycles := map[string]interface{}{}
+	for _, s := range project.Services {
+		lifecycles[s.Name] = s.Extensions[extLifecycle]
+	}
+	assert.DeepEqual(t, lifecycles, map[string]interface{}{
+		"db":     nil,
+		"api":    restartOnly,
+		"worker": forceRecreate,
+		"proxy":  forceRecreate,
+		"admin":  forceRecreate,
+		"cache":  nil,
+	})
+}
+
+func TestSetDependentLifecycleKeepsForceRecreate(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name: "db",
+			},
+			{
+				Name: "api",
+				DependsOn: types.DependsOnConfig{
+					"db": types.ServiceDependency{Condition: types.ServiceConditionStarted},
+				},
+				Extensions: map[string]interface{}{
+					extDependsOnRestart: []interface{}{"db"},
+					extLifecycle:        forceRecreate,
+				},
+			},
+		},
+	}
+
+	setDependentLifecycle(&project, "db")
+
+	assert.Equal(t, project.Services[1].Extensions[extLifecycle], forceRecreate)
+}
+
+func TestGetDependsOnRestart(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "api",
+		DependsOn: types.DependsOnConfig{
+			"db": types.ServiceDependency{Condition: types.ServiceConditionStarted},
+		},
+	}
+	restart, err := getDependsOnRestart(service)
+	assert.NilError(t, err)
+	assert.Equal(t, len(restart), 0)
+
+	service.Extensions = map[string]interface{}{extDependsOnRestart: []interface{}{"db"}}
+	restart, err = getDependsOnRestart(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, restart, []string{"db"})
+
+	service.Extensions[extDependsOnRestart] = []interface{}{"cache"}
+	_, err = getDependsOnRestart(service)
+	assert.Error(t, err, `service "api": x-depends_on_restart lists "cache", which isn't declared in depends_on`)
+
+	service.Extensions[extDependsOnRestart] = "db"
+	_, err = getDependsOnRestart(service)
+	assert.Error(t, err, `service "api": invalid x-depends_on_restart db, must be a list of services`)
+}
+
+func TestServiceHashIncludesImageID(t *testing.T) {
+	web := types.ServiceConfig{Name: "web", Image: "nginx:latest"}
+
+	legacy, err := jsonHash(web)
+	assert.NilError(t, err)
+	unknown, err := serviceHash(web, "")
+	assert.NilError(t, err)
+	assert.Equal(t, unknown, legacy)
+
+	created, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	same, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Equal(t, same, created)
+
+	// tag moved to another image, configuration is unchanged
+	moved, err := serviceHash(web, "sha256:new")
+	assert.NilError(t, err)
+	assert.Assert(t, moved != created)
+
+	// resolved image ID doesn't leak into the hash through extensions
+	web.Extensions = map[string]interface{}{extImageID: "sha256:new"}
+	withExtension, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Equal(t, withExtension, created)
+}
+
+func TestServiceHashIncludesAppliedExtensions(t *testing.T) {
+	web := types.ServiceConfig{Name: "web", Image: "nginx:latest"}
+	legacy, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+
+	web.Extensions = map[string]interface{}{extPidsLimit: 10}
+	limited, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Assert(t, limited != legacy)
+
+	web.Extensions[extPidsLimit] = 200
+	raised, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Assert(t, raised != limited)
+
+	web.Extensions[extBlkioConfig] = map[string]interface{}{"weight": 300}
+	weighted, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Assert(t, weighted != raised)
+
+	web.Networks = map[string]*types.ServiceNetworkConfig{
+		"front": {Extensions: map[string]interface{}{extNetworkPriority: 10}},
+	}
+	prioritized, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Assert(t, prioritized != weighted)
+
+	web.HealthCheck = &types.HealthCheckConfig{Extensions: map[string]interface{}{extHealthStartInterval: "1s"}}
+	probed, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Assert(t, probed != prioritized)
+
+	// named generic resources are only honored by orchestrating backends
+	web.Deploy = &types.DeployConfig{Resources: types.Resources{Reservations: &types.Resource{}}}
+	unreserved, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	web.Deploy.Resources.Reservations.Extensions = map[string]interface{}{extGenericResources: []interface{}{"FPGA=fpga-1"}}
+	reserved, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+	assert.Equal(t, reserved, unreserved)
+}
@@ -18,16 +18,23 @@ package compose
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"math"
+	"os"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/docker/compose-cli/api/compose"
 	convert "github.com/docker/compose-cli/local/moby"
 	"github.com/docker/compose-cli/progress"
 
 	"github.com/compose-spec/compose-go/types"
 	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/blkiodev"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
@@ -35,23 +42,108 @@ import (
 	volume_api "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
-func (s *composeService) Create(ctx context.Context, project *types.Project) error {
-	err := s.ensureImagesExists(ctx, project)
+func (s *composeService) Create(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
+	if err := s.checkBindSources(project.Services); err != nil {
+		return err
+	}
+	err := s.ensureImages(ctx, project, imagesOptions{
+		quietBuild:       opts.QuietBuild,
+		quietPull:        opts.QuietPull,
+		verifySignatures: opts.VerifySignatures,
+		verbose:          opts.Verbose,
+		sinceBuild:       opts.SinceBuild,
+	})
+	if err != nil {
+		return err
+	}
+	warnings, err := s.bindOverDirectoryWarnings(ctx, project)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		logrus.Warn(warning)
+	}
+	for _, warning := range dropHealthStartInterval(s.apiClient.ClientVersion(), project.Services) {
+		logrus.Warn(warning)
+	}
+
+	if !opts.Offline && opts.Pull != "" {
+		err = s.checkImagesDigest(ctx, project, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, warning := range templateDriverWarnings(project) {
+		logrus.Warn(warning)
+	}
+	for _, warning := range externalSecretWarnings(project) {
+		logrus.Warn(warning)
+	}
+	for _, warning := range genericResourcesWarnings(project) {
+		logrus.Warn(warning)
+	}
+
+	if opts.EnvFile != "" {
+		if project.Extensions == nil {
+			project.Extensions = map[string]interface{}{}
+		}
+		project.Extensions[extEnvFile] = opts.EnvFile
+	}
+
+	// services get their own extensions map, shared with the copies used while walking the dependency graph, so
+	// that the lifecycle set on dependents when a service is recreated is visible when they get converged
+	for i, service := range project.Services {
+		if _, err := getDependsOnRestart(service); err != nil {
+			return err
+		}
+		if service.Extensions == nil {
+			service.Extensions = map[string]interface{}{}
+		}
+		if opts.Recreate == compose.RecreateForce {
+			service.Extensions[extLifecycle] = forceRecreate
+		}
+		project.Services[i] = service
+	}
+
+	if opts.ReplaceImageOnTagChange {
+		err = s.resolveImageIDs(ctx, project, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.ensureProjectResources(ctx, project)
+	if err != nil {
+		return err
+	}
+	err = writeEnvironmentSecrets(project)
 	if err != nil {
 		return err
 	}
 
+	err = InDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
+		return s.ensureService(c, project, service)
+	})
+	if err != nil {
+		return err
+	}
+	return s.pruneImageVolumes(ctx, project)
+}
+
+// ensureProjectResources creates project networks and volumes if missing
+func (s *composeService) ensureProjectResources(ctx context.Context, project *types.Project) error {
 	for k, network := range project.Networks {
 		if !network.External.External && network.Name != "" {
-			network.Name = fmt.Sprintf("%s_%s", project.Name, k)
+			network.Name = compose.ResourceName(project.Name, k)
 			project.Networks[k] = network
 		}
-		network.Labels = network.Labels.Add(networkLabel, k)
-		network.Labels = network.Labels.Add(projectLabel, project.Name)
-		network.Labels = network.Labels.Add(versionLabel, ComposeVersion)
+		network.Labels = getNetworkLabels(project.Name, k, network)
 		err := s.ensureNetwork(ctx, network)
 		if err != nil {
 			return err
@@ -59,26 +151,34 @@ func (s *composeService) Create(ctx context.Context, project *types.Project) err
 	}
 
 	for k, volume := range project.Volumes {
-		if !volume.External.External && volume.Name != "" {
-			volume.Name = fmt.Sprintf("%s_%s", project.Name, k)
+		if volume.External.External {
+			volume.Name = externalVolumeName(k, volume)
 			project.Volumes[k] = volume
+			err := s.ensureExternalVolume(ctx, volume)
+			if err != nil {
+				return err
+			}
+			continue
 		}
-		volume.Labels = volume.Labels.Add(volumeLabel, k)
-		volume.Labels = volume.Labels.Add(projectLabel, project.Name)
-		volume.Labels = volume.Labels.Add(versionLabel, ComposeVersion)
+		volume.Name = volumeName(project.Name, k, volume)
+		if volume.Name != compose.ResourceName(project.Name, k) {
+			logrus.Warnf("volume %q is created as %q, without project prefix: it might collide with another project using the same name", k, volume.Name)
+		}
+		project.Volumes[k] = volume
+		volume.Labels = volume.Labels.Add(volumeLabel(), k)
+		volume.Labels = volume.Labels.Add(projectLabel(), project.Name)
+		volume.Labels = volume.Labels.Add(versionLabel(), ComposeVersion)
 		err := s.ensureVolume(ctx, volume)
 		if err != nil {
 			return err
 		}
 	}
 
-	return InDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
-		return s.ensureService(c, project, service)
-	})
+	return s.ensureImageVolumes(ctx, project)
 }
 
 func getContainerCreateOptions(p *types.Project, s types.ServiceConfig, number int, inherit *moby.Container) (*container.Config, *container.HostConfig, *network.NetworkingConfig, error) {
-	hash, err := jsonHash(s)
+	hash, err := serviceHash(s, "")
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -88,15 +188,17 @@ func getContainerCreateOptions(p *types.Project, s types.ServiceConfig, number i
 		labels[k] = v
 	}
 
-	// TODO: change oneoffLabel value for containers started with `docker compose run`
-	labels[projectLabel] = p.Name
-	labels[serviceLabel] = s.Name
-	labels[versionLabel] = ComposeVersion
-	labels[oneoffLabel] = "False"
-	labels[configHashLabel] = hash
-	labels[workingDirLabel] = p.WorkingDir
-	labels[configFilesLabel] = strings.Join(p.ComposeFiles, ",")
-	labels[containerNumberLabel] = strconv.Itoa(number)
+	labels[projectLabel()] = p.Name
+	labels[serviceLabel()] = s.Name
+	labels[versionLabel()] = ComposeVersion
+	labels[oneoffLabel()] = "False"
+	labels[configHashLabel()] = hash
+	labels[workingDirLabel()] = p.WorkingDir
+	labels[configFilesLabel()] = strings.Join(p.ComposeFiles, ",")
+	labels[containerNumberLabel()] = strconv.Itoa(number)
+	if envFile, ok := p.Extensions[extEnvFile].(string); ok {
+		labels[envFileLabel()] = envFile
+	}
 
 	var (
 		runCmd     strslice.StrSlice
@@ -110,7 +212,15 @@ func getContainerCreateOptions(p *types.Project, s types.ServiceConfig, number i
 	}
 	image := s.Image
 	if s.Image == "" {
-		image = fmt.Sprintf("%s_%s", p.Name, s.Name)
+		image = compose.ResourceName(p.Name, s.Name)
+	}
+
+	if _, err := getHealthStartInterval(s); err != nil {
+		return nil, nil, nil, err
+	}
+	healthcheck, err := getHealthCheck(s)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	var (
@@ -139,7 +249,7 @@ func getContainerCreateOptions(p *types.Project, s types.ServiceConfig, number i
 		Labels:          labels,
 		StopSignal:      s.StopSignal,
 		Env:             convert.ToMobyEnv(s.Environment),
-		Healthcheck:     convert.ToMobyHealthCheck(s.HealthCheck),
+		Healthcheck:     healthcheck,
 		// Volumes:         // FIXME unclear to me the overlap with HostConfig.Mounts
 		StopTimeout: convert.ToSeconds(s.StopGracePeriod),
 	}
@@ -149,8 +259,15 @@ func getContainerCreateOptions(p *types.Project, s types.ServiceConfig, number i
 		return nil, nil, nil, err
 	}
 	bindings := buildContainerBindingOptions(s)
+	resources, err := buildContainerResources(s)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	networkMode := getNetworkMode(p, s)
+	networkMode, err := getNetworkMode(p, s)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	hostConfig := container.HostConfig{
 		Mounts:         mountOptions,
 		CapAdd:         strslice.StrSlice(s.CapAdd),
@@ -161,12 +278,284 @@ func getContainerCreateOptions(p *types.Project, s types.ServiceConfig, number i
 		// ShmSize: , TODO
 		Sysctls:      s.Sysctls,
 		PortBindings: bindings,
+		Resources:    resources,
 	}
 
 	networkConfig := buildDefaultNetworkConfig(s, networkMode)
 	return &containerConfig, &hostConfig, networkConfig, nil
 }
 
+func buildContainerResources(s types.ServiceConfig) (container.Resources, error) {
+	pidsLimit, err := getPidsLimit(s)
+	if err != nil {
+		return container.Resources{}, err
+	}
+	memoryReservation, err := getMemoryReservation(s)
+	if err != nil {
+		return container.Resources{}, err
+	}
+	cpus, err := getNanoCPUs(s)
+	if err != nil {
+		return container.Resources{}, err
+	}
+	if err := checkCPUsReservation(s); err != nil {
+		return container.Resources{}, err
+	}
+	resources := container.Resources{
+		PidsLimit:         pidsLimit,
+		NanoCPUs:          cpus,
+		Memory:            int64(s.MemLimit),
+		MemoryReservation: memoryReservation,
+		CPUShares:         s.CPUShares,
+		CPUQuota:          s.CPUQuota,
+		CPUPeriod:         s.CPUPeriod,
+		CpusetCpus:        s.CPUSet,
+	}
+	blkio, err := getBlkioConfig(s)
+	if err != nil || blkio == nil {
+		return resources, err
+	}
+
+	resources.BlkioWeight = blkio.Weight
+	for _, d := range blkio.WeightDevice {
+		if err := checkBlkioDevice(d.Path); err != nil {
+			return container.Resources{}, err
+		}
+		resources.BlkioWeightDevice = append(resources.BlkioWeightDevice, &blkiodev.WeightDevice{
+			Path:   d.Path,
+			Weight: d.Weight,
+		})
+	}
+	throttles := []struct {
+		config []blkioThrottleDevice
+		target *[]*blkiodev.ThrottleDevice
+	}{
+		{blkio.DeviceReadBps, &resources.BlkioDeviceReadBps},
+		{blkio.DeviceReadIOps, &resources.BlkioDeviceReadIOps},
+		{blkio.DeviceWriteBps, &resources.BlkioDeviceWriteBps},
+		{blkio.DeviceWriteIOps, &resources.BlkioDeviceWriteIOps},
+	}
+	for _, throttle := range throttles {
+		for _, d := range throttle.config {
+			if err := checkBlkioDevice(d.Path); err != nil {
+				return container.Resources{}, err
+			}
+			rate, err := units.RAMInBytes(fmt.Sprint(d.Rate))
+			if err != nil || rate < 0 {
+				return container.Resources{}, fmt.Errorf("service %q: invalid blkio_config rate %v for device %s", s.Name, d.Rate, d.Path)
+			}
+			*throttle.target = append(*throttle.target, &blkiodev.ThrottleDevice{
+				Path: d.Path,
+				Rate: uint64(rate),
+			})
+		}
+	}
+	return resources, nil
+}
+
+// extBlkioConfig holds service blkio_config, which the compose-go version in use drops while loading, see
+// ApplySpecAttributes
+const extBlkioConfig = "x-blkio_config"
+
+type blkioConfig struct {
+	Weight          uint16                `json:"weight"`
+	WeightDevice    []blkioWeightDevice   `json:"weight_device"`
+	DeviceReadBps   []blkioThrottleDevice `json:"device_read_bps"`
+	DeviceReadIOps  []blkioThrottleDevice `json:"device_read_iops"`
+	DeviceWriteBps  []blkioThrottleDevice `json:"device_write_bps"`
+	DeviceWriteIOps []blkioThrottleDevice `json:"device_write_iops"`
+}
+
+type blkioWeightDevice struct {
+	Path   string `json:"path"`
+	Weight uint16 `json:"weight"`
+}
+
+type blkioThrottleDevice struct {
+	Path string `json:"path"`
+	// Rate is a number, or a byte size with unit like 1mb
+	Rate interface{} `json:"rate"`
+}
+
+// getBlkioConfig decodes service blkio_config, if set
+func getBlkioConfig(s types.ServiceConfig) (*blkioConfig, error) {
+	value, ok := s.Extensions[extBlkioConfig]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var config blkioConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, errors.Wrapf(err, "service %q: invalid blkio_config", s.Name)
+	}
+	return &config, nil
+}
+
+// templateDriverWarnings reports secrets and configs declaring a template_driver, which engine only applies to swarm
+// services
+func templateDriverWarnings(project *types.Project) []string {
+	var warnings []string
+	warn := func(kind, name, driver string) {
+		if driver != "" {
+			warnings = append(warnings, fmt.Sprintf("%s %q: template_driver %q isn't supported by local backend, ignoring", kind, name, driver))
+		}
+	}
+	for name, config := range project.Configs {
+		warn("config", name, config.TemplateDriver)
+	}
+	for name, secret := range project.Secrets {
+		warn("secret", name, secret.TemplateDriver)
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// getNanoCPUs resolves service cpus as billionths of CPU, falling back to deploy.resources.limits.cpus like
+// reservations are, whether in compatibility mode or not
+func getNanoCPUs(s types.ServiceConfig) (int64, error) {
+	if s.CPUS < 0 {
+		return 0, fmt.Errorf("service %q: cpus must be positive, got %g", s.Name, s.CPUS)
+	}
+	if s.CPUS != 0 || s.Deploy == nil || s.Deploy.Resources.Limits == nil || s.Deploy.Resources.Limits.NanoCPUs == "" {
+		return nanoCPUs(s.CPUS), nil
+	}
+	limit := s.Deploy.Resources.Limits.NanoCPUs
+	cpus, err := strconv.ParseFloat(limit, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "service %q: invalid deploy.resources.limits.cpus %q", s.Name, limit)
+	}
+	if cpus <= 0 {
+		return 0, fmt.Errorf("service %q: deploy.resources.limits.cpus must be positive, got %q", s.Name, limit)
+	}
+	return nanoCPUs(float32(cpus)), nil
+}
+
+// nanoCPUs converts a cpus fraction into billionths of CPU, formatting float32 back to its shortest decimal form to get rid of conversion noise
+func nanoCPUs(cpus float32) int64 {
+	value, _ := strconv.ParseFloat(strconv.FormatFloat(float64(cpus), 'f', -1, 32), 64)
+	return int64(math.Round(value * 1e9))
+}
+
+// checkBlkioDevice checks blkio_config device exists. Only relevant on Linux, other platforms run engine in a VM
+func checkBlkioDevice(path string) error {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return errors.Wrapf(err, "invalid blkio_config device %s", path)
+	}
+	return nil
+}
+
+// extPidsLimit holds service pids_limit, which the compose-go version in use drops while loading, see
+// ApplySpecAttributes
+const extPidsLimit = "x-pids_limit"
+
+// getPidsLimit validates service pids_limit, which must be a positive number or -1 for unlimited
+func getPidsLimit(s types.ServiceConfig) (*int64, error) {
+	value, ok := s.Extensions[extPidsLimit]
+	if !ok {
+		return nil, nil
+	}
+	limit, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+	if err != nil || limit == 0 || limit < -1 {
+		return nil, fmt.Errorf("service %q: invalid pids_limit %v, must be a positive number or -1 for unlimited", s.Name, value)
+	}
+	return &limit, nil
+}
+
+// extHealthStartInterval declares healthcheck start_interval, which the compose-go version in use doesn't parse
+const extHealthStartInterval = "x-start_interval"
+
+// getHealthStartInterval validates healthcheck start_interval, the probe interval during start_period
+func getHealthStartInterval(s types.ServiceConfig) (time.Duration, error) {
+	if s.HealthCheck == nil {
+		return 0, nil
+	}
+	value, ok := s.HealthCheck.Extensions[extHealthStartInterval]
+	if !ok {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("service %q: invalid healthcheck start_interval %v, must be a positive duration", s.Name, value)
+	}
+	return interval, nil
+}
+
+// getHealthCheck validates healthcheck test is one of the documented forms: a string run by the container shell,
+// ["CMD", args...], ["CMD-SHELL", command] or ["NONE"], the loader already turning a string into the CMD-SHELL form
+func getHealthCheck(s types.ServiceConfig) (*container.HealthConfig, error) {
+	if s.HealthCheck == nil {
+		return nil, nil
+	}
+	test := s.HealthCheck.Test
+	if s.HealthCheck.Disable {
+		if len(test) > 0 && test[0] != "NONE" {
+			return nil, fmt.Errorf("service %q: healthcheck disable can't be combined with a test command", s.Name)
+		}
+		return convert.ToMobyHealthCheck(s.HealthCheck), nil
+	}
+	if len(test) > 0 {
+		switch test[0] {
+		case "NONE":
+			if len(test) > 1 {
+				return nil, fmt.Errorf("service %q: healthcheck test [\"NONE\"] doesn't accept arguments", s.Name)
+			}
+		case "CMD", "CMD-SHELL":
+			if len(test) == 1 {
+				return nil, fmt.Errorf("service %q: healthcheck test %q requires a command", s.Name, test[0])
+			}
+		default:
+			return nil, fmt.Errorf("service %q: healthcheck test must be a string, or a list starting with \"CMD\", \"CMD-SHELL\" or \"NONE\", got %q", s.Name, test[0])
+		}
+	}
+	return convert.ToMobyHealthCheck(s.HealthCheck), nil
+}
+
+// getMemoryReservation resolves service mem_reservation, or deploy.resources.reservations.memory, which must not exceed memory limit
+func getMemoryReservation(s types.ServiceConfig) (int64, error) {
+	reservation, limit := s.MemReservation, s.MemLimit
+	if s.Deploy != nil {
+		if r := s.Deploy.Resources.Reservations; reservation == 0 && r != nil {
+			reservation = r.MemoryBytes
+		}
+		if l := s.Deploy.Resources.Limits; limit == 0 && l != nil {
+			limit = l.MemoryBytes
+		}
+	}
+	if limit != 0 && reservation > limit {
+		return 0, fmt.Errorf("service %q: memory reservation %s exceeds memory limit %s", s.Name, units.BytesSize(float64(reservation)), units.BytesSize(float64(limit)))
+	}
+	return int64(reservation), nil
+}
+
+// checkCPUsReservation validates deploy.resources.reservations.cpus doesn't exceed cpus limit. Engine has no cpus
+// reservation, so this one is only honored by orchestrating backends
+func checkCPUsReservation(s types.ServiceConfig) error {
+	if s.Deploy == nil || s.Deploy.Resources.Reservations == nil || s.Deploy.Resources.Reservations.NanoCPUs == "" {
+		return nil
+	}
+	reservation, err := strconv.ParseFloat(s.Deploy.Resources.Reservations.NanoCPUs, 64)
+	if err != nil {
+		return errors.Wrapf(err, "service %q: invalid deploy.resources.reservations.cpus", s.Name)
+	}
+	limit := float64(s.CPUS)
+	if l := s.Deploy.Resources.Limits; limit == 0 && l != nil && l.NanoCPUs != "" {
+		limit, err = strconv.ParseFloat(l.NanoCPUs, 64)
+		if err != nil {
+			return errors.Wrapf(err, "service %q: invalid deploy.resources.limits.cpus", s.Name)
+		}
+	}
+	if limit != 0 && reservation > limit {
+		return fmt.Errorf("service %q: cpus reservation %g exceeds cpus limit %g", s.Name, reservation, limit)
+	}
+	return nil
+}
+
 func buildContainerPorts(s types.ServiceConfig) nat.PortSet {
 	ports := nat.PortSet{}
 	for _, p := range s.Ports {
@@ -223,15 +612,27 @@ func buildContainerMountOptions(p types.Project, s types.ServiceConfig, inherit
 		}
 		mounts = append(mounts, mount)
 	}
+
+	secrets, err := secretMounts(p, s)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range secrets {
+		if !contains(inherited, secret.Target) {
+			mounts = append(mounts, secret)
+		}
+	}
 	return mounts, nil
 }
 
 func buildMount(project types.Project, volume types.ServiceVolumeConfig) (mount.Mount, error) {
+	if volume.Type == volumeTypeImage {
+		return buildImageMount(project, volume)
+	}
 	source := volume.Source
-	if volume.Type == types.VolumeTypeBind && !filepath.IsAbs(source) {
-		// volume source has already been prefixed with workdir if required, by compose-go project loader
+	if volume.Type == types.VolumeTypeBind {
 		var err error
-		source, err = filepath.Abs(source)
+		source, err = bindSource(volume)
 		if err != nil {
 			return mount.Mount{}, err
 		}
@@ -240,21 +641,47 @@ func buildMount(project types.Project, volume types.ServiceVolumeConfig) (mount.
 		pVolume, ok := project.Volumes[volume.Source]
 		if ok {
 			source = pVolume.Name
+			if pVolume.External.External {
+				source = externalVolumeName(volume.Source, pVolume)
+			}
 		}
 	}
 
+	consistency, err := buildMountConsistency(volume)
+	if err != nil {
+		return mount.Mount{}, err
+	}
+
 	return mount.Mount{
 		Type:          mount.Type(volume.Type),
 		Source:        source,
 		Target:        volume.Target,
 		ReadOnly:      volume.ReadOnly,
-		Consistency:   mount.Consistency(volume.Consistency),
+		Consistency:   consistency,
 		BindOptions:   buildBindOption(volume.Bind),
 		VolumeOptions: buildVolumeOptions(volume.Volume),
 		TmpfsOptions:  buildTmpfsOptions(volume.Tmpfs),
 	}, nil
 }
 
+// buildMountConsistency validates volume consistency, which only applies to bind mounts on Docker Desktop for Mac
+func buildMountConsistency(volume types.ServiceVolumeConfig) (mount.Consistency, error) {
+	consistency := mount.Consistency(volume.Consistency)
+	switch consistency {
+	case "", mount.ConsistencyDefault:
+		return consistency, nil
+	case mount.ConsistencyFull, mount.ConsistencyCached, mount.ConsistencyDelegated:
+		if volume.Type != types.VolumeTypeBind {
+			logrus.Warnf("consistency %q ignored on %s mount %s, only applies to bind mounts", consistency, volume.Type, volume.Target)
+			return "", nil
+		}
+		return consistency, nil
+	default:
+		return "", fmt.Errorf("invalid consistency %q for mount %s, must be one of %q, %q or %q", consistency, volume.Target,
+			mount.ConsistencyFull, mount.ConsistencyCached, mount.ConsistencyDelegated)
+	}
+}
+
 func buildBindOption(bind *types.ServiceVolumeBind) *mount.BindOptions {
 	if bind == nil {
 		return nil
@@ -306,15 +733,17 @@ func getAliases(s types.ServiceConfig, c *types.ServiceNetworkConfig) []string {
 	return aliases
 }
 
-func getNetworkMode(p *types.Project, service types.ServiceConfig) container.NetworkMode {
+func getNetworkMode(p *types.Project, service types.ServiceConfig) (container.NetworkMode, error) {
 	mode := service.NetworkMode
 	if mode == "" {
 		if len(p.Networks) > 0 {
-			for name := range getNetworksForService(service) {
-				return container.NetworkMode(p.Networks[name].Name)
+			networks, err := getNetworksByPriority(service)
+			if err != nil {
+				return "", err
 			}
+			return container.NetworkMode(p.Networks[networks[0]].Name), nil
 		}
-		return container.NetworkMode("none")
+		return container.NetworkMode("none"), nil
 	}
 
 	// FIXME incomplete implementation
@@ -325,7 +754,41 @@ func getNetworkMode(p *types.Project, service types.ServiceConfig) container.Net
 		panic("Not yet implemented")
 	}
 
-	return container.NetworkMode(mode)
+	return container.NetworkMode(mode), nil
+}
+
+// extNetworkPriority holds service network priority, which the compose-go version in use drops while loading, see
+// ApplySpecAttributes
+const extNetworkPriority = "x-priority"
+
+// getNetworksByPriority lists the networks a service joins, highest priority first, then by name. Containers are
+// created on the first one, which engine takes the default gateway from, then connected to the others in this order
+func getNetworksByPriority(s types.ServiceConfig) ([]string, error) {
+	networks := getNetworksForService(s)
+	priorities := map[string]int{}
+	var names []string
+	for name, config := range networks {
+		names = append(names, name)
+		if config == nil {
+			continue
+		}
+		value, ok := config.Extensions[extNetworkPriority]
+		if !ok {
+			continue
+		}
+		priority, ok := value.(int)
+		if !ok {
+			return nil, fmt.Errorf("service %q: network %q priority must be an integer, got %v", s.Name, name, value)
+		}
+		priorities[name] = priority
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if priorities[names[i]] != priorities[names[j]] {
+			return priorities[names[i]] > priorities[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names, nil
 }
 
 func getNetworksForService(s types.ServiceConfig) map[string]*types.ServiceNetworkConfig {
@@ -335,8 +798,30 @@ func getNetworksForService(s types.ServiceConfig) map[string]*types.ServiceNetwo
 	return map[string]*types.ServiceNetworkConfig{"default": nil}
 }
 
+// getNetworkLabels merges user defined labels for a network with the ones compose relies on to track project resources
+func getNetworkLabels(projectName string, key string, n types.NetworkConfig) types.Labels {
+	labels := types.Labels{}
+	for k, v := range n.Labels {
+		labels[k] = v
+	}
+	labels[networkLabel()] = key
+	labels[projectLabel()] = projectName
+	labels[versionLabel()] = ComposeVersion
+	return labels
+}
+
 func (s *composeService) ensureNetwork(ctx context.Context, n types.NetworkConfig) error {
-	_, err := s.apiClient.NetworkInspect(ctx, n.Name, moby.NetworkInspectOptions{})
+	existing, err := s.apiClient.NetworkInspect(ctx, n.Name, moby.NetworkInspectOptions{})
+	if err == nil && !n.External.External {
+		for k, v := range n.Labels {
+			if k == versionLabel() {
+				continue
+			}
+			if actual, ok := existing.Labels[k]; !ok || actual != v {
+				logrus.Warnf("network %s already exists with a different value for label %q, it must be removed for label to be applied", n.Name, k)
+			}
+		}
+	}
 	if err != nil {
 		if errdefs.IsNotFound(err) {
 			if n.External.External {
@@ -367,12 +852,12 @@ func (s *composeService) ensureNetwork(ctx context.Context, n types.NetworkConfi
 			}
 			networkEventName := fmt.Sprintf("Network %q", n.Name)
 			w := progress.ContextWriter(ctx)
-			w.Event(progress.CreatingEvent(networkEventName))
+			w.Event(progress.CreatingEvent(networkEventName).WithResource(progress.NetworkKind, n.Name))
 			if _, err := s.apiClient.NetworkCreate(ctx, n.Name, createOpts); err != nil {
-				w.Event(progress.ErrorEvent(networkEventName))
+				w.Event(progress.ErrorEvent(networkEventName).WithResource(progress.NetworkKind, n.Name))
 				return errors.Wrapf(err, "failed to create network %s", n.Name)
 			}
-			w.Event(progress.CreatedEvent(networkEventName))
+			w.Event(progress.CreatedEvent(networkEventName).WithResource(progress.NetworkKind, n.Name))
 			return nil
 		}
 		return err
@@ -383,14 +868,45 @@ func (s *composeService) ensureNetwork(ctx context.Context, n types.NetworkConfi
 func (s *composeService) ensureNetworkDown(ctx context.Context, networkID string, networkName string) error {
 	w := progress.ContextWriter(ctx)
 	eventName := fmt.Sprintf("Network %q", networkName)
-	w.Event(progress.RemovingEvent(eventName))
+	w.Event(progress.RemovingEvent(eventName).WithResource(progress.NetworkKind, networkName))
 
 	if err := s.apiClient.NetworkRemove(ctx, networkID); err != nil {
-		w.Event(progress.ErrorEvent(eventName))
+		w.Event(progress.ErrorEvent(eventName).WithResource(progress.NetworkKind, networkName))
 		return errors.Wrapf(err, fmt.Sprintf("failed to create network %s", networkID))
 	}
 
-	w.Event(progress.RemovedEvent(eventName))
+	w.Event(progress.RemovedEvent(eventName).WithResource(progress.NetworkKind, networkName))
+	return nil
+}
+
+// externalVolumeName resolves the actual name of an external volume, supporting legacy `external: { name: xx }` syntax
+func externalVolumeName(key string, volume types.VolumeConfig) string {
+	if volume.External.Name != "" {
+		return volume.External.Name
+	}
+	if volume.Name != "" {
+		return volume.Name
+	}
+	return key
+}
+
+// volumeName resolves the actual name of a project volume. An explicit `name` is used as is, otherwise the name is
+// prefixed by project name. As compose-go loader defaults name to the volume key, a `name` equal to the key is prefixed
+func volumeName(projectName string, key string, volume types.VolumeConfig) string {
+	if volume.Name != "" && volume.Name != key {
+		return volume.Name
+	}
+	return compose.ResourceName(projectName, key)
+}
+
+func (s *composeService) ensureExternalVolume(ctx context.Context, volume types.VolumeConfig) error {
+	_, err := s.apiClient.VolumeInspect(ctx, volume.Name)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return fmt.Errorf("volume %s declared as external, but could not be found", volume.Name)
+		}
+		return err
+	}
 	return nil
 }
 
@@ -403,7 +919,7 @@ func (s *composeService) ensureVolume(ctx context.Context, volume types.VolumeCo
 		}
 		eventName := fmt.Sprintf("Volume %q", volume.Name)
 		w := progress.ContextWriter(ctx)
-		w.Event(progress.CreatingEvent(eventName))
+		w.Event(progress.CreatingEvent(eventName).WithResource(progress.VolumeKind, volume.Name))
 		// TODO we miss support for driver_opts and labels
 		_, err := s.apiClient.VolumeCreate(ctx, volume_api.VolumeCreateBody{
 			Labels:     volume.Labels,
@@ -412,10 +928,10 @@ func (s *composeService) ensureVolume(ctx context.Context, volume types.VolumeCo
 			DriverOpts: volume.DriverOpts,
 		})
 		if err != nil {
-			w.Event(progress.ErrorEvent(eventName))
+			w.Event(progress.ErrorEvent(eventName).WithResource(progress.VolumeKind, volume.Name))
 			return err
 		}
-		w.Event(progress.CreatedEvent(eventName))
+		w.Event(progress.CreatedEvent(eventName).WithResource(progress.VolumeKind, volume.Name))
 	}
 	return nil
 }
@@ -19,9 +19,12 @@ package compose
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	composetypes "github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/blkiodev"
 	mountTypes "github.com/docker/docker/api/types/mount"
 	"gotest.tools/v3/assert"
 )
@@ -60,3 +63,407 @@ func TestBuildVolumeMount(t *testing.T) {
 	assert.Equal(t, mount.Source, "myProject_myVolume")
 	assert.Equal(t, mount.Type, mountTypes.TypeVolume)
 }
+
+func TestVolumeName(t *testing.T) {
+	assert.Equal(t, volumeName("myProject", "cache", composetypes.VolumeConfig{}), "myProject_cache")
+	assert.Equal(t, volumeName("myProject", "cache", composetypes.VolumeConfig{Name: "cache"}), "myProject_cache")
+	assert.Equal(t, volumeName("myProject", "cache", composetypes.VolumeConfig{Name: "shared_cache"}), "shared_cache")
+}
+
+func TestBuildNamedVolumeMount(t *testing.T) {
+	project := composetypes.Project{
+		Name: "myProject",
+		Volumes: composetypes.Volumes(map[string]composetypes.VolumeConfig{
+			"cache": {
+				Name: volumeName("myProject", "cache", composetypes.VolumeConfig{Name: "shared_cache"}),
+			},
+		}),
+	}
+	volume := composetypes.ServiceVolumeConfig{
+		Type:   composetypes.VolumeTypeVolume,
+		Source: "cache",
+		Target: "/cache",
+	}
+	mount, err := buildMount(project, volume)
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Source, "shared_cache")
+}
+
+func TestBuildExternalVolumeMount(t *testing.T) {
+	project := composetypes.Project{
+		Name: "myProject",
+		Volumes: composetypes.Volumes(map[string]composetypes.VolumeConfig{
+			"data": {
+				External: composetypes.External{
+					External: true,
+					Name:     "legacy_data",
+				},
+			},
+		}),
+	}
+	volume := composetypes.ServiceVolumeConfig{
+		Type:   composetypes.VolumeTypeVolume,
+		Source: "data",
+		Target: "/data",
+	}
+	mount, err := buildMount(project, volume)
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Source, "legacy_data")
+	assert.Equal(t, mount.Type, mountTypes.TypeVolume)
+}
+
+func TestNetworkLabels(t *testing.T) {
+	network := composetypes.NetworkConfig{
+		Name: "myProject_default",
+		Labels: composetypes.Labels{
+			"com.example.sdn": "enabled",
+		},
+	}
+	labels := getNetworkLabels("myProject", "default", network)
+	assert.DeepEqual(t, labels, composetypes.Labels{
+		"com.example.sdn":            "enabled",
+		"com.docker.compose.network": "default",
+		"com.docker.compose.project": "myProject",
+		"com.docker.compose.version": ComposeVersion,
+	})
+	// user defined labels must not be altered
+	assert.Equal(t, len(network.Labels), 1)
+}
+
+func TestGetPidsLimit(t *testing.T) {
+	pidsLimit := func(value interface{}) composetypes.ServiceConfig {
+		return composetypes.ServiceConfig{Name: "test", Extensions: map[string]interface{}{extPidsLimit: value}}
+	}
+	limit, err := getPidsLimit(composetypes.ServiceConfig{Name: "test"})
+	assert.NilError(t, err)
+	assert.Assert(t, limit == nil)
+
+	limit, err = getPidsLimit(pidsLimit(10))
+	assert.NilError(t, err)
+	assert.Equal(t, *limit, int64(10))
+
+	limit, err = getPidsLimit(pidsLimit("-1"))
+	assert.NilError(t, err)
+	assert.Equal(t, *limit, int64(-1))
+
+	_, err = getPidsLimit(pidsLimit(-2))
+	assert.ErrorContains(t, err, `service "test": invalid pids_limit -2`)
+	_, err = getPidsLimit(pidsLimit("many"))
+	assert.ErrorContains(t, err, `service "test": invalid pids_limit many`)
+}
+
+func TestGetMemoryReservation(t *testing.T) {
+	deploy := func(reservation, limit composetypes.UnitBytes) *composetypes.DeployConfig {
+		return &composetypes.DeployConfig{
+			Resources: composetypes.Resources{
+				Reservations: &composetypes.Resource{MemoryBytes: reservation},
+				Limits:       &composetypes.Resource{MemoryBytes: limit},
+			},
+		}
+	}
+
+	reservation, err := getMemoryReservation(composetypes.ServiceConfig{Name: "test"})
+	assert.NilError(t, err)
+	assert.Equal(t, reservation, int64(0))
+
+	reservation, err = getMemoryReservation(composetypes.ServiceConfig{Name: "test", Deploy: deploy(32*1024*1024, 0)})
+	assert.NilError(t, err)
+	assert.Equal(t, reservation, int64(32*1024*1024))
+
+	reservation, err = getMemoryReservation(composetypes.ServiceConfig{Name: "test", MemReservation: 16 * 1024 * 1024, Deploy: deploy(32*1024*1024, 0)})
+	assert.NilError(t, err)
+	assert.Equal(t, reservation, int64(16*1024*1024))
+
+	_, err = getMemoryReservation(composetypes.ServiceConfig{Name: "test", Deploy: deploy(64*1024*1024, 32*1024*1024)})
+	assert.ErrorContains(t, err, `service "test": memory reservation 64MiB exceeds memory limit 32MiB`)
+
+	_, err = getMemoryReservation(composetypes.ServiceConfig{Name: "test", MemLimit: 32 * 1024 * 1024, Deploy: deploy(64*1024*1024, 0)})
+	assert.ErrorContains(t, err, `service "test": memory reservation 64MiB exceeds memory limit 32MiB`)
+}
+
+func TestCheckCPUsReservation(t *testing.T) {
+	deploy := func(reservation, limit string) *composetypes.DeployConfig {
+		return &composetypes.DeployConfig{
+			Resources: composetypes.Resources{
+				Reservations: &composetypes.Resource{NanoCPUs: reservation},
+				Limits:       &composetypes.Resource{NanoCPUs: limit},
+			},
+		}
+	}
+
+	assert.NilError(t, checkCPUsReservation(composetypes.ServiceConfig{Name: "test"}))
+	assert.NilError(t, checkCPUsReservation(composetypes.ServiceConfig{Name: "test", Deploy: deploy("0.25", "0.5")}))
+	assert.NilError(t, checkCPUsReservation(composetypes.ServiceConfig{Name: "test", Deploy: deploy("2", "")}))
+
+	err := checkCPUsReservation(composetypes.ServiceConfig{Name: "test", Deploy: deploy("1", "0.5")})
+	assert.ErrorContains(t, err, `service "test": cpus reservation 1 exceeds cpus limit 0.5`)
+
+	err = checkCPUsReservation(composetypes.ServiceConfig{Name: "test", CPUS: 0.5, Deploy: deploy("1", "")})
+	assert.ErrorContains(t, err, `service "test": cpus reservation 1 exceeds cpus limit 0.5`)
+}
+
+func TestGetNanoCPUs(t *testing.T) {
+	cpus, err := getNanoCPUs(composetypes.ServiceConfig{Name: "test"})
+	assert.NilError(t, err)
+	assert.Equal(t, cpus, int64(0))
+
+	cpus, err = getNanoCPUs(composetypes.ServiceConfig{Name: "test", CPUS: 1.5})
+	assert.NilError(t, err)
+	assert.Equal(t, cpus, int64(1500000000))
+
+	cpus, err = getNanoCPUs(composetypes.ServiceConfig{Name: "test", CPUS: 0.3})
+	assert.NilError(t, err)
+	assert.Equal(t, cpus, int64(300000000))
+
+	_, err = getNanoCPUs(composetypes.ServiceConfig{Name: "test", CPUS: -1})
+	assert.ErrorContains(t, err, `service "test": cpus must be positive, got -1`)
+
+	limited := func(cpus string) composetypes.ServiceConfig {
+		return composetypes.ServiceConfig{
+			Name: "test",
+			Deploy: &composetypes.DeployConfig{
+				Resources: composetypes.Resources{Limits: &composetypes.Resource{NanoCPUs: cpus}},
+			},
+		}
+	}
+	cpus, err = getNanoCPUs(limited("1.5"))
+	assert.NilError(t, err)
+	assert.Equal(t, cpus, int64(1500000000))
+
+	service := limited("1.5")
+	service.CPUS = 0.5
+	cpus, err = getNanoCPUs(service)
+	assert.NilError(t, err)
+	assert.Equal(t, cpus, int64(500000000))
+
+	_, err = getNanoCPUs(limited("0"))
+	assert.ErrorContains(t, err, `service "test": deploy.resources.limits.cpus must be positive, got "0"`)
+
+	_, err = getNanoCPUs(limited("many"))
+	assert.ErrorContains(t, err, `service "test": invalid deploy.resources.limits.cpus "many"`)
+}
+
+func TestGetHealthStartInterval(t *testing.T) {
+	check := func(value interface{}) composetypes.ServiceConfig {
+		return composetypes.ServiceConfig{
+			Name: "web",
+			HealthCheck: &composetypes.HealthCheckConfig{
+				Extensions: map[string]interface{}{extHealthStartInterval: value},
+			},
+		}
+	}
+
+	interval, err := getHealthStartInterval(composetypes.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	assert.Equal(t, interval, time.Duration(0))
+
+	interval, err = getHealthStartInterval(check("2s"))
+	assert.NilError(t, err)
+	assert.Equal(t, interval, 2*time.Second)
+
+	_, err = getHealthStartInterval(check("soon"))
+	assert.ErrorContains(t, err, `service "web": invalid healthcheck start_interval soon, must be a positive duration`)
+
+	_, err = getHealthStartInterval(check("-1s"))
+	assert.ErrorContains(t, err, `invalid healthcheck start_interval -1s`)
+}
+
+func TestGetHealthCheckTestForms(t *testing.T) {
+	project := loadTestProject(t, `
+services:
+  shell:
+    image: nginx
+    healthcheck:
+      test: curl -f localhost || exit 1
+  exec:
+    image: nginx
+    healthcheck:
+      test: ["CMD", "curl", "-f", "localhost"]
+  cmdshell:
+    image: nginx
+    healthcheck:
+      test: ["CMD-SHELL", "curl -f localhost || exit 1"]
+  none:
+    image: nginx
+    healthcheck:
+      test: ["NONE"]
+  disabled:
+    image: nginx
+    healthcheck:
+      disable: true
+  inherited:
+    image: nginx
+    healthcheck:
+      interval: 5s
+`)
+	expected := map[string][]string{
+		"shell":     {"CMD-SHELL", "curl -f localhost || exit 1"},
+		"exec":      {"CMD", "curl", "-f", "localhost"},
+		"cmdshell":  {"CMD-SHELL", "curl -f localhost || exit 1"},
+		"none":      {"NONE"},
+		"disabled":  {"NONE"},
+		"inherited": nil,
+	}
+	for name, test := range expected {
+		service, err := project.GetService(name)
+		assert.NilError(t, err)
+		healthcheck, err := getHealthCheck(service)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string(healthcheck.Test), test)
+	}
+
+	healthcheck, err := getHealthCheck(composetypes.ServiceConfig{Name: "web"})
+	assert.NilError(t, err)
+	assert.Assert(t, healthcheck == nil)
+}
+
+func TestGetHealthCheckInvalidTest(t *testing.T) {
+	check := func(test []string, disable bool) error {
+		_, err := getHealthCheck(composetypes.ServiceConfig{
+			Name:        "web",
+			HealthCheck: &composetypes.HealthCheckConfig{Test: test, Disable: disable},
+		})
+		return err
+	}
+	assert.ErrorContains(t, check([]string{"curl", "-f", "localhost"}, false),
+		`service "web": healthcheck test must be a string, or a list starting with "CMD", "CMD-SHELL" or "NONE", got "curl"`)
+	assert.ErrorContains(t, check([]string{"CMD"}, false), `service "web": healthcheck test "CMD" requires a command`)
+	assert.ErrorContains(t, check([]string{"CMD-SHELL"}, false), `healthcheck test "CMD-SHELL" requires a command`)
+	assert.ErrorContains(t, check([]string{"NONE", "true"}, false), `healthcheck test ["NONE"] doesn't accept arguments`)
+	assert.ErrorContains(t, check([]string{"CMD", "true"}, true), `service "web": healthcheck disable can't be combined with a test command`)
+	assert.NilError(t, check([]string{"NONE"}, true))
+}
+
+func TestTemplateDriverWarnings(t *testing.T) {
+	project := &composetypes.Project{
+		Configs: composetypes.Configs{
+			"nginx": {File: "./nginx.conf", TemplateDriver: "golang"},
+			"plain": {File: "./plain.conf"},
+		},
+		Secrets: composetypes.Secrets{
+			"token": {File: "./token", TemplateDriver: "golang"},
+		},
+	}
+	assert.DeepEqual(t, templateDriverWarnings(project), []string{
+		`config "nginx": template_driver "golang" isn't supported by local backend, ignoring`,
+		`secret "token": template_driver "golang" isn't supported by local backend, ignoring`,
+	})
+	assert.Assert(t, templateDriverWarnings(&composetypes.Project{}) == nil)
+}
+
+func TestGetNetworksByPriority(t *testing.T) {
+	service := composetypes.ServiceConfig{
+		Name: "web",
+		Networks: map[string]*composetypes.ServiceNetworkConfig{
+			"back":  nil,
+			"front": {Extensions: map[string]interface{}{extNetworkPriority: 100}},
+			"admin": {Extensions: map[string]interface{}{extNetworkPriority: 10}},
+			"audit": nil,
+		},
+	}
+	networks, err := getNetworksByPriority(service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, networks, []string{"front", "admin", "audit", "back"})
+
+	project := &composetypes.Project{
+		Networks: composetypes.Networks{
+			"front": {Name: "myproject_front"},
+			"admin": {Name: "myproject_admin"},
+			"audit": {Name: "myproject_audit"},
+			"back":  {Name: "myproject_back"},
+		},
+	}
+	mode, err := getNetworkMode(project, service)
+	assert.NilError(t, err)
+	assert.Equal(t, string(mode), "myproject_front")
+
+	service.Networks["back"] = &composetypes.ServiceNetworkConfig{Extensions: map[string]interface{}{extNetworkPriority: "high"}}
+	_, err = getNetworksByPriority(service)
+	assert.Error(t, err, `service "web": network "back" priority must be an integer, got high`)
+}
+
+func TestBuildMountConsistency(t *testing.T) {
+	project := composetypes.Project{}
+	volume := composetypes.ServiceVolumeConfig{
+		Type:        composetypes.VolumeTypeBind,
+		Source:      "",
+		Target:      "/data",
+		Consistency: "cached",
+	}
+	mount, err := buildMount(project, volume)
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Consistency, mountTypes.ConsistencyCached)
+
+	volume.Consistency = "delegated"
+	mount, err = buildMount(project, volume)
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Consistency, mountTypes.ConsistencyDelegated)
+
+	volume.Consistency = "bogus"
+	_, err = buildMount(project, volume)
+	assert.ErrorContains(t, err, `invalid consistency "bogus" for mount /data`)
+
+	volume = composetypes.ServiceVolumeConfig{
+		Type:        composetypes.VolumeTypeTmpfs,
+		Target:      "/tmp",
+		Consistency: "cached",
+	}
+	mount, err = buildMount(project, volume)
+	assert.NilError(t, err)
+	assert.Equal(t, mount.Consistency, mountTypes.Consistency(""))
+}
+
+func TestBuildContainerResources(t *testing.T) {
+	resources, err := buildContainerResources(composetypes.ServiceConfig{
+		CPUShares:      512,
+		CPUQuota:       50000,
+		CPUPeriod:      100000,
+		CPUSet:         "0,1",
+		CPUS:           0.1,
+		MemLimit:       64 * 1024 * 1024,
+		MemReservation: 32 * 1024 * 1024,
+		Extensions: map[string]interface{}{
+			extBlkioConfig: map[string]interface{}{
+				"weight": 300,
+				"weight_device": []interface{}{
+					map[string]interface{}{"path": "/dev/null", "weight": 400},
+				},
+				"device_read_bps": []interface{}{
+					map[string]interface{}{"path": "/dev/null", "rate": "1mb"},
+				},
+				"device_write_iops": []interface{}{
+					map[string]interface{}{"path": "/dev/null", "rate": 30},
+				},
+			},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, resources.CPUShares, int64(512))
+	assert.Equal(t, resources.CPUQuota, int64(50000))
+	assert.Equal(t, resources.CPUPeriod, int64(100000))
+	assert.Equal(t, resources.CpusetCpus, "0,1")
+	assert.Equal(t, resources.NanoCPUs, int64(100000000))
+	assert.Equal(t, resources.Memory, int64(64*1024*1024))
+	assert.Equal(t, resources.MemoryReservation, int64(32*1024*1024))
+	assert.Equal(t, resources.BlkioWeight, uint16(300))
+	assert.DeepEqual(t, resources.BlkioWeightDevice, []*blkiodev.WeightDevice{{Path: "/dev/null", Weight: 400}})
+	assert.DeepEqual(t, resources.BlkioDeviceReadBps, []*blkiodev.ThrottleDevice{{Path: "/dev/null", Rate: 1024 * 1024}})
+	assert.DeepEqual(t, resources.BlkioDeviceWriteIOps, []*blkiodev.ThrottleDevice{{Path: "/dev/null", Rate: 30}})
+	assert.Equal(t, len(resources.BlkioDeviceReadIOps), 0)
+}
+
+func TestBuildContainerResourcesMissingDevice(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("blkio devices are only checked on Linux")
+	}
+	_, err := buildContainerResources(composetypes.ServiceConfig{
+		Extensions: map[string]interface{}{
+			extBlkioConfig: map[string]interface{}{
+				"device_read_bps": []interface{}{
+					map[string]interface{}{"path": "/dev/does-not-exist", "rate": 1024},
+				},
+			},
+		},
+	})
+	assert.ErrorContains(t, err, "invalid blkio_config device /dev/does-not-exist")
+}
@@ -19,6 +19,7 @@ package compose
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -95,9 +96,13 @@ func run(ctx context.Context, graph *Graph, eg *errgroup.Group, nodes []*Vertex,
 		if len(traversalConfig.filterAdjacentByStatusFn(graph, n.Service.Name, traversalConfig.adjacentServiceStatusToSkip)) != 0 {
 			continue
 		}
+		// a node reached from multiple adjacent nodes completing concurrently must only be processed once
+		if !graph.Visit(n.Key) {
+			continue
+		}
 
 		eg.Go(func() error {
-			err := fn(ctx, n.Service)
+			err := fn(withServiceName(ctx, n.Service.Name), n.Service)
 			if err != nil {
 				return err
 			}
@@ -114,6 +119,7 @@ func run(ctx context.Context, graph *Graph, eg *errgroup.Group, nodes []*Vertex,
 // Graph represents project as service dependencies
 type Graph struct {
 	Vertices map[string]*Vertex
+	visited  map[string]bool
 	lock     sync.RWMutex
 }
 
@@ -157,6 +163,7 @@ func NewGraph(services types.Services, initialStatus ServiceStatus) *Graph {
 	graph := &Graph{
 		lock:     sync.RWMutex{},
 		Vertices: map[string]*Vertex{},
+		visited:  map[string]bool{},
 	}
 
 	for _, s := range services {
@@ -164,7 +171,7 @@ func NewGraph(services types.Services, initialStatus ServiceStatus) *Graph {
 	}
 
 	for _, s := range services {
-		for _, name := range s.GetDependencies() {
+		for _, name := range getDependencies(s) {
 			_ = graph.AddEdge(s.Name, name)
 		}
 	}
@@ -255,6 +262,17 @@ func (g *Graph) Roots() []*Vertex {
 	return res
 }
 
+// Visit marks a vertex as being processed by graph traversal, returning false if it already was
+func (g *Graph) Visit(key string) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.visited[key] {
+		return false
+	}
+	g.visited[key] = true
+	return true
+}
+
 // UpdateStatus updates the status of a certain vertex
 func (g *Graph) UpdateStatus(key string, status ServiceStatus) {
 	g.lock.Lock()
@@ -326,6 +344,42 @@ func (g *Graph) HasCycles() (bool, error) {
 	return false, nil
 }
 
+// Cycles lists all the elementary cycles of the graph as dependency paths, each one starting and ending with its lowest
+// key, e.g. [a b c a] when a depends on b, b on c, and c on a
+func (g *Graph) Cycles() [][]string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	var cycles [][]string
+	for _, start := range SortedVertexKeys(g.Vertices) {
+		var walk func(key string, path []string)
+		walk = func(key string, path []string) {
+			for _, child := range SortedVertexKeys(g.Vertices[key].Children) {
+				switch {
+				case child == start:
+					cycle := append([]string{}, path...)
+					cycles = append(cycles, append(cycle, start))
+				case child > start && !contains(path, child):
+					// cycles going through a lower key were already found when starting from it
+					walk(child, append(path, child))
+				}
+			}
+		}
+		walk(start, []string{start})
+	}
+	return cycles
+}
+
+// SortedVertexKeys returns the keys of vertices in alphabetical order, for a stable walk of the graph
+func SortedVertexKeys(vertices map[string]*Vertex) []string {
+	var keys []string
+	for key := range vertices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (g *Graph) visit(key string, path []string, discovered []string, finished []string) ([]string, []string, error) {
 	discovered = append(discovered, key)
 
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/compose-spec/compose-go/types"
@@ -67,3 +68,70 @@ func TestInDependencyReverseDownCommandOrder(t *testing.T) {
 	assert.Equal(t, <-order, "test2")
 	assert.Equal(t, <-order, "test3")
 }
+
+func TestInReverseDependencyOrderVisitsSharedDependencyOnce(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:      "front",
+				DependsOn: map[string]types.ServiceDependency{"api": {}, "worker": {}},
+			},
+			{
+				Name:      "api",
+				DependsOn: map[string]types.ServiceDependency{"db": {}},
+			},
+			{
+				Name:      "worker",
+				DependsOn: map[string]types.ServiceDependency{"db": {}},
+			},
+			{
+				Name: "db",
+			},
+		},
+	}
+	var (
+		lock  sync.Mutex
+		order []string
+	)
+	err := InReverseDependencyOrder(context.TODO(), &project, func(ctx context.Context, config types.ServiceConfig) error {
+		lock.Lock()
+		defer lock.Unlock()
+		order = append(order, config.Name)
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(order), 4)
+	assert.Equal(t, order[0], "front")
+	assert.Equal(t, order[3], "db")
+}
+
+func TestCycles(t *testing.T) {
+	graph := NewGraph([]types.ServiceConfig{
+		{Name: "a", DependsOn: map[string]types.ServiceDependency{"b": {}}},
+		{Name: "b", DependsOn: map[string]types.ServiceDependency{"c": {}}},
+		{Name: "c", DependsOn: map[string]types.ServiceDependency{"a": {}, "d": {}}},
+		{Name: "d"},
+		{Name: "e", DependsOn: map[string]types.ServiceDependency{"a": {}, "f": {}}},
+		{Name: "f", Links: []string{"e"}},
+	}, ServiceStopped)
+
+	assert.DeepEqual(t, graph.Cycles(), [][]string{
+		{"a", "b", "c", "a"},
+		{"e", "f", "e"},
+	})
+
+	assert.Assert(t, NewGraph(project.Services, ServiceStopped).Cycles() == nil)
+}
+
+func TestWithDependents(t *testing.T) {
+	selected, err := withDependents(&project, []string{"test2"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, selected, map[string]bool{"test1": true, "test2": true})
+
+	selected, err = withDependents(&project, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, selected, map[string]bool{"test1": true, "test2": true, "test3": true})
+
+	_, err = withDependents(&project, []string{"unknown"})
+	assert.Error(t, err, "no such service: unknown")
+}
@@ -0,0 +1,257 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+
+	"github.com/compose-spec/compose-go/types"
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/distribution/reference"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/registry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/config"
+)
+
+// imageIdentity returns the ID of image and the digest it was pulled by. Digest is empty for images which don't come
+// from a registry, both are for images which don't exist locally
+func (s *composeService) imageIdentity(ctx context.Context, image string) (string, string, error) {
+	inspect, _, err := s.apiClient.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+	digest, err := digestForRepository(image, inspect.RepoDigests)
+	return inspect.ID, digest, err
+}
+
+// digestForRepository selects within an image's RepoDigests the one matching the repository image was referenced from
+func digestForRepository(image string, repoDigests []string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", err
+	}
+	for _, rd := range repoDigests {
+		named, err := reference.ParseNormalizedNamed(rd)
+		if err != nil {
+			continue
+		}
+		canonical, ok := named.(reference.Canonical)
+		if ok && named.Name() == ref.Name() {
+			return canonical.Digest().String(), nil
+		}
+	}
+	return "", nil
+}
+
+// checkImagesDigest compares the digest service containers have been created from with the one registry currently
+// exposes for the same tag. Depending on pull strategy, a drift is reported as a warning, or updated image is pulled
+// and containers get recreated. Registry is queried once per image, whatever the number of services using it
+func (s *composeService) checkImagesDigest(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
+	configFile, err := cliconfig.Load(config.Dir(ctx))
+	if err != nil {
+		return err
+	}
+	info, err := s.apiClient.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if info.IndexServerAddress == "" {
+		info.IndexServerAddress = registry.IndexServer
+	}
+
+	var drifted []types.ServiceConfig
+	remoteDigests := map[string]string{}
+	for _, service := range project.Services {
+		if service.Image == "" || service.Build != nil {
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(service.Image)
+		if err != nil {
+			return err
+		}
+		if _, ok := ref.(reference.Canonical); ok {
+			// image is pinned by digest, can't drift
+			continue
+		}
+
+		containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+			Filters: filters.NewArgs(
+				projectFilter(project.Name),
+				serviceFilter(service.Name),
+			),
+			All: true,
+		})
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			continue
+		}
+
+		remote, checked := remoteDigests[service.Image]
+		if !checked {
+			auth, err := encodedAuth(ref, configFile, info.IndexServerAddress)
+			if err != nil {
+				return err
+			}
+			inspect, err := s.apiClient.DistributionInspect(ctx, service.Image, auth)
+			if err != nil {
+				logrus.Warnf("failed to check registry for updates of image %s: %v", service.Image, err)
+			} else {
+				remote = inspect.Descriptor.Digest.String()
+			}
+			remoteDigests[service.Image] = remote
+		}
+		if remote == "" {
+			continue
+		}
+		for _, c := range containers {
+			digest, ok := c.Labels[imageDigestLabel()]
+			if ok && digest != remote {
+				drifted = append(drifted, service)
+				break
+			}
+		}
+	}
+
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	if opts.Pull != compose.PullAlways {
+		for _, service := range drifted {
+			logrus.Warnf("image %s used by service %s has been updated on registry, use --pull always to recreate containers", service.Image, service.Name)
+		}
+		return nil
+	}
+
+	_, err = s.Pull(ctx, &types.Project{
+		Name:     project.Name,
+		Services: drifted,
+	}, compose.PullOptions{
+		VerifySignatures: opts.VerifySignatures,
+	})
+	if err != nil {
+		return err
+	}
+	for _, d := range drifted {
+		for i, service := range project.Services {
+			if service.Name != d.Name {
+				continue
+			}
+			if service.Extensions == nil {
+				service.Extensions = map[string]interface{}{}
+			}
+			service.Extensions[extLifecycle] = forceRecreate
+			project.Services[i] = service
+		}
+	}
+	return nil
+}
+
+// resolveImageIDs pulls service images, unless offline, and records the ID each image tag now points to. Containers
+// created from another image then have a diverged config hash and get recreated
+func (s *composeService) resolveImageIDs(ctx context.Context, project *types.Project, opts compose.CreateOptions) error {
+	var tagged []types.ServiceConfig
+	for _, service := range project.Services {
+		if service.Image != "" && service.Build == nil {
+			tagged = append(tagged, service)
+		}
+	}
+	if len(tagged) == 0 {
+		return nil
+	}
+	if !opts.Offline {
+		_, err := s.Pull(ctx, &types.Project{
+			Name:     project.Name,
+			Services: tagged,
+		}, compose.PullOptions{
+			VerifySignatures: opts.VerifySignatures,
+			Verbose:          opts.Verbose,
+		})
+		if err != nil {
+			logrus.Warnf("failed to pull images, comparing containers with local images: %v", err)
+		}
+	}
+	for i, service := range project.Services {
+		if service.Image == "" || service.Build != nil {
+			continue
+		}
+		id, _, err := s.imageIdentity(ctx, service.Image)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			continue
+		}
+		service.Extensions[extImageID] = id
+		project.Services[i] = service
+	}
+	return nil
+}
+
+func (s *composeService) ResolveImageDigests(ctx context.Context, project *types.Project) (map[string]string, error) {
+	configFile, err := cliconfig.Load(config.Dir(ctx))
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.apiClient.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info.IndexServerAddress == "" {
+		info.IndexServerAddress = registry.IndexServer
+	}
+
+	pinned := map[string]string{}
+	for _, service := range project.Services {
+		if service.Image == "" || service.Build != nil {
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(service.Image)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ref.(reference.Canonical); ok {
+			pinned[service.Name] = service.Image
+			continue
+		}
+		auth, err := encodedAuth(ref, configFile, info.IndexServerAddress)
+		if err != nil {
+			return nil, err
+		}
+		remote, err := s.apiClient.DistributionInspect(ctx, service.Image, auth)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve digest for image %s", service.Image)
+		}
+		canonical, err := reference.WithDigest(reference.TrimNamed(ref), remote.Descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+		pinned[service.Name] = reference.FamiliarString(canonical)
+	}
+	return pinned, nil
+}
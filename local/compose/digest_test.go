@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestDigestForRepository(t *testing.T) {
+	repoDigests := []string{
+		"myregistry.com/nginx@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		"nginx@sha256:2222222222222222222222222222222222222222222222222222222222222222",
+	}
+
+	digest, err := digestForRepository("nginx:latest", repoDigests)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:2222222222222222222222222222222222222222222222222222222222222222")
+
+	digest, err = digestForRepository("myregistry.com/nginx", repoDigests)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:1111111111111111111111111111111111111111111111111111111111111111")
+
+	digest, err = digestForRepository("myapp:latest", repoDigests)
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "")
+}
+
+func TestCheckImagesDigest(t *testing.T) {
+	const (
+		oldDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		newDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	inspected, pulled := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1.41/info":
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/v1.41/containers/json":
+			filter, err := filters.FromJSON(r.URL.Query().Get("filters"))
+			assert.NilError(t, err)
+			var containers []moby.Container
+			for _, service := range []string{"web", "worker"} {
+				if filter.ExactMatch("label", serviceLabel()+"="+service) {
+					containers = append(containers, moby.Container{ID: service, Labels: map[string]string{imageDigestLabel(): oldDigest}})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(containers)
+		case strings.HasPrefix(r.URL.Path, "/v1.41/distribution/"):
+			inspected++
+			_, _ = w.Write([]byte(`{"Descriptor":{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","digest":"` + newDigest + `","size":1}}`))
+		case r.URL.Path == "/v1.41/images/create":
+			pulled++
+			_, _ = w.Write([]byte(`{"status":"Downloaded newer image"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.41"))
+	assert.NilError(t, err)
+	s := composeService{apiClient: apiClient}
+
+	newProject := func() *types.Project {
+		return &types.Project{Name: "test", Services: types.Services{
+			{Name: "web", Image: "nginx:latest"},
+			{Name: "worker", Image: "nginx:latest"},
+			{Name: "db", Image: "postgres:13"},
+		}}
+	}
+
+	// drift is only reported, registry being queried once per image
+	project := newProject()
+	assert.NilError(t, s.checkImagesDigest(context.Background(), project, compose.CreateOptions{Pull: compose.PullMissing}))
+	assert.Equal(t, inspected, 1)
+	assert.Equal(t, pulled, 0)
+	for _, service := range project.Services {
+		assert.Equal(t, service.Extensions[extLifecycle], nil)
+	}
+
+	// updated image is pulled, and all services using it get recreated
+	project = newProject()
+	assert.NilError(t, s.checkImagesDigest(context.Background(), project, compose.CreateOptions{Pull: compose.PullAlways}))
+	assert.Equal(t, pulled, 1)
+	assert.Equal(t, project.Services[0].Extensions[extLifecycle], forceRecreate)
+	assert.Equal(t, project.Services[1].Extensions[extLifecycle], forceRecreate)
+	assert.Equal(t, project.Services[2].Extensions[extLifecycle], nil)
+}
@@ -18,19 +18,28 @@ package compose
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/progress"
 
 	"github.com/compose-spec/compose-go/cli"
 	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/distribution/reference"
 	moby "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
-func (s *composeService) Down(ctx context.Context, projectName string) error {
+func (s *composeService) Down(ctx context.Context, projectName string, options compose.DownOptions) error {
 	eg, _ := errgroup.WithContext(ctx)
 	w := progress.ContextWriter(ctx)
 
@@ -38,16 +47,24 @@ func (s *composeService) Down(ctx context.Context, projectName string) error {
 	if err != nil {
 		return err
 	}
+	if !options.CreatedSince.IsZero() {
+		options.CreatedSince, err = s.toEngineTime(ctx, options.CreatedSince)
+		if err != nil {
+			return err
+		}
+	}
 
-	err = InReverseDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
-		filter := filters.NewArgs(projectFilter(project.Name), serviceFilter(service.Name))
-		return s.removeContainers(ctx, w, eg, filter)
-	})
-
-	if err != nil {
-		return err
+	if cycle, cycleErr := NewGraph(project.Services, ServiceStarted).HasCycles(); cycle {
+		// project might have been created before dependencies were changed, teardown must not be blocked
+		logrus.Warnf("%s, removing containers regardless of dependencies", cycleErr)
+		err = s.removeContainers(ctx, w, filters.NewArgs(projectFilter(project.Name)), options)
+	} else {
+		// dependent services are removed first, only independent ones being removed concurrently
+		err = InReverseDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
+			filter := filters.NewArgs(projectFilter(project.Name), serviceFilter(service.Name))
+			return s.removeContainers(c, w, filter, options)
+		})
 	}
-	err = eg.Wait()
 	if err != nil {
 		return err
 	}
@@ -60,6 +77,9 @@ func (s *composeService) Down(ctx context.Context, projectName string) error {
 		return err
 	}
 	for _, n := range networks {
+		if n.Created.Before(options.CreatedSince) {
+			continue
+		}
 		networkID := n.ID
 		networkName := n.Name
 		eg.Go(func() error {
@@ -67,10 +87,32 @@ func (s *composeService) Down(ctx context.Context, projectName string) error {
 		})
 	}
 
-	return eg.Wait()
+	err = eg.Wait()
+	if err != nil {
+		return err
+	}
+	// a rollback leaves containers created before, which still mount secrets
+	if options.CreatedSince.IsZero() {
+		if err := removeEnvironmentSecrets(projectName); err != nil {
+			return err
+		}
+		if err := removeReplacedLogs(projectName); err != nil {
+			return err
+		}
+	}
+	if options.Images != "" {
+		if err := s.removeImages(ctx, w, project, options.Images); err != nil {
+			return err
+		}
+	}
+	n := newNotifier(project)
+	n.notify(notifyDownComplete, "", "")
+	n.wait()
+	return nil
 }
 
-func (s *composeService) removeContainers(ctx context.Context, w progress.Writer, eg *errgroup.Group, filter filters.Args) error {
+// removeContainers stops and removes containers matching filter concurrently, and waits for them all to be removed
+func (s *composeService) removeContainers(ctx context.Context, w progress.Writer, filter filters.Args, options compose.DownOptions) error {
 	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
 		Filters: filter,
 		All:     true,
@@ -78,28 +120,211 @@ func (s *composeService) removeContainers(ctx context.Context, w progress.Writer
 	if err != nil {
 		return err
 	}
-	for _, container := range containers {
+	containers, err = s.containersCreatedSince(ctx, containers, options.CreatedSince)
+	if err != nil {
+		return err
+	}
+	return s.stopAndRemoveContainers(ctx, w, containers, options.Timeout)
+}
+
+// removeOrphans stops and removes containers of project services which are no longer declared by the compose file.
+// Services disabled as none of their profiles is active are still declared
+func (s *composeService) removeOrphans(ctx context.Context, project *types.Project) error {
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: filters.NewArgs(
+			projectFilter(project.Name),
+			filters.Arg("label", fmt.Sprintf("%s=%s", oneoffLabel(), "False")),
+		),
+		All: true,
+	})
+	if err != nil {
+		return err
+	}
+	var orphans []moby.Container
+	for _, c := range containers {
+		if _, err := project.GetService(c.Labels[serviceLabel()]); err != nil && !isDisabledService(project, c.Labels[serviceLabel()]) {
+			orphans = append(orphans, c)
+		}
+	}
+	return s.stopAndRemoveContainers(ctx, progress.ContextWriter(ctx), orphans, nil)
+}
+
+// stopAndRemoveContainers stops and removes containers concurrently, and waits for them all to be removed
+func (s *composeService) stopAndRemoveContainers(ctx context.Context, w progress.Writer, containers []moby.Container, timeout *time.Duration) error {
+	// a zero timeout skips graceful stop, forced removal kills the container right away
+	kill := timeout != nil && *timeout == 0
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, c := range containers {
+		container := c
 		eg.Go(func() error {
-			eventName := "Container " + getContainerName(container)
-			w.Event(progress.StoppingEvent(eventName))
-			err := s.apiClient.ContainerStop(ctx, container.ID, nil)
-			if err != nil {
-				w.Event(progress.ErrorMessageEvent(eventName, "Error while Stopping"))
-				return err
+			name := getContainerName(container)
+			eventName := "Container " + name
+			if !kill {
+				w.Event(progress.StoppingEvent(eventName).WithResource(progress.ContainerKind, name))
+				err := s.apiClient.ContainerStop(ctx, container.ID, timeout)
+				if err != nil && !errdefs.IsNotFound(err) {
+					w.Event(progress.ErrorMessageEvent(eventName, "Error while Stopping").WithResource(progress.ContainerKind, name))
+					return err
+				}
 			}
-			w.Event(progress.RemovingEvent(eventName))
-			err = s.apiClient.ContainerRemove(ctx, container.ID, moby.ContainerRemoveOptions{})
-			if err != nil {
-				w.Event(progress.ErrorMessageEvent(eventName, "Error while Removing"))
+			w.Event(progress.RemovingEvent(eventName).WithResource(progress.ContainerKind, name))
+			// teardown must not depend on resources removed behind compose's back, like the container image
+			err := s.apiClient.ContainerRemove(ctx, container.ID, moby.ContainerRemoveOptions{Force: kill})
+			if err != nil && !errdefs.IsNotFound(err) {
+				w.Event(progress.ErrorMessageEvent(eventName, "Error while Removing").WithResource(progress.ContainerKind, name))
 				return err
 			}
-			w.Event(progress.RemovedEvent(eventName))
+			w.Event(progress.RemovedEvent(eventName).WithResource(progress.ContainerKind, name))
 			return nil
 		})
 	}
+	return eg.Wait()
+}
+
+// removeImages removes service images, only the ones built without a custom image name in local mode. Teardown of
+// containers never depends on images, so images somebody already removed are only reported as skipped
+func (s *composeService) removeImages(ctx context.Context, w progress.Writer, project *types.Project, mode string) error {
+	images := imagesToRemove(project, mode)
+	if mode == compose.RemoveImagesLocal {
+		built, err := s.localBuiltImages(ctx, project.Name)
+		if err != nil {
+			return err
+		}
+		images = mergeImageNames(images, built)
+	}
+	for _, image := range images {
+		eventName := fmt.Sprintf("Image %q", image)
+		w.Event(progress.RemovingEvent(eventName).WithResource(progress.ImageKind, image))
+		_, err := s.apiClient.ImageRemove(ctx, image, moby.ImageRemoveOptions{PruneChildren: true})
+		switch {
+		case errdefs.IsNotFound(err):
+			w.Event(progress.NewEvent(eventName, progress.Done, "Skipped").WithResource(progress.ImageKind, image))
+		case err != nil:
+			w.Event(progress.ErrorMessageEvent(eventName, "Error while Removing").WithResource(progress.ImageKind, image))
+			return err
+		default:
+			w.Event(progress.RemovedEvent(eventName).WithResource(progress.ImageKind, image))
+		}
+	}
 	return nil
 }
 
+// imagesToRemove lists the images of project services down --rmi removes
+func imagesToRemove(project *types.Project, mode string) []string {
+	seen := map[string]bool{}
+	var images []string
+	add := func(image string) {
+		if !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+	for _, service := range project.Services {
+		if service.Image == "" {
+			// services restored from container labels of a project read from stdin only have a name
+			add(canonicalImageName(service, project))
+			continue
+		}
+		if mode != compose.RemoveImagesAll {
+			continue
+		}
+		add(service.Image)
+		if service.Build != nil {
+			add(canonicalImageName(service, project))
+		}
+	}
+	return images
+}
+
+// localBuiltImages lists the tags of the images compose built for project which aren't a custom image name, according
+// to the primary image label: built images are also tagged `project_service` when service declares an image name,
+// this secondary tag being removed while the primary one is kept
+func (s *composeService) localBuiltImages(ctx context.Context, projectName string) ([]string, error) {
+	images, err := s.apiClient.ImageList(ctx, moby.ImageListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", imageProjectLabel(), projectName))),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, image := range images {
+		primary := normalizeImageName(image.Labels[imagePrimaryLabel()])
+		canonical := normalizeImageName(compose.ResourceName(projectName, image.Labels[imageServiceLabel()]))
+		for _, tag := range image.RepoTags {
+			if tag := normalizeImageName(tag); tag != primary || primary == canonical {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags, nil
+}
+
+// mergeImageNames appends to images the ones not already listed, whatever the form they are named by
+func mergeImageNames(images []string, others []string) []string {
+	seen := map[string]bool{}
+	for _, image := range images {
+		seen[normalizeImageName(image)] = true
+	}
+	for _, image := range others {
+		if !seen[normalizeImageName(image)] {
+			seen[normalizeImageName(image)] = true
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// normalizeImageName returns the familiar form of an image name with its tag, defaulting to latest
+func normalizeImageName(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	return reference.FamiliarString(reference.TagNameOnly(named))
+}
+
+// containersCreatedSince filters containers created since a time. Container list only has a creation time in seconds,
+// so containers are inspected for the precise one
+func (s *composeService) containersCreatedSince(ctx context.Context, containers []moby.Container, since time.Time) ([]moby.Container, error) {
+	if since.IsZero() {
+		return containers, nil
+	}
+	var created []moby.Container
+	for _, c := range containers {
+		inspect, err := s.apiClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		if inspect.ContainerJSONBase != nil && createdSince(inspect.Created, since) {
+			created = append(created, c)
+		}
+	}
+	return created, nil
+}
+
+// toEngineTime converts a time of client clock into engine clock, which resources creation time comes from, as both
+// clocks may differ
+func (s *composeService) toEngineTime(ctx context.Context, t time.Time) (time.Time, error) {
+	info, err := s.apiClient.Info(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	engineNow, err := time.Parse(time.RFC3339Nano, info.SystemTime)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid engine system time %q", info.SystemTime)
+	}
+	return t.Add(engineNow.Sub(time.Now())), nil
+}
+
+// createdSince tells if a resource was created since a time, resources with an unknown creation time being considered older
+func createdSince(created string, since time.Time) bool {
+	t, err := time.Parse(time.RFC3339Nano, created)
+	if err != nil {
+		return false
+	}
+	return !t.Before(since)
+}
+
 func (s *composeService) projectFromContainerLabels(ctx context.Context, projectName string) (*types.Project, error) {
 	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
 		Filters: filters.NewArgs(
@@ -123,7 +348,7 @@ func (s *composeService) projectFromContainerLabels(ctx context.Context, project
 	if options.ConfigPaths[0] == "-" {
 		for _, container := range containers {
 			fakeProject.Services = append(fakeProject.Services, types.ServiceConfig{
-				Name: container.Labels[serviceLabel],
+				Name: container.Labels[serviceLabel()],
 			})
 		}
 		return fakeProject, nil
@@ -138,12 +363,26 @@ func (s *composeService) projectFromContainerLabels(ctx context.Context, project
 
 func loadProjectOptionsFromLabels(c moby.Container) (*cli.ProjectOptions, error) {
 	var configFiles []string
-	relativePathConfigFiles := strings.Split(c.Labels[configFilesLabel], ",")
+	relativePathConfigFiles := strings.Split(c.Labels[configFilesLabel()], ",")
 	for _, c := range relativePathConfigFiles {
 		configFiles = append(configFiles, filepath.Base(c))
 	}
+	var env []string
+	if envFile, ok := c.Labels[envFileLabel()]; ok {
+		vars, err := godotenv.Read(envFile)
+		switch {
+		case os.IsNotExist(err):
+			logrus.Warnf("env file %s used to create project %q doesn't exist anymore", envFile, c.Labels[projectLabel()])
+		case err != nil:
+			return nil, errors.Wrapf(err, "failed to read %s", envFile)
+		}
+		for k, v := range vars {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
 	return cli.NewProjectOptions(configFiles,
+		cli.WithEnv(env),
 		cli.WithOsEnv,
-		cli.WithWorkingDirectory(c.Labels[workingDirLabel]),
-		cli.WithName(c.Labels[projectLabel]))
+		cli.WithWorkingDirectory(c.Labels[workingDirLabel()]),
+		cli.WithName(c.Labels[projectLabel()]))
 }
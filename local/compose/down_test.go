@@ -0,0 +1,124 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestLoadProjectOptionsFromLabelsEnvFile(t *testing.T) {
+	dir := fs.NewDir(t, "env-file", fs.WithFile("prod.env", "SUFFIX=prod\n"))
+	defer dir.Remove()
+
+	container := moby.Container{
+		Labels: map[string]string{
+			projectLabel():     "myProject",
+			workingDirLabel():  dir.Path(),
+			configFilesLabel(): dir.Join("docker-compose.yml"),
+			envFileLabel():     dir.Join("prod.env"),
+		},
+	}
+	options, err := loadProjectOptionsFromLabels(container)
+	assert.NilError(t, err)
+	assert.Equal(t, options.Environment["SUFFIX"], "prod")
+
+	container.Labels[envFileLabel()] = dir.Join("missing.env")
+	options, err = loadProjectOptionsFromLabels(container)
+	assert.NilError(t, err)
+	_, ok := options.Environment["SUFFIX"]
+	assert.Assert(t, !ok)
+}
+
+func TestCreatedSince(t *testing.T) {
+	since := time.Date(2020, 12, 1, 10, 0, 0, 500000000, time.UTC)
+	assert.Assert(t, createdSince("2020-12-01T10:00:00.5Z", since))
+	assert.Assert(t, createdSince("2020-12-01T10:00:01.123456789Z", since))
+	assert.Assert(t, !createdSince("2020-12-01T10:00:00.4Z", since))
+	assert.Assert(t, !createdSince("", since))
+}
+
+func TestImagesToRemove(t *testing.T) {
+	project := &types.Project{
+		Name: "myProject",
+		Services: types.Services{
+			{Name: "built", Build: &types.BuildConfig{Context: "."}},
+			{Name: "tagged", Image: "registry/tagged:1.0", Build: &types.BuildConfig{Context: "."}},
+			{Name: "pulled", Image: "nginx"},
+			{Name: "pulledToo", Image: "nginx"},
+		},
+	}
+	assert.DeepEqual(t, imagesToRemove(project, "local"), []string{"myProject_built"})
+	assert.DeepEqual(t, imagesToRemove(project, "all"), []string{"myProject_built", "registry/tagged:1.0", "myProject_tagged", "nginx"})
+}
+
+func TestLocalBuiltImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter, err := filters.FromJSON(r.URL.Query().Get("filters"))
+		assert.NilError(t, err)
+		assert.Assert(t, filter.ExactMatch("label", imageProjectLabel()+"=myproject"))
+		_ = json.NewEncoder(w).Encode([]moby.ImageSummary{
+			{
+				ID:       "sha256:built",
+				RepoTags: []string{"myproject_built:latest"},
+				Labels:   map[string]string{imageServiceLabel(): "built", imagePrimaryLabel(): "myproject_built"},
+			},
+			{
+				ID:       "sha256:tagged",
+				RepoTags: []string{"registry/tagged:1.0", "myproject_tagged:latest"},
+				Labels:   map[string]string{imageServiceLabel(): "tagged", imagePrimaryLabel(): "registry/tagged:1.0"},
+			},
+		})
+	}))
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.41"))
+	assert.NilError(t, err)
+	s := composeService{apiClient: apiClient}
+
+	built, err := s.localBuiltImages(context.Background(), "myproject")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, built, []string{"myproject_built:latest", "myproject_tagged:latest"})
+	assert.DeepEqual(t, mergeImageNames([]string{"myproject_built"}, built), []string{"myproject_built", "myproject_tagged:latest"})
+}
+
+func TestToEngineTime(t *testing.T) {
+	// engine clock is an hour ahead of client one
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"SystemTime":"` + time.Now().Add(time.Hour).Format(time.RFC3339Nano) + `"}`))
+	}))
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.41"))
+	assert.NilError(t, err)
+	s := composeService{apiClient: apiClient}
+
+	since := time.Now()
+	engineSince, err := s.toEngineTime(context.Background(), since)
+	assert.NilError(t, err)
+	offset := engineSince.Sub(since)
+	assert.Assert(t, offset > 59*time.Minute && offset <= time.Hour, offset)
+}
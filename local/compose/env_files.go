@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+)
+
+// extEnvFiles declares env files in the compose-spec long form `{path: optional.env, required: false}`, which the
+// compose-go version in use rejects in env_file. Entries can also be a plain path, required like env_file ones
+const extEnvFiles = "x-env_file"
+
+// envFile is an x-env_file entry
+type envFile struct {
+	Path     string
+	Required bool
+}
+
+// ApplyEnvFiles sets the variables of the x-env_file files declared by services in their environment, skipping missing
+// files which aren't required. Later files override earlier ones, and variables set by environment or env_file win
+func ApplyEnvFiles(project *types.Project) error {
+	for i, service := range project.Services {
+		files, err := getEnvFiles(service)
+		if err != nil {
+			return err
+		}
+		vars := map[string]string{}
+		for _, file := range files {
+			path := file.Path
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(project.WorkingDir, path)
+			}
+			content, err := godotenv.Read(path)
+			switch {
+			case os.IsNotExist(err) && !file.Required:
+				continue
+			case os.IsNotExist(err):
+				return fmt.Errorf("service %q: env file %s not found", service.Name, path)
+			case err != nil:
+				return errors.Wrapf(err, "service %q: failed to read %s", service.Name, path)
+			}
+			for key, value := range content {
+				vars[key] = value
+			}
+		}
+		for key, value := range vars {
+			if _, ok := service.Environment[key]; ok {
+				continue
+			}
+			if project.Services[i].Environment == nil {
+				project.Services[i].Environment = types.MappingWithEquals{}
+			}
+			value := value
+			project.Services[i].Environment[key] = &value
+		}
+	}
+	return nil
+}
+
+func getEnvFiles(s types.ServiceConfig) ([]envFile, error) {
+	value, ok := s.Extensions[extEnvFiles]
+	if !ok {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("service %q: invalid %s %v, must be a list", s.Name, extEnvFiles, value)
+	}
+	var files []envFile
+	for i, item := range items {
+		switch entry := item.(type) {
+		case string:
+			files = append(files, envFile{Path: entry, Required: true})
+		case map[string]interface{}:
+			file := envFile{Required: true}
+			file.Path, _ = entry["path"].(string)
+			if file.Path == "" {
+				return nil, fmt.Errorf("service %q: %s[%d] requires a path", s.Name, extEnvFiles, i)
+			}
+			if required, ok := entry["required"]; ok {
+				parsed, err := strconv.ParseBool(fmt.Sprint(required))
+				if err != nil {
+					return nil, fmt.Errorf("service %q: %s[%d]: invalid required %v, must be a boolean", s.Name, extEnvFiles, i, required)
+				}
+				file.Required = parsed
+			}
+			files = append(files, file)
+		default:
+			return nil, fmt.Errorf("service %q: invalid %s[%d] %v, must be a path or a mapping", s.Name, extEnvFiles, i, item)
+		}
+	}
+	return files, nil
+}
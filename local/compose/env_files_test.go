@@ -0,0 +1,68 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestApplyEnvFiles(t *testing.T) {
+	dir := fs.NewDir(t, "env-files",
+		fs.WithFile("common.env", "LEVEL=info\nREGION=eu\n"),
+		fs.WithFile("local.env", "LEVEL=debug\n"))
+	defer dir.Remove()
+
+	debug := "1"
+	envFiles := func(files ...interface{}) types.ServiceConfig {
+		return types.ServiceConfig{
+			Name:        "web",
+			Environment: types.MappingWithEquals{"DEBUG": &debug},
+			Extensions:  map[string]interface{}{extEnvFiles: files},
+		}
+	}
+
+	project := &types.Project{WorkingDir: dir.Path(), Services: types.Services{
+		envFiles("common.env",
+			map[string]interface{}{"path": "local.env", "required": false},
+			map[string]interface{}{"path": "optional.env", "required": false}),
+	}}
+	assert.NilError(t, ApplyEnvFiles(project))
+	environment := map[string]string{}
+	for key, value := range project.Services[0].Environment {
+		environment[key] = *value
+	}
+	assert.DeepEqual(t, environment, map[string]string{"DEBUG": "1", "LEVEL": "debug", "REGION": "eu"})
+
+	project = &types.Project{WorkingDir: dir.Path(), Services: types.Services{
+		envFiles(map[string]interface{}{"path": "required.env", "required": true}),
+	}}
+	assert.Error(t, ApplyEnvFiles(project), `service "web": env file `+dir.Join("required.env")+" not found")
+
+	project = &types.Project{WorkingDir: dir.Path(), Services: types.Services{
+		envFiles(map[string]interface{}{"path": "required.env"}),
+	}}
+	assert.Error(t, ApplyEnvFiles(project), `service "web": env file `+dir.Join("required.env")+" not found")
+
+	project = &types.Project{WorkingDir: dir.Path(), Services: types.Services{
+		envFiles(map[string]interface{}{"path": "common.env", "required": "maybe"}),
+	}}
+	assert.Error(t, ApplyEnvFiles(project), `service "web": x-env_file[0]: invalid required maybe, must be a boolean`)
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func (s *composeService) Events(ctx context.Context, projectName string, options compose.EventsOptions) error {
+	events, errs := s.apiClient.Events(ctx, moby.EventsOptions{
+		Filters: filters.NewArgs(projectFilter(projectName)),
+	})
+	for {
+		select {
+		case event := <-events:
+			attributes := map[string]string{}
+			for k, v := range event.Actor.Attributes {
+				if strings.HasPrefix(k, compose.LabelPrefix()+".") {
+					continue
+				}
+				attributes[k] = v
+			}
+			e := compose.Event{
+				Timestamp:  time.Unix(0, event.TimeNano),
+				Type:       event.Type,
+				Service:    event.Actor.Attributes[serviceLabel()],
+				Container:  event.Actor.ID,
+				Status:     event.Action,
+				Attributes: attributes,
+			}
+			if !matchEvent(options.Filters, e) {
+				continue
+			}
+			err := options.Consumer(e)
+			if err != nil {
+				return err
+			}
+		case err := <-errs:
+			return err
+		}
+	}
+}
+
+// matchEvent checks event against all filters, at least one of the values set for a key must match
+func matchEvent(filters map[string][]string, event compose.Event) bool {
+	for key, values := range filters {
+		var actual string
+		switch key {
+		case "type":
+			actual = event.Type
+		case "event":
+			// some actions carry details, like `health_status: healthy`, filter can be either the action or its name
+			if contains(values, event.Status) {
+				continue
+			}
+			actual = strings.SplitN(event.Status, ":", 2)[0]
+		case "service":
+			actual = event.Service
+		}
+		if !contains(values, actual) {
+			return false
+		}
+	}
+	return true
+}
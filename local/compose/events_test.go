@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestMatchEvent(t *testing.T) {
+	events := []compose.Event{
+		{Type: "container", Service: "web", Status: "create"},
+		{Type: "container", Service: "web", Status: "start"},
+		{Type: "container", Service: "web", Status: "die"},
+		{Type: "container", Service: "db", Status: "die"},
+		{Type: "network", Status: "connect"},
+		{Type: "container", Service: "db", Status: "health_status: healthy"},
+		{Type: "container", Service: "db", Status: "exec_start: pg_isready"},
+	}
+
+	matching := func(filters map[string][]string) []compose.Event {
+		var matched []compose.Event
+		for _, e := range events {
+			if matchEvent(filters, e) {
+				matched = append(matched, e)
+			}
+		}
+		return matched
+	}
+
+	assert.Equal(t, len(matching(nil)), 7)
+	assert.DeepEqual(t, matching(map[string][]string{"event": {"die"}}), []compose.Event{events[2], events[3]})
+	assert.DeepEqual(t, matching(map[string][]string{"event": {"start", "die"}, "service": {"web"}}), []compose.Event{events[1], events[2]})
+	assert.DeepEqual(t, matching(map[string][]string{"type": {"network"}}), []compose.Event{events[4]})
+	assert.Equal(t, len(matching(map[string][]string{"type": {"network"}, "event": {"die"}})), 0)
+	assert.DeepEqual(t, matching(map[string][]string{"event": {"health_status"}}), []compose.Event{events[5]})
+	assert.DeepEqual(t, matching(map[string][]string{"event": {"health_status: healthy"}}), []compose.Event{events[5]})
+	assert.Equal(t, len(matching(map[string][]string{"event": {"health_status: unhealthy"}})), 0)
+	assert.DeepEqual(t, matching(map[string][]string{"event": {"exec_start"}, "service": {"db"}}), []compose.Event{events[6]})
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func (s *composeService) Exec(ctx context.Context, project *types.Project, opts compose.ExecOptions) (int, error) {
+	service, err := project.GetService(opts.Service)
+	if err != nil {
+		return 0, err
+	}
+
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: filters.NewArgs(
+			projectFilter(project.Name),
+			serviceFilter(service.Name),
+			filters.Arg("label", fmt.Sprintf("%s=%d", containerNumberLabel(), opts.Index)),
+		),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(containers) == 0 {
+		return 0, fmt.Errorf("service %q is not running container #%d", service.Name, opts.Index)
+	}
+	container := containers[0]
+
+	exec, err := s.apiClient.ContainerExecCreate(ctx, container.ID, moby.ExecConfig{
+		Cmd:          opts.Command,
+		Env:          opts.Environment,
+		User:         opts.User,
+		Privileged:   opts.Privileged,
+		Tty:          opts.Tty,
+		DetachKeys:   opts.DetachKeys,
+		WorkingDir:   declaredWorkingDir(opts.WorkingDir, service),
+		AttachStdin:  opts.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := s.apiClient.ContainerExecAttach(ctx, exec.ID, moby.ExecStartCheck{
+		Tty: opts.Tty,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Close()
+
+	readChannel := make(chan error, 10)
+	writeChannel := make(chan error, 10)
+
+	go func() {
+		var err error
+		if opts.Tty {
+			_, err = io.Copy(opts.Stdout, resp.Reader)
+		} else {
+			_, err = stdcopy.StdCopy(opts.Stdout, opts.Stderr, resp.Reader)
+		}
+		readChannel <- err
+	}()
+
+	if opts.Stdin != nil {
+		go func() {
+			_, err := io.Copy(resp.Conn, opts.Stdin)
+			writeChannel <- err
+		}()
+	}
+
+	// when user detaches using the detach keys sequence, engine closes the connection and we stop reading
+	for {
+		select {
+		case err := <-readChannel:
+			if err != nil {
+				return 0, err
+			}
+			return s.execExitCode(ctx, exec.ID)
+		case err := <-writeChannel:
+			if err != nil {
+				return 0, err
+			}
+			// stdin is exhausted, let command complete
+			resp.CloseWrite() // nolint:errcheck
+		}
+	}
+}
+
+// execExitCode returns the exit code of a completed exec, or 0 if user detached and command is still running
+func (s *composeService) execExitCode(ctx context.Context, execID string) (int, error) {
+	inspect, err := s.apiClient.ContainerExecInspect(ctx, execID)
+	if err != nil {
+		return 0, err
+	}
+	if inspect.Running {
+		return 0, nil
+	}
+	return inspect.ExitCode, nil
+}
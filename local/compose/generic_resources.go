@@ -0,0 +1,135 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// extGenericResources lists, next to generic_resources in service reservations, the compose-spec generic resources
+// compose-go doesn't support yet. That is `named_resource_spec` ones, identified by a string value like
+// `{kind: FPGA, value: fpga-1}`
+const extGenericResources = "x-generic_resources"
+
+// namedResource is a `named_resource_spec` generic resource
+type namedResource struct {
+	Kind  string
+	Value string
+}
+
+// ValidateGenericResources checks generic resources reserved by services are well-formed
+func ValidateGenericResources(project *types.Project) error {
+	for _, service := range project.Services {
+		reservations := serviceReservations(service)
+		if reservations == nil {
+			continue
+		}
+		for i, resource := range reservations.GenericResources {
+			if err := validateDiscreteResource(resource.DiscreteResourceSpec); err != nil {
+				return fmt.Errorf("service %q: generic_resources[%d]: %w", service.Name, i, err)
+			}
+		}
+		if _, err := namedResources(reservations); err != nil {
+			return fmt.Errorf("service %q: %w", service.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateDiscreteResource(spec *types.DiscreteGenericResource) error {
+	switch {
+	case spec == nil:
+		return fmt.Errorf("discrete_resource_spec is required, declare named resources in %s", extGenericResources)
+	case spec.Kind == "":
+		return fmt.Errorf("discrete_resource_spec requires a kind")
+	case spec.Value <= 0:
+		return fmt.Errorf("discrete_resource_spec %q requires a positive value", spec.Kind)
+	}
+	return nil
+}
+
+// namedResources parses the named generic resources declared by reservations
+func namedResources(reservations *types.Resource) ([]namedResource, error) {
+	value, ok := reservations.Extensions[extGenericResources]
+	if !ok {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list", extGenericResources)
+	}
+	var resources []namedResource
+	for i, item := range items {
+		entry, ok := item.(map[string]interface{})
+		spec, isSpec := entry["named_resource_spec"].(map[string]interface{})
+		if !ok || !isSpec || len(entry) != 1 {
+			return nil, fmt.Errorf("%s[%d] must declare a named_resource_spec", extGenericResources, i)
+		}
+		for key := range spec {
+			if key != "kind" && key != "value" {
+				return nil, fmt.Errorf("%s[%d]: named_resource_spec: unsupported attribute %q", extGenericResources, i, key)
+			}
+		}
+		kind, _ := spec["kind"].(string)
+		if kind == "" {
+			return nil, fmt.Errorf("%s[%d]: named_resource_spec requires a kind", extGenericResources, i)
+		}
+		value, _ := spec["value"].(string)
+		if value == "" {
+			return nil, fmt.Errorf("%s[%d]: named_resource_spec %q requires a value", extGenericResources, i, kind)
+		}
+		resources = append(resources, namedResource{Kind: kind, Value: value})
+	}
+	return resources, nil
+}
+
+func serviceReservations(service types.ServiceConfig) *types.Resource {
+	if service.Deploy == nil {
+		return nil
+	}
+	return service.Deploy.Resources.Reservations
+}
+
+// genericResourcesWarnings reports services reserving generic resources, which engine only schedules swarm services on
+func genericResourcesWarnings(project *types.Project) []string {
+	var warnings []string
+	for _, service := range project.Services {
+		reservations := serviceReservations(service)
+		if reservations == nil {
+			continue
+		}
+		var kinds []string
+		for _, resource := range reservations.GenericResources {
+			if resource.DiscreteResourceSpec != nil {
+				kinds = append(kinds, resource.DiscreteResourceSpec.Kind)
+			}
+		}
+		named, _ := namedResources(reservations)
+		for _, resource := range named {
+			kinds = append(kinds, resource.Kind)
+		}
+		if len(kinds) > 0 {
+			warnings = append(warnings, fmt.Sprintf("service %q: generic_resources (%s) aren't supported by local backend, ignoring", service.Name, strings.Join(kinds, ", ")))
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
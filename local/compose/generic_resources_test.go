@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func reservingProject(reservations *types.Resource) *types.Project {
+	return &types.Project{
+		Services: types.Services{
+			{Name: "web"},
+			{
+				Name: "worker",
+				Deploy: &types.DeployConfig{
+					Resources: types.Resources{Reservations: reservations},
+				},
+			},
+		},
+	}
+}
+
+func namedResourceSpec(spec map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"named_resource_spec": spec}
+}
+
+func TestValidateGenericResources(t *testing.T) {
+	reservations := &types.Resource{
+		GenericResources: []types.GenericResource{
+			{DiscreteResourceSpec: &types.DiscreteGenericResource{Kind: "SSD", Value: 2}},
+		},
+		Extensions: map[string]interface{}{
+			extGenericResources: []interface{}{
+				namedResourceSpec(map[string]interface{}{"kind": "FPGA", "value": "fpga-1"}),
+			},
+		},
+	}
+	assert.NilError(t, ValidateGenericResources(reservingProject(reservations)))
+	named, err := namedResources(reservations)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, named, []namedResource{{Kind: "FPGA", Value: "fpga-1"}})
+
+	discreteTests := []struct {
+		spec *types.DiscreteGenericResource
+		err  string
+	}{
+		{nil, "generic_resources[0]: discrete_resource_spec is required, declare named resources in x-generic_resources"},
+		{&types.DiscreteGenericResource{Value: 1}, "generic_resources[0]: discrete_resource_spec requires a kind"},
+		{&types.DiscreteGenericResource{Kind: "SSD"}, `generic_resources[0]: discrete_resource_spec "SSD" requires a positive value`},
+	}
+	for _, test := range discreteTests {
+		err := ValidateGenericResources(reservingProject(&types.Resource{
+			GenericResources: []types.GenericResource{{DiscreteResourceSpec: test.spec}},
+		}))
+		assert.Error(t, err, `service "worker": `+test.err)
+	}
+
+	namedTests := []struct {
+		value interface{}
+		err   string
+	}{
+		{"fpga-1", "x-generic_resources must be a list"},
+		{[]interface{}{"fpga-1"}, "x-generic_resources[0] must declare a named_resource_spec"},
+		{[]interface{}{map[string]interface{}{"discrete_resource_spec": map[string]interface{}{"kind": "SSD", "value": 1}}}, "x-generic_resources[0] must declare a named_resource_spec"},
+		{[]interface{}{namedResourceSpec(map[string]interface{}{"value": "fpga-1"})}, "x-generic_resources[0]: named_resource_spec requires a kind"},
+		{[]interface{}{namedResourceSpec(map[string]interface{}{"kind": "FPGA"})}, `x-generic_resources[0]: named_resource_spec "FPGA" requires a value`},
+		{[]interface{}{namedResourceSpec(map[string]interface{}{"kind": "FPGA", "value": "fpga-1", "count": 2})}, `x-generic_resources[0]: named_resource_spec: unsupported attribute "count"`},
+	}
+	for _, test := range namedTests {
+		err := ValidateGenericResources(reservingProject(&types.Resource{
+			Extensions: map[string]interface{}{extGenericResources: test.value},
+		}))
+		assert.Error(t, err, `service "worker": `+test.err)
+	}
+}
+
+func TestGenericResourcesWarnings(t *testing.T) {
+	project := reservingProject(&types.Resource{
+		GenericResources: []types.GenericResource{
+			{DiscreteResourceSpec: &types.DiscreteGenericResource{Kind: "SSD", Value: 2}},
+		},
+		Extensions: map[string]interface{}{
+			extGenericResources: []interface{}{
+				namedResourceSpec(map[string]interface{}{"kind": "FPGA", "value": "fpga-1"}),
+			},
+		},
+	})
+	assert.DeepEqual(t, genericResourcesWarnings(project), []string{
+		`service "worker": generic_resources (SSD, FPGA) aren't supported by local backend, ignoring`,
+	})
+}
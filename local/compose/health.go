@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/compose-cli/api/compose"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+func (s *composeService) Health(ctx context.Context, projectName string, service string, index int) ([]compose.ContainerHealth, error) {
+	args := filters.NewArgs(
+		projectFilter(projectName),
+		serviceFilter(service),
+	)
+	if index > 0 {
+		args.Add("label", fmt.Sprintf("%s=%d", containerNumberLabel(), index))
+	}
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: args,
+		All:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no container found for service %q", service)
+	}
+
+	var health []compose.ContainerHealth
+	for _, c := range containers {
+		container, err := s.apiClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		if container.State == nil || container.State.Health == nil {
+			return nil, fmt.Errorf("container for service %q has no healthcheck configured", service)
+		}
+		var log []compose.HealthcheckResult
+		for _, result := range container.State.Health.Log {
+			log = append(log, compose.HealthcheckResult{
+				Start:    result.Start,
+				End:      result.End,
+				ExitCode: result.ExitCode,
+				Output:   result.Output,
+			})
+		}
+		health = append(health, compose.ContainerHealth{
+			ID:            c.ID,
+			Name:          getContainerName(c),
+			Service:       service,
+			Status:        container.State.Health.Status,
+			FailingStreak: container.State.Health.FailingStreak,
+			Log:           log,
+			State:         container.State.Status,
+			ExitCode:      container.State.ExitCode,
+		})
+	}
+	return health, nil
+}
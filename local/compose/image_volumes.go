@@ -0,0 +1,259 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	volume_api "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// volumeTypeImage mounts the filesystem of an image, which compose-go doesn't declare a constant for yet
+	volumeTypeImage = "image"
+	// extImageVolumes maps the images mounted by services to the volumes holding their filesystem, set on project
+	// once volumes got populated, and on services for the images they mount
+	extImageVolumes = "x-image-volumes"
+	// imageVolumeMountPoint is where the helper container populating an image volume mounts it
+	imageVolumeMountPoint = "/.compose-image-volume"
+	// extReadOnly holds the read_only an image volume declares, as the loader can't tell it from an unset one
+	extReadOnly = "x-read_only"
+)
+
+// imageVolumeSources lists the images services mount as volumes
+func imageVolumeSources(project *types.Project) []string {
+	seen := map[string]bool{}
+	var images []string
+	for _, service := range project.Services {
+		for _, volume := range service.Volumes {
+			if volume.Type == volumeTypeImage && volume.Source != "" && !seen[volume.Source] {
+				seen[volume.Source] = true
+				images = append(images, volume.Source)
+			}
+		}
+	}
+	sort.Strings(images)
+	return images
+}
+
+// imageVolumeName is the name of the volume holding the filesystem of an image, derived from image ID so that an
+// updated image gets a new volume
+func imageVolumeName(projectName string, imageID string) string {
+	id := strings.TrimPrefix(imageID, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return compose.ResourceName(projectName, "image_"+id)
+}
+
+// ensureImageVolumes copies the filesystem of images services mount as volumes into project volumes, engine API this
+// version of compose uses having no image mount type. Engine archive API copies the files out of a container created
+// from the image, which is never started, into the volume mounted by another one
+func (s *composeService) ensureImageVolumes(ctx context.Context, project *types.Project) error {
+	volumes, err := s.resolveImageVolumes(ctx, project, false)
+	if err != nil {
+		return err
+	}
+	for _, image := range imageVolumeSources(project) {
+		name := volumes[image]
+		_, err = s.apiClient.VolumeInspect(ctx, name)
+		if err == nil {
+			continue
+		}
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		eventName := fmt.Sprintf("Volume %q", name)
+		w := progress.ContextWriter(ctx)
+		w.Event(progress.CreatingEvent(eventName).WithResource(progress.VolumeKind, name))
+		if err := s.populateImageVolume(ctx, project.Name, image, name); err != nil {
+			w.Event(progress.ErrorEvent(eventName).WithResource(progress.VolumeKind, name))
+			return err
+		}
+		w.Event(progress.CreatedEvent(eventName).WithResource(progress.VolumeKind, name))
+	}
+	setImageVolumes(project, volumes)
+	return nil
+}
+
+// resolveImageVolumes names the volumes holding the filesystem of images services mount as volumes, without creating
+// them. Missing images are skipped if skipMissing is set
+func (s *composeService) resolveImageVolumes(ctx context.Context, project *types.Project, skipMissing bool) (map[string]string, error) {
+	volumes := map[string]string{}
+	for _, image := range imageVolumeSources(project) {
+		inspect, _, err := s.apiClient.ImageInspectWithRaw(ctx, image)
+		if err != nil {
+			if skipMissing && errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		volumes[image] = imageVolumeName(project.Name, inspect.ID)
+	}
+	return volumes, nil
+}
+
+// setImageVolumes records on project the volumes holding image filesystems, and on services the ones they mount so
+// that service hash changes with the mounted image
+func setImageVolumes(project *types.Project, volumes map[string]string) {
+	if len(volumes) == 0 {
+		return
+	}
+	if project.Extensions == nil {
+		project.Extensions = map[string]interface{}{}
+	}
+	project.Extensions[extImageVolumes] = volumes
+	for i, service := range project.Services {
+		mounted := map[string]string{}
+		for _, volume := range service.Volumes {
+			if name, ok := volumes[volume.Source]; ok && volume.Type == volumeTypeImage {
+				mounted[volume.Source] = name
+			}
+		}
+		if len(mounted) > 0 {
+			project.Services[i].Extensions = setExtension(service.Extensions, extImageVolumes, mounted)
+		}
+	}
+}
+
+// populateImageVolume creates volume with the filesystem of image, removing it if the copy fails so that a partial
+// copy is never used
+func (s *composeService) populateImageVolume(ctx context.Context, projectName string, image string, volume string) error {
+	_, err := s.apiClient.VolumeCreate(ctx, volume_api.VolumeCreateBody{
+		Name: volume,
+		Labels: map[string]string{
+			projectLabel():     projectName,
+			versionLabel():     ComposeVersion,
+			imageVolumeLabel(): image,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = s.copyImageToVolume(ctx, image, volume)
+	if err != nil {
+		_ = s.apiClient.VolumeRemove(ctx, volume, true)
+	}
+	return err
+}
+
+func (s *composeService) copyImageToVolume(ctx context.Context, image string, volume string) error {
+	source, err := s.createImageVolumeHelper(ctx, image, nil)
+	if err != nil {
+		return err
+	}
+	defer s.apiClient.ContainerRemove(ctx, source, moby.ContainerRemoveOptions{Force: true}) //nolint:errcheck
+
+	// volume isn't populated with the content image has at mount point
+	target, err := s.createImageVolumeHelper(ctx, image, []mount.Mount{
+		{
+			Type:          mount.TypeVolume,
+			Source:        volume,
+			Target:        imageVolumeMountPoint,
+			VolumeOptions: &mount.VolumeOptions{NoCopy: true},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer s.apiClient.ContainerRemove(ctx, target, moby.ContainerRemoveOptions{Force: true}) //nolint:errcheck
+
+	content, _, err := s.apiClient.CopyFromContainer(ctx, source, "/.")
+	if err != nil {
+		return err
+	}
+	defer content.Close() //nolint:errcheck
+	return s.apiClient.CopyToContainer(ctx, target, imageVolumeMountPoint, content, moby.CopyToContainerOptions{})
+}
+
+// createImageVolumeHelper creates a container from image which is never started, only giving access to files through
+// the engine archive API. Entrypoint is set as image might not declare any command
+func (s *composeService) createImageVolumeHelper(ctx context.Context, image string, mounts []mount.Mount) (string, error) {
+	created, err := s.apiClient.ContainerCreate(ctx, &container.Config{
+		Image:      image,
+		Entrypoint: []string{imageVolumeMountPoint},
+	}, &container.HostConfig{
+		Mounts: mounts,
+	}, nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// buildImageMount mounts the volume populated with the filesystem of volume source image. Image filesystem is
+// read-only
+func buildImageMount(project types.Project, volume types.ServiceVolumeConfig) (mount.Mount, error) {
+	volumes, _ := project.Extensions[extImageVolumes].(map[string]string)
+	name, ok := volumes[volume.Source]
+	if !ok {
+		return mount.Mount{}, fmt.Errorf("no volume populated with image %q filesystem for mount %s", volume.Source, volume.Target)
+	}
+	return mount.Mount{
+		Type:          mount.TypeVolume,
+		Source:        name,
+		Target:        volume.Target,
+		ReadOnly:      imageVolumeReadOnly(volume),
+		VolumeOptions: &mount.VolumeOptions{NoCopy: true},
+	}, nil
+}
+
+// imageVolumeReadOnly tells if an image volume is mounted read-only, which it is unless it declares `read_only: false`.
+// Writes then go to the volume shared by all containers mounting the same image, until image changes
+func imageVolumeReadOnly(volume types.ServiceVolumeConfig) bool {
+	if readOnly, ok := volume.Extensions[extReadOnly].(bool); ok {
+		return readOnly
+	}
+	return true
+}
+
+// pruneImageVolumes removes the project volumes holding the filesystem of images no service mounts anymore, such as
+// the ones of a previous version of an image. Volumes still in use, by a one-off container for instance, are kept
+// until a later run
+func (s *composeService) pruneImageVolumes(ctx context.Context, project *types.Project) error {
+	current := map[string]bool{}
+	volumes, _ := project.Extensions[extImageVolumes].(map[string]string)
+	for _, name := range volumes {
+		current[name] = true
+	}
+	list, err := s.apiClient.VolumeList(ctx, filters.NewArgs(projectFilter(project.Name), filters.Arg("label", imageVolumeLabel())))
+	if err != nil {
+		return err
+	}
+	for _, volume := range list.Volumes {
+		if current[volume.Name] {
+			continue
+		}
+		err := s.apiClient.VolumeRemove(ctx, volume.Name, false)
+		if err != nil && !errdefs.IsConflict(err) && !errdefs.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	volume_api "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+)
+
+func TestImageVolumeSources(t *testing.T) {
+	project := loadTestProject(t, `
+services:
+  web:
+    image: nginx
+    volumes:
+      - type: image
+        source: docs:latest
+        target: /usr/share/nginx/html
+      - type: volume
+        source: data
+        target: /data
+  worker:
+    image: alpine
+    volumes:
+      - type: image
+        source: models
+        target: /models
+      - type: image
+        source: docs:latest
+        target: /docs
+volumes:
+  data: {}
+`)
+	assert.DeepEqual(t, imageVolumeSources(project), []string{"docs:latest", "models"})
+}
+
+func TestImageVolumeName(t *testing.T) {
+	assert.Equal(t, imageVolumeName("demo", "sha256:0123456789abcdef0123"), "demo_image_0123456789ab")
+	assert.Equal(t, imageVolumeName("demo", "abc"), "demo_image_abc")
+}
+
+func TestBuildImageMount(t *testing.T) {
+	project := loadTestProject(t, `
+services:
+  web:
+    image: nginx
+    volumes:
+      - type: image
+        source: docs:latest
+        target: /usr/share/nginx/html
+`)
+	volume := project.Services[0].Volumes[0]
+
+	_, err := buildMount(*project, volume)
+	assert.Error(t, err, `no volume populated with image "docs:latest" filesystem for mount /usr/share/nginx/html`)
+
+	project.Extensions = map[string]interface{}{
+		extImageVolumes: map[string]string{"docs:latest": "demo_image_0123456789ab"},
+	}
+	m, err := buildMount(*project, volume)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, m, mount.Mount{
+		Type:          mount.TypeVolume,
+		Source:        "demo_image_0123456789ab",
+		Target:        "/usr/share/nginx/html",
+		ReadOnly:      true,
+		VolumeOptions: &mount.VolumeOptions{NoCopy: true},
+	})
+
+	volume.Extensions = map[string]interface{}{extReadOnly: false}
+	m, err = buildMount(*project, volume)
+	assert.NilError(t, err)
+	assert.Assert(t, !m.ReadOnly)
+}
+
+func TestPruneImageVolumes(t *testing.T) {
+	var removed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.41/volumes":
+			filter, err := filters.FromJSON(r.URL.Query().Get("filters"))
+			assert.NilError(t, err)
+			assert.Assert(t, filter.ExactMatch("label", projectLabel()+"=demo"))
+			assert.Assert(t, filter.Match("label", imageVolumeLabel()))
+			_ = json.NewEncoder(w).Encode(volume_api.VolumeListOKBody{Volumes: []*moby.Volume{
+				{Name: "demo_image_0123456789ab"},
+				{Name: "demo_image_ba9876543210"},
+				{Name: "demo_image_inuse"},
+			}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/v1.41/volumes/demo_image_inuse":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"volume is in use"}`))
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v1.41/volumes/"):
+			removed = append(removed, strings.TrimPrefix(r.URL.Path, "/v1.41/volumes/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.41"))
+	assert.NilError(t, err)
+	s := composeService{apiClient: apiClient}
+
+	project := &types.Project{
+		Name: "demo",
+		Extensions: map[string]interface{}{
+			extImageVolumes: map[string]string{"docs:latest": "demo_image_ba9876543210"},
+		},
+	}
+	assert.NilError(t, s.pruneImageVolumes(context.Background(), project))
+	assert.DeepEqual(t, removed, []string{"demo_image_0123456789ab"})
+}
+
+func TestSetImageVolumesChangesServiceHash(t *testing.T) {
+	project := loadTestProject(t, `
+services:
+  web:
+    image: nginx
+    volumes:
+      - type: image
+        source: docs:latest
+        target: /usr/share/nginx/html
+  db:
+    image: postgres
+`)
+	setImageVolumes(project, map[string]string{"docs:latest": "demo_image_0123456789ab"})
+	web, err := project.GetService("web")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, web.Extensions[extImageVolumes], map[string]string{"docs:latest": "demo_image_0123456789ab"})
+	db, err := project.GetService("db")
+	assert.NilError(t, err)
+	assert.Assert(t, db.Extensions == nil)
+	created, err := serviceHash(web, "")
+	assert.NilError(t, err)
+
+	setImageVolumes(project, map[string]string{"docs:latest": "demo_image_ba9876543210"})
+	web, err = project.GetService("web")
+	assert.NilError(t, err)
+	updated, err := serviceHash(web, "")
+	assert.NilError(t, err)
+	assert.Assert(t, updated != created)
+}
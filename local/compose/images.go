@@ -0,0 +1,70 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func (s *composeService) Images(ctx context.Context, project *types.Project) ([]compose.ImageSummary, error) {
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: filters.NewArgs(projectFilter(project.Name)),
+		All:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	imageIDs, err := s.serviceImageIDs(ctx, project, containers)
+	if err != nil {
+		return nil, err
+	}
+
+	digests := map[string]string{}
+	var images []compose.ImageSummary
+	for _, c := range containers {
+		digest, ok := digests[c.ImageID]
+		if !ok {
+			inspect, _, err := s.apiClient.ImageInspectWithRaw(ctx, c.ImageID)
+			if err != nil && !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+			if len(inspect.RepoDigests) > 0 {
+				// repository digests are `name@digest`
+				digest = inspect.RepoDigests[0][strings.LastIndex(inspect.RepoDigests[0], "@")+1:]
+			}
+			digests[c.ImageID] = digest
+		}
+		service := c.Labels[serviceLabel()]
+		images = append(images, compose.ImageSummary{
+			ContainerName: getContainerName(c),
+			Service:       service,
+			Image:         c.Image,
+			ImageID:       c.ImageID,
+			Digest:        digest,
+			Mismatch:      imageMismatch(c, imageIDs[service]),
+		})
+	}
+	return images, nil
+}
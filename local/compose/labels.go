@@ -20,32 +20,41 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/api/types/filters"
-)
 
-const (
-	containerNumberLabel = "com.docker.compose.container-number"
-	oneoffLabel          = "com.docker.compose.oneoff"
-	projectLabel         = "com.docker.compose.project"
-	volumeLabel          = "com.docker.compose.volume"
-	workingDirLabel      = "com.docker.compose.project.working_dir"
-	configFilesLabel     = "com.docker.compose.project.config_files"
-	serviceLabel         = "com.docker.compose.service"
-	versionLabel         = "com.docker.compose.version"
-	configHashLabel      = "com.docker.compose.config-hash"
-	networkLabel         = "com.docker.compose.network"
-
-	//ComposeVersion Compose version
-	ComposeVersion = "1.0-alpha"
+	"github.com/docker/compose-cli/api/compose"
 )
 
+// ComposeVersion Compose version
+const ComposeVersion = "1.0-alpha"
+
+// labels compose relies on, in the label namespace COMPOSE_LABEL_PREFIX sets when they're used
+
+func containerNumberLabel() string { return compose.Label("container-number") }
+func oneoffLabel() string          { return compose.Label("oneoff") }
+func projectLabel() string         { return compose.ProjectTag() }
+func volumeLabel() string          { return compose.VolumeTag() }
+func workingDirLabel() string      { return compose.Label("project.working_dir") }
+func configFilesLabel() string     { return compose.Label("project.config_files") }
+func envFileLabel() string         { return compose.Label("project.environment_file") }
+func serviceLabel() string         { return compose.ServiceTag() }
+func versionLabel() string         { return compose.Label("version") }
+func configHashLabel() string      { return compose.Label("config-hash") }
+func networkLabel() string         { return compose.NetworkTag() }
+func imageDigestLabel() string     { return compose.Label("image.digest") }
+func imagePrimaryLabel() string    { return compose.Label("image.primary") }
+func buildHashLabel() string       { return compose.Label("build.context-hash") }
+func imageVolumeLabel() string     { return compose.Label("volume.image") }
+func imageProjectLabel() string    { return compose.Label("image.project") }
+func imageServiceLabel() string    { return compose.Label("image.service") }
+
 func projectFilter(projectName string) filters.KeyValuePair {
-	return filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel, projectName))
+	return filters.Arg("label", fmt.Sprintf("%s=%s", projectLabel(), projectName))
 }
 
 func serviceFilter(serviceName string) filters.KeyValuePair {
-	return filters.Arg("label", fmt.Sprintf("%s=%s", serviceLabel, serviceName))
+	return filters.Arg("label", fmt.Sprintf("%s=%s", serviceLabel(), serviceName))
 }
 
 func hasProjectLabelFilter() filters.KeyValuePair {
-	return filters.Arg("label", projectLabel)
+	return filters.Arg("label", projectLabel())
 }
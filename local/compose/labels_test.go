@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestLabelPrefix(t *testing.T) {
+	previous, isSet := os.LookupEnv(compose.LabelPrefixEnv)
+	defer func() {
+		if isSet {
+			os.Setenv(compose.LabelPrefixEnv, previous) //nolint:errcheck
+		} else {
+			os.Unsetenv(compose.LabelPrefixEnv) //nolint:errcheck
+		}
+	}()
+
+	assert.NilError(t, os.Unsetenv(compose.LabelPrefixEnv))
+	assert.Equal(t, compose.LabelPrefix(), "com.docker.compose")
+	assert.Equal(t, projectLabel(), "com.docker.compose.project")
+
+	// labels follow the prefix set once the process started
+	assert.NilError(t, os.Setenv(compose.LabelPrefixEnv, "com.example.compose"))
+	assert.Equal(t, compose.LabelPrefix(), "com.example.compose")
+	assert.Equal(t, projectLabel(), "com.example.compose.project")
+	assert.Equal(t, containerNumberLabel(), "com.example.compose.container-number")
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"time"
 
 	"github.com/docker/compose-cli/api/compose"
 
@@ -29,46 +30,138 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-func (s *composeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer) error {
+func (s *composeService) Logs(ctx context.Context, projectName string, consumer compose.LogConsumer, options compose.LogOptions) error {
 	list, err := s.apiClient.ContainerList(ctx, types.ContainerListOptions{
 		Filters: filters.NewArgs(
 			projectFilter(projectName),
 		),
+		All: !options.NoExited,
 	})
 	if err != nil {
 		return err
 	}
-	eg, ctx := errgroup.WithContext(ctx)
+
+	var containers []types.ContainerJSON
 	for _, c := range list {
-		service := c.Labels[serviceLabel]
+		if len(options.Services) > 0 && !contains(options.Services, c.Labels[serviceLabel()]) {
+			continue
+		}
 		container, err := s.apiClient.ContainerInspect(ctx, c.ID)
 		if err != nil {
 			return err
 		}
+		containers = append(containers, container)
+	}
+	// containers replaced by a recreation only have the logs saved before they got removed, which don't tell when
+	// lines were logged
+	var (
+		replaced      []replacedLogsHeader
+		replacedLines map[string][]replacedLogLine
+	)
+	if !options.NoExited && options.Since == "" {
+		replaced, replacedLines, err = replacedLogs(projectName, options.Services)
+		if err != nil {
+			return err
+		}
+	}
+
+	// replicas and exited containers are all declared before any log is consumed, so their prefix is known upfront
+	if rc, ok := consumer.(compose.ReplicaLogConsumer); ok {
+		for _, container := range containers {
+			rc.ContainerReplica(container.ID, replicaName(container.Config.Labels))
+		}
+		for _, header := range replaced {
+			rc.ContainerReplica(header.Container, header.Replica)
+		}
+	}
+	if ec, ok := consumer.(compose.ExitedLogConsumer); ok {
+		for _, container := range containers {
+			if container.State != nil && !container.State.Running {
+				ec.ContainerExited(container.ID, replicaName(container.Config.Labels))
+			}
+		}
+		for _, header := range replaced {
+			ec.ContainerExited(header.Container, header.Replica)
+		}
+	}
+	// replaced containers logged before the ones replacing them
+	for _, header := range replaced {
+		for _, line := range tailLines(replacedLines[header.Container], options.Tail) {
+			logReplacedLine(consumer, header, line)
+		}
+	}
 
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, c := range containers {
+		container := c
+		service := container.Config.Labels[serviceLabel()]
 		eg.Go(func() error {
 			r, err := s.apiClient.ContainerLogs(ctx, container.ID, types.ContainerLogsOptions{
 				ShowStdout: true,
 				ShowStderr: true,
-				Follow:     true,
+				Follow:     options.Follow,
+				Since:      logsSince(options.Since, container),
+				Tail:       options.Tail,
+				Timestamps: options.Timestamps,
 			})
-			defer r.Close() // nolint errcheck
-
 			if err != nil {
 				return err
 			}
-			w := getWriter(service, container.ID, consumer)
-			if container.Config.Tty {
-				_, err = io.Copy(w, r)
-			} else {
-				_, err = stdcopy.StdCopy(w, w, r)
-			}
-			return err
+			defer r.Close() // nolint errcheck
+
+			return copyLogs(r, container.Config.Tty, service, container.ID, consumer)
 		})
 	}
 	return eg.Wait()
 }
 
+// replicaName identifies a container among the service replicas, as `service_number`
+func replicaName(labels map[string]string) string {
+	number, ok := labels[containerNumberLabel()]
+	if !ok {
+		return labels[serviceLabel()]
+	}
+	return compose.ResourceName(labels[serviceLabel()], number)
+}
+
+// copyLogs demultiplexes container output into consumer, in the order lines were emitted
+func copyLogs(r io.Reader, tty bool, service, container string, consumer compose.LogConsumer) error {
+	var err error
+	if tty {
+		// stdout and stderr are merged by the pseudo-terminal
+		_, err = io.Copy(getStreamWriter(service, container, compose.StdoutStream, consumer), r)
+		return err
+	}
+	stdout := getStreamWriter(service, container, compose.StdoutStream, consumer)
+	stderr := getStreamWriter(service, container, compose.StderrStream, consumer)
+	_, err = stdcopy.StdCopy(stdout, stderr, r)
+	return err
+}
+
+// logsSince resolves the time to get container logs since, translating SinceContainerStart into the container last start boundary
+func logsSince(since string, container types.ContainerJSON) string {
+	if since != compose.SinceContainerStart {
+		return since
+	}
+	if container.ContainerJSONBase == nil || container.State == nil {
+		return ""
+	}
+	started, err := time.Parse(time.RFC3339Nano, container.State.StartedAt)
+	if err != nil {
+		return ""
+	}
+	finished, err := time.Parse(time.RFC3339Nano, container.State.FinishedAt)
+	if err != nil || finished.IsZero() {
+		// container never restarted, all logs are since container start
+		return ""
+	}
+	if finished.After(started) {
+		return container.State.StartedAt
+	}
+	// first lines can be logged before StartedAt is recorded, so rely on the time container previously stopped
+	return container.State.FinishedAt
+}
+
 type splitBuffer struct {
 	service   string
 	container string
@@ -84,6 +177,19 @@ func getWriter(service, container string, l compose.LogConsumer) io.Writer {
 	}
 }
 
+// getStreamWriter creates a io.Writer like getWriter, telling consumer about the stream lines are written to if supported
+func getStreamWriter(service, container, stream string, l compose.LogConsumer) io.Writer {
+	if sl, ok := l.(compose.StreamLogConsumer); ok {
+		return streamSplitBuffer{
+			service:   service,
+			container: container,
+			stream:    stream,
+			consumer:  sl,
+		}
+	}
+	return getWriter(service, container, l)
+}
+
 func (s splitBuffer) Write(b []byte) (n int, err error) {
 	split := bytes.Split(b, []byte{'\n'})
 	for _, line := range split {
@@ -93,3 +199,20 @@ func (s splitBuffer) Write(b []byte) (n int, err error) {
 	}
 	return len(b), nil
 }
+
+type streamSplitBuffer struct {
+	service   string
+	container string
+	stream    string
+	consumer  compose.StreamLogConsumer
+}
+
+func (s streamSplitBuffer) Write(b []byte) (n int, err error) {
+	split := bytes.Split(b, []byte{'\n'})
+	for _, line := range split {
+		if len(line) != 0 {
+			s.consumer.LogStream(s.service, s.container, s.stream, string(line))
+		}
+	}
+	return len(b), nil
+}
@@ -0,0 +1,116 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestLogsSince(t *testing.T) {
+	withState := func(startedAt, finishedAt string) types.ContainerJSON {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				State: &types.ContainerState{
+					StartedAt:  startedAt,
+					FinishedAt: finishedAt,
+				},
+			},
+		}
+	}
+
+	restarted := withState("2020-12-14T10:15:30.123456789Z", "2020-12-14T10:15:29.5Z")
+	assert.Equal(t, logsSince(compose.SinceContainerStart, restarted), "2020-12-14T10:15:29.5Z")
+	assert.Equal(t, logsSince("42m", restarted), "42m")
+	assert.Equal(t, logsSince("", restarted), "")
+
+	neverRestarted := withState("2020-12-14T10:15:30.123456789Z", "0001-01-01T00:00:00Z")
+	assert.Equal(t, logsSince(compose.SinceContainerStart, neverRestarted), "")
+
+	stopped := withState("2020-12-14T10:15:30.123456789Z", "2020-12-14T10:20:00Z")
+	assert.Equal(t, logsSince(compose.SinceContainerStart, stopped), "2020-12-14T10:15:30.123456789Z")
+
+	assert.Equal(t, logsSince(compose.SinceContainerStart, types.ContainerJSON{}), "")
+}
+
+type recordingConsumer struct {
+	lines []string
+}
+
+func (r *recordingConsumer) Log(service, container, message string) {
+	r.lines = append(r.lines, message)
+}
+
+type recordingStreamConsumer struct {
+	recordingConsumer
+}
+
+func (r *recordingStreamConsumer) LogStream(service, container, stream, message string) {
+	r.lines = append(r.lines, stream+": "+message)
+}
+
+func multiplexedLogs(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	stdout := stdcopy.NewStdWriter(buf, stdcopy.Stdout)
+	stderr := stdcopy.NewStdWriter(buf, stdcopy.Stderr)
+	for _, w := range []struct {
+		stream  io.Writer
+		message string
+	}{
+		{stdout, "starting\n"},
+		{stderr, "{\"level\":\"warn\"}\n"},
+		{stdout, "listening\n"},
+		{stderr, "{\"level\":\"error\"}\n"},
+	} {
+		_, err := w.stream.Write([]byte(w.message))
+		assert.NilError(t, err)
+	}
+	return buf
+}
+
+func TestCopyLogsMergedPreservesOrder(t *testing.T) {
+	consumer := &recordingConsumer{}
+	err := copyLogs(multiplexedLogs(t), false, "web", "123", consumer)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, consumer.lines, []string{"starting", `{"level":"warn"}`, "listening", `{"level":"error"}`})
+}
+
+func TestCopyLogsWithStreams(t *testing.T) {
+	consumer := &recordingStreamConsumer{}
+	err := copyLogs(multiplexedLogs(t), false, "web", "123", consumer)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, consumer.lines, []string{"stdout: starting", `stderr: {"level":"warn"}`, "stdout: listening", `stderr: {"level":"error"}`})
+}
+
+func TestCopyLogsTty(t *testing.T) {
+	consumer := &recordingStreamConsumer{}
+	err := copyLogs(bytes.NewBufferString("starting\nlistening\n"), true, "web", "123", consumer)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, consumer.lines, []string{"stdout: starting", "stdout: listening"})
+}
+
+func TestReplicaName(t *testing.T) {
+	assert.Equal(t, replicaName(map[string]string{serviceLabel(): "web", containerNumberLabel(): "2"}), "web_2")
+	assert.Equal(t, replicaName(map[string]string{serviceLabel(): "web"}), "web")
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/docker/compose-cli/api/compose"
 
@@ -27,29 +29,82 @@ import (
 	"github.com/docker/docker/api/types/filters"
 )
 
-func (s *composeService) List(ctx context.Context, projectName string) ([]compose.Stack, error) {
+func (s *composeService) List(ctx context.Context, projectName string, options compose.ListOptions) ([]compose.Stack, error) {
 	list, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
 		Filters: filters.NewArgs(hasProjectLabelFilter()),
+		All:     options.All,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return containersToStacks(list)
+	stacks, err := containersToStacks(list)
+	if err != nil {
+		return nil, err
+	}
+	for i, stack := range stacks {
+		if !allStopped(stack.Containers) {
+			continue
+		}
+		stacks[i].Exited, err = s.lastExited(ctx, list, stack.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stacks, nil
+}
+
+// lastExited returns the time the last container of a project exited
+func (s *composeService) lastExited(ctx context.Context, containers []moby.Container, projectName string) (time.Time, error) {
+	var last time.Time
+	for _, c := range containers {
+		if c.Labels[projectLabel()] != projectName {
+			continue
+		}
+		inspect, err := s.apiClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return last, err
+		}
+		finished, err := time.Parse(time.RFC3339Nano, inspect.State.FinishedAt)
+		if err == nil && finished.After(last) {
+			last = finished
+		}
+	}
+	return last, nil
+}
+
+// allStopped tells if all containers, counted by state, are exited or dead
+func allStopped(containers map[string]int) bool {
+	for state, nb := range containers {
+		if nb > 0 && state != "exited" && state != "dead" {
+			return false
+		}
+	}
+	return len(containers) > 0
 }
 
 func containersToStacks(containers []moby.Container) ([]compose.Stack, error) {
-	containersByLabel, keys, err := groupContainerByLabel(containers, projectLabel)
+	containersByLabel, keys, err := groupContainerByLabel(containers, projectLabel())
 	if err != nil {
 		return nil, err
 	}
 	var projects []compose.Stack
 	for _, project := range keys {
-		projects = append(projects, compose.Stack{
-			ID:     project,
-			Name:   project,
-			Status: combinedStatus(containerToState(containersByLabel[project])),
-		})
+		containers := containersByLabel[project]
+		statuses := containerToState(containers)
+		stack := compose.Stack{
+			ID:         project,
+			Name:       project,
+			Status:     combinedStatus(statuses),
+			Containers: countStatuses(statuses),
+			Created:    newestCreated(containers),
+			WorkingDir: containers[0].Labels[workingDirLabel()],
+			EnvFile:    containers[0].Labels[envFileLabel()],
+		}
+		if files := containers[0].Labels[configFilesLabel()]; files != "" {
+			stack.ConfigFiles = strings.Split(files, ",")
+		}
+		projects = append(projects, stack)
 	}
 	return projects, nil
 }
@@ -62,6 +117,24 @@ func containerToState(containers []moby.Container) []string {
 	return statuses
 }
 
+func countStatuses(statuses []string) map[string]int {
+	nbByStatus := map[string]int{}
+	for _, status := range statuses {
+		nbByStatus[status]++
+	}
+	return nbByStatus
+}
+
+func newestCreated(containers []moby.Container) time.Time {
+	var newest int64
+	for _, c := range containers {
+		if c.Created > newest {
+			newest = c.Created
+		}
+	}
+	return time.Unix(newest, 0)
+}
+
 func combinedStatus(statuses []string) string {
 	nbByStatus := map[string]int{}
 	keys := []string{}
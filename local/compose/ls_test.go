@@ -18,6 +18,7 @@ package compose
 
 import (
 	"testing"
+	"time"
 
 	"github.com/docker/compose-cli/api/compose"
 
@@ -28,33 +29,48 @@ import (
 func TestContainersToStacks(t *testing.T) {
 	containers := []moby.Container{
 		{
-			ID:     "service1",
-			State:  "running",
-			Labels: map[string]string{projectLabel: "project1"},
+			ID:      "service1",
+			State:   "running",
+			Created: 1600000000,
+			Labels:  map[string]string{projectLabel(): "project1"},
 		},
 		{
-			ID:     "service2",
-			State:  "running",
-			Labels: map[string]string{projectLabel: "project1"},
+			ID:      "service2",
+			State:   "exited",
+			Created: 1600000100,
+			Labels:  map[string]string{projectLabel(): "project1"},
 		},
 		{
-			ID:     "service3",
-			State:  "running",
-			Labels: map[string]string{projectLabel: "project2"},
+			ID:      "service3",
+			State:   "running",
+			Created: 1600000050,
+			Labels: map[string]string{
+				projectLabel():     "project2",
+				workingDirLabel():  "/src/project2",
+				configFilesLabel(): "/src/project2/docker-compose.yml,/src/project2/prod.yml",
+				envFileLabel():     "/src/project2/prod.env",
+			},
 		},
 	}
 	stacks, err := containersToStacks(containers)
 	assert.NilError(t, err)
 	assert.DeepEqual(t, stacks, []compose.Stack{
 		{
-			ID:     "project1",
-			Name:   "project1",
-			Status: "running(2)",
+			ID:         "project1",
+			Name:       "project1",
+			Status:     "exited(1), running(1)",
+			Containers: map[string]int{"running": 1, "exited": 1},
+			Created:    time.Unix(1600000100, 0),
 		},
 		{
-			ID:     "project2",
-			Name:   "project2",
-			Status: "running(1)",
+			ID:          "project2",
+			Name:        "project2",
+			Status:      "running(1)",
+			Containers:  map[string]int{"running": 1},
+			Created:     time.Unix(1600000050, 0),
+			WorkingDir:  "/src/project2",
+			ConfigFiles: []string{"/src/project2/docker-compose.yml", "/src/project2/prod.yml"},
+			EnvFile:     "/src/project2/prod.env",
 		},
 	})
 }
@@ -64,3 +80,10 @@ func TestStacksMixedStatus(t *testing.T) {
 	assert.Equal(t, combinedStatus([]string{"running", "running", "running"}), "running(3)")
 	assert.Equal(t, combinedStatus([]string{"running", "exited", "running"}), "exited(1), running(2)")
 }
+
+func TestAllStopped(t *testing.T) {
+	assert.Assert(t, allStopped(map[string]int{"exited": 2, "dead": 1}))
+	assert.Assert(t, !allStopped(map[string]int{"exited": 2, "running": 1}))
+	assert.Assert(t, !allStopped(map[string]int{"created": 1}))
+	assert.Assert(t, !allStopped(map[string]int{}))
+}
@@ -0,0 +1,171 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+const (
+	// extNotify sets a command to be executed on project state changes, with a JSON notification as stdin
+	extNotify = "x-notify"
+
+	notifyUpComplete       = "up-complete"
+	notifyServiceUnhealthy = "service-unhealthy"
+	notifyContainerDied    = "container-died"
+	notifyDownComplete     = "down-complete"
+)
+
+// notifyTimeout is the time a notify command is given to complete before it gets killed
+var notifyTimeout = 10 * time.Second
+
+type notification struct {
+	Project   string    `json:"project"`
+	Event     string    `json:"event"`
+	Service   string    `json:"service,omitempty"`
+	Container string    `json:"container,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type notifier struct {
+	project    string
+	command    []string
+	workingDir string
+	pending    sync.WaitGroup
+}
+
+// newNotifier creates a notifier for the x-notify command set by project, nil if none is set.
+// A notifier never fails compose operations, so invalid configuration is only logged
+func newNotifier(project *types.Project) *notifier {
+	command, err := notifyCommand(project.Extensions[extNotify])
+	if err != nil {
+		logrus.Warnf("ignoring %s: %v", extNotify, err)
+		return nil
+	}
+	if len(command) == 0 {
+		return nil
+	}
+	return &notifier{
+		project:    project.Name,
+		command:    command,
+		workingDir: project.WorkingDir,
+	}
+}
+
+// notifyCommand parses x-notify as a list of arguments, or as a string split on whitespaces
+func notifyCommand(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.Fields(v), nil
+	case []interface{}:
+		var command []string
+		for _, arg := range v {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("command arguments must be strings, got %v", arg)
+			}
+			command = append(command, s)
+		}
+		return command, nil
+	default:
+		return nil, fmt.Errorf("must be a string or a list of strings, got %T", value)
+	}
+}
+
+// notify runs the notify command in background, failures are only logged
+func (n *notifier) notify(event, service, container string) {
+	if n == nil {
+		return
+	}
+	payload, err := json.Marshal(notification{
+		Project:   n.project,
+		Event:     event,
+		Service:   service,
+		Container: container,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		logrus.Warnf("failed to notify %s: %v", event, err)
+		return
+	}
+	n.pending.Add(1)
+	go func() {
+		defer n.pending.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, n.command[0], n.command[1:]...)
+		cmd.Dir = n.workingDir
+		cmd.Stdin = bytes.NewReader(payload)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			logrus.Warnf("%s command failed to handle %s: %v %s", extNotify, event, err, strings.TrimSpace(string(out)))
+		}
+	}()
+}
+
+// watch notifies about containers dying or becoming unhealthy, until the returned stop function is called
+func (n *notifier) watch(ctx context.Context, events func(context.Context, string, compose.EventsOptions) error) func() {
+	if n == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := events(ctx, n.project, compose.EventsOptions{
+			Filters: map[string][]string{"type": {"container"}},
+			Consumer: func(event compose.Event) error {
+				switch event.Status {
+				case "die":
+					n.notify(notifyContainerDied, event.Service, event.Container)
+				case "health_status: unhealthy":
+					n.notify(notifyServiceUnhealthy, event.Service, event.Container)
+				}
+				return nil
+			},
+		})
+		if err != nil && ctx.Err() == nil {
+			logrus.Warnf("%s stopped watching containers: %v", extNotify, err)
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// wait lets pending notify commands complete, which each takes at most notifyTimeout
+func (n *notifier) wait() {
+	if n == nil {
+		return
+	}
+	n.pending.Wait()
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestNotifyCommand(t *testing.T) {
+	command, err := notifyCommand("notify-dashboard --channel ops")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, command, []string{"notify-dashboard", "--channel", "ops"})
+
+	command, err = notifyCommand([]interface{}{"sh", "-c", "cat > /tmp/event.json"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, command, []string{"sh", "-c", "cat > /tmp/event.json"})
+
+	command, err = notifyCommand(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, command == nil)
+
+	_, err = notifyCommand([]interface{}{"notify", 42})
+	assert.Error(t, err, "command arguments must be strings, got 42")
+
+	_, err = notifyCommand(map[string]interface{}{"url": "http://dashboard"})
+	assert.Error(t, err, "must be a string or a list of strings, got map[string]interface {}")
+}
+
+func TestNotifierWithoutCommand(t *testing.T) {
+	n := newNotifier(&types.Project{Name: "myproject"})
+	assert.Assert(t, n == nil)
+	// a nil notifier is a no-op
+	n.notify(notifyUpComplete, "", "")
+	n.watch(context.Background(), nil)()
+	n.wait()
+}
+
+func TestNotify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("notify command relies on sh")
+	}
+	dir := t.TempDir()
+	n := newNotifier(&types.Project{
+		Name:       "myproject",
+		WorkingDir: dir,
+		Extensions: map[string]interface{}{
+			extNotify: []interface{}{"sh", "-c", "cat > notification.json"},
+		},
+	})
+	n.notify(notifyContainerDied, "web", "123")
+	n.wait()
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "notification.json"))
+	assert.NilError(t, err)
+	var payload notification
+	assert.NilError(t, json.Unmarshal(content, &payload))
+	assert.Equal(t, payload.Project, "myproject")
+	assert.Equal(t, payload.Event, notifyContainerDied)
+	assert.Equal(t, payload.Service, "web")
+	assert.Equal(t, payload.Container, "123")
+}
+
+func TestNotifyTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("notify command relies on sleep")
+	}
+	defer func(timeout time.Duration) {
+		notifyTimeout = timeout
+	}(notifyTimeout)
+	notifyTimeout = 100 * time.Millisecond
+
+	n := newNotifier(&types.Project{
+		Name:       "myproject",
+		Extensions: map[string]interface{}{extNotify: "sleep 10"},
+	})
+	start := time.Now()
+	n.notify(notifyUpComplete, "", "")
+	assert.Assert(t, time.Since(start) < notifyTimeout, "notify should not block")
+	n.wait()
+	assert.Assert(t, time.Since(start) < 5*time.Second, "notify command should have been killed")
+}
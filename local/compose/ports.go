@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	// extPortName names a service port, for service discovery tooling
+	extPortName = "x-name"
+	// extPortAppProtocol declares the application protocol a service port speaks, e.g. http or postgres
+	extPortAppProtocol = "x-app_protocol"
+)
+
+// PortName returns the name declared for a service port, if any
+func PortName(port types.ServicePortConfig) string {
+	name, _ := port.Extensions[extPortName].(string)
+	return name
+}
+
+// PortAppProtocol returns the application protocol declared for a service port, if any
+func PortAppProtocol(port types.ServicePortConfig) string {
+	protocol, _ := port.Extensions[extPortAppProtocol].(string)
+	return protocol
+}
+
+// publishedPorts lists the ports a listed container publishes, as ps displays them
+func publishedPorts(ports []moby.Port) []string {
+	var published []string
+	for _, p := range ports {
+		if p.PublicPort == 0 {
+			continue
+		}
+		published = append(published, fmt.Sprintf("%s:%d->%d/%s", p.IP, p.PublicPort, p.PrivatePort, p.Type))
+	}
+	sort.Strings(published)
+	return published
+}
+
+// bindingPorts lists the ports an inspected container publishes, as ps displays them
+func bindingPorts(bindings nat.PortMap) []string {
+	var published []string
+	for port, hosts := range bindings {
+		for _, host := range hosts {
+			if host.HostPort == "" {
+				continue
+			}
+			published = append(published, fmt.Sprintf("%s:%s->%d/%s", host.HostIP, host.HostPort, port.Int(), port.Proto()))
+		}
+	}
+	sort.Strings(published)
+	return published
+}
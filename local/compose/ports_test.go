@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func loadTestProject(t *testing.T, content string) *types.Project {
+	config, err := loader.ParseYAML([]byte(content))
+	assert.NilError(t, err)
+	project, err := loader.Load(types.ConfigDetails{
+		WorkingDir:  ".",
+		ConfigFiles: []types.ConfigFile{{Filename: "docker-compose.yml", Config: config}},
+	})
+	assert.NilError(t, err)
+	return project
+}
+
+func TestPortMetadataRoundTrip(t *testing.T) {
+	project := loadTestProject(t, `
+services:
+  db:
+    image: postgres
+    ports:
+      - target: 5432
+        published: 5432
+        x-name: sql
+        x-app_protocol: postgres
+      - target: 8080
+`)
+	ports := project.Services[0].Ports
+	assert.Equal(t, PortName(ports[0]), "sql")
+	assert.Equal(t, PortAppProtocol(ports[0]), "postgres")
+	assert.Equal(t, PortName(ports[1]), "")
+	assert.Equal(t, PortAppProtocol(ports[1]), "")
+
+	rendered, err := (&composeService{}).Convert(context.Background(), project, compose.ConvertOptions{Format: "yaml"})
+	assert.NilError(t, err)
+	assert.Assert(t, cmp.Contains(string(rendered), "x-name: sql"))
+	assert.Assert(t, cmp.Contains(string(rendered), "x-app_protocol: postgres"))
+}
+
+func TestPublishedPorts(t *testing.T) {
+	assert.DeepEqual(t, publishedPorts([]moby.Port{
+		{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+		{PrivatePort: 9000, Type: "tcp"},
+		{IP: "127.0.0.1", PrivatePort: 53, PublicPort: 5353, Type: "udp"},
+	}), []string{"0.0.0.0:8080->80/tcp", "127.0.0.1:5353->53/udp"})
+
+	assert.DeepEqual(t, bindingPorts(nat.PortMap{
+		"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "8080"}},
+		"9000/tcp": nil,
+		"53/udp":   {{HostIP: "127.0.0.1", HostPort: "5353"}},
+	}), []string{"0.0.0.0:8080->80/tcp", "127.0.0.1:5353->53/udp"})
+	assert.Assert(t, publishedPorts(nil) == nil)
+}
@@ -0,0 +1,167 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+const (
+	// ProfilesEnv selects the active profiles, as a comma separated list
+	ProfilesEnv = "COMPOSE_PROFILES"
+	// extProfiles holds service profiles, which the compose-go version in use drops while loading, see
+	// ApplySpecAttributes
+	extProfiles = "x-profiles"
+	// extDisabledServices holds on project the services none of the active profiles enables, so that targeting one
+	// still enables it, and their containers aren't orphans
+	extDisabledServices = "x-disabled-services"
+	// allProfiles activates all profiles
+	allProfiles = "*"
+)
+
+// serviceProfiles lists the profiles a service belongs to
+func serviceProfiles(service types.ServiceConfig) []string {
+	values, _ := service.Extensions[extProfiles].([]interface{})
+	var profiles []string
+	for _, value := range values {
+		profiles = append(profiles, fmt.Sprint(value))
+	}
+	return profiles
+}
+
+// ProjectProfiles lists, sorted, the profiles services of project belong to, whether they are active or not
+func ProjectProfiles(project *types.Project) []string {
+	seen := map[string]bool{}
+	var profiles []string
+	for _, service := range DeclaredServices(project) {
+		for _, profile := range serviceProfiles(service) {
+			if !seen[profile] {
+				seen[profile] = true
+				profiles = append(profiles, profile)
+			}
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// DeclaredServices lists the services project declares, disabled ones included
+func DeclaredServices(project *types.Project) types.Services {
+	return append(append(types.Services{}, project.Services...), disabledServices(project)...)
+}
+
+// ApplyProfiles disables the services which belong to profiles, none of them being active. Services which don't
+// belong to any profile are always enabled. An unknown profile is an error, as it is most likely a typo
+func ApplyProfiles(project *types.Project, profiles []string) error {
+	known := ProjectProfiles(project)
+	active := map[string]bool{}
+	for _, profile := range profiles {
+		if profile != allProfiles && !contains(known, profile) {
+			if len(known) == 0 {
+				return fmt.Errorf("no such profile %q, project doesn't declare any", profile)
+			}
+			return fmt.Errorf("no such profile %q, valid profiles are: %s", profile, strings.Join(known, ", "))
+		}
+		active[profile] = true
+	}
+
+	var enabled types.Services
+	disabled := disabledServices(project)
+	for _, service := range project.Services {
+		if isEnabled(service, active) {
+			enabled = append(enabled, service)
+		} else {
+			disabled = append(disabled, service)
+		}
+	}
+	project.Services = enabled
+	setDisabledServices(project, disabled)
+	return checkEnabledDependencies(project)
+}
+
+// EnableServices enables the named services, even if none of their profiles is active, as targeting a service
+// activates its profiles. Their dependencies aren't enabled, a dependency on a disabled service being an error
+func EnableServices(project *types.Project, names []string) error {
+	var disabled types.Services
+	for _, service := range disabledServices(project) {
+		if contains(names, service.Name) {
+			project.Services = append(project.Services, service)
+		} else {
+			disabled = append(disabled, service)
+		}
+	}
+	setDisabledServices(project, disabled)
+	return checkEnabledDependencies(project)
+}
+
+func isEnabled(service types.ServiceConfig, active map[string]bool) bool {
+	profiles := serviceProfiles(service)
+	if len(profiles) == 0 || active[allProfiles] {
+		return true
+	}
+	for _, profile := range profiles {
+		if active[profile] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkEnabledDependencies checks enabled services don't depend on disabled ones
+func checkEnabledDependencies(project *types.Project) error {
+	for _, service := range project.Services {
+		for _, dependency := range service.GetDependencies() {
+			for _, disabled := range disabledServices(project) {
+				if disabled.Name == dependency {
+					return fmt.Errorf("service %q depends on service %q, which is disabled: activate one of its profiles: %s",
+						service.Name, dependency, strings.Join(serviceProfiles(disabled), ", "))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func disabledServices(project *types.Project) types.Services {
+	services, _ := project.Extensions[extDisabledServices].(types.Services)
+	return services
+}
+
+func setDisabledServices(project *types.Project, services types.Services) {
+	if len(services) == 0 {
+		delete(project.Extensions, extDisabledServices)
+		return
+	}
+	if project.Extensions == nil {
+		project.Extensions = map[string]interface{}{}
+	}
+	project.Extensions[extDisabledServices] = services
+}
+
+// isDisabledService tells if a service is declared by project, but disabled as none of its profiles is active
+func isDisabledService(project *types.Project, name string) bool {
+	for _, service := range disabledServices(project) {
+		if service.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func profilesProject() *types.Project {
+	withProfiles := func(profiles ...interface{}) map[string]interface{} {
+		return map[string]interface{}{extProfiles: profiles}
+	}
+	return &types.Project{
+		Services: types.Services{
+			{Name: "web", Image: "nginx"},
+			{Name: "debug", Image: "busybox", Extensions: withProfiles("debug")},
+			{
+				Name:       "adminer",
+				Image:      "adminer",
+				DependsOn:  types.DependsOnConfig{"db": {Condition: types.ServiceConditionStarted}},
+				Extensions: withProfiles("debug", "tools"),
+			},
+			{Name: "db", Image: "postgres", Extensions: withProfiles("db")},
+		},
+	}
+}
+
+func TestProjectProfiles(t *testing.T) {
+	project := profilesProject()
+	assert.DeepEqual(t, ProjectProfiles(project), []string{"db", "debug", "tools"})
+
+	assert.NilError(t, ApplyProfiles(project, nil))
+	assert.DeepEqual(t, ProjectProfiles(project), []string{"db", "debug", "tools"})
+}
+
+func TestApplyProfiles(t *testing.T) {
+	project := profilesProject()
+	assert.NilError(t, ApplyProfiles(project, nil))
+	assert.DeepEqual(t, enabledServices(project), []string{"web"})
+	assert.Equal(t, len(DeclaredServices(project)), 4)
+
+	project = profilesProject()
+	assert.NilError(t, ApplyProfiles(project, []string{"db"}))
+	assert.DeepEqual(t, enabledServices(project), []string{"db", "web"})
+
+	project = profilesProject()
+	assert.NilError(t, ApplyProfiles(project, []string{"*"}))
+	assert.DeepEqual(t, enabledServices(project), []string{"adminer", "db", "debug", "web"})
+
+	project = profilesProject()
+	err := ApplyProfiles(project, []string{"tools"})
+	assert.Error(t, err, `service "adminer" depends on service "db", which is disabled: activate one of its profiles: db`)
+
+	project = profilesProject()
+	assert.NilError(t, ApplyProfiles(project, []string{"tools", "db"}))
+	assert.DeepEqual(t, enabledServices(project), []string{"adminer", "db", "web"})
+}
+
+func TestApplyUnknownProfile(t *testing.T) {
+	project := profilesProject()
+	err := ApplyProfiles(project, []string{"bogus"})
+	assert.Error(t, err, `no such profile "bogus", valid profiles are: db, debug, tools`)
+
+	project = &types.Project{Services: types.Services{{Name: "web", Image: "nginx"}}}
+	err = ApplyProfiles(project, []string{"bogus"})
+	assert.Error(t, err, `no such profile "bogus", project doesn't declare any`)
+}
+
+func TestEnableServices(t *testing.T) {
+	project := profilesProject()
+	assert.NilError(t, ApplyProfiles(project, nil))
+
+	assert.NilError(t, EnableServices(project, []string{"debug"}))
+	assert.DeepEqual(t, enabledServices(project), []string{"debug", "web"})
+
+	err := EnableServices(project, []string{"adminer"})
+	assert.Error(t, err, `service "adminer" depends on service "db", which is disabled: activate one of its profiles: db`)
+}
+
+func TestConvertSkipsDisabledServices(t *testing.T) {
+	project := profilesProject()
+	assert.NilError(t, ApplyProfiles(project, nil))
+
+	s := composeService{}
+	yaml, err := s.Convert(context.Background(), project, compose.ConvertOptions{Format: "yaml"})
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(yaml), "debug"), string(yaml))
+	_, ok := project.Extensions[extDisabledServices].(types.Services)
+	assert.Assert(t, ok)
+}
+
+func enabledServices(project *types.Project) []string {
+	names := project.ServiceNames()
+	sort.Strings(names)
+	return names
+}
@@ -20,16 +20,21 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/docker/compose-cli/api/compose"
+
+	"github.com/compose-spec/compose-go/types"
 	moby "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
 )
 
 func (s *composeService) Ps(ctx context.Context, projectName string) ([]compose.ContainerSummary, error) {
 	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
 		Filters: filters.NewArgs(
 			projectFilter(projectName),
+			filters.Arg("label", fmt.Sprintf("%s=%s", oneoffLabel(), "False")),
 		),
 	})
 	if err != nil {
@@ -38,30 +43,151 @@ func (s *composeService) Ps(ctx context.Context, projectName string) ([]compose.
 
 	var summary []compose.ContainerSummary
 	for _, c := range containers {
-		var publishers []compose.PortPublisher
-		for _, p := range c.Ports {
-			var url string
-			if p.PublicPort != 0 {
-				url = fmt.Sprintf("%s:%d", p.IP, p.PublicPort)
-			}
-			publishers = append(publishers, compose.PortPublisher{
-				URL:           url,
-				TargetPort:    int(p.PrivatePort),
-				PublishedPort: int(p.PublicPort),
-				Protocol:      p.Type,
-			})
-		}
+		summary = append(summary, toContainerSummary(c))
+	}
+	return summary, nil
+}
 
-		summary = append(summary, compose.ContainerSummary{
-			ID:         c.ID,
-			Name:       getContainerName(c),
-			Project:    c.Labels[projectLabel],
-			Service:    c.Labels[serviceLabel],
-			State:      c.State,
-			Publishers: publishers,
+func toContainerSummary(c moby.Container) compose.ContainerSummary {
+	var publishers []compose.PortPublisher
+	for _, p := range c.Ports {
+		var url string
+		if p.PublicPort != 0 {
+			url = fmt.Sprintf("%s:%d", p.IP, p.PublicPort)
+		}
+		publishers = append(publishers, compose.PortPublisher{
+			URL:           url,
+			TargetPort:    int(p.PrivatePort),
+			PublishedPort: int(p.PublicPort),
+			Protocol:      p.Type,
 		})
 	}
-	return summary, nil
+
+	return compose.ContainerSummary{
+		ID:         c.ID,
+		Name:       getContainerName(c),
+		Project:    c.Labels[projectLabel()],
+		Service:    c.Labels[serviceLabel()],
+		State:      c.State,
+		Command:    c.Command,
+		Health:     getHealthFromStatus(c.Status),
+		Publishers: publishers,
+	}
+}
+
+// getHealthFromStatus extracts the healthcheck status engine appends to the human readable container status, as in
+// `Up 5 minutes (healthy)`
+func getHealthFromStatus(status string) string {
+	switch {
+	case strings.HasSuffix(status, "(healthy)"):
+		return moby.Healthy
+	case strings.HasSuffix(status, "(unhealthy)"):
+		return moby.Unhealthy
+	case strings.HasSuffix(status, "(health: starting)"):
+		return moby.Starting
+	default:
+		return ""
+	}
+}
+
+func (s *composeService) Divergences(ctx context.Context, project *types.Project) ([]compose.ContainerDivergence, error) {
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: filters.NewArgs(
+			projectFilter(project.Name),
+		),
+		All: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imageIDs, err := s.serviceImageIDs(ctx, project, containers)
+	if err != nil {
+		return nil, err
+	}
+	imageVolumes, err := s.resolveImageVolumes(ctx, project, true)
+	if err != nil {
+		return nil, err
+	}
+	setImageVolumes(project, imageVolumes)
+	dropHealthStartInterval(s.apiClient.ClientVersion(), project.Services)
+	var divergences []compose.ContainerDivergence
+	for _, c := range containers {
+		divergence, diverged, err := diagnoseContainer(project, c, imageIDs[c.Labels[serviceLabel()]])
+		if err != nil {
+			return nil, err
+		}
+		if diverged {
+			divergences = append(divergences, divergence)
+		}
+	}
+	return divergences, nil
+}
+
+// diagnoseContainer compares a container with the compose model, using the same config hash `up` relies on to
+// recreate containers. imageID is the current ID of the service image, if known
+func diagnoseContainer(project *types.Project, c moby.Container, imageID string) (compose.ContainerDivergence, bool, error) {
+	divergence := compose.ContainerDivergence{
+		ContainerSummary: toContainerSummary(c),
+	}
+	service, err := project.GetService(c.Labels[serviceLabel()])
+	if err != nil && isDisabledService(project, c.Labels[serviceLabel()]) {
+		// up leaves containers of services disabled by profiles alone
+		return divergence, false, nil
+	}
+	if err != nil {
+		divergence.Reason = "orphan, service is not defined in compose file"
+		divergence.Action = "none, use down to remove it"
+		return divergence, true, nil
+	}
+	expected, err := serviceHash(service, c.ImageID)
+	if err != nil {
+		return divergence, false, err
+	}
+	if c.Labels[configHashLabel()] != expected {
+		divergence.Reason = "service configuration changed"
+		divergence.Action = "recreate"
+		return divergence, true, nil
+	}
+	if imageMismatch(c, imageID) {
+		divergence.Reason = "image tag points to a newer image"
+		divergence.Action = "none, use --force-recreate to recreate"
+		return divergence, true, nil
+	}
+	return divergence, false, nil
+}
+
+// serviceImageIDs resolves the ID of the image currently tagged for the services containers belong to, indexed by
+// service name. Services whose image doesn't exist locally, or which aren't defined anymore, are missing
+func (s *composeService) serviceImageIDs(ctx context.Context, project *types.Project, containers []moby.Container) (map[string]string, error) {
+	byImage := map[string]string{}
+	ids := map[string]string{}
+	for _, c := range containers {
+		service, err := project.GetService(c.Labels[serviceLabel()])
+		if err != nil {
+			continue
+		}
+		image := getImageName(service, project)
+		id, ok := byImage[image]
+		if !ok {
+			inspect, _, err := s.apiClient.ImageInspectWithRaw(ctx, image)
+			if err != nil && !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+			id = inspect.ID
+			byImage[image] = id
+		}
+		if id != "" {
+			ids[service.Name] = id
+		}
+	}
+	return ids, nil
+}
+
+// imageMismatch checks a container doesn't run the image currently tagged for its service, typically because the
+// image was rebuilt or pulled since container was created. imageID is empty if not known
+func imageMismatch(c moby.Container, imageID string) bool {
+	return imageID != "" && c.ImageID != imageID
 }
 
 func groupContainerByLabel(containers []moby.Container, labelName string) (map[string][]moby.Container, []string, error) {
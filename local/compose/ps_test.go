@@ -0,0 +1,120 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+)
+
+func TestPsExcludesOneOffContainers(t *testing.T) {
+	var filter filters.Args
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		filter, err = filters.FromJSON(r.URL.Query().Get("filters"))
+		assert.NilError(t, err)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(server.URL, "http://")), client.WithVersion("1.41"))
+	assert.NilError(t, err)
+
+	s := composeService{apiClient: apiClient}
+	_, err = s.Ps(context.Background(), "test")
+	assert.NilError(t, err)
+	assert.Assert(t, filter.ExactMatch("label", projectLabel()+"=test"))
+	assert.Assert(t, filter.ExactMatch("label", oneoffLabel()+"=False"))
+}
+
+func TestDiagnoseContainer(t *testing.T) {
+	web := types.ServiceConfig{Name: "web", Image: "nginx"}
+	project := &types.Project{
+		Name:     "test",
+		Services: types.Services{web},
+	}
+	hash, err := serviceHash(web, "sha256:old")
+	assert.NilError(t, err)
+
+	container := func(service string, hash string) moby.Container {
+		return moby.Container{
+			ID:      "123",
+			Names:   []string{"/test_" + service + "_1"},
+			ImageID: "sha256:old",
+			State:   "running",
+			Labels: map[string]string{
+				projectLabel():    "test",
+				serviceLabel():    service,
+				configHashLabel(): hash,
+			},
+		}
+	}
+
+	_, diverged, err := diagnoseContainer(project, container("web", hash), "sha256:old")
+	assert.NilError(t, err)
+	assert.Assert(t, !diverged)
+
+	_, diverged, err = diagnoseContainer(project, container("web", hash), "")
+	assert.NilError(t, err)
+	assert.Assert(t, !diverged)
+
+	d, diverged, err := diagnoseContainer(project, container("db", hash), "")
+	assert.NilError(t, err)
+	assert.Assert(t, diverged)
+	assert.Equal(t, d.Name, "test_db_1")
+	assert.Equal(t, d.Reason, "orphan, service is not defined in compose file")
+	assert.Equal(t, d.Action, "none, use down to remove it")
+
+	setDisabledServices(project, types.Services{{Name: "db", Image: "postgres"}})
+	_, diverged, err = diagnoseContainer(project, container("db", hash), "")
+	assert.NilError(t, err)
+	assert.Assert(t, !diverged)
+
+	d, diverged, err = diagnoseContainer(project, container("web", "stale"), "sha256:old")
+	assert.NilError(t, err)
+	assert.Assert(t, diverged)
+	assert.Equal(t, d.Reason, "service configuration changed")
+	assert.Equal(t, d.Action, "recreate")
+
+	d, diverged, err = diagnoseContainer(project, container("web", hash), "sha256:new")
+	assert.NilError(t, err)
+	assert.Assert(t, diverged)
+	assert.Equal(t, d.Reason, "image tag points to a newer image")
+	assert.Equal(t, d.Action, "none, use --force-recreate to recreate")
+}
+
+func TestGetHealthFromStatus(t *testing.T) {
+	assert.Equal(t, getHealthFromStatus("Up 5 minutes (healthy)"), moby.Healthy)
+	assert.Equal(t, getHealthFromStatus("Up 5 minutes (unhealthy)"), moby.Unhealthy)
+	assert.Equal(t, getHealthFromStatus("Up 2 seconds (health: starting)"), moby.Starting)
+	assert.Equal(t, getHealthFromStatus("Up 5 minutes"), "")
+}
+
+func TestImageMismatch(t *testing.T) {
+	container := moby.Container{ImageID: "sha256:old"}
+	assert.Assert(t, !imageMismatch(container, "sha256:old"))
+	assert.Assert(t, !imageMismatch(container, ""))
+	assert.Assert(t, imageMismatch(container, "sha256:new"))
+}
@@ -21,29 +21,53 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 
 	"github.com/compose-spec/compose-go/types"
 	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/distribution/reference"
 	moby "github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/registry"
+	"github.com/docker/go-units"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/config"
 	"github.com/docker/compose-cli/progress"
 )
 
-func (s *composeService) Pull(ctx context.Context, project *types.Project) error {
+func (s *composeService) Pull(ctx context.Context, project *types.Project, opts compose.PullOptions) (compose.TransferSummary, error) {
+	results := newTransferResults()
+	pullable := *project
+	pullable.Services = nil
+	for _, service := range project.Services {
+		switch {
+		case service.Image == "":
+			results.skip(service.Name, "no image to pull")
+		case opts.IgnoreBuildable && service.Build != nil:
+			results.skip(service.Name, "image gets built")
+		default:
+			pullable.Services = append(pullable.Services, service)
+		}
+	}
+
+	if opts.VerifySignatures {
+		err := s.pullTrustedImages(ctx, &pullable, notaryVerifier{}, opts.Verbose)
+		results.done(err, pullable.ServiceNames()...)
+		return results.result("pull", false)
+	}
+
 	configFile, err := cliconfig.Load(config.Dir(ctx))
 	if err != nil {
-		return err
+		return compose.TransferSummary{}, err
 	}
 	info, err := s.apiClient.Info(ctx)
 	if err != nil {
-		return err
+		return compose.TransferSummary{}, err
 	}
 
 	if info.IndexServerAddress == "" {
@@ -51,77 +75,143 @@ func (s *composeService) Pull(ctx context.Context, project *types.Project) error
 	}
 
 	w := progress.ContextWriter(ctx)
-	eg, ctx := errgroup.WithContext(ctx)
+	// a failed pull doesn't interrupt the others, so that summary reports all services
+	var eg errgroup.Group
+
+	// pull each image once, even if used by multiple services
+	images := map[string][]string{}
+	var order []string
+	for _, service := range pullable.Services {
+		if _, ok := images[service.Image]; !ok {
+			order = append(order, service.Image)
+		}
+		images[service.Image] = append(images[service.Image], service.Name)
+	}
 
-	for _, srv := range project.Services {
-		service := srv
+	for _, img := range order {
+		image := img
+		services := images[image]
 		eg.Go(func() error {
-			w.Event(progress.Event{
-				ID:     service.Name,
-				Status: progress.Working,
-				Text:   "Pulling",
-			})
-			ref, err := reference.ParseNormalizedNamed(service.Image)
-			if err != nil {
-				return err
+			for _, service := range services {
+				w.Event(progress.Event{
+					ID:     service,
+					Status: progress.Working,
+					Text:   "Pulling",
+					Kind:   progress.ImageKind,
+					Name:   image,
+				})
 			}
-
-			repoInfo, err := registry.ParseRepositoryInfo(ref)
+			err := s.pullServiceImage(ctx, services, image, configFile, info.IndexServerAddress, w, opts.Verbose)
 			if err != nil {
-				return err
+				for _, service := range services {
+					w.Event(progress.ErrorEvent(service).WithResource(progress.ImageKind, image))
+				}
 			}
+			results.done(err, services...)
+			return nil
+		})
+	}
 
-			key := repoInfo.Index.Name
-			if repoInfo.Index.Official {
-				key = info.IndexServerAddress
-			}
+	_ = eg.Wait()
+	return results.result("pull", false)
+}
 
-			authConfig, err := configFile.GetAuthConfig(key)
-			if err != nil {
-				return err
-			}
+func (s *composeService) pullServiceImage(ctx context.Context, services []string, image string, configFile *configfile.ConfigFile, indexServer string, w progress.Writer, verbose bool) error {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+	auth, err := encodedAuth(ref, configFile, indexServer)
+	if err != nil {
+		return err
+	}
+	return s.pullImage(ctx, services, image, auth, w, verbose)
+}
 
-			buf, err := json.Marshal(authConfig)
-			if err != nil {
-				return err
-			}
+// pullImage pulls image, reporting aggregated progress for all services using it. Layers progress is only reported in verbose mode
+func (s *composeService) pullImage(ctx context.Context, services []string, image string, auth string, w progress.Writer, verbose bool) error {
+	stream, err := s.apiClient.ImagePull(ctx, image, moby.ImagePullOptions{
+		RegistryAuth: auth,
+	})
+	if err != nil {
+		return err
+	}
 
-			stream, err := s.apiClient.ImagePull(ctx, service.Image, moby.ImagePullOptions{
-				RegistryAuth: base64.URLEncoding.EncodeToString(buf),
-			})
-			if err != nil {
+	pp := newPullProgress()
+	dec := json.NewDecoder(stream)
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if jm.Error != nil {
+			return errors.New(jm.Error.Message)
+		}
+		if verbose {
+			toPullProgressEvent(services[0], jm, w)
+		}
+		if pp.update(jm) {
+			for _, service := range services {
 				w.Event(progress.Event{
-					ID:     service.Name,
-					Status: progress.Error,
-					Text:   "Error",
+					ID:         service,
+					Status:     progress.Working,
+					Text:       "Pulling",
+					StatusText: pp.String(),
+					Kind:       progress.ImageKind,
+					Name:       image,
 				})
-				return err
-			}
-
-			dec := json.NewDecoder(stream)
-			for {
-				var jm jsonmessage.JSONMessage
-				if err := dec.Decode(&jm); err != nil {
-					if err == io.EOF {
-						break
-					}
-					return err
-				}
-				if jm.Error != nil {
-					return errors.New(jm.Error.Message)
-				}
-				toPullProgressEvent(service.Name, jm, w)
 			}
-			w.Event(progress.Event{
-				ID:     service.Name,
-				Status: progress.Done,
-				Text:   "Pulled",
-			})
-			return nil
+		}
+	}
+	for _, service := range services {
+		w.Event(progress.Event{
+			ID:     service,
+			Status: progress.Done,
+			Text:   "Pulled",
+			Kind:   progress.ImageKind,
+			Name:   image,
 		})
 	}
+	return nil
+}
+
+// resolveAuthConfig resolves credentials to access repository for ref, relying on credential helpers if configured
+func resolveAuthConfig(ref reference.Named, configFile *configfile.ConfigFile, indexServer string) (moby.AuthConfig, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return moby.AuthConfig{}, err
+	}
 
-	return eg.Wait()
+	key := repoInfo.Index.Name
+	if repoInfo.Index.Official {
+		key = indexServer
+	}
+
+	authConfig, err := configFile.GetAuthConfig(key)
+	if err != nil {
+		return moby.AuthConfig{}, err
+	}
+	return moby.AuthConfig(authConfig), nil
+}
+
+// encodedAuth resolves credentials to access repository for ref, encoded for the engine API
+func encodedAuth(ref reference.Named, configFile *configfile.ConfigFile, indexServer string) (string, error) {
+	authConfig, err := resolveAuthConfig(ref, configFile, indexServer)
+	if err != nil {
+		return "", err
+	}
+	return encodeAuthConfig(authConfig)
+}
+
+func encodeAuthConfig(authConfig moby.AuthConfig) (string, error) {
+	buf, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(buf), nil
 }
 
 func toPullProgressEvent(parent string, jm jsonmessage.JSONMessage, w progress.Writer) {
@@ -156,3 +246,78 @@ func toPullProgressEvent(parent string, jm jsonmessage.JSONMessage, w progress.W
 		StatusText: text,
 	})
 }
+
+const progressBarWidth = 20
+
+type layerProgress struct {
+	current int64
+	total   int64
+	done    bool
+}
+
+// pullProgress aggregates layers progress of an image pull into a single progress bar
+type pullProgress struct {
+	layers map[string]*layerProgress
+	order  []string
+}
+
+func newPullProgress() *pullProgress {
+	return &pullProgress{
+		layers: map[string]*layerProgress{},
+	}
+}
+
+// update records progress for a layer from a pull message, and reports aggregated progress changed
+func (p *pullProgress) update(jm jsonmessage.JSONMessage) bool {
+	if jm.ID == "" || strings.HasPrefix(jm.Status, "Pulling from") {
+		return false
+	}
+	layer, ok := p.layers[jm.ID]
+	if !ok {
+		layer = &layerProgress{}
+		p.layers[jm.ID] = layer
+		p.order = append(p.order, jm.ID)
+	}
+	switch jm.Status {
+	case "Downloading":
+		if jm.Progress == nil {
+			return false
+		}
+		layer.current = jm.Progress.Current
+		layer.total = jm.Progress.Total
+	case "Download complete", "Pull complete", "Already exists":
+		if layer.done {
+			return false
+		}
+		layer.done = true
+		layer.current = layer.total
+	default:
+		return !ok
+	}
+	return true
+}
+
+func (p *pullProgress) String() string {
+	var current, total int64
+	var done int
+	for _, id := range p.order {
+		layer := p.layers[id]
+		current += layer.current
+		total += layer.total
+		if layer.done {
+			done++
+		}
+	}
+	if total == 0 {
+		return fmt.Sprintf("%d/%d layers", done, len(p.order))
+	}
+	filled := int(current * progressBarWidth / total)
+	if filled > progressBarWidth {
+		filled = progressBarWidth
+	}
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	return fmt.Sprintf("[%s] %s/%s", bar, units.HumanSize(float64(current)), units.HumanSize(float64(total)))
+}
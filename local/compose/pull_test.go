@@ -0,0 +1,46 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"gotest.tools/v3/assert"
+)
+
+func TestPullProgress(t *testing.T) {
+	p := newPullProgress()
+	assert.Assert(t, !p.update(jsonmessage.JSONMessage{ID: "latest", Status: "Pulling from library/nginx"}))
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "aaa", Status: "Pulling fs layer"}))
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "bbb", Status: "Pulling fs layer"}))
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "aaa", Status: "Downloading", Progress: &jsonmessage.JSONProgress{Current: 1000, Total: 4000}}))
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "bbb", Status: "Downloading", Progress: &jsonmessage.JSONProgress{Current: 0, Total: 4000}}))
+	assert.Equal(t, p.String(), "[==>                 ] 1kB/8kB")
+
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "aaa", Status: "Download complete"}))
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "bbb", Status: "Download complete"}))
+	assert.Assert(t, !p.update(jsonmessage.JSONMessage{ID: "bbb", Status: "Pull complete"}))
+	assert.Equal(t, p.String(), "[====================] 8kB/8kB")
+}
+
+func TestPullProgressSharedLayers(t *testing.T) {
+	p := newPullProgress()
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "aaa", Status: "Already exists"}))
+	assert.Assert(t, p.update(jsonmessage.JSONMessage{ID: "bbb", Status: "Already exists"}))
+	assert.Equal(t, p.String(), "2/2 layers")
+}
@@ -23,11 +23,13 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/docker/compose-cli/api/compose"
 	"github.com/docker/compose-cli/config"
 	"github.com/docker/compose-cli/progress"
 
 	"github.com/compose-spec/compose-go/types"
 	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/distribution/reference"
 	moby "github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/jsonmessage"
@@ -36,77 +38,92 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-func (s *composeService) Push(ctx context.Context, project *types.Project) error {
+func (s *composeService) Push(ctx context.Context, project *types.Project, opts compose.PushOptions) (compose.TransferSummary, error) {
 	configFile, err := cliconfig.Load(config.Dir(ctx))
 	if err != nil {
-		return err
+		return compose.TransferSummary{}, err
 	}
-	eg, ctx := errgroup.WithContext(ctx)
-
 	info, err := s.apiClient.Info(ctx)
 	if err != nil {
-		return err
+		return compose.TransferSummary{}, err
 	}
 	if info.IndexServerAddress == "" {
 		info.IndexServerAddress = registry.IndexServer
 	}
 
+	results := newTransferResults()
+	// a failed push doesn't interrupt the others, so that summary reports all services
+	var eg errgroup.Group
 	for _, service := range project.Services {
-		if service.Build == nil {
+		switch {
+		case service.Build == nil:
+			results.skip(service.Name, "no build section")
+			continue
+		case service.Image == "":
+			results.skip(service.Name, "no image name to push to")
 			continue
 		}
 		service := service
 		eg.Go(func() error {
 			w := progress.ContextWriter(ctx)
-
-			ref, err := reference.ParseNormalizedNamed(service.Image)
+			err := s.pushServiceImage(ctx, service, configFile, info.IndexServerAddress, w)
 			if err != nil {
-				return err
+				w.Event(progress.ErrorEvent("Pushing " + service.Name))
 			}
+			results.done(err, service.Name)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	return results.result("push", opts.IgnoreFailures)
+}
 
-			repoInfo, err := registry.ParseRepositoryInfo(ref)
-			if err != nil {
-				return err
-			}
+func (s *composeService) pushServiceImage(ctx context.Context, service types.ServiceConfig, configFile *configfile.ConfigFile, indexServer string, w progress.Writer) error {
+	ref, err := reference.ParseNormalizedNamed(service.Image)
+	if err != nil {
+		return err
+	}
 
-			key := repoInfo.Index.Name
-			if repoInfo.Index.Official {
-				key = info.IndexServerAddress
-			}
-			authConfig, err := configFile.GetAuthConfig(key)
-			if err != nil {
-				return err
-			}
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return err
+	}
 
-			buf, err := json.Marshal(authConfig)
-			if err != nil {
-				return err
-			}
+	key := repoInfo.Index.Name
+	if repoInfo.Index.Official {
+		key = indexServer
+	}
+	authConfig, err := configFile.GetAuthConfig(key)
+	if err != nil {
+		return err
+	}
 
-			stream, err := s.apiClient.ImagePush(ctx, service.Image, moby.ImagePushOptions{
-				RegistryAuth: base64.URLEncoding.EncodeToString(buf),
-			})
-			if err != nil {
-				return err
-			}
-			dec := json.NewDecoder(stream)
-			for {
-				var jm jsonmessage.JSONMessage
-				if err := dec.Decode(&jm); err != nil {
-					if err == io.EOF {
-						break
-					}
-					return err
-				}
-				if jm.Error != nil {
-					return errors.New(jm.Error.Message)
-				}
-				toPushProgressEvent("Pushing "+service.Name, jm, w)
+	buf, err := json.Marshal(authConfig)
+	if err != nil {
+		return err
+	}
+
+	stream, err := s.apiClient.ImagePush(ctx, service.Image, moby.ImagePushOptions{
+		RegistryAuth: base64.URLEncoding.EncodeToString(buf),
+	})
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(stream)
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if err == io.EOF {
+				break
 			}
-			return nil
-		})
+			return err
+		}
+		if jm.Error != nil {
+			return errors.New(jm.Error.Message)
+		}
+		toPushProgressEvent("Pushing "+service.Name, jm, w)
 	}
-	return eg.Wait()
+	return nil
 }
 
 func toPushProgressEvent(prefix string, jm jsonmessage.JSONMessage, w progress.Writer) {
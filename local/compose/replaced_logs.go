@@ -0,0 +1,175 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	moby "github.com/docker/docker/api/types"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// replacedLogsDir is where the logs of containers replaced by a recreation are saved, one directory per project. Only
+// the logs of the last container a replica replaced are kept
+func replacedLogsDir(projectName string) string {
+	return filepath.Join(os.TempDir(), "compose-logs", projectName)
+}
+
+// replacedLogsHeader is the first line of a replaced container logs file, followed by one replacedLogLine per line
+type replacedLogsHeader struct {
+	Container string `json:"container"`
+	Service   string `json:"service"`
+	Replica   string `json:"replica"`
+}
+
+type replacedLogLine struct {
+	Stream string `json:"stream"`
+	Line   string `json:"line"`
+}
+
+// saveReplacedLogs saves the logs of a container about to be removed by a recreation, so that logs still shows them
+func (s *composeService) saveReplacedLogs(ctx context.Context, projectName string, container moby.Container) error {
+	inspect, err := s.apiClient.ContainerInspect(ctx, container.ID)
+	if err != nil {
+		return err
+	}
+	r, err := s.apiClient.ContainerLogs(ctx, container.ID, moby.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer r.Close() // nolint errcheck
+
+	dir := replacedLogsDir(projectName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	header := replacedLogsHeader{
+		Container: container.ID,
+		Service:   container.Labels[serviceLabel()],
+		Replica:   replicaName(container.Labels),
+	}
+	return writeReplacedLogs(filepath.Join(dir, header.Replica+".log"), header, r, inspect.Config != nil && inspect.Config.Tty)
+}
+
+// writeReplacedLogs writes container output to file, replacing the logs of the container replica previously replaced
+func writeReplacedLogs(file string, header replacedLogsHeader, r io.Reader, tty bool) error {
+	tmp := file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(header)
+	if err == nil {
+		err = copyLogs(r, tty, header.Service, header.Container, replacedLogsWriter{encoder: encoder})
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// replacedLogsWriter is a StreamLogConsumer encoding each line it gets as a replacedLogLine
+type replacedLogsWriter struct {
+	encoder *json.Encoder
+}
+
+func (w replacedLogsWriter) Log(service, container, message string) {
+	w.LogStream(service, container, compose.StdoutStream, message)
+}
+
+func (w replacedLogsWriter) LogStream(service, container, stream, message string) {
+	_ = w.encoder.Encode(replacedLogLine{Stream: stream, Line: message})
+}
+
+// replacedLogs reads the saved logs of the containers replaced for services, all services if none is set
+func replacedLogs(projectName string, services []string) ([]replacedLogsHeader, map[string][]replacedLogLine, error) {
+	files, err := filepath.Glob(filepath.Join(replacedLogsDir(projectName), "*.log"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(files)
+	var headers []replacedLogsHeader
+	lines := map[string][]replacedLogLine{}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		var header replacedLogsHeader
+		if err := decoder.Decode(&header); err != nil {
+			return nil, nil, err
+		}
+		if len(services) > 0 && !contains(services, header.Service) {
+			continue
+		}
+		headers = append(headers, header)
+		for decoder.More() {
+			var line replacedLogLine
+			if err := decoder.Decode(&line); err != nil {
+				return nil, nil, err
+			}
+			lines[header.Container] = append(lines[header.Container], line)
+		}
+	}
+	return headers, lines, nil
+}
+
+// tailLines keeps the last lines tail asks for, all of them unless tail is a number
+func tailLines(lines []replacedLogLine, tail string) []replacedLogLine {
+	n, err := strconv.Atoi(tail)
+	if err != nil || n < 0 || n >= len(lines) {
+		return lines
+	}
+	return lines[len(lines)-n:]
+}
+
+// logReplacedLine passes a saved line to consumer, with the stream it was written to if consumer supports it
+func logReplacedLine(consumer compose.LogConsumer, header replacedLogsHeader, line replacedLogLine) {
+	if sl, ok := consumer.(compose.StreamLogConsumer); ok {
+		sl.LogStream(header.Service, header.Container, line.Stream, line.Line)
+		return
+	}
+	consumer.Log(header.Service, header.Container, line.Line)
+}
+
+// removeReplacedLogs removes the saved logs of project replaced containers once its containers are gone
+func removeReplacedLogs(projectName string) error {
+	return os.RemoveAll(replacedLogsDir(projectName))
+}
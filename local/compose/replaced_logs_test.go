@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestReplacedLogs(t *testing.T) {
+	projectName := "replaced-logs-" + strings.ToLower(filepath.Base(t.TempDir()))
+	dir := replacedLogsDir(projectName)
+	assert.NilError(t, os.MkdirAll(dir, 0700))
+	defer removeReplacedLogs(projectName) //nolint:errcheck
+
+	var output bytes.Buffer
+	_, err := stdcopy.NewStdWriter(&output, stdcopy.Stdout).Write([]byte("starting\nlistening\n"))
+	assert.NilError(t, err)
+	_, err = stdcopy.NewStdWriter(&output, stdcopy.Stderr).Write([]byte("out of memory\n"))
+	assert.NilError(t, err)
+	web := replacedLogsHeader{Container: "123", Service: "web", Replica: "web_1"}
+	assert.NilError(t, writeReplacedLogs(filepath.Join(dir, "web_1.log"), web, &output, false))
+	db := replacedLogsHeader{Container: "456", Service: "db", Replica: "db_1"}
+	assert.NilError(t, writeReplacedLogs(filepath.Join(dir, "db_1.log"), db, strings.NewReader("ready\n"), true))
+
+	headers, lines, err := replacedLogs(projectName, []string{"web"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, headers, []replacedLogsHeader{web})
+	assert.DeepEqual(t, lines["123"], []replacedLogLine{
+		{Stream: compose.StdoutStream, Line: "starting"},
+		{Stream: compose.StdoutStream, Line: "listening"},
+		{Stream: compose.StderrStream, Line: "out of memory"},
+	})
+	assert.DeepEqual(t, tailLines(lines["123"], "1"), []replacedLogLine{{Stream: compose.StderrStream, Line: "out of memory"}})
+	assert.Equal(t, len(tailLines(lines["123"], "all")), 3)
+
+	headers, lines, err = replacedLogs(projectName, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, headers, []replacedLogsHeader{db, web})
+	assert.DeepEqual(t, lines["456"], []replacedLogLine{{Stream: compose.StdoutStream, Line: "ready"}})
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+func (s *composeService) RestartFailed(ctx context.Context, projectName string, options compose.RestartFailedOptions) ([]string, error) {
+	project, err := s.projectFromContainerLabels(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	w := progress.ContextWriter(ctx)
+
+	var (
+		mu        sync.Mutex
+		restarted []string
+	)
+	err = InDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
+		containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+			Filters: filters.NewArgs(projectFilter(project.Name), serviceFilter(service.Name)),
+			All:     true,
+		})
+		if err != nil {
+			return err
+		}
+		for _, container := range containers {
+			inspected, err := s.apiClient.ContainerInspect(ctx, container.ID)
+			if err != nil {
+				return err
+			}
+			if !isFailed(inspected) {
+				continue
+			}
+			name := getContainerName(container)
+			if !options.DryRun {
+				eventName := "Container " + name
+				w.Event(progress.NewEvent(eventName, progress.Working, "Restarting").WithResource(progress.ContainerKind, name))
+				err = s.apiClient.ContainerRestart(ctx, container.ID, nil)
+				if err != nil {
+					w.Event(progress.ErrorMessageEvent(eventName, "Error while Restarting").WithResource(progress.ContainerKind, name))
+					return err
+				}
+				w.Event(progress.NewEvent(eventName, progress.Done, "Restarted").WithResource(progress.ContainerKind, name))
+			}
+			mu.Lock()
+			restarted = append(restarted, name)
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(restarted)
+	return restarted, nil
+}
+
+// isFailed checks a container exited with a non-zero code, or is reported unhealthy by its healthcheck
+func isFailed(container moby.ContainerJSON) bool {
+	if container.ContainerJSONBase == nil || container.State == nil {
+		return false
+	}
+	state := container.State
+	if state.Status == "exited" && state.ExitCode != 0 {
+		return true
+	}
+	return state.Health != nil && state.Health.Status == moby.Unhealthy
+}
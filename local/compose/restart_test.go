@@ -0,0 +1,41 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	moby "github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestIsFailed(t *testing.T) {
+	withState := func(state moby.ContainerState) moby.ContainerJSON {
+		return moby.ContainerJSON{
+			ContainerJSONBase: &moby.ContainerJSONBase{
+				State: &state,
+			},
+		}
+	}
+
+	assert.Assert(t, isFailed(withState(moby.ContainerState{Status: "exited", ExitCode: 1})))
+	assert.Assert(t, !isFailed(withState(moby.ContainerState{Status: "exited", ExitCode: 0})))
+	assert.Assert(t, !isFailed(withState(moby.ContainerState{Status: "running"})))
+	assert.Assert(t, isFailed(withState(moby.ContainerState{Status: "running", Health: &moby.Health{Status: moby.Unhealthy}})))
+	assert.Assert(t, !isFailed(withState(moby.ContainerState{Status: "running", Health: &moby.Health{Status: moby.Healthy}})))
+	assert.Assert(t, !isFailed(moby.ContainerJSON{}))
+}
@@ -0,0 +1,267 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+	convert "github.com/docker/compose-cli/local/moby"
+)
+
+func (s *composeService) RunOneOffContainer(ctx context.Context, project *types.Project, opts compose.RunOptions) (int, error) {
+	service, err := project.GetService(opts.Service)
+	if err != nil {
+		return 0, err
+	}
+	service = applyRunOptions(service, opts)
+
+	err = s.checkBindSources(types.Services{service})
+	if err != nil {
+		return 0, err
+	}
+	err = s.ensureImages(ctx, project, imagesOptions{
+		quietPull: opts.QuietPull,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if opts.RemoveOrphans {
+		err = s.removeOrphans(ctx, project)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = s.ensureProjectResources(ctx, project)
+	if err != nil {
+		return 0, err
+	}
+	err = writeEnvironmentSecrets(project)
+	if err != nil {
+		return 0, err
+	}
+
+	containerConfig, hostConfig, networkingConfig, err := getContainerCreateOptions(project, service, 1, nil)
+	if err != nil {
+		return 0, err
+	}
+	containerConfig.Labels[oneoffLabel()] = "True"
+	if err := s.applyVolumesFrom(ctx, project, service, hostConfig); err != nil {
+		return 0, err
+	}
+	for _, warning := range downgradeForAPIVersion(s.apiClient.ClientVersion(), service.Name, containerConfig, hostConfig) {
+		logrus.Warn(warning)
+	}
+	services := types.Services{service}
+	for _, warning := range dropHealthStartInterval(s.apiClient.ClientVersion(), services) {
+		logrus.Warn(warning)
+	}
+	if interval, err := getHealthStartInterval(services[0]); err == nil && interval > 0 {
+		ctx = withHealthStartInterval(ctx, interval)
+	}
+	name := compose.ResourceName(project.Name, service.Name, "run", stringid.TruncateID(stringid.GenerateRandomID()))
+	created, err := s.apiClient.ContainerCreate(ctx, containerConfig, hostConfig, networkingConfig, nil, name)
+	if err != nil {
+		return 0, err
+	}
+	id := created.ID
+	if opts.AutoRemove {
+		defer func() {
+			// user may have canceled ctx, still remove the container
+			s.removeOneOffContainer(context.Background(), id, name, opts.KeepVolumes)
+		}()
+	}
+	networks, err := getNetworksByPriority(service)
+	if err != nil {
+		return 0, err
+	}
+	for _, netName := range networks {
+		if _, ok := service.Networks[netName]; !ok {
+			// service without networks only joins the default network it was created on
+			continue
+		}
+		network := project.Networks[netName]
+		err = s.connectContainerToNetwork(ctx, id, service.Name, network.Name)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	runCtx := ctx
+	if opts.Signals != nil {
+		// caller's ctx get canceled on interrupt, we still have to wait for container to handle forwarded signals
+		runCtx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, opts.Timeout)
+		defer cancel()
+	}
+
+	// attach before container starts so we don't miss early output
+	stdin, stdout, err := s.getContainerStreams(runCtx, moby.Container{ID: id, State: convert.ContainerCreated})
+	if err != nil {
+		return 0, err
+	}
+	if stdin != nil {
+		defer stdin.Close() //nolint:errcheck
+	}
+	attached := make(chan error, 1)
+	go func() {
+		defer stdout.Close() //nolint:errcheck
+		w := opts.Writer
+		if w == nil {
+			w = ioutil.Discard
+		}
+		var err error
+		if service.Tty {
+			_, err = io.Copy(w, stdout)
+		} else {
+			_, err = stdcopy.StdCopy(w, w, stdout)
+		}
+		attached <- err
+	}()
+
+	statusC, errC := s.apiClient.ContainerWait(runCtx, id, container.WaitConditionNextExit)
+	err = s.apiClient.ContainerStart(runCtx, id, moby.ContainerStartOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		select {
+		case sig := <-opts.Signals:
+			err := s.apiClient.ContainerKill(context.Background(), id, signalName(sig))
+			if err != nil {
+				logrus.Warnf("failed to forward signal %s to one-off container %q: %v", sig, name, err)
+			}
+		case status := <-statusC:
+			// wait for logs to be flushed before reporting completion
+			<-attached
+			return int(status.StatusCode), nil
+		case err := <-errC:
+			if runCtx.Err() == context.DeadlineExceeded {
+				err = s.apiClient.ContainerKill(context.Background(), id, "KILL")
+				if err != nil {
+					logrus.Warnf("failed to kill one-off container %q: %v", name, err)
+				}
+				return 0, fmt.Errorf("service %q one-off container did not complete within %s", opts.Service, opts.Timeout)
+			}
+			return 0, err
+		}
+	}
+}
+
+// removeOneOffContainer removes a one-off container with its anonymous volumes, unless keepVolumes is set, in which
+// case the volumes kept are reported
+func (s *composeService) removeOneOffContainer(ctx context.Context, id string, name string, keepVolumes bool) {
+	var anonymous []string
+	if keepVolumes {
+		inspect, err := s.apiClient.ContainerInspect(ctx, id)
+		if err != nil {
+			logrus.Warnf("failed to inspect one-off container %q: %v", name, err)
+		}
+		anonymous = anonymousVolumes(inspect.Mounts)
+	}
+	err := s.apiClient.ContainerRemove(ctx, id, moby.ContainerRemoveOptions{
+		Force:         true,
+		RemoveVolumes: !keepVolumes,
+	})
+	if err != nil {
+		logrus.Warnf("failed to remove one-off container %q: %v", name, err)
+		return
+	}
+	for _, volume := range anonymous {
+		_, _ = fmt.Fprintf(os.Stderr, "Kept anonymous volume %s\n", volume)
+	}
+}
+
+// anonymousVolumes lists the volumes engine named after a random ID when mounting them in a container
+func anonymousVolumes(mounts []moby.MountPoint) []string {
+	var names []string
+	for _, m := range mounts {
+		if m.Type == mount.TypeVolume && stringid.ValidateID(m.Name) == nil {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+// signalName converts a signal received by the CLI into the name expected by the engine API
+func signalName(sig os.Signal) string {
+	switch sig {
+	case syscall.SIGINT:
+		return "SIGINT"
+	case syscall.SIGTERM:
+		return "SIGTERM"
+	}
+	if s, ok := sig.(syscall.Signal); ok {
+		return strconv.Itoa(int(s))
+	}
+	return sig.String()
+}
+
+// applyRunOptions overrides service command, user and working_dir with the ones set for the run, and merges
+// environment, so unset options are inherited from the service
+func applyRunOptions(service types.ServiceConfig, opts compose.RunOptions) types.ServiceConfig {
+	if len(opts.Command) > 0 {
+		service.Command = opts.Command
+	}
+	if opts.User != "" {
+		service.User = opts.User
+	}
+	if opts.WorkingDir != "" {
+		service.WorkingDir = opts.WorkingDir
+	}
+	service.Environment = mergeEnvironment(service.Environment, opts.Environment)
+	return service
+}
+
+// mergeEnvironment overrides service environment with KEY=VALUE entries. A KEY without value is unset
+func mergeEnvironment(environment types.MappingWithEquals, overrides []string) types.MappingWithEquals {
+	if len(overrides) == 0 {
+		return environment
+	}
+	merged := types.MappingWithEquals{}
+	for k, v := range environment {
+		merged[k] = v
+	}
+	for _, env := range overrides {
+		kv := strings.SplitN(env, "=", 2)
+		if len(kv) == 2 {
+			merged[kv[0]] = &kv[1]
+		} else {
+			merged[kv[0]] = nil
+		}
+	}
+	return merged
+}
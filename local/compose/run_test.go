@@ -0,0 +1,135 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestMergeEnvironment(t *testing.T) {
+	foo, bar := "foo", "bar"
+	environment := types.MappingWithEquals{"FOO": &foo, "BAR": &bar}
+
+	merged := mergeEnvironment(environment, []string{"FOO=override", "QIX=", "ZOT"})
+	assert.Equal(t, *merged["FOO"], "override")
+	assert.Equal(t, *merged["BAR"], "bar")
+	assert.Equal(t, *merged["QIX"], "")
+	zot, ok := merged["ZOT"]
+	assert.Assert(t, ok)
+	assert.Assert(t, zot == nil)
+	assert.Equal(t, *environment["FOO"], "foo")
+}
+
+func TestApplyRunOptions(t *testing.T) {
+	foo, bar := "foo", "bar"
+	service := types.ServiceConfig{
+		Name:        "web",
+		Command:     types.ShellCommand{"serve"},
+		User:        "www",
+		WorkingDir:  "/srv",
+		Environment: types.MappingWithEquals{"FOO": &foo},
+	}
+
+	tests := []struct {
+		name        string
+		opts        compose.RunOptions
+		command     types.ShellCommand
+		user        string
+		workingDir  string
+		environment map[string]string
+	}{
+		{
+			name:        "inherited",
+			command:     types.ShellCommand{"serve"},
+			user:        "www",
+			workingDir:  "/srv",
+			environment: map[string]string{"FOO": "foo"},
+		},
+		{
+			name:        "command overridden",
+			opts:        compose.RunOptions{Command: []string{"ls"}},
+			command:     types.ShellCommand{"ls"},
+			user:        "www",
+			workingDir:  "/srv",
+			environment: map[string]string{"FOO": "foo"},
+		},
+		{
+			name:        "user overridden",
+			opts:        compose.RunOptions{User: "root"},
+			command:     types.ShellCommand{"serve"},
+			user:        "root",
+			workingDir:  "/srv",
+			environment: map[string]string{"FOO": "foo"},
+		},
+		{
+			name:        "working dir overridden",
+			opts:        compose.RunOptions{WorkingDir: "/tmp"},
+			command:     types.ShellCommand{"serve"},
+			user:        "www",
+			workingDir:  "/tmp",
+			environment: map[string]string{"FOO": "foo"},
+		},
+		{
+			name:        "environment merged",
+			opts:        compose.RunOptions{Environment: []string{"FOO=override", "BAR=" + bar}},
+			command:     types.ShellCommand{"serve"},
+			user:        "www",
+			workingDir:  "/srv",
+			environment: map[string]string{"FOO": "override", "BAR": "bar"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			applied := applyRunOptions(service, test.opts)
+			assert.DeepEqual(t, applied.Command, test.command)
+			assert.Equal(t, applied.User, test.user)
+			assert.Equal(t, applied.WorkingDir, test.workingDir)
+			environment := map[string]string{}
+			for k, v := range applied.Environment {
+				environment[k] = *v
+			}
+			assert.DeepEqual(t, environment, test.environment)
+		})
+	}
+	assert.Equal(t, service.User, "www")
+	assert.Equal(t, *service.Environment["FOO"], "foo")
+}
+
+func TestSignalName(t *testing.T) {
+	assert.Equal(t, signalName(syscall.SIGINT), "SIGINT")
+	assert.Equal(t, signalName(syscall.SIGTERM), "SIGTERM")
+	assert.Equal(t, signalName(syscall.Signal(10)), "10")
+}
+
+func TestAnonymousVolumes(t *testing.T) {
+	const anonymous = "0f8a2c7b5d1e4f3a9b6c8d7e2f1a0b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a"
+	mounts := []moby.MountPoint{
+		{Type: mount.TypeVolume, Name: anonymous, Destination: "/data"},
+		{Type: mount.TypeVolume, Name: "myproject_db", Destination: "/var/lib/db"},
+		{Type: mount.TypeBind, Source: "/src", Destination: "/src"},
+	}
+	assert.DeepEqual(t, anonymousVolumes(mounts), []string{anonymous})
+	assert.Assert(t, anonymousVolumes(nil) == nil)
+}
@@ -0,0 +1,174 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// secretsDir is where values of environment secrets are written for containers to mount them, one directory per project
+func secretsDir(projectName string) string {
+	return filepath.Join(os.TempDir(), "compose-secrets", projectName)
+}
+
+// SecretEnvironment returns the environment variable a secret gets its value from, if it isn't read from a file
+func SecretEnvironment(secret types.SecretConfig) string {
+	if secret.File != "" {
+		return ""
+	}
+	env, _ := secret.Extensions[extSecretEnvironment].(string)
+	return env
+}
+
+// SetSecretEnvironment makes a top-level secret get its value from an environment variable, declaring it if needed
+func SetSecretEnvironment(project *types.Project, name string, variable string) {
+	secret := project.Secrets[name]
+	extensions := map[string]interface{}{}
+	for k, v := range secret.Extensions {
+		extensions[k] = v
+	}
+	extensions[extSecretEnvironment] = variable
+	secret.Name = name
+	secret.File = ""
+	secret.External = types.External{}
+	secret.Extensions = extensions
+	if project.Secrets == nil {
+		project.Secrets = types.Secrets{}
+	}
+	project.Secrets[name] = secret
+}
+
+// SetSecretFile makes a top-level secret get its value from a file, declaring it if needed
+func SetSecretFile(project *types.Project, name string, file string) {
+	secret := project.Secrets[name]
+	extensions := map[string]interface{}{}
+	for k, v := range secret.Extensions {
+		if k != extSecretEnvironment {
+			extensions[k] = v
+		}
+	}
+	secret.Name = name
+	secret.File = file
+	secret.External = types.External{}
+	secret.Extensions = extensions
+	if project.Secrets == nil {
+		project.Secrets = types.Secrets{}
+	}
+	project.Secrets[name] = secret
+}
+
+// secretMounts lists the read-only bind mounts exposing service secrets in /run/secrets, or at their target if absolute
+func secretMounts(p types.Project, s types.ServiceConfig) ([]mount.Mount, error) {
+	var mounts []mount.Mount
+	for _, ref := range s.Secrets {
+		secret, ok := p.Secrets[ref.Source]
+		if !ok {
+			return nil, fmt.Errorf("service %q: secret %q is not defined in top-level secrets", s.Name, ref.Source)
+		}
+		if secret.External.External {
+			continue
+		}
+		source := secret.File
+		if source == "" {
+			if SecretEnvironment(secret) == "" {
+				return nil, fmt.Errorf("service %q: secret %q must set either file or %s", s.Name, ref.Source, extSecretEnvironment)
+			}
+			source = filepath.Join(secretsDir(p.Name), ref.Source)
+		}
+		target := ref.Target
+		if target == "" {
+			target = ref.Source
+		}
+		if !path.IsAbs(target) {
+			target = path.Join("/run/secrets", target)
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   source,
+			Target:   target,
+			ReadOnly: true,
+		})
+	}
+	return mounts, nil
+}
+
+// externalSecretWarnings reports secrets services use which are external, engine only provides those to swarm services
+func externalSecretWarnings(project *types.Project) []string {
+	var warnings []string
+	for _, service := range project.Services {
+		for _, ref := range service.Secrets {
+			if secret, ok := project.Secrets[ref.Source]; ok && secret.External.External {
+				warnings = append(warnings, fmt.Sprintf("service %q uses secret %q which is external, it isn't available to containers created by local backend", service.Name, ref.Source))
+			}
+		}
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// writeEnvironmentSecrets writes the values of environment secrets services use into secretsDir, only readable by
+// owner. Files are rewritten in place, so running containers see updated values
+func writeEnvironmentSecrets(project *types.Project) error {
+	written := map[string]bool{}
+	for _, service := range project.Services {
+		for _, ref := range service.Secrets {
+			secret, ok := project.Secrets[ref.Source]
+			if !ok || written[ref.Source] {
+				continue
+			}
+			env := SecretEnvironment(secret)
+			if env == "" {
+				continue
+			}
+			value, ok := os.LookupEnv(env)
+			if !ok {
+				return fmt.Errorf("secret %q: environment variable %s is not set", ref.Source, env)
+			}
+			dir := secretsDir(project.Name)
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				return err
+			}
+			file := filepath.Join(dir, ref.Source)
+			if _, err := os.Stat(file); err == nil {
+				if err := os.Chmod(file, 0600); err != nil {
+					return err
+				}
+			}
+			if err := ioutil.WriteFile(file, []byte(value), 0600); err != nil {
+				return err
+			}
+			if err := os.Chmod(file, 0400); err != nil {
+				return err
+			}
+			written[ref.Source] = true
+		}
+	}
+	return nil
+}
+
+// removeEnvironmentSecrets removes the values of project environment secrets once its containers are gone
+func removeEnvironmentSecrets(projectName string) error {
+	return os.RemoveAll(secretsDir(projectName))
+}
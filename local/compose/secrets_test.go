@@ -0,0 +1,96 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/mount"
+	"gotest.tools/v3/assert"
+)
+
+func TestSecretMounts(t *testing.T) {
+	project := types.Project{
+		Name: "myproject",
+		Secrets: types.Secrets{
+			"cert":     {Name: "cert", File: "/certs/server.pem"},
+			"db_pass":  {Name: "db_pass", Extensions: map[string]interface{}{extSecretEnvironment: "DB_PASS"}},
+			"external": {Name: "external", External: types.External{External: true}},
+		},
+	}
+	service := types.ServiceConfig{
+		Name: "db",
+		Secrets: []types.ServiceSecretConfig{
+			{Source: "cert", Target: "/etc/ssl/server.pem"},
+			{Source: "db_pass"},
+			{Source: "external"},
+		},
+	}
+	mounts, err := secretMounts(project, service)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, mounts, []mount.Mount{
+		{Type: mount.TypeBind, Source: "/certs/server.pem", Target: "/etc/ssl/server.pem", ReadOnly: true},
+		{Type: mount.TypeBind, Source: filepath.Join(secretsDir("myproject"), "db_pass"), Target: "/run/secrets/db_pass", ReadOnly: true},
+	})
+	assert.DeepEqual(t, externalSecretWarnings(&types.Project{Services: types.Services{service}, Secrets: project.Secrets}), []string{
+		`service "db" uses secret "external" which is external, it isn't available to containers created by local backend`,
+	})
+
+	service.Secrets = []types.ServiceSecretConfig{{Source: "missing"}}
+	_, err = secretMounts(project, service)
+	assert.Error(t, err, `service "db": secret "missing" is not defined in top-level secrets`)
+}
+
+func TestWriteEnvironmentSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+	assert.NilError(t, os.Setenv("TMPDIR", dir))
+	defer os.Unsetenv("TMPDIR") //nolint:errcheck
+	assert.NilError(t, os.Setenv("TEST_DB_PASS", "s3cr3t"))
+	defer os.Unsetenv("TEST_DB_PASS") //nolint:errcheck
+
+	project := &types.Project{
+		Name:     "myproject",
+		Services: types.Services{{Name: "db", Secrets: []types.ServiceSecretConfig{{Source: "db_pass"}}}},
+		Secrets: types.Secrets{
+			"db_pass": {Name: "db_pass", Extensions: map[string]interface{}{extSecretEnvironment: "TEST_DB_PASS"}},
+		},
+	}
+	// twice, as up rewrites values of a project already running
+	assert.NilError(t, writeEnvironmentSecrets(project))
+	assert.NilError(t, writeEnvironmentSecrets(project))
+	file := filepath.Join(dir, "compose-secrets", "myproject", "db_pass")
+	content, err := ioutil.ReadFile(file)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "s3cr3t")
+	info, err := os.Stat(file)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0400))
+
+	assert.NilError(t, removeEnvironmentSecrets("myproject"))
+	_, err = os.Stat(filepath.Dir(file))
+	assert.Assert(t, os.IsNotExist(err))
+
+	assert.NilError(t, os.Unsetenv("TEST_DB_PASS"))
+	err = writeEnvironmentSecrets(project)
+	assert.Error(t, err, `secret "db_pass": environment variable TEST_DB_PASS is not set`)
+}
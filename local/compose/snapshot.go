@@ -0,0 +1,242 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	volume_api "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+const (
+	// snapshotManifest is the file describing the volumes archived in a snapshot directory
+	snapshotManifest = "snapshot.json"
+	// snapshotHelperImage is the image used by the helper containers volumes are mounted in to be archived or restored
+	snapshotHelperImage = "busybox"
+	snapshotMountPoint  = "/volume"
+)
+
+// snapshot describes the volumes archived in a snapshot directory
+type snapshot struct {
+	Project string           `json:"project"`
+	Volumes []volumeSnapshot `json:"volumes"`
+}
+
+type volumeSnapshot struct {
+	Name    string            `json:"name"`
+	Driver  string            `json:"driver,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Archive string            `json:"archive"`
+}
+
+func (s *composeService) Snapshot(ctx context.Context, projectName string, options compose.SnapshotOptions) ([]string, error) {
+	list, err := s.apiClient.VolumeList(ctx, filters.NewArgs(projectFilter(projectName)))
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Volumes) == 0 {
+		return nil, fmt.Errorf("no volume found for project %q", projectName)
+	}
+	sort.Slice(list.Volumes, func(i, j int) bool {
+		return list.Volumes[i].Name < list.Volumes[j].Name
+	})
+
+	err = os.MkdirAll(options.Dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	err = s.ensureSnapshotHelperImage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := progress.ContextWriter(ctx)
+	manifest := snapshot{Project: projectName}
+	var archives []string
+	for _, volume := range list.Volumes {
+		eventName := fmt.Sprintf("Volume %q", volume.Name)
+		w.Event(progress.NewEvent(eventName, progress.Working, "Archiving").WithResource(progress.VolumeKind, volume.Name))
+		archive := filepath.Join(options.Dir, volume.Name+".tar")
+		err = s.withSnapshotHelper(ctx, volume.Name, func(id string) error {
+			content, _, err := s.apiClient.CopyFromContainer(ctx, id, snapshotMountPoint+"/.")
+			if err != nil {
+				return err
+			}
+			defer content.Close() //nolint:errcheck
+			return writeArchive(archive, content)
+		})
+		if err != nil {
+			w.Event(progress.ErrorEvent(eventName).WithResource(progress.VolumeKind, volume.Name))
+			return nil, err
+		}
+		w.Event(progress.NewEvent(eventName, progress.Done, "Archived").WithResource(progress.VolumeKind, volume.Name))
+		manifest.Volumes = append(manifest.Volumes, volumeSnapshot{
+			Name:    volume.Name,
+			Driver:  volume.Driver,
+			Labels:  volume.Labels,
+			Archive: filepath.Base(archive),
+		})
+		archives = append(archives, archive)
+	}
+	return archives, writeSnapshotManifest(options.Dir, manifest)
+}
+
+func (s *composeService) Restore(ctx context.Context, options compose.RestoreOptions) ([]string, error) {
+	manifest, err := readSnapshotManifest(options.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, volume := range manifest.Volumes {
+		_, err := s.apiClient.VolumeInspect(ctx, volume.Name)
+		if err == nil {
+			return nil, fmt.Errorf("volume %q already exists, remove it before restoring project %q", volume.Name, manifest.Project)
+		}
+		if !errdefs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	err = s.ensureSnapshotHelperImage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w := progress.ContextWriter(ctx)
+	var restored []string
+	for _, volume := range manifest.Volumes {
+		eventName := fmt.Sprintf("Volume %q", volume.Name)
+		w.Event(progress.NewEvent(eventName, progress.Working, "Restoring").WithResource(progress.VolumeKind, volume.Name))
+		err := s.restoreVolume(ctx, options.Dir, volume)
+		if err != nil {
+			w.Event(progress.ErrorEvent(eventName).WithResource(progress.VolumeKind, volume.Name))
+			return nil, err
+		}
+		w.Event(progress.NewEvent(eventName, progress.Done, "Restored").WithResource(progress.VolumeKind, volume.Name))
+		restored = append(restored, volume.Name)
+	}
+	return restored, nil
+}
+
+func (s *composeService) restoreVolume(ctx context.Context, dir string, volume volumeSnapshot) error {
+	archive, err := os.Open(filepath.Join(dir, volume.Archive))
+	if err != nil {
+		return err
+	}
+	defer archive.Close() //nolint:errcheck
+
+	_, err = s.apiClient.VolumeCreate(ctx, volume_api.VolumeCreateBody{
+		Name:   volume.Name,
+		Driver: volume.Driver,
+		Labels: volume.Labels,
+	})
+	if err != nil {
+		return err
+	}
+	return s.withSnapshotHelper(ctx, volume.Name, func(id string) error {
+		return s.apiClient.CopyToContainer(ctx, id, snapshotMountPoint, archive, moby.CopyToContainerOptions{})
+	})
+}
+
+// withSnapshotHelper creates a container with volume mounted, which is never started but gives access to volume
+// content through the engine archive API, and removes it once fn returns
+func (s *composeService) withSnapshotHelper(ctx context.Context, volume string, fn func(id string) error) error {
+	created, err := s.apiClient.ContainerCreate(ctx, &container.Config{
+		Image: snapshotHelperImage,
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{
+			{
+				Type:   mount.TypeVolume,
+				Source: volume,
+				Target: snapshotMountPoint,
+			},
+		},
+	}, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	err = fn(created.ID)
+	rmErr := s.apiClient.ContainerRemove(ctx, created.ID, moby.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		return err
+	}
+	return rmErr
+}
+
+func (s *composeService) ensureSnapshotHelperImage(ctx context.Context) error {
+	_, _, err := s.apiClient.ImageInspectWithRaw(ctx, snapshotHelperImage)
+	if err == nil {
+		return nil
+	}
+	if !errdefs.IsNotFound(err) {
+		return err
+	}
+	stream, err := s.apiClient.ImagePull(ctx, snapshotHelperImage, moby.ImagePullOptions{})
+	if err != nil {
+		return err
+	}
+	defer stream.Close() //nolint:errcheck
+	_, err = io.Copy(ioutil.Discard, stream)
+	return err
+}
+
+func writeArchive(path string, content io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, content)
+	if err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	return f.Close()
+}
+
+func writeSnapshotManifest(dir string, manifest snapshot) error {
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, snapshotManifest), b, 0644)
+}
+
+func readSnapshotManifest(dir string) (snapshot, error) {
+	var manifest snapshot
+	b, err := ioutil.ReadFile(filepath.Join(dir, snapshotManifest))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return manifest, fmt.Errorf("%s is not a snapshot directory, %s is missing", dir, snapshotManifest)
+		}
+		return manifest, err
+	}
+	err = json.Unmarshal(b, &manifest)
+	return manifest, err
+}
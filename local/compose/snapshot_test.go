@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSnapshotManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	_, err = readSnapshotManifest(dir)
+	assert.ErrorContains(t, err, "is not a snapshot directory, snapshot.json is missing")
+
+	manifest := snapshot{
+		Project: "myproject",
+		Volumes: []volumeSnapshot{
+			{
+				Name:    "myproject_data",
+				Driver:  "local",
+				Labels:  map[string]string{projectLabel(): "myproject", volumeLabel(): "data"},
+				Archive: "myproject_data.tar",
+			},
+		},
+	}
+	assert.NilError(t, writeSnapshotManifest(dir, manifest))
+
+	read, err := readSnapshotManifest(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, read, manifest)
+}
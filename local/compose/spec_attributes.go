@@ -0,0 +1,180 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/interpolation"
+	"github.com/compose-spec/compose-go/loader"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/pkg/errors"
+)
+
+// serviceSpecAttributes maps compose-spec service attributes the compose-go version in use validates but drops while
+// loading, to the service extension compose honors them from
+var serviceSpecAttributes = map[string]string{
+	"pids_limit":   extPidsLimit,
+	"blkio_config": extBlkioConfig,
+	"profiles":     extProfiles,
+}
+
+// specAttributeCasts types the interpolated spec attributes compose reads as numbers
+var specAttributeCasts = map[interpolation.Path]interpolation.Cast{
+	interpolation.NewPath("services", interpolation.PathMatchAll, "networks", interpolation.PathMatchAll, "priority"): func(value string) (interface{}, error) {
+		return strconv.Atoi(value)
+	},
+}
+
+// ApplySpecAttributes reads from compose files the compose-spec attributes the compose-go version in use validates
+// but drops while loading, and sets them as the extensions compose honors them from. Files are read in order, an
+// attribute declared by a later file overriding the earlier ones as compose-go merges files. A spec attribute wins
+// over its extension
+func ApplySpecAttributes(project *types.Project, files []string, environment map[string]string) error {
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		dict, err := loader.ParseYAML(content)
+		if err != nil {
+			return err
+		}
+		dict, err = interpolation.Interpolate(dict, interpolation.Options{
+			LookupValue: func(key string) (string, bool) {
+				value, ok := environment[key]
+				return value, ok
+			},
+			TypeCastMapping: specAttributeCasts,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", file)
+		}
+		services, _ := dict["services"].(map[string]interface{})
+		for i, service := range project.Services {
+			if config, ok := services[service.Name].(map[string]interface{}); ok {
+				applyServiceSpecAttributes(&project.Services[i], config)
+			}
+		}
+	}
+	return nil
+}
+
+func applyServiceSpecAttributes(service *types.ServiceConfig, config map[string]interface{}) {
+	for attribute, extension := range serviceSpecAttributes {
+		if value, ok := config[attribute]; ok {
+			service.Extensions = setExtension(service.Extensions, extension, value)
+		}
+	}
+	// build declared as a context path has no attribute
+	if build, ok := config["build"].(map[string]interface{}); ok && service.Build != nil {
+		if value, ok := build["shm_size"]; ok {
+			service.Build.Extensions = setExtension(service.Build.Extensions, extShmSize, value)
+		}
+	}
+	// networks declared as a list have no attribute
+	networks, _ := config["networks"].(map[string]interface{})
+	for name, value := range networks {
+		network, _ := value.(map[string]interface{})
+		priority, ok := network["priority"]
+		if !ok {
+			continue
+		}
+		if service.Networks == nil {
+			service.Networks = map[string]*types.ServiceNetworkConfig{}
+		}
+		if service.Networks[name] == nil {
+			service.Networks[name] = &types.ServiceNetworkConfig{}
+		}
+		service.Networks[name].Extensions = setExtension(service.Networks[name].Extensions, extNetworkPriority, priority)
+	}
+	volumes, _ := config["volumes"].([]interface{})
+	for _, value := range volumes {
+		switch volume := value.(type) {
+		case string:
+			applyShortVolumeConsistency(service, volume)
+		case map[string]interface{}:
+			applyLongBindCreateHostPath(service, volume)
+			applyImageVolumeReadOnly(service, volume)
+		}
+	}
+}
+
+// applyShortVolumeConsistency sets the consistency a short syntax volume declares as an option, like
+// `./src:/app:cached`, which the loader ignores
+func applyShortVolumeConsistency(service *types.ServiceConfig, spec string) {
+	volume, err := loader.ParseVolume(spec)
+	if err != nil || strings.HasSuffix(spec, volume.Target) {
+		return
+	}
+	for _, option := range strings.Split(spec[strings.LastIndex(spec, ":")+1:], ",") {
+		switch mount.Consistency(option) {
+		case mount.ConsistencyFull, mount.ConsistencyCached, mount.ConsistencyDelegated, mount.ConsistencyDefault:
+			for i, v := range service.Volumes {
+				if v.Target == volume.Target && v.Consistency == "" {
+					service.Volumes[i].Consistency = option
+				}
+			}
+		}
+	}
+}
+
+// applyLongBindCreateHostPath disables the creation of a missing source for long syntax binds not declaring it, short
+// syntax ones always creating it
+func applyLongBindCreateHostPath(service *types.ServiceConfig, volume map[string]interface{}) {
+	if volume["type"] != types.VolumeTypeBind {
+		return
+	}
+	if bind, ok := volume["bind"].(map[string]interface{}); ok {
+		if _, ok := bind[extCreateHostPath]; ok {
+			return
+		}
+	}
+	for i, v := range service.Volumes {
+		if v.Type != types.VolumeTypeBind || v.Target != volume["target"] {
+			continue
+		}
+		if v.Bind == nil {
+			service.Volumes[i].Bind = &types.ServiceVolumeBind{}
+		}
+		service.Volumes[i].Bind.Extensions = setExtension(service.Volumes[i].Bind.Extensions, extCreateHostPath, false)
+	}
+}
+
+// applyImageVolumeReadOnly records the read_only an image volume declares, so that `read_only: false` makes it writable
+func applyImageVolumeReadOnly(service *types.ServiceConfig, volume map[string]interface{}) {
+	readOnly, ok := volume["read_only"].(bool)
+	if volume["type"] != volumeTypeImage || !ok {
+		return
+	}
+	for i, v := range service.Volumes {
+		if v.Type == volumeTypeImage && v.Target == volume["target"] {
+			service.Volumes[i].Extensions = setExtension(v.Extensions, extReadOnly, readOnly)
+		}
+	}
+}
+
+func setExtension(extensions map[string]interface{}, name string, value interface{}) map[string]interface{} {
+	if extensions == nil {
+		extensions = map[string]interface{}{}
+	}
+	extensions[name] = value
+	return extensions
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestApplySpecAttributes(t *testing.T) {
+	dir := fs.NewDir(t, "spec-attributes",
+		fs.WithFile("docker-compose.yml", `
+services:
+  web:
+    image: nginx
+    profiles: [frontend]
+    pids_limit: 10
+    blkio_config:
+      weight: 300
+      device_read_bps:
+        - path: /dev/null
+          rate: 1mb
+    networks:
+      front:
+        priority: ${FRONT_PRIORITY}
+      back:
+    volumes:
+      - ./html:/usr/share/nginx/html:ro,cached
+      - ./logs:/var/log/nginx:delegated
+      - ./data:/data
+      - type: bind
+        source: ./conf
+        target: /etc/nginx/conf.d
+      - type: bind
+        source: ./certs
+        target: /etc/nginx/certs
+        bind:
+          x-create_host_path: true
+      - type: image
+        source: docs
+        target: /usr/share/nginx/docs
+        read_only: false
+      - type: image
+        source: assets
+        target: /usr/share/nginx/assets
+  db:
+    image: postgres
+    x-pids_limit: 20
+    build:
+      context: .
+      shm_size: 1g
+`),
+		fs.WithFile("docker-compose.override.yml", `
+services:
+  web:
+    pids_limit: ${WEB_PIDS}
+`))
+	defer dir.Remove()
+
+	project := &types.Project{
+		Services: types.Services{
+			{Name: "web", Image: "nginx", Networks: map[string]*types.ServiceNetworkConfig{"front": nil, "back": nil}, Volumes: []types.ServiceVolumeConfig{
+				{Type: types.VolumeTypeBind, Source: "html", Target: "/usr/share/nginx/html", ReadOnly: true},
+				{Type: types.VolumeTypeBind, Source: "logs", Target: "/var/log/nginx"},
+				{Type: types.VolumeTypeBind, Source: "data", Target: "/data"},
+				{Type: types.VolumeTypeBind, Source: "conf", Target: "/etc/nginx/conf.d"},
+				{Type: types.VolumeTypeBind, Source: "certs", Target: "/etc/nginx/certs", Bind: &types.ServiceVolumeBind{Extensions: map[string]interface{}{extCreateHostPath: true}}},
+				{Type: volumeTypeImage, Source: "docs", Target: "/usr/share/nginx/docs"},
+				{Type: volumeTypeImage, Source: "assets", Target: "/usr/share/nginx/assets"},
+			}},
+			{Name: "db", Image: "postgres", Build: &types.BuildConfig{Context: "."}, Extensions: map[string]interface{}{extPidsLimit: 20}},
+		},
+	}
+	err := ApplySpecAttributes(project, []string{dir.Join("docker-compose.yml"), dir.Join("docker-compose.override.yml")},
+		map[string]string{"WEB_PIDS": "50", "FRONT_PRIORITY": "100"})
+	assert.NilError(t, err)
+	assert.Equal(t, project.Services[0].Extensions[extPidsLimit], "50")
+	assert.Equal(t, project.Services[1].Extensions[extPidsLimit], 20)
+
+	limit, err := getPidsLimit(project.Services[0])
+	assert.NilError(t, err)
+	assert.Equal(t, *limit, int64(50))
+
+	blkio, err := getBlkioConfig(project.Services[0])
+	assert.NilError(t, err)
+	assert.Equal(t, blkio.Weight, uint16(300))
+	assert.Equal(t, blkio.DeviceReadBps[0].Rate, "1mb")
+
+	networks, err := getNetworksByPriority(project.Services[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, networks, []string{"front", "back"})
+
+	assert.DeepEqual(t, serviceProfiles(project.Services[0]), []string{"frontend"})
+
+	volumes := project.Services[0].Volumes
+	assert.Equal(t, volumes[0].Consistency, "cached")
+	assert.Equal(t, volumes[1].Consistency, "delegated")
+	assert.Equal(t, volumes[2].Consistency, "")
+	assert.Assert(t, createHostPath(volumes[0]))
+	assert.Assert(t, !createHostPath(volumes[3]))
+	assert.Assert(t, createHostPath(volumes[4]))
+	assert.Assert(t, !imageVolumeReadOnly(volumes[5]))
+	assert.Assert(t, imageVolumeReadOnly(volumes[6]))
+
+	shmSize, err := buildShmSize(project.Services[1])
+	assert.NilError(t, err)
+	assert.Equal(t, shmSize, int64(1024*1024*1024))
+}
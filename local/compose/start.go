@@ -26,6 +26,9 @@ import (
 )
 
 func (s *composeService) Start(ctx context.Context, project *types.Project, consumer compose.LogConsumer) error {
+	n := newNotifier(project)
+	defer n.wait()
+
 	var group *errgroup.Group
 	if consumer != nil {
 		eg, err := s.attach(ctx, project, consumer)
@@ -33,6 +36,9 @@ func (s *composeService) Start(ctx context.Context, project *types.Project, cons
 			return err
 		}
 		group = eg
+		// containers are only watched while attached
+		stopWatching := n.watch(ctx, s.Events)
+		defer stopWatching()
 	}
 
 	err := InDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
@@ -41,6 +47,7 @@ func (s *composeService) Start(ctx context.Context, project *types.Project, cons
 	if err != nil {
 		return err
 	}
+	n.notify(notifyUpComplete, "", "")
 	if group != nil {
 		return group.Wait()
 	}
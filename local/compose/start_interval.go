@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+type healthStartIntervalKey struct{}
+
+// withHealthStartInterval records in context the healthcheck start_interval of the container created with it
+func withHealthStartInterval(ctx context.Context, interval time.Duration) context.Context {
+	return context.WithValue(ctx, healthStartIntervalKey{}, interval)
+}
+
+// WithHealthStartInterval configures an engine client to send the healthcheck start_interval recorded in the context of
+// container creation calls, as the engine API types compose is built with have no field for it
+func WithHealthStartInterval() client.Opt {
+	return func(c *client.Client) error {
+		httpClient := c.HTTPClient()
+		httpClient.Transport = startIntervalTransport{next: httpClient.Transport}
+		return client.WithHTTPClient(httpClient)(c)
+	}
+}
+
+// startIntervalTransport sets Healthcheck.StartInterval in the body of container creation calls
+type startIntervalTransport struct {
+	next http.RoundTripper
+}
+
+func (t startIntervalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	interval, ok := req.Context().Value(healthStartIntervalKey{}).(time.Duration)
+	if !ok || req.Method != http.MethodPost || req.Body == nil || !strings.HasSuffix(req.URL.Path, "/containers/create") {
+		return t.next.RoundTrip(req)
+	}
+	var body map[string]interface{}
+	decoder := json.NewDecoder(req.Body)
+	// numbers are kept as is, so that large ones like memory limits don't lose precision
+	decoder.UseNumber()
+	err := decoder.Decode(&body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if healthcheck, ok := body["Healthcheck"].(map[string]interface{}); ok {
+		healthcheck["StartInterval"] = interval.Nanoseconds()
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(encoded))
+	req.ContentLength = int64(len(encoded))
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+)
+
+func TestHealthStartIntervalTransport(t *testing.T) {
+	var created []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		decoder := json.NewDecoder(r.Body)
+		decoder.UseNumber()
+		assert.NilError(t, decoder.Decode(&body))
+		created = append(created, body)
+		_, _ = w.Write([]byte(`{"Id":"123"}`))
+	}))
+	defer server.Close()
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.44"),
+		WithHealthStartInterval())
+	assert.NilError(t, err)
+
+	config := &container.Config{
+		Image:       "nginx",
+		Healthcheck: &container.HealthConfig{Test: []string{"CMD", "true"}, StartPeriod: time.Minute},
+	}
+	hostConfig := &container.HostConfig{Resources: container.Resources{Memory: 1<<53 + 1}}
+	ctx := withHealthStartInterval(context.Background(), time.Second)
+	_, err = apiClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "web")
+	assert.NilError(t, err)
+	_, err = apiClient.ContainerCreate(context.Background(), config, hostConfig, nil, nil, "worker")
+	assert.NilError(t, err)
+
+	healthcheck := created[0]["Healthcheck"].(map[string]interface{})
+	assert.Equal(t, healthcheck["StartInterval"], json.Number("1000000000"))
+	assert.Equal(t, healthcheck["StartPeriod"], json.Number("60000000000"))
+	assert.Equal(t, created[0]["HostConfig"].(map[string]interface{})["Memory"], json.Number("9007199254740993"))
+	_, ok := created[1]["Healthcheck"].(map[string]interface{})["StartInterval"]
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,117 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/sirupsen/logrus"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// statsTimeout is the time given to each container to report a stats sample, so a hung container doesn't stall the others
+var statsTimeout = 5 * time.Second
+
+func (s *composeService) Stats(ctx context.Context, projectName string) (map[string]compose.ContainerStats, error) {
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: filters.NewArgs(
+			projectFilter(projectName),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		stats = map[string]compose.ContainerStats{}
+	)
+	for _, c := range containers {
+		container := c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sample, err := s.sampleStats(ctx, container.ID)
+			if err != nil {
+				logrus.Debugf("failed to get stats for container %s: %v", getContainerName(container), err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			stats[container.ID] = sample
+		}()
+	}
+	wg.Wait()
+	return stats, nil
+}
+
+func (s *composeService) sampleStats(ctx context.Context, containerID string) (compose.ContainerStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, statsTimeout)
+	defer cancel()
+	// without streaming, engine waits for a second sample to be collected so CPU usage can be computed
+	res, err := s.apiClient.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return compose.ContainerStats{}, err
+	}
+	defer res.Body.Close() //nolint:errcheck
+
+	var v moby.StatsJSON
+	if err := json.NewDecoder(res.Body).Decode(&v); err != nil {
+		return compose.ContainerStats{}, err
+	}
+	return toContainerStats(v), nil
+}
+
+// toContainerStats computes resources usage the same way `docker stats` does
+func toContainerStats(v moby.StatsJSON) compose.ContainerStats {
+	return compose.ContainerStats{
+		CPUPercent:  cpuPercent(v),
+		MemoryUsage: memoryUsage(v.MemoryStats),
+		MemoryLimit: v.MemoryStats.Limit,
+	}
+}
+
+func cpuPercent(v moby.StatsJSON) float64 {
+	cpuDelta := float64(v.CPUStats.CPUUsage.TotalUsage) - float64(v.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(v.CPUStats.SystemUsage) - float64(v.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(v.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(v.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if systemDelta <= 0 || cpuDelta <= 0 {
+		return 0
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// memoryUsage excludes page cache which can be reclaimed, cgroup v1 and v2 reporting it under distinct keys
+func memoryUsage(mem moby.MemoryStats) uint64 {
+	if v, ok := mem.Stats["total_inactive_file"]; ok && v < mem.Usage {
+		return mem.Usage - v
+	}
+	if v := mem.Stats["inactive_file"]; v < mem.Usage {
+		return mem.Usage - v
+	}
+	return mem.Usage
+}
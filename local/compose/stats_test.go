@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	moby "github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+func TestToContainerStats(t *testing.T) {
+	v := moby.StatsJSON{
+		Stats: moby.Stats{
+			PreCPUStats: moby.CPUStats{
+				CPUUsage:    moby.CPUUsage{TotalUsage: 1000},
+				SystemUsage: 10000,
+			},
+			CPUStats: moby.CPUStats{
+				CPUUsage:    moby.CPUUsage{TotalUsage: 1500},
+				SystemUsage: 14000,
+				OnlineCPUs:  2,
+			},
+			MemoryStats: moby.MemoryStats{
+				Usage: 300 * 1024 * 1024,
+				Limit: 1024 * 1024 * 1024,
+				Stats: map[string]uint64{"total_inactive_file": 100 * 1024 * 1024},
+			},
+		},
+	}
+	assert.DeepEqual(t, toContainerStats(v), compose.ContainerStats{
+		CPUPercent:  25,
+		MemoryUsage: 200 * 1024 * 1024,
+		MemoryLimit: 1024 * 1024 * 1024,
+	})
+}
+
+func TestMemoryUsage(t *testing.T) {
+	cgroupV2 := moby.MemoryStats{Usage: 500, Stats: map[string]uint64{"inactive_file": 200}}
+	assert.Equal(t, memoryUsage(cgroupV2), uint64(300))
+
+	noCache := moby.MemoryStats{Usage: 500}
+	assert.Equal(t, memoryUsage(noCache), uint64(500))
+
+	inconsistent := moby.MemoryStats{Usage: 100, Stats: map[string]uint64{"total_inactive_file": 200}}
+	assert.Equal(t, memoryUsage(inconsistent), uint64(100))
+}
+
+func TestCPUPercentWithoutPreviousSample(t *testing.T) {
+	v := moby.StatsJSON{
+		Stats: moby.Stats{
+			CPUStats: moby.CPUStats{
+				CPUUsage:    moby.CPUUsage{TotalUsage: 1500, PercpuUsage: []uint64{750, 750}},
+				SystemUsage: 14000,
+			},
+		},
+	}
+	assert.Equal(t, cpuPercent(v), 1500.0/14000*2*100)
+}
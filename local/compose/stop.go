@@ -0,0 +1,160 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/api/compose"
+	"github.com/docker/compose-cli/progress"
+)
+
+func (s *composeService) Stop(ctx context.Context, projectName string, options compose.StopOptions) error {
+	project, err := s.projectFromContainerLabels(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	stop := func(c context.Context, service types.ServiceConfig) error {
+		return s.forEachContainer(c, project.Name, service.Name, func(container moby.Container) error {
+			name := getContainerName(container)
+			eventName := "Container " + name
+			w.Event(progress.StoppingEvent(eventName).WithResource(progress.ContainerKind, name))
+			if err := s.apiClient.ContainerStop(c, container.ID, nil); err != nil {
+				w.Event(progress.ErrorMessageEvent(eventName, "Error while Stopping").WithResource(progress.ContainerKind, name))
+				return err
+			}
+			w.Event(progress.NewEvent(eventName, progress.Done, "Stopped").WithResource(progress.ContainerKind, name))
+			return nil
+		})
+	}
+	if options.NoDeps {
+		return inParallel(ctx, project, options.Services, stop)
+	}
+	selected, err := withDependents(project, options.Services)
+	if err != nil {
+		return err
+	}
+	return InReverseDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
+		if !selected[service.Name] {
+			return nil
+		}
+		return stop(c, service)
+	})
+}
+
+func (s *composeService) Restart(ctx context.Context, projectName string, options compose.RestartOptions) error {
+	project, err := s.projectFromContainerLabels(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	w := progress.ContextWriter(ctx)
+	restart := func(c context.Context, service types.ServiceConfig) error {
+		return s.forEachContainer(c, project.Name, service.Name, func(container moby.Container) error {
+			name := getContainerName(container)
+			eventName := "Container " + name
+			w.Event(progress.NewEvent(eventName, progress.Working, "Restarting").WithResource(progress.ContainerKind, name))
+			if err := s.apiClient.ContainerRestart(c, container.ID, nil); err != nil {
+				w.Event(progress.ErrorMessageEvent(eventName, "Error while Restarting").WithResource(progress.ContainerKind, name))
+				return err
+			}
+			w.Event(progress.NewEvent(eventName, progress.Done, "Restarted").WithResource(progress.ContainerKind, name))
+			return nil
+		})
+	}
+	if options.NoDeps {
+		return inParallel(ctx, project, options.Services, restart)
+	}
+	selected, err := withDependents(project, options.Services)
+	if err != nil {
+		return err
+	}
+	return InDependencyOrder(ctx, project, func(c context.Context, service types.ServiceConfig) error {
+		if !selected[service.Name] {
+			return nil
+		}
+		return restart(c, service)
+	})
+}
+
+// forEachContainer applies fn concurrently to all containers of a service
+func (s *composeService) forEachContainer(ctx context.Context, projectName, serviceName string, fn func(moby.Container) error) error {
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		Filters: filters.NewArgs(projectFilter(projectName), serviceFilter(serviceName)),
+		All:     true,
+	})
+	if err != nil {
+		return err
+	}
+	eg, _ := errgroup.WithContext(ctx)
+	for _, c := range containers {
+		container := c
+		eg.Go(func() error {
+			return fn(container)
+		})
+	}
+	return eg.Wait()
+}
+
+// inParallel applies fn concurrently to the selected services, all of them if none is, regardless of dependencies
+func inParallel(ctx context.Context, project *types.Project, services []string, fn func(context.Context, types.ServiceConfig) error) error {
+	selected, err := project.GetServices(services)
+	if err != nil {
+		return err
+	}
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, s := range selected {
+		service := s
+		eg.Go(func() error {
+			return fn(ctx, service)
+		})
+	}
+	return eg.Wait()
+}
+
+// withDependents selects services and the ones depending on them, directly or not. All services are selected if none is
+func withDependents(project *types.Project, services []string) (map[string]bool, error) {
+	if len(services) == 0 {
+		services = project.ServiceNames()
+	}
+	graph := NewGraph(project.Services, ServiceStopped)
+	selected := map[string]bool{}
+	var add func(v *Vertex)
+	add = func(v *Vertex) {
+		if selected[v.Key] {
+			return
+		}
+		selected[v.Key] = true
+		for _, dependent := range v.GetParents() {
+			add(dependent)
+		}
+	}
+	for _, name := range services {
+		v, ok := graph.Vertices[name]
+		if !ok {
+			return nil, fmt.Errorf("no such service: %s", name)
+		}
+		add(v)
+	}
+	return selected, nil
+}
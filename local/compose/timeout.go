@@ -0,0 +1,184 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// httpTimeoutEnv sets, in seconds, how long engine calls may take, as docker-compose v1 does
+const httpTimeoutEnv = "COMPOSE_HTTP_TIMEOUT"
+
+type serviceKey struct{}
+
+// withServiceName records in context the service engine calls are made for, so that errors can name it
+func withServiceName(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+
+func serviceName(ctx context.Context) string {
+	service, _ := ctx.Value(serviceKey{}).(string)
+	return service
+}
+
+// WithHTTPTimeout configures an engine client to apply the COMPOSE_HTTP_TIMEOUT time limit to non-streaming calls.
+// Streaming calls, like pulling images or following logs, have no time limit.
+func WithHTTPTimeout() client.Opt {
+	return func(c *client.Client) error {
+		timeout, err := httpTimeout()
+		if err != nil {
+			return err
+		}
+		httpClient := c.HTTPClient()
+		httpClient.Timeout = 0
+		if timeout > 0 {
+			httpClient.Transport = timeoutTransport{
+				next:    httpClient.Transport,
+				timeout: timeout,
+			}
+		}
+		return client.WithHTTPClient(httpClient)(c)
+	}
+}
+
+func httpTimeout() (time.Duration, error) {
+	value, ok := os.LookupEnv(httpTimeoutEnv)
+	if !ok || value == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid %s value %q, must be a number of seconds", httpTimeoutEnv, value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+var apiVersionPrefix = regexp.MustCompile(`^/v[0-9.]+`)
+
+// operation is how an engine call is named in errors, like "POST /containers/create"
+func operation(req *http.Request) string {
+	return req.Method + " " + apiVersionPrefix.ReplaceAllString(req.URL.Path, "")
+}
+
+// isStreaming tells engine calls which stream their response for as long as the operation runs
+func isStreaming(req *http.Request) bool {
+	path := apiVersionPrefix.ReplaceAllString(req.URL.Path, "")
+	switch path {
+	case "/images/create", "/images/load", "/images/get", "/build", "/events", "/session", "/grpc":
+		return true
+	}
+	switch {
+	case strings.HasPrefix(path, "/images/"):
+		return strings.HasSuffix(path, "/push") || strings.HasSuffix(path, "/get")
+	case strings.HasPrefix(path, "/exec/"):
+		return strings.HasSuffix(path, "/start")
+	case strings.HasPrefix(path, "/containers/"):
+		if strings.HasSuffix(path, "/stats") {
+			stream := req.URL.Query().Get("stream")
+			return stream != "0" && stream != "false"
+		}
+		for _, suffix := range []string{"/attach", "/attach/ws", "/logs", "/wait", "/archive", "/export"} {
+			if strings.HasSuffix(path, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timeoutTransport cancels non-streaming engine calls, including reading their response, after timeout
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isStreaming(req) {
+		return t.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, t.check(ctx, req, err)
+	}
+	resp.Body = timeoutBody{
+		ReadCloser: resp.Body,
+		ctx:        ctx,
+		cancel:     cancel,
+		timeout:    t,
+		req:        req,
+	}
+	return resp, nil
+}
+
+// check replaces err by a timeoutError if the call failed because it ran out of time, rather than being canceled
+func (t timeoutTransport) check(ctx context.Context, req *http.Request, err error) error {
+	if ctx.Err() != context.DeadlineExceeded || req.Context().Err() != nil {
+		return err
+	}
+	return timeoutError{
+		operation: operation(req),
+		service:   serviceName(req.Context()),
+		timeout:   t.timeout,
+	}
+}
+
+type timeoutBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout timeoutTransport
+	req     *http.Request
+}
+
+func (b timeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = b.timeout.check(b.ctx, b.req, err)
+	}
+	return n, err
+}
+
+func (b timeoutBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// timeoutError doesn't implement net.Error, as the engine client would report it as a connection failure
+type timeoutError struct {
+	operation string
+	service   string
+	timeout   time.Duration
+}
+
+func (e timeoutError) Error() string {
+	if e.service == "" {
+		return fmt.Sprintf("%s: operation timed out after %gs (%s)", e.operation, e.timeout.Seconds(), httpTimeoutEnv)
+	}
+	return fmt.Sprintf("service %s, %s: operation timed out after %gs (%s)", e.service, e.operation, e.timeout.Seconds(), httpTimeoutEnv)
+}
@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"gotest.tools/v3/assert"
+)
+
+func TestHTTPTimeout(t *testing.T) {
+	stalled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.41/containers/json":
+			select {
+			case <-stalled:
+			case <-r.Context().Done():
+			}
+		case "/v1.41/images/create":
+			// slow pull streams progress for longer than timeout
+			time.Sleep(500 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"status":"Downloaded newer image"}`))
+		}
+	}))
+	defer server.Close()
+	defer close(stalled)
+
+	os.Setenv(httpTimeoutEnv, "0.1")  //nolint:errcheck
+	defer os.Unsetenv(httpTimeoutEnv) //nolint:errcheck
+	apiClient, err := client.NewClientWithOpts(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.41"), WithHTTPTimeout())
+	assert.NilError(t, err)
+
+	ctx := withServiceName(context.Background(), "web")
+	_, err = apiClient.ContainerList(ctx, moby.ContainerListOptions{})
+	assert.ErrorContains(t, err, "service web, GET /containers/json: operation timed out after 0.1s (COMPOSE_HTTP_TIMEOUT)")
+
+	stream, err := apiClient.ImagePull(ctx, "nginx", moby.ImagePullOptions{})
+	assert.NilError(t, err)
+	defer stream.Close() //nolint:errcheck
+}
+
+func TestHTTPTimeoutEnv(t *testing.T) {
+	defer os.Unsetenv(httpTimeoutEnv) //nolint:errcheck
+
+	timeout, err := httpTimeout()
+	assert.NilError(t, err)
+	assert.Equal(t, timeout, time.Duration(0))
+
+	os.Setenv(httpTimeoutEnv, "300") //nolint:errcheck
+	timeout, err = httpTimeout()
+	assert.NilError(t, err)
+	assert.Equal(t, timeout, 300*time.Second)
+
+	os.Setenv(httpTimeoutEnv, "5m") //nolint:errcheck
+	_, err = httpTimeout()
+	assert.Error(t, err, `invalid COMPOSE_HTTP_TIMEOUT value "5m", must be a number of seconds`)
+}
+
+func TestIsStreaming(t *testing.T) {
+	streaming := func(method, target string) bool {
+		return isStreaming(httptest.NewRequest(method, target, nil))
+	}
+	assert.Assert(t, streaming("POST", "/v1.41/images/create?fromImage=nginx"))
+	assert.Assert(t, streaming("POST", "/v1.41/images/myregistry.com/app/push"))
+	assert.Assert(t, streaming("POST", "/v1.41/build"))
+	assert.Assert(t, streaming("GET", "/v1.41/containers/123/logs?follow=1"))
+	assert.Assert(t, streaming("GET", "/v1.41/containers/123/stats"))
+	assert.Assert(t, !streaming("GET", "/v1.41/containers/123/stats?stream=0"))
+	assert.Assert(t, !streaming("POST", "/v1.41/containers/create?name=test_web_1"))
+	assert.Assert(t, !streaming("GET", "/v1.41/images/nginx/json"))
+	assert.Assert(t, !streaming("GET", "/v1.41/distribution/nginx/json"))
+}
@@ -0,0 +1,76 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/compose-cli/api/compose"
+)
+
+// transferResults collects, from concurrent pushes or pulls, the outcome of transferring the image of each service
+type transferResults struct {
+	mu      sync.Mutex
+	summary compose.TransferSummary
+}
+
+func newTransferResults() *transferResults {
+	return &transferResults{
+		summary: compose.TransferSummary{
+			Skipped: map[string]string{},
+			Failed:  map[string]error{},
+		},
+	}
+}
+
+func (r *transferResults) skip(service string, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.summary.Skipped[service] = reason
+}
+
+// done records the outcome of transferring an image used by services
+func (r *transferResults) done(err error, services ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, service := range services {
+		if err != nil {
+			r.summary.Failed[service] = err
+		} else {
+			r.summary.Succeeded = append(r.summary.Succeeded, service)
+		}
+	}
+}
+
+// result returns the summary, and an error listing failed services unless failures are ignored
+func (r *transferResults) result(action string, ignoreFailures bool) (compose.TransferSummary, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.Strings(r.summary.Succeeded)
+	if len(r.summary.Failed) == 0 || ignoreFailures {
+		return r.summary, nil
+	}
+	var failed []string
+	for service := range r.summary.Failed {
+		failed = append(failed, service)
+	}
+	sort.Strings(failed)
+	return r.summary, fmt.Errorf("failed to %s service(s) %s", action, strings.Join(failed, ", "))
+}
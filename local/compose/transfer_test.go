@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTransferResults(t *testing.T) {
+	results := newTransferResults()
+	results.skip("db", "no build section")
+	results.done(nil, "worker", "app")
+	denied := errors.New("denied: requested access to the resource is denied")
+	results.done(denied, "api")
+
+	summary, err := results.result("push", false)
+	assert.Error(t, err, "failed to push service(s) api")
+	assert.DeepEqual(t, summary.Succeeded, []string{"app", "worker"})
+	assert.DeepEqual(t, summary.Skipped, map[string]string{"db": "no build section"})
+	assert.Equal(t, summary.Failed["api"], denied)
+
+	_, err = results.result("push", true)
+	assert.NilError(t, err)
+}
+
+func TestTransferResultsSkippedOnly(t *testing.T) {
+	results := newTransferResults()
+	results.skip("app", "image gets built")
+
+	summary, err := results.result("pull", false)
+	assert.NilError(t, err)
+	assert.Equal(t, len(summary.Succeeded), 0)
+	assert.Equal(t, len(summary.Failed), 0)
+}
@@ -0,0 +1,181 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	cliconfig "github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/trust"
+	"github.com/docker/distribution/reference"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/registry"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/theupdateframework/notary/tuf/data"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/compose-cli/config"
+	"github.com/docker/compose-cli/progress"
+)
+
+// SignatureVerifier resolves a tagged image into the digest its signed trust data refers to
+type SignatureVerifier interface {
+	Verify(ctx context.Context, ref reference.NamedTagged, authConfig moby.AuthConfig) (reference.Canonical, error)
+}
+
+type trustedImage struct {
+	tagged    reference.NamedTagged
+	canonical reference.Canonical
+}
+
+// pullTrustedImages pulls services images by the digest resolved from their signature, then tags them as
+// `docker pull` does with content trust enabled
+func (s *composeService) pullTrustedImages(ctx context.Context, project *types.Project, verifier SignatureVerifier, verbose bool) error {
+	configFile, err := cliconfig.Load(config.Dir(ctx))
+	if err != nil {
+		return err
+	}
+	info, err := s.apiClient.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if info.IndexServerAddress == "" {
+		info.IndexServerAddress = registry.IndexServer
+	}
+	resolveAuth := func(ref reference.Named) (moby.AuthConfig, error) {
+		return resolveAuthConfig(ref, configFile, info.IndexServerAddress)
+	}
+
+	trusted, err := trustedReferences(ctx, project, verifier, resolveAuth)
+	if err != nil {
+		return err
+	}
+
+	w := progress.ContextWriter(ctx)
+	eg, ctx := errgroup.WithContext(ctx)
+	for name, image := range trusted {
+		name, image := name, image
+		eg.Go(func() error {
+			present, err := s.localImagePresent(ctx, image.canonical.String())
+			if err != nil {
+				return err
+			}
+			if !present {
+				authConfig, err := resolveAuth(image.canonical)
+				if err != nil {
+					return err
+				}
+				auth, err := encodeAuthConfig(authConfig)
+				if err != nil {
+					return err
+				}
+				w.Event(progress.Event{
+					ID:     name,
+					Status: progress.Working,
+					Text:   "Pulling",
+					Kind:   progress.ImageKind,
+					Name:   image.canonical.String(),
+				})
+				err = s.pullImage(ctx, []string{name}, image.canonical.String(), auth, w, verbose)
+				if err != nil {
+					return err
+				}
+			}
+			return s.apiClient.ImageTag(ctx, image.canonical.String(), reference.FamiliarString(image.tagged))
+		})
+	}
+	return eg.Wait()
+}
+
+// trustedReferences resolves signed digests for services images, and fails listing all unsigned ones
+func trustedReferences(ctx context.Context, project *types.Project, verifier SignatureVerifier, resolveAuth func(reference.Named) (moby.AuthConfig, error)) (map[string]trustedImage, error) {
+	trusted := map[string]trustedImage{}
+	var unsigned []string
+	for _, service := range project.Services {
+		if service.Build != nil {
+			logrus.Warnf("service %q uses a locally built image, its signature is not verified", service.Name)
+			continue
+		}
+		ref, err := reference.ParseNormalizedNamed(service.Image)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ref.(reference.Canonical); ok {
+			// image is pinned by digest
+			continue
+		}
+		tagged, ok := reference.TagNameOnly(ref).(reference.NamedTagged)
+		if !ok {
+			continue
+		}
+		authConfig, err := resolveAuth(tagged)
+		if err != nil {
+			return nil, err
+		}
+		canonical, err := verifier.Verify(ctx, tagged, authConfig)
+		if err != nil {
+			unsigned = append(unsigned, fmt.Sprintf("service %q: %s: %v", service.Name, reference.FamiliarString(tagged), err))
+			continue
+		}
+		trusted[service.Name] = trustedImage{
+			tagged:    tagged,
+			canonical: canonical,
+		}
+	}
+	if len(unsigned) > 0 {
+		return nil, fmt.Errorf("signature verification failed:\n%s", strings.Join(unsigned, "\n"))
+	}
+	return trusted, nil
+}
+
+// notaryVerifier relies on Docker Content Trust signed metadata
+type notaryVerifier struct{}
+
+func (v notaryVerifier) Verify(ctx context.Context, ref reference.NamedTagged, authConfig moby.AuthConfig) (reference.Canonical, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return nil, err
+	}
+	notaryRepo, err := trust.GetNotaryRepository(os.Stdin, ioutil.Discard, "compose-cli", repoInfo, &authConfig, "pull")
+	if err != nil {
+		return nil, errors.Wrap(err, "error establishing connection to trust repository")
+	}
+
+	target, err := notaryRepo.GetTargetByName(ref.Tag(), trust.ReleasesRole, data.CanonicalTargetsRole)
+	if err != nil {
+		return nil, trust.NotaryError(repoInfo.Name.Name(), err)
+	}
+	// Only get the tag if it's in the top level targets role or the releases delegation role
+	// ignore it if it's in any other delegation roles
+	if target.Role != trust.ReleasesRole && target.Role != data.CanonicalTargetsRole {
+		return nil, fmt.Errorf("no trust data for %s", ref.Tag())
+	}
+
+	h, ok := target.Hashes["sha256"]
+	if !ok {
+		return nil, errors.New("no valid hash, expecting sha256")
+	}
+	return reference.WithDigest(reference.TrimNamed(ref), digest.NewDigestFromHex("sha256", hex.EncodeToString(h)))
+}
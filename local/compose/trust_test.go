@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"github.com/docker/distribution/reference"
+	moby "github.com/docker/docker/api/types"
+	"github.com/opencontainers/go-digest"
+	"gotest.tools/v3/assert"
+)
+
+const signedDigest = digest.Digest("sha256:2222222222222222222222222222222222222222222222222222222222222222")
+
+type fakeVerifier struct {
+	signed map[string]bool
+}
+
+func (v fakeVerifier) Verify(ctx context.Context, ref reference.NamedTagged, authConfig moby.AuthConfig) (reference.Canonical, error) {
+	if !v.signed[reference.FamiliarString(ref)] {
+		return nil, errors.New("no trust data")
+	}
+	return reference.WithDigest(reference.TrimNamed(ref), signedDigest)
+}
+
+func noAuth(reference.Named) (moby.AuthConfig, error) {
+	return moby.AuthConfig{}, nil
+}
+
+func TestTrustedReferences(t *testing.T) {
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web", Image: "nginx"},
+			{Name: "app", Build: &types.BuildConfig{Context: "."}},
+			{Name: "pinned", Image: "redis@sha256:1111111111111111111111111111111111111111111111111111111111111111"},
+		},
+	}
+	verifier := fakeVerifier{signed: map[string]bool{"nginx:latest": true}}
+
+	trusted, err := trustedReferences(context.Background(), project, verifier, noAuth)
+	assert.NilError(t, err)
+	assert.Equal(t, len(trusted), 1)
+	assert.Equal(t, reference.FamiliarString(trusted["web"].tagged), "nginx:latest")
+	assert.Equal(t, trusted["web"].canonical.Digest(), signedDigest)
+}
+
+func TestTrustedReferencesListUnsigned(t *testing.T) {
+	project := &types.Project{
+		Services: []types.ServiceConfig{
+			{Name: "web", Image: "nginx"},
+			{Name: "db", Image: "mysql:8"},
+			{Name: "cache", Image: "redis:6"},
+		},
+	}
+	verifier := fakeVerifier{signed: map[string]bool{"nginx:latest": true}}
+
+	_, err := trustedReferences(context.Background(), project, verifier, noAuth)
+	assert.ErrorContains(t, err, `service "db": mysql:8: no trust data`)
+	assert.ErrorContains(t, err, `service "cache": redis:6: no trust data`)
+}
@@ -19,6 +19,7 @@ package compose
 import (
 	"encoding/json"
 
+	"github.com/compose-spec/compose-go/types"
 	"github.com/opencontainers/go-digest"
 )
 
@@ -30,6 +31,55 @@ func jsonHash(o interface{}) (string, error) {
 	return digest.SHA256.FromBytes(bytes).String(), nil
 }
 
+// serviceHash is the config hash of service containers created from image imageID, so that a tag moved to another
+// image makes containers diverge even though the service configuration is unchanged. compose-go leaves extensions
+// out of service JSON representation, so the ones containers are configured from are hashed as well
+func serviceHash(service types.ServiceConfig, imageID string) (string, error) {
+	var config interface{} = service
+	if imageID != "" {
+		config = struct {
+			Service types.ServiceConfig
+			ImageID string
+		}{service, imageID}
+	}
+	if extensions := appliedExtensions(service); len(extensions) > 0 {
+		config = struct {
+			Config     interface{}
+			Extensions map[string]interface{}
+		}{config, extensions}
+	}
+	return jsonHash(config)
+}
+
+// appliedExtensions collects the extensions service containers are configured from, by their path in service
+func appliedExtensions(service types.ServiceConfig) map[string]interface{} {
+	extensions := map[string]interface{}{}
+	for _, name := range []string{extPidsLimit, extBlkioConfig, extImageVolumes} {
+		if value, ok := service.Extensions[name]; ok {
+			extensions[name] = value
+		}
+	}
+	for name, network := range service.Networks {
+		if network == nil {
+			continue
+		}
+		if value, ok := network.Extensions[extNetworkPriority]; ok {
+			extensions["networks."+name+"."+extNetworkPriority] = value
+		}
+	}
+	for _, volume := range service.Volumes {
+		if value, ok := volume.Extensions[extReadOnly]; ok && volume.Type == volumeTypeImage {
+			extensions["volumes."+volume.Target+"."+extReadOnly] = value
+		}
+	}
+	if service.HealthCheck != nil {
+		if value, ok := service.HealthCheck.Extensions[extHealthStartInterval]; ok {
+			extensions["healthcheck."+extHealthStartInterval] = value
+		}
+	}
+	return extensions
+}
+
 func contains(slice []string, item string) bool {
 	for _, v := range slice {
 		if v == item {
@@ -0,0 +1,160 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/sirupsen/logrus"
+)
+
+const containerPrefix = "container:"
+
+// volumesFrom is a parsed legacy `volumes_from` entry, `service[:mode]` or `container:name[:mode]`
+type volumesFrom struct {
+	source    string
+	container bool
+	mode      string
+}
+
+func parseVolumesFrom(entry string) (volumesFrom, error) {
+	v := volumesFrom{source: entry}
+	if strings.HasPrefix(entry, containerPrefix) {
+		v.container = true
+		v.source = strings.TrimPrefix(entry, containerPrefix)
+	}
+	if i := strings.LastIndex(v.source, ":"); i >= 0 {
+		v.source, v.mode = v.source[:i], v.source[i+1:]
+	}
+	switch v.mode {
+	case "", "ro", "rw":
+	default:
+		return volumesFrom{}, fmt.Errorf("invalid volumes_from %q, mode must be ro or rw", entry)
+	}
+	if v.source == "" {
+		return volumesFrom{}, fmt.Errorf("invalid volumes_from %q", entry)
+	}
+	return v, nil
+}
+
+// getDependencies lists the services service depends on, including the ones it gets volumes from
+func getDependencies(service types.ServiceConfig) []string {
+	dependencies := service.GetDependencies()
+	for _, entry := range service.VolumesFrom {
+		v, err := parseVolumesFrom(entry)
+		if err != nil || v.container || contains(dependencies, v.source) {
+			continue
+		}
+		dependencies = append(dependencies, v.source)
+	}
+	return dependencies
+}
+
+// applyVolumesFrom replicates mounts of the containers service gets volumes from, unless service declares a volume
+// for the same target
+func (s *composeService) applyVolumesFrom(ctx context.Context, project *types.Project, service types.ServiceConfig, hostConfig *container.HostConfig) error {
+	if len(service.VolumesFrom) == 0 {
+		return nil
+	}
+	logrus.Warnf("service %q: volumes_from is deprecated, declare named volumes shared by services instead", service.Name)
+	var targets []string
+	for _, m := range hostConfig.Mounts {
+		targets = append(targets, m.Target)
+	}
+	for _, entry := range service.VolumesFrom {
+		v, err := parseVolumesFrom(entry)
+		if err != nil {
+			return err
+		}
+		source, err := s.volumesFromContainer(ctx, project, v)
+		if err != nil {
+			return err
+		}
+		for _, m := range volumesFromMounts(source, v.mode) {
+			if contains(targets, m.Target) {
+				continue
+			}
+			hostConfig.Mounts = append(hostConfig.Mounts, m)
+			targets = append(targets, m.Target)
+		}
+	}
+	return nil
+}
+
+// volumesFromContainer resolves the container a volumes_from entry refers to
+func (s *composeService) volumesFromContainer(ctx context.Context, project *types.Project, v volumesFrom) (moby.ContainerJSON, error) {
+	if v.container {
+		return s.apiClient.ContainerInspect(ctx, v.source)
+	}
+	referenced, err := project.GetService(v.source)
+	if err != nil {
+		return moby.ContainerJSON{}, err
+	}
+	if getScale(referenced) > 1 {
+		return moby.ContainerJSON{}, fmt.Errorf("cannot get volumes from service %q as it is scaled to %d containers", v.source, getScale(referenced))
+	}
+	containers, err := s.apiClient.ContainerList(ctx, moby.ContainerListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			projectFilter(project.Name),
+			serviceFilter(v.source),
+			filters.Arg("label", fmt.Sprintf("%s=%s", oneoffLabel(), "False")),
+		),
+	})
+	if err != nil {
+		return moby.ContainerJSON{}, err
+	}
+	if len(containers) != 1 {
+		return moby.ContainerJSON{}, fmt.Errorf("cannot get volumes from service %q: expected a single container, found %d", v.source, len(containers))
+	}
+	return s.apiClient.ContainerInspect(ctx, containers[0].ID)
+}
+
+// volumesFromMounts converts container mounts into mount options, mode overriding the original read-only flag
+func volumesFromMounts(source moby.ContainerJSON, mode string) []mount.Mount {
+	var mounts []mount.Mount
+	for _, m := range source.Mounts {
+		if m.Type == mount.TypeTmpfs {
+			continue
+		}
+		src := m.Source
+		if m.Type == mount.TypeVolume {
+			src = m.Name
+		}
+		readOnly := !m.RW
+		switch mode {
+		case "ro":
+			readOnly = true
+		case "rw":
+			readOnly = false
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     m.Type,
+			Source:   src,
+			Target:   m.Destination,
+			ReadOnly: readOnly,
+		})
+	}
+	return mounts
+}
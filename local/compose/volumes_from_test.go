@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	moby "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseVolumesFrom(t *testing.T) {
+	v, err := parseVolumesFrom("datastore")
+	assert.NilError(t, err)
+	assert.Equal(t, v, volumesFrom{source: "datastore"})
+
+	v, err = parseVolumesFrom("datastore:ro")
+	assert.NilError(t, err)
+	assert.Equal(t, v, volumesFrom{source: "datastore", mode: "ro"})
+
+	v, err = parseVolumesFrom("container:legacy_data:rw")
+	assert.NilError(t, err)
+	assert.Equal(t, v, volumesFrom{source: "legacy_data", container: true, mode: "rw"})
+
+	_, err = parseVolumesFrom("datastore:z")
+	assert.ErrorContains(t, err, `invalid volumes_from "datastore:z"`)
+}
+
+func TestVolumesFromDependencyOrder(t *testing.T) {
+	project := types.Project{
+		Services: []types.ServiceConfig{
+			{
+				Name:        "app",
+				VolumesFrom: []string{"datastore:ro", "container:legacy_data"},
+			},
+			{
+				Name: "datastore",
+			},
+		},
+	}
+	assert.DeepEqual(t, getDependencies(project.Services[0]), []string{"datastore"})
+
+	order := make(chan string)
+	//nolint:errcheck, unparam
+	go InDependencyOrder(context.TODO(), &project, func(ctx context.Context, config types.ServiceConfig) error {
+		order <- config.Name
+		return nil
+	})
+	assert.Equal(t, <-order, "datastore")
+	assert.Equal(t, <-order, "app")
+}
+
+func TestVolumesFromMounts(t *testing.T) {
+	source := moby.ContainerJSON{
+		Mounts: []moby.MountPoint{
+			{Type: mount.TypeVolume, Name: "myproject_data", Source: "/var/lib/docker/volumes/myproject_data/_data", Destination: "/data", RW: true},
+			{Type: mount.TypeBind, Source: "/etc/config", Destination: "/config", RW: false},
+			{Type: mount.TypeTmpfs, Destination: "/tmp", RW: true},
+		},
+	}
+
+	assert.DeepEqual(t, volumesFromMounts(source, ""), []mount.Mount{
+		{Type: mount.TypeVolume, Source: "myproject_data", Target: "/data", ReadOnly: false},
+		{Type: mount.TypeBind, Source: "/etc/config", Target: "/config", ReadOnly: true},
+	})
+	assert.DeepEqual(t, volumesFromMounts(source, "ro"), []mount.Mount{
+		{Type: mount.TypeVolume, Source: "myproject_data", Target: "/data", ReadOnly: true},
+		{Type: mount.TypeBind, Source: "/etc/config", Target: "/config", ReadOnly: true},
+	})
+	assert.DeepEqual(t, volumesFromMounts(source, "rw"), []mount.Mount{
+		{Type: mount.TypeVolume, Source: "myproject_data", Target: "/data", ReadOnly: false},
+		{Type: mount.TypeBind, Source: "/etc/config", Target: "/config", ReadOnly: false},
+	})
+}
@@ -0,0 +1,44 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package compose
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestDeclaredWorkingDir(t *testing.T) {
+	tests := []struct {
+		name       string
+		override   string
+		workingDir string
+		expected   string
+	}{
+		{name: "none, container default applies"},
+		{name: "service working_dir", workingDir: "/srv", expected: "/srv"},
+		{name: "overridden", override: "/tmp", expected: "/tmp"},
+		{name: "override wins over service", override: "/tmp", workingDir: "/srv", expected: "/tmp"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := types.ServiceConfig{Name: "web", WorkingDir: test.workingDir}
+			assert.Equal(t, declaredWorkingDir(test.override, service), test.expected)
+		})
+	}
+}
@@ -30,6 +30,15 @@ const (
 	Error
 )
 
+// Kinds of resources events report about
+const (
+	ContainerKind = "container"
+	NetworkKind   = "network"
+	VolumeKind    = "volume"
+	ImageKind     = "image"
+	ServiceKind   = "service"
+)
+
 // Event represents a progress event.
 type Event struct {
 	ID         string
@@ -37,6 +46,11 @@ type Event struct {
 	Text       string
 	Status     EventStatus
 	StatusText string
+	// Kind and Name identify the resource event reports about, ID only being meant for display
+	Kind string
+	Name string
+	// Ports are the ports a container publishes, as `host_ip:published->target/protocol`
+	Ports []string
 
 	startTime time.Time
 	endTime   time.Time
@@ -102,6 +116,13 @@ func NewEvent(ID string, status EventStatus, statusText string) Event {
 	}
 }
 
+// WithResource sets the kind and name of the resource e reports about
+func (e Event) WithResource(kind, name string) Event {
+	e.Kind = kind
+	e.Name = name
+	return e
+}
+
 func (e *Event) stop() {
 	e.endTime = time.Now()
 	e.spinner.Stop()
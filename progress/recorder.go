@@ -0,0 +1,132 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Resource is the outcome of processing a resource, as reported by progress events for its ID
+type Resource struct {
+	ID string
+	// Kind and Name are the ones events reported for resource, if any
+	Kind string
+	Name string
+	// Action is the last action reported, like "Created" or "Removed"
+	Action string
+	Status EventStatus
+	// Error is the error message reported, if any
+	Error string
+	Start time.Time
+	End   time.Time
+	// Ports are the last published ports reported for a container
+	Ports []string
+}
+
+// Duration is the time elapsed between the first and last events reported for resource
+func (r Resource) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Recorder collects the resources progress events are reported for, so the outcome of an operation can be summarized
+type Recorder struct {
+	mtx       sync.Mutex
+	ids       []string
+	resources map[string]*Resource
+	now       func() time.Time
+}
+
+// NewRecorder creates a Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		resources: map[string]*Resource{},
+		now:       time.Now,
+	}
+}
+
+type recorderKey struct{}
+
+// WithRecorder adds the recorder to the context, so events sent to the context writer get recorded
+func WithRecorder(ctx context.Context, recorder *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+func recording(ctx context.Context, w Writer) Writer {
+	recorder, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return w
+	}
+	return &recordingWriter{Writer: w, recorder: recorder}
+}
+
+// Resources returns recorded resources, in the order they were first reported
+func (r *Recorder) Resources() []Resource {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	resources := make([]Resource, 0, len(r.ids))
+	for _, id := range r.ids {
+		resources = append(resources, *r.resources[id])
+	}
+	return resources
+}
+
+func (r *Recorder) record(e Event) {
+	// events with a parent report details, like image layers being pulled
+	if e.ParentID != "" {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	now := r.now()
+	resource, ok := r.resources[e.ID]
+	if !ok {
+		resource = &Resource{ID: e.ID, Start: now}
+		r.resources[e.ID] = resource
+		r.ids = append(r.ids, e.ID)
+	}
+	resource.Status = e.Status
+	if e.Kind != "" {
+		resource.Kind = e.Kind
+		resource.Name = e.Name
+	}
+	if len(e.Ports) > 0 {
+		resource.Ports = e.Ports
+	}
+	resource.End = now
+	switch {
+	case e.Status == Error && e.StatusText != "":
+		resource.Error = e.StatusText
+	case e.Status == Error:
+		resource.Error = e.Text
+	case e.Text != "":
+		resource.Action = e.Text
+	default:
+		resource.Action = e.StatusText
+	}
+}
+
+type recordingWriter struct {
+	Writer
+	recorder *Recorder
+}
+
+func (w *recordingWriter) Event(e Event) {
+	w.recorder.record(e)
+	w.Writer.Event(e)
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package progress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	clock := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	w := ContextWriter(WithRecorder(context.Background(), recorder))
+
+	w.Event(CreatingEvent(`Network "default"`).WithResource(NetworkKind, "default"))
+	w.Event(Event{ID: "web", Status: Working, Text: "Pulling"})
+	w.Event(Event{ID: "layer", ParentID: "web", Status: Working, Text: "Downloading"})
+	w.Event(CreatedEvent(`Network "default"`))
+	w.Event(Event{ID: "web", Status: Done, Text: "Pulled"})
+	w.Event(StartingEvent("project_web_1").WithResource(ContainerKind, "project_web_1"))
+	w.Event(ErrorMessageEvent("project_web_1", "port is already allocated"))
+	started := StartedEvent("project_db_1").WithResource(ContainerKind, "project_db_1")
+	started.Ports = []string{"0.0.0.0:5432->5432/tcp"}
+	w.Event(started)
+
+	resources := recorder.Resources()
+	assert.Equal(t, len(resources), 4)
+	assert.DeepEqual(t, resources[0], Resource{
+		ID:     `Network "default"`,
+		Kind:   NetworkKind,
+		Name:   "default",
+		Action: "Created",
+		Status: Done,
+		Start:  time.Date(2020, 12, 1, 10, 0, 1, 0, time.UTC),
+		End:    time.Date(2020, 12, 1, 10, 0, 3, 0, time.UTC),
+	})
+	assert.Equal(t, resources[0].Duration(), 2*time.Second)
+	assert.Equal(t, resources[1].Action, "Pulled")
+	assert.Equal(t, resources[1].Kind, "")
+	assert.Equal(t, resources[2].Action, "Starting")
+	assert.Equal(t, resources[2].Status, Error)
+	assert.Equal(t, resources[2].Error, "port is already allocated")
+	assert.Equal(t, resources[2].Kind, ContainerKind)
+	assert.DeepEqual(t, resources[3].Ports, []string{"0.0.0.0:5432->5432/tcp"})
+}
+
+func TestRecorderNotInContext(t *testing.T) {
+	assert.Equal(t, ContextWriter(context.Background()), &noopWriter{})
+}
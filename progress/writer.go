@@ -44,7 +44,7 @@ func WithContextWriter(ctx context.Context, writer Writer) context.Context {
 func ContextWriter(ctx context.Context) Writer {
 	s, ok := ctx.Value(writerKey{}).(Writer)
 	if !ok {
-		return &noopWriter{}
+		return recording(ctx, &noopWriter{})
 	}
 	return s
 }
@@ -64,7 +64,7 @@ func Run(ctx context.Context, pf progressFunc) (string, error) {
 		return w.Start(context.Background())
 	})
 
-	ctx = WithContextWriter(ctx, w)
+	ctx = WithContextWriter(ctx, recording(ctx, w))
 
 	eg.Go(func() error {
 		defer w.Stop()
@@ -17,15 +17,21 @@
 package e2e
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"gotest.tools/assert"
 	"gotest.tools/v3/icmd"
+	"gotest.tools/v3/poll"
 
 	. "github.com/docker/compose-cli/tests/framework"
 )
@@ -66,20 +72,22 @@ func TestLocalComposeUp(t *testing.T) {
 	})
 
 	t.Run("check compose labels", func(t *testing.T) {
+		// default mode, container names use the same `_` separator as in compatibility mode
 		res := c.RunDockerCmd("inspect", projectName+"_web_1")
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.container-number": "1"`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.project": "compose-e2e-demo"`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.oneoff": "False",`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.config-hash":`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.project.config_files": "./fixtures/sentences/docker-compose.yaml"`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.project.working_dir":`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.service": "web"`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.version":`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.container-number": "1"`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.project": "compose-e2e-demo"`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.oneoff": "False",`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.config-hash":`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.project.config_files": "./fixtures/sentences/docker-compose.yaml"`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.project.working_dir":`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.service": "web"`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.version":`})
 
 		res = c.RunDockerCmd("network", "inspect", projectName+"_default")
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.network": "default"`})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.project": `})
-		res.Assert(t, icmd.Expected{Out: `"com.docker.compose.version": `})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.network": "default"`})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.project": `})
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.version": `})
+		res.Assert(t, icmd.Expected{Out: `"my-network-label": "test"`})
 	})
 
 	t.Run("check user labels", func(t *testing.T) {
@@ -88,6 +96,29 @@ func TestLocalComposeUp(t *testing.T) {
 
 	})
 
+	t.Run("logs of a service", func(t *testing.T) {
+		poll.WaitOn(t, func(l poll.LogT) poll.Result {
+			res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName)
+			if strings.Contains(res.Stdout(), "db_1 ") {
+				return poll.Success()
+			}
+			return poll.Continue("db logs not collected: %s", res.Combined())
+		}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+		res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName, "web")
+		for _, line := range Lines(res.Stdout()) {
+			assert.Assert(t, line == "" || strings.HasPrefix(line, "web_1 "), res.Stdout())
+		}
+
+		res = c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName, "--timestamps", "--tail", "1", "db")
+		lines := Lines(res.Stdout())
+		assert.Equal(t, len(lines), 1, res.Stdout())
+		fields := strings.Fields(lines[0])
+		assert.Assert(t, len(fields) > 2, lines[0])
+		_, err := time.Parse(time.RFC3339Nano, fields[2])
+		assert.NilError(t, err, lines[0])
+	})
+
 	t.Run("down", func(t *testing.T) {
 		_ = c.RunDockerCmd("compose", "down", "--project-name", projectName)
 	})
@@ -103,12 +134,42 @@ func TestLocalComposeUp(t *testing.T) {
 	})
 }
 
+func TestLocalComposeV1Invocation(t *testing.T) {
+	// not parallel, sentences fixture binds port 80 as TestLocalComposeUp does
+	c := NewE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-v1"
+
+	dockerCompose := filepath.Join(c.ConfigDir, "docker-compose")
+	err := os.Symlink(filepath.Join(binDir, DockerExecutableName), dockerCompose)
+	assert.NilError(t, err)
+
+	t.Run("up", func(t *testing.T) {
+		res := icmd.RunCmd(c.NewCmd(dockerCompose, "-f", "./fixtures/sentences/docker-compose.yaml", "-p", projectName, "up", "-d"))
+		res.Assert(t, icmd.Success)
+	})
+
+	t.Run("check running project", func(t *testing.T) {
+		res := icmd.RunCmd(c.NewCmd(dockerCompose, "-p", projectName, "ps"))
+		res.Assert(t, icmd.Expected{Out: `web`})
+
+		res = c.RunDockerCmd("inspect", projectName+"_web_1")
+		res.Assert(t, icmd.Expected{Out: `"` + ComposeLabelPrefix + `.project": "compose-e2e-v1"`})
+	})
+
+	t.Run("down", func(t *testing.T) {
+		res := icmd.RunCmd(c.NewCmd(dockerCompose, "-p", projectName, "down"))
+		res.Assert(t, icmd.Success)
+	})
+}
+
 func TestLocalComposeBuild(t *testing.T) {
 	c := NewParallelE2eCLI(t, binDir)
 
 	t.Run("build named and unnamed images", func(t *testing.T) {
 		//ensure local test run does not reuse previously build image
 		c.RunDockerOrExitError("rmi", "build-test_nginx")
+		c.RunDockerOrExitError("rmi", "build-test_nginx2")
 		c.RunDockerOrExitError("rmi", "custom-nginx")
 
 		res := c.RunDockerCmd("compose", "build", "--workdir", "fixtures/build-test")
@@ -118,8 +179,21 @@ func TestLocalComposeBuild(t *testing.T) {
 		c.RunDockerCmd("image", "inspect", "custom-nginx")
 	})
 
+	t.Run("build named image with canonical tag", func(t *testing.T) {
+		res := c.RunDockerCmd("image", "inspect", "custom-nginx", "--format", "{{ .Id }}")
+		id := strings.TrimSpace(res.Stdout())
+		res = c.RunDockerCmd("image", "inspect", "build-test_nginx2", "--format", "{{ .Id }}")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), id)
+
+		res = c.RunDockerCmd("image", "inspect", "custom-nginx", "--format", `{{ index .Config.Labels "`+ComposeLabelPrefix+`.image.primary" }}`)
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "custom-nginx")
+		res = c.RunDockerCmd("image", "inspect", "build-test_nginx", "--format", `{{ index .Config.Labels "`+ComposeLabelPrefix+`.image.primary" }}`)
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "build-test_nginx")
+	})
+
 	t.Run("build as part of up", func(t *testing.T) {
 		c.RunDockerOrExitError("rmi", "build-test_nginx")
+		c.RunDockerOrExitError("rmi", "build-test_nginx2")
 		c.RunDockerOrExitError("rmi", "custom-nginx")
 
 		res := c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/build-test")
@@ -142,12 +216,110 @@ func TestLocalComposeBuild(t *testing.T) {
 		assert.Assert(t, !strings.Contains(res.Stdout(), "COPY static /usr/share/nginx/html"), res.Stdout())
 	})
 
+	t.Run("build with additional tags", func(t *testing.T) {
+		c.RunDockerCmd("compose", "build", "--workdir", "fixtures/build-test", "--build-tag", "latest", "--build-tag", "e2e-sha")
+		t.Cleanup(func() {
+			c.RunDockerOrExitError("rmi", "build-test_nginx:e2e-sha")
+			c.RunDockerOrExitError("rmi", "custom-nginx:e2e-sha")
+		})
+
+		for _, image := range []string{"build-test_nginx", "custom-nginx"} {
+			res := c.RunDockerCmd("image", "inspect", image, "--format", "{{ .Id }}")
+			id := strings.TrimSpace(res.Stdout())
+			for _, tag := range []string{"latest", "e2e-sha"} {
+				res = c.RunDockerCmd("image", "inspect", image+":"+tag, "--format", "{{ .Id }}")
+				assert.Equal(t, strings.TrimSpace(res.Stdout()), id)
+			}
+		}
+	})
+
+	t.Run("build with labels", func(t *testing.T) {
+		c.RunDockerCmd("compose", "build", "--workdir", "fixtures/build-test", "--label", "org.opencontainers.image.revision=e2e-sha")
+
+		labels := func(image string) map[string]string {
+			res := c.RunDockerCmd("image", "inspect", image, "--format", "{{ json .Config.Labels }}")
+			var labels map[string]string
+			assert.NilError(t, json.Unmarshal([]byte(res.Stdout()), &labels))
+			return labels
+		}
+		nginx := labels("build-test_nginx")
+		assert.Equal(t, nginx["org.opencontainers.image.source"], "https://github.com/docker/compose-cli")
+		assert.Equal(t, nginx["org.opencontainers.image.revision"], "e2e-sha")
+		assert.Equal(t, nginx[ComposeLabelPrefix+".project"], "build-test")
+		assert.Equal(t, nginx[ComposeLabelPrefix+".service"], "nginx")
+		assert.Assert(t, nginx[ComposeLabelPrefix+".version"] != "")
+
+		custom := labels("custom-nginx")
+		assert.Equal(t, custom["org.opencontainers.image.revision"], "e2e-sha")
+		assert.Equal(t, custom[ComposeLabelPrefix+".service"], "nginx2")
+		_, ok := custom["org.opencontainers.image.source"]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("quiet build", func(t *testing.T) {
+		c.RunDockerCmd("compose", "down", "--workdir", "fixtures/build-test")
+		c.RunDockerOrExitError("rmi", "build-test_nginx")
+		c.RunDockerOrExitError("rmi", "custom-nginx")
+
+		res := c.RunDockerCmd("compose", "alpha", "up", "-d", "--quiet-build", "--workdir", "fixtures/build-test")
+		assert.Assert(t, !strings.Contains(res.Combined(), "COPY static /usr/share/nginx/html"), res.Combined())
+		res.Assert(t, icmd.Expected{Out: "Built service nginx"})
+		res.Assert(t, icmd.Expected{Out: "Built service nginx2"})
+
+		res = c.RunDockerOrExitError("compose", "alpha", "up", "-d", "--quiet-build", "--workdir", "fixtures/build-failing")
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: "build-step-failed"})
+	})
+
+	t.Run("build logs to files", func(t *testing.T) {
+		logDir := filepath.Join(t.TempDir(), "logs")
+		res := c.RunDockerCmd("compose", "build", "--build-log-dir", logDir, "--workdir", "fixtures/build-test")
+		assert.Assert(t, !strings.Contains(res.Combined(), "COPY static /usr/share/nginx/html"), res.Combined())
+		res.Assert(t, icmd.Expected{Out: "Built service nginx"})
+		content, err := ioutil.ReadFile(filepath.Join(logDir, "nginx.log"))
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(content), "COPY static /usr/share/nginx/html"), string(content))
+
+		res = c.RunDockerOrExitError("compose", "build", "--build-log-dir", logDir, "--workdir", "fixtures/build-failing")
+		res.Assert(t, icmd.Expected{ExitCode: 1, Out: "Failed to build service failing, see " + filepath.Join(logDir, "failing.log")})
+		content, err = ioutil.ReadFile(filepath.Join(logDir, "failing.log"))
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(content), "build-step-failed"), string(content))
+	})
+
+	t.Run("pull skips buildable services", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "pull", "--ignore-buildable", "--workdir", "fixtures/build-test")
+		res.Assert(t, icmd.Expected{Out: "Skipped: nginx (no image to pull), nginx2 (image gets built)"})
+		assert.Assert(t, !strings.Contains(res.Stdout(), "Failed"), res.Stdout())
+	})
+
 	t.Run("cleanup build project", func(t *testing.T) {
 		c.RunDockerCmd("compose", "down", "--workdir", "fixtures/build-test")
 		c.RunDockerCmd("rmi", "build-test_nginx")
+		c.RunDockerCmd("rmi", "build-test_nginx2")
 		c.RunDockerCmd("rmi", "custom-nginx")
 	})
 }
+func TestLocalComposeExec(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-exec"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/exec", "--project-name", projectName)
+
+	t.Run("exec without a terminal", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "exec", "--workdir", "fixtures/exec", "--project-name", projectName, "app", "echo", "hello")
+		res.Assert(t, icmd.Expected{Out: "hello"})
+	})
+
+	t.Run("exec exit code", func(t *testing.T) {
+		res := c.RunDockerOrExitError("compose", "exec", "-T", "--workdir", "fixtures/exec", "--project-name", projectName, "app", "sh", "-c", "exit 3")
+		res.Assert(t, icmd.Expected{ExitCode: 3})
+	})
+}
+
 func TestLocalComposeVolume(t *testing.T) {
 	c := NewParallelE2eCLI(t, binDir)
 
@@ -171,8 +343,1239 @@ func TestLocalComposeVolume(t *testing.T) {
 		res.Assert(t, icmd.Expected{Out: `[{"Type":"volume","Source":"compose-e2e-volume_staticVol","Target":"/usr/share/nginx/html","ReadOnly":true},{"Type":"volume","Target":"/usr/src/app/node_modules"}]`})
 	})
 
+	t.Run("missing bind sources", func(t *testing.T) {
+		res := c.RunDockerOrExitError("compose", "up", "-d", "-f", "./fixtures/volume-test/missing-binds.yml", "--project-name", projectName+"-missing")
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: "2 invalid bind mount(s):"})
+		res.Assert(t, icmd.Expected{Err: "fixtures/volume-test/statc doesn't exist"})
+		res.Assert(t, icmd.Expected{Err: "fixtures/volume-test/nginx.confg doesn't exist"})
+		res = c.RunDockerCmd("ps", "--all", "--filter", "label="+ComposeLabelPrefix+".project="+projectName+"-missing", "--quiet")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "")
+	})
+
 	t.Run("cleanup volume project", func(t *testing.T) {
 		c.RunDockerCmd("compose", "down", "--project-name", projectName)
 		c.RunDockerCmd("volume", "rm", projectName+"_staticVol")
 	})
 }
+
+func TestLocalComposeDotEnv(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-environment"
+
+	t.Run("up", func(t *testing.T) {
+		c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/environment", "--project-name", projectName)
+	})
+
+	t.Run("interpolated environment", func(t *testing.T) {
+		res := c.RunDockerCmd("exec", projectName+"_app_1", "printenv", "URL")
+		res.Assert(t, icmd.Expected{Out: "http://backend:8080"})
+	})
+
+	t.Run("interpolated labels", func(t *testing.T) {
+		res := c.RunDockerCmd("inspect", projectName+"_app_1", "--format", "{{ index .Config.Labels \"upstream\" }}")
+		res.Assert(t, icmd.Expected{Out: "backend"})
+	})
+
+	t.Run("down", func(t *testing.T) {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+}
+
+func TestLocalComposeDownTimeoutZero(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-down-timeout"
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/stop-signal", "--project-name", projectName)
+
+	start := time.Now()
+	c.RunDockerCmd("compose", "down", "-t", "0", "--project-name", projectName)
+	assert.Assert(t, time.Since(start) < 5*time.Second, "down took %s", time.Since(start))
+
+	res := c.RunDockerCmd("ps", "--all", "--filter", "label="+ComposeLabelPrefix+".project="+projectName, "--quiet")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "")
+}
+
+func TestLocalComposeRunForwardsSignal(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-run-signal"
+
+	res := icmd.StartCmd(c.NewDockerCmd("compose", "run", "--workdir", "fixtures/run-signal", "--project-name", projectName, "trap"))
+	assert.NilError(t, res.Error)
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		if strings.Contains(res.Stdout(), "started") {
+			return poll.Success()
+		}
+		return poll.Continue("one-off container did not start yet: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(30*time.Second))
+
+	assert.NilError(t, res.Cmd.Process.Signal(syscall.SIGINT))
+	res = icmd.WaitOnCmd(30*time.Second, res)
+	res.Assert(t, icmd.Expected{ExitCode: 3, Out: "interrupted"})
+
+	res = c.RunDockerCmd("ps", "--all", "--filter", "label="+ComposeLabelPrefix+".project="+projectName, "--quiet")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "")
+
+	c.RunDockerCmd("compose", "down", "--project-name", projectName)
+}
+
+func TestLocalComposeRestartFailed(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-restart-failed"
+	startedAt := func(container string) string {
+		res := c.RunDockerCmd("inspect", projectName+"_"+container+"_1", "--format", "{{ .State.StartedAt }}")
+		return strings.TrimSpace(res.Stdout())
+	}
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/restart-failed", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		res := c.RunDockerCmd("inspect", projectName+"_failing_1", "--format", "{{ .State.Status }}")
+		if strings.TrimSpace(res.Stdout()) == "exited" {
+			return poll.Success()
+		}
+		return poll.Continue("failing container did not exit yet: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+	failingStartedAt := startedAt("failing")
+	healthyStartedAt := startedAt("healthy")
+
+	t.Run("dry run", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "alpha", "restart-failed", "--project-name", projectName, "--dry-run")
+		res.Assert(t, icmd.Expected{Out: "Would restart " + projectName + "_failing_1"})
+		assert.Assert(t, !strings.Contains(res.Stdout(), "healthy"), res.Stdout())
+		assert.Equal(t, startedAt("failing"), failingStartedAt)
+	})
+
+	t.Run("restart failed only", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "alpha", "restart-failed", "--project-name", projectName)
+		res.Assert(t, icmd.Expected{Out: "Restarted " + projectName + "_failing_1"})
+		assert.Assert(t, !strings.Contains(res.Stdout(), "healthy"), res.Stdout())
+		assert.Assert(t, startedAt("failing") != failingStartedAt)
+		assert.Equal(t, startedAt("healthy"), healthyStartedAt)
+	})
+}
+
+func TestLocalComposePidsLimit(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-pids-limit"
+
+	t.Run("up", func(t *testing.T) {
+		c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/pids-limit", "--project-name", projectName)
+	})
+
+	t.Run("pids limit applied", func(t *testing.T) {
+		res := c.RunDockerCmd("inspect", projectName+"_limited_1", "--format", "{{ .HostConfig.PidsLimit }}")
+		res.Assert(t, icmd.Expected{Out: "10"})
+	})
+
+	t.Run("fork beyond limit fails", func(t *testing.T) {
+		res := icmd.RunCmd(c.NewDockerCmd("exec", projectName+"_limited_1", "sh", "-c", "for i in $(seq 20); do sleep 30 & done; wait"))
+		assert.Assert(t, strings.Contains(res.Stderr(), "can't fork"), res.Combined())
+	})
+
+	t.Run("down", func(t *testing.T) {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+}
+
+func TestLocalComposeLogsSinceContainerStart(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-logs"
+
+	t.Run("up", func(t *testing.T) {
+		c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/logs-test", "--project-name", projectName)
+	})
+
+	t.Run("restart container", func(t *testing.T) {
+		poll.WaitOn(t, func(l poll.LogT) poll.Result {
+			res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName)
+			if strings.Contains(res.Stdout(), "container started") {
+				return poll.Success()
+			}
+			return poll.Continue("container did not log yet: %s", res.Combined())
+		}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+		c.RunDockerCmd("restart", "-t", "0", projectName+"_ping_1")
+	})
+
+	t.Run("logs since container start", func(t *testing.T) {
+		poll.WaitOn(t, func(l poll.LogT) poll.Result {
+			res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName)
+			if strings.Count(res.Stdout(), "container started") == 2 {
+				return poll.Success()
+			}
+			return poll.Continue("restarted container did not log yet: %s", res.Combined())
+		}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+		res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName, "--since", "container-start")
+		assert.Equal(t, strings.Count(res.Stdout(), "container started"), 1, res.Stdout())
+	})
+
+	t.Run("down", func(t *testing.T) {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+}
+
+func TestLocalComposeHealth(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-health"
+
+	t.Run("up", func(t *testing.T) {
+		c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/health", "--project-name", projectName)
+	})
+
+	t.Run("failing healthcheck output", func(t *testing.T) {
+		poll.WaitOn(t, func(l poll.LogT) poll.Result {
+			res := c.RunDockerOrExitError("compose", "alpha", "health", "failing", "--project-name", projectName)
+			if strings.Contains(res.Stdout(), "healthcheck failure") {
+				return poll.Success()
+			}
+			return poll.Continue("healthcheck output not reported yet: %s", res.Combined())
+		}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+		res := c.RunDockerCmd("compose", "alpha", "health", "failing", "--project-name", projectName, "--format", "json")
+		res.Assert(t, icmd.Expected{Out: `"ExitCode":1`})
+	})
+
+	t.Run("wait healthy", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "alpha", "wait-healthy", "healthy", "--project-name", projectName, "--timeout", "30s")
+		res.Assert(t, icmd.Expected{Out: "healthy"})
+	})
+
+	t.Run("wait healthy timeout", func(t *testing.T) {
+		res := c.RunDockerOrExitError("compose", "alpha", "wait-healthy", "--project-name", projectName, "--timeout", "5s")
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: `service "failing" is unhealthy`})
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: projectName + "_failing_1 (unhealthy):"})
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: "[exit 1] healthcheck failure"})
+	})
+
+	t.Run("wait from labels", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "wait", "healthy", "--health", "--project-name", projectName, "--timeout", "30s")
+		res.Assert(t, icmd.Expected{Out: `service "healthy" is ready`})
+
+		res = c.RunDockerOrExitError("compose", "wait", "--health", "--project-name", projectName, "--timeout", "5s")
+		res.Assert(t, icmd.Expected{ExitCode: 1, Out: `service "failing" is unhealthy, `})
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: `service "failing" is unhealthy`})
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: projectName + "_failing_1 (unhealthy):"})
+
+		res = c.RunDockerCmd("compose", "wait", "--project-name", projectName, "--timeout", "5s")
+		res.Assert(t, icmd.Expected{Out: `service "failing" is ready`})
+	})
+
+	t.Run("down", func(t *testing.T) {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+}
+
+func TestLocalComposeProfilesOrphans(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-profiles-orphans"
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/profiles", "--project-name", projectName)
+	res := c.RunDockerCmd("compose", "ps", "--project-name", projectName)
+	assert.Assert(t, !strings.Contains(res.Stdout(), projectName+"_debug_1"), res.Stdout())
+
+	c.RunDockerCmd("compose", "up", "-d", "--profile", "debug", "--workdir", "fixtures/profiles", "--project-name", projectName)
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/profiles", "--project-name", projectName)
+	res = c.RunDockerCmd("compose", "ps", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: projectName + "_debug_1"})
+
+	res = c.RunDockerCmd("compose", "ps", "--orphans", "--workdir", "fixtures/profiles", "--project-name", projectName)
+	assert.Assert(t, !strings.Contains(res.Stdout(), projectName+"_debug_1"), res.Stdout())
+
+	c.RunDockerCmd("compose", "run", "--remove-orphans", "--workdir", "fixtures/profiles", "--project-name", projectName, "web", "true")
+	res = c.RunDockerCmd("compose", "ps", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: projectName + "_debug_1"})
+}
+
+func TestLocalComposeProfiles(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-profiles"
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("compose", "down", "--project-name", projectName)
+	})
+
+	res := c.RunDockerCmd("compose", "config", "--profiles", "--workdir", "fixtures/profiles")
+	res.Assert(t, icmd.Expected{Out: "debug"})
+
+	res = c.RunDockerOrExitError("compose", "up", "-d", "--profile", "bogus", "--workdir", "fixtures/profiles", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{ExitCode: 1, Err: `no such profile "bogus", valid profiles are: debug`})
+
+	c.RunDockerCmd("compose", "up", "-d", "--profile", "debug", "--workdir", "fixtures/profiles", "--project-name", projectName)
+	res = c.RunDockerCmd("compose", "ps", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: projectName + "_debug_1"})
+}
+
+func TestLocalComposeHealthcheckTestForms(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-health-forms"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/health-forms", "--project-name", projectName)
+
+	t.Run("string, CMD and CMD-SHELL get healthy", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "alpha", "wait-healthy", "shell", "exec", "cmdshell", "--project-name", projectName, "--timeout", "30s")
+		res.Assert(t, icmd.Expected{Out: "healthy"})
+
+		res = c.RunDockerCmd("inspect", projectName+"_shell_1", "--format", "{{json .Config.Healthcheck.Test}}")
+		res.Assert(t, icmd.Expected{Out: `["CMD-SHELL","wget -q -O /dev/null localhost || exit 1"]`})
+		res = c.RunDockerCmd("inspect", projectName+"_exec_1", "--format", "{{json .Config.Healthcheck.Test}}")
+		res.Assert(t, icmd.Expected{Out: `["CMD","wget","-q","-O","/dev/null","localhost"]`})
+	})
+
+	t.Run("NONE disables healthcheck", func(t *testing.T) {
+		res := c.RunDockerCmd("inspect", projectName+"_none_1", "--format", "{{json .State.Health}}")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "null")
+	})
+}
+
+func TestLocalComposePlugin(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	pluginDir := t.TempDir()
+	icmd.RunCommand("go", "build", "-o", filepath.Join(pluginDir, "docker-compose-summary"), "../../example/compose-plugin").Assert(t, icmd.Success)
+	withPlugins := func(args ...string) *icmd.Result {
+		cmd := c.NewDockerCmd(args...)
+		cmd.Env = append(cmd.Env, "PATH="+pluginDir+string(os.PathListSeparator)+c.PathEnvVar())
+		return icmd.RunCmd(cmd)
+	}
+
+	t.Run("plugin gets resolved project", func(t *testing.T) {
+		res := withPlugins("compose", "summary", "--workdir", "fixtures/health", "--project-name", "compose-e2e-plugin", "--verbose")
+		res.Assert(t, icmd.Expected{Out: "Project compose-e2e-plugin in context default"})
+		res.Assert(t, icmd.Expected{Out: "Arguments: --verbose"})
+		res.Assert(t, icmd.Expected{Out: "failing\tnginx:alpine"})
+		res.Assert(t, icmd.Expected{Out: "healthy\tnginx:alpine"})
+	})
+
+	t.Run("unknown command lists plugins", func(t *testing.T) {
+		res := withPlugins("compose", "summarize")
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: `unknown command "summarize" for "docker compose", available plugins:`})
+		res.Assert(t, icmd.Expected{ExitCode: 1, Err: filepath.Join(pluginDir, "docker-compose-summary")})
+	})
+}
+
+func TestLocalComposeImageVolume(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-image-volume"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		res := c.RunDockerCmd("volume", "ls", "--quiet", "--filter", "label="+ComposeLabelPrefix+".project="+projectName)
+		for _, volume := range Lines(res.Stdout()) {
+			c.RunDockerOrExitError("volume", "rm", volume)
+		}
+	})
+
+	t.Run("image files appear in container", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "run", "--workdir", "fixtures/image-volume", "--project-name", projectName, "reader")
+		res.Assert(t, icmd.Expected{Out: "nginx.conf"})
+	})
+
+	t.Run("image volume is read-only", func(t *testing.T) {
+		res := c.RunDockerOrExitError("compose", "run", "--workdir", "fixtures/image-volume", "--project-name", projectName, "reader", "touch", "/nginx/written")
+		assert.Assert(t, res.ExitCode != 0)
+		assert.Assert(t, strings.Contains(res.Combined(), "Read-only file system"), res.Combined())
+	})
+}
+
+func TestLocalComposeCompatibility(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-compatibility"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	t.Run("default mode ignores deploy resources", func(t *testing.T) {
+		c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/compatibility", "--project-name", projectName)
+		res := c.RunDockerCmd("inspect", projectName+"_web_1", "--format", "{{ .HostConfig.Memory }} {{ .HostConfig.NanoCpus }}")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "0 0")
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	t.Run("compatibility flag", func(t *testing.T) {
+		c.RunDockerCmd("compose", "--compatibility", "up", "-d", "--workdir", "fixtures/compatibility", "--project-name", projectName)
+		// compatibility mode, container names use the `_` separator
+		res := c.RunDockerCmd("inspect", projectName+"_web_1", "--format", "{{ .HostConfig.Memory }} {{ .HostConfig.NanoCpus }}")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "67108864 500000000")
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	t.Run("compatibility env var", func(t *testing.T) {
+		cmd := c.NewDockerCmd("compose", "up", "-d", "--workdir", "fixtures/compatibility", "--project-name", projectName)
+		cmd.Env = append(cmd.Env, "COMPOSE_COMPATIBILITY=true")
+		icmd.RunCmd(cmd).Assert(t, icmd.Success)
+		res := c.RunDockerCmd("inspect", projectName+"_web_1", "--format", "{{ .HostConfig.Memory }} {{ .HostConfig.NanoCpus }}")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "67108864 500000000")
+	})
+}
+
+func TestLocalComposeFractionalCPUs(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-fractional-cpus"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/fractional-cpus", "--project-name", projectName)
+	for _, service := range []string{"web", "worker"} {
+		res := c.RunDockerCmd("inspect", projectName+"_"+service+"_1", "--format", "{{ .HostConfig.NanoCpus }}")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "1500000000", service)
+	}
+}
+
+func TestLocalComposeImageWorkingDir(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-image-workdir"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerOrExitError("rmi", "compose-e2e-image-workdir")
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/image-workdir", "--project-name", projectName)
+
+	t.Run("exec", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "exec", "-T", "--workdir", "fixtures/image-workdir", "--project-name", projectName, "app", "pwd")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "/app")
+	})
+
+	t.Run("run", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "run", "--workdir", "fixtures/image-workdir", "--project-name", projectName, "app", "pwd")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "/app")
+	})
+
+	t.Run("working dir overridden", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "exec", "-T", "-w", "/tmp", "--workdir", "fixtures/image-workdir", "--project-name", projectName, "app", "pwd")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "/tmp")
+
+		res = c.RunDockerCmd("compose", "run", "-w", "/tmp", "-u", "nobody", "--workdir", "fixtures/image-workdir", "--project-name", projectName, "app", "sh", "-c", "pwd && whoami")
+		assert.Equal(t, strings.TrimSpace(res.Stdout()), "/tmp\nnobody")
+	})
+}
+
+func TestLocalComposeUpOnFailure(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-up-on-failure"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	services := func() []string {
+		res := c.RunDockerCmd("ps", "--all", "--filter", "label="+ComposeLabelPrefix+".project="+projectName, "--format", `{{ index .Labels "`+ComposeLabelPrefix+`.service" }}`)
+		names := strings.Fields(res.Stdout())
+		sort.Strings(names)
+		return names
+	}
+
+	for _, test := range []struct {
+		policy   string
+		services []string
+	}{
+		{policy: "rollback", services: []string{"web"}},
+		{policy: "leave", services: []string{"broken", "web"}},
+		{policy: "down", services: []string{}},
+	} {
+		t.Run(test.policy, func(t *testing.T) {
+			// web already runs, so only broken gets created by the failing up
+			c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/up-on-failure", "--project-name", projectName, "web")
+
+			res := c.RunDockerOrExitError("compose", "alpha", "up", "-d", "--workdir", "fixtures/up-on-failure", "--project-name", projectName, "--on-failure", test.policy)
+			res.Assert(t, icmd.Expected{ExitCode: 1, Err: "(--on-failure=" + test.policy + ")"})
+			assert.DeepEqual(t, services(), test.services)
+
+			c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		})
+	}
+}
+
+func TestLocalComposeFormatJSON(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-format-json"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	type summary struct {
+		Status    string
+		Error     string
+		Resources []struct {
+			Kind   string
+			Name   string
+			Action string
+			Error  string
+		}
+	}
+	parse := func(t *testing.T, out string) summary {
+		var s summary
+		assert.NilError(t, json.Unmarshal([]byte(out), &s), out)
+		return s
+	}
+
+	t.Run("up", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "up", "-d", "--format", "json", "--workdir", "fixtures/up-on-failure", "--project-name", projectName, "web")
+		s := parse(t, res.Stdout())
+		assert.Equal(t, s.Status, "success")
+		var started bool
+		for _, r := range s.Resources {
+			if r.Kind == "container" && strings.Contains(r.Name, "web") && r.Action == "Started" {
+				started = true
+			}
+		}
+		assert.Assert(t, started, res.Stdout())
+	})
+
+	t.Run("up failure", func(t *testing.T) {
+		res := c.RunDockerOrExitError("compose", "up", "-d", "--format", "json", "--workdir", "fixtures/up-on-failure", "--project-name", projectName)
+		assert.Equal(t, res.ExitCode, 1)
+		s := parse(t, res.Stdout())
+		assert.Equal(t, s.Status, "failure")
+		assert.Assert(t, strings.Contains(s.Error, "does-not-exist"), s.Error)
+	})
+
+	t.Run("down", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "down", "--format", "json", "--project-name", projectName)
+		s := parse(t, res.Stdout())
+		assert.Equal(t, s.Status, "success")
+		removed := 0
+		for _, r := range s.Resources {
+			if r.Kind == "container" && r.Action == "Removed" {
+				removed++
+			}
+		}
+		assert.Equal(t, removed, 2, res.Stdout())
+	})
+}
+
+func TestLocalComposeVolumeName(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-volume-name"
+	res := c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/volume-name", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerCmd("volume", "rm", "compose-e2e-shared-cache")
+	})
+	res.Assert(t, icmd.Expected{Err: "might collide with another project"})
+
+	res = c.RunDockerCmd("volume", "inspect", "compose-e2e-shared-cache", "--format", `{{ index .Labels "`+ComposeLabelPrefix+`.project" }}`)
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), projectName)
+
+	res = c.RunDockerOrExitError("volume", "inspect", projectName+"_cache")
+	assert.Assert(t, res.ExitCode != 0)
+
+	res = c.RunDockerCmd("inspect", projectName+"_web_1", "--format", "{{ range .Mounts }}{{ .Name }}{{ end }}")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "compose-e2e-shared-cache")
+}
+
+func TestLocalComposeMemoryReservation(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-reservations"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/reservations", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	res := c.RunDockerCmd("inspect", projectName+"_web_1", "--format", "{{ .HostConfig.MemoryReservation }}")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "33554432")
+}
+
+func TestLocalComposeVolumesFrom(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-volumes-from"
+	res := c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/volumes-from", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerCmd("volume", "rm", projectName+"_data")
+	})
+	res.Assert(t, icmd.Expected{Err: "volumes_from is deprecated"})
+
+	res = c.RunDockerCmd("inspect", projectName+"_app_1", "--format", "{{ range .Mounts }}{{ .Name }} {{ .Destination }} {{ .RW }}{{ end }}")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), projectName+"_data /data false")
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		res := c.RunDockerOrExitError("exec", projectName+"_app_1", "cat", "/data/file")
+		if strings.TrimSpace(res.Stdout()) == "stored" {
+			return poll.Success()
+		}
+		return poll.Continue("shared volume content not visible yet: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+}
+
+func TestLocalComposeDependsOnRestart(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-depends-on-restart"
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/depends-on-restart", "--project-name", projectName)
+	inspect := func(container string) string {
+		res := c.RunDockerCmd("inspect", projectName+"_"+container+"_1", "--format", "{{ .Id }} {{ .State.StartedAt }}")
+		return strings.TrimSpace(res.Stdout())
+	}
+	api, worker := inspect("api"), inspect("worker")
+
+	// changing db environment recreates it
+	cmd := c.NewDockerCmd("compose", "up", "-d", "--workdir", "fixtures/depends-on-restart", "--project-name", projectName)
+	cmd.Env = append(cmd.Env, "DB_VERSION=2")
+	icmd.RunCmd(cmd).Assert(t, icmd.Success)
+
+	restarted := inspect("api")
+	assert.Equal(t, strings.Fields(restarted)[0], strings.Fields(api)[0], "api must be restarted, not recreated")
+	assert.Assert(t, restarted != api, "api must be restarted")
+	assert.Assert(t, strings.Fields(inspect("worker"))[0] != strings.Fields(worker)[0], "worker must be recreated")
+}
+
+func TestLocalComposeDownOrder(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-down-order"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/down-order", "--project-name", projectName)
+
+	res := c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	output := res.Stdout()
+	appRemoved := strings.Index(output, "Container "+projectName+"_app_1 Removed")
+	dbStopping := strings.Index(output, "Container "+projectName+"_db_1 Stopping")
+	assert.Assert(t, appRemoved >= 0 && dbStopping >= 0, output)
+	assert.Assert(t, appRemoved < dbStopping, "app must be removed before db is stopped:\n%s", output)
+}
+
+func TestLocalComposeStopNoDeps(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-stop-no-deps"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/down-order", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+	state := func(service string) string {
+		res := c.RunDockerCmd("inspect", projectName+"_"+service+"_1", "--format", "{{ .State.Status }}")
+		return strings.TrimSpace(res.Stdout())
+	}
+
+	c.RunDockerCmd("compose", "stop", "--no-deps", "--project-name", projectName, "app")
+	assert.Equal(t, state("app"), "exited")
+	assert.Equal(t, state("db"), "running")
+
+	c.RunDockerCmd("compose", "restart", "--no-deps", "--project-name", projectName, "app")
+	assert.Equal(t, state("app"), "running")
+
+	c.RunDockerCmd("compose", "stop", "--no-deps", "--project-name", projectName, "db")
+	assert.Equal(t, state("db"), "exited")
+	assert.Equal(t, state("app"), "running")
+
+	c.RunDockerCmd("compose", "restart", "--project-name", projectName, "db")
+	assert.Equal(t, state("db"), "running")
+
+	// without --no-deps, services depending on the stopped one are stopped first
+	c.RunDockerCmd("compose", "stop", "--project-name", projectName, "db")
+	assert.Equal(t, state("db"), "exited")
+	assert.Equal(t, state("app"), "exited")
+}
+
+func TestLocalComposeSnapshotRestore(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-snapshot"
+	const volumeName = projectName + "_data"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/snapshot", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerOrExitError("volume", "rm", volumeName)
+	})
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		res := c.RunDockerOrExitError("exec", projectName+"_writer_1", "cat", "/data/greeting")
+		if strings.TrimSpace(res.Stdout()) == "snapshot-content" {
+			return poll.Success()
+		}
+		return poll.Continue("volume content not written yet: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+	dir := filepath.Join(t.TempDir(), "snapshot")
+	res := c.RunDockerCmd("compose", "alpha", "snapshot", "--project-name", projectName, "--out", dir)
+	res.Assert(t, icmd.Expected{Out: filepath.Join(dir, volumeName+".tar")})
+
+	c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	c.RunDockerCmd("volume", "rm", volumeName)
+
+	res = c.RunDockerCmd("compose", "alpha", "restore", "--in", dir)
+	res.Assert(t, icmd.Expected{Out: volumeName})
+
+	res = c.RunDockerOrExitError("compose", "alpha", "restore", "--in", dir)
+	res.Assert(t, icmd.Expected{ExitCode: 1, Err: "already exists"})
+
+	res = c.RunDockerCmd("volume", "inspect", volumeName, "--format", `{{ index .Labels "`+ComposeLabelPrefix+`.project" }}`)
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), projectName)
+
+	res = c.RunDockerCmd("run", "--rm", "-v", volumeName+":/data", "busybox", "cat", "/data/greeting")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "snapshot-content")
+}
+
+func TestLocalComposeLogsExited(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-logs-exited"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/logs-exited", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName)
+		if strings.Contains(res.Stdout(), "oneshot_1 (exited)") && strings.Contains(res.Stdout(), "ping-last") {
+			return poll.Success()
+		}
+		return poll.Continue("logs of exited container not collected: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+	res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName, "--tail", "1")
+	output := res.Stdout()
+	assert.Assert(t, strings.Contains(output, "ping-last") && strings.Contains(output, "oneshot-last"), output)
+	assert.Assert(t, !strings.Contains(output, "ping-first") && !strings.Contains(output, "oneshot-first"), output)
+
+	res = c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName, "--no-exited")
+	assert.Assert(t, !strings.Contains(res.Stdout(), "oneshot"), res.Stdout())
+
+	res = c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName, "oneshot")
+	assert.Assert(t, !strings.Contains(res.Stdout(), "ping"), res.Stdout())
+	res.Assert(t, icmd.Expected{Out: "oneshot-last"})
+
+	dir := filepath.Join(t.TempDir(), "logs")
+	res = c.RunDockerCmd("compose", "alpha", "logs", "--project-name", projectName, "--save", dir, "--tail", "1")
+	res.Assert(t, icmd.Expected{Out: "Saved logs of 2 containers to " + dir})
+	for file, expected := range map[string]string{"ping-1.log": "ping-last\n", "oneshot-1.log": "oneshot-last\n"} {
+		content, err := ioutil.ReadFile(filepath.Join(dir, file))
+		assert.NilError(t, err)
+		assert.Equal(t, string(content), expected)
+	}
+
+	t.Run("recreated", func(t *testing.T) {
+		c.RunDockerCmd("compose", "up", "-d", "--force-recreate", "--workdir", "fixtures/logs-exited", "--project-name", projectName, "ping")
+		res := c.RunDockerCmd("compose", "logs", "--follow=false", "--project-name", projectName, "ping")
+		assert.Assert(t, strings.Contains(res.Stdout(), "ping_1 (exited)"), res.Stdout())
+		assert.Equal(t, strings.Count(res.Stdout(), "ping-last"), 2, res.Stdout())
+	})
+}
+
+func TestLocalComposeRunKeepVolumes(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-run-volumes"
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	res := c.RunDockerCmd("compose", "run", "--keep-volumes", "--workdir", "fixtures/run-volumes", "--project-name", projectName, "app", "sh", "-c", "echo kept > /data/file")
+	volume := ""
+	for _, line := range strings.Split(res.Stderr(), "\n") {
+		if strings.HasPrefix(line, "Kept anonymous volume ") {
+			volume = strings.TrimPrefix(line, "Kept anonymous volume ")
+		}
+	}
+	assert.Assert(t, volume != "", res.Combined())
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("volume", "rm", volume)
+	})
+
+	res = c.RunDockerCmd("run", "--rm", "-v", volume+":/data", "busybox", "cat", "/data/file")
+	res.Assert(t, icmd.Expected{Out: "kept"})
+
+	res = c.RunDockerCmd("ps", "--all", "--filter", "label="+ComposeLabelPrefix+".project="+projectName, "--quiet")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "")
+}
+
+func TestLocalComposeNetworkPriority(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-network-priority"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/network-priority", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	res := c.RunDockerCmd("exec", projectName+"_web_1", "ip", "route", "show", "default")
+	res.Assert(t, icmd.Expected{Out: "default via 172.31.20.1"})
+}
+
+func TestLocalComposeTemplateDriver(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	res := c.RunDockerCmd("compose", "config", "--workdir", "fixtures/template-driver")
+	assert.Equal(t, strings.Count(res.Stdout(), "template_driver: golang"), 2, res.Stdout())
+
+	const projectName = "compose-e2e-template-driver"
+	res = c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/template-driver", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+	res.Assert(t, icmd.Expected{Err: `config "app": template_driver "golang" isn't supported by local backend, ignoring`})
+	res.Assert(t, icmd.Expected{Err: `secret "token": template_driver "golang" isn't supported by local backend, ignoring`})
+
+	t.Run("context-check", func(t *testing.T) {
+		res := c.RunDockerOrExitError("compose", "alpha", "context-check", "--workdir", "fixtures/template-driver")
+		res.Assert(t, icmd.Expected{ExitCode: 1, Out: `config "app": template_driver "golang" isn't supported by local backend, ignoring`})
+		res.Assert(t, icmd.Expected{Err: `2 feature(s) unsupported by context "default" (moby)`})
+
+		res = c.RunDockerCmd("compose", "alpha", "context-check", "--ignore-unsupported", "--workdir", "fixtures/template-driver")
+		res.Assert(t, icmd.Expected{Out: `secret "token": template_driver "golang" isn't supported by local backend, ignoring`})
+
+		res = c.RunDockerCmd("compose", "alpha", "context-check", "--workdir", "fixtures/label-file")
+		res.Assert(t, icmd.Expected{Out: `All features used are supported by context "default" (moby)`})
+	})
+}
+
+func TestLocalComposeAttach(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-attach"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/attach", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		res := c.RunDockerCmd("compose", "logs", "--follow=false", "--workdir", "fixtures/attach", "--project-name", projectName)
+		if strings.Contains(res.Stdout(), "web-started") {
+			return poll.Success()
+		}
+		return poll.Continue("web logs not collected: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+	res := c.RunDockerCmd("compose", "logs", "--follow=false", "--workdir", "fixtures/attach", "--project-name", projectName)
+	assert.Assert(t, !strings.Contains(res.Stdout(), "db-started"), res.Stdout())
+
+	res = c.RunDockerCmd("compose", "logs", "--follow=false", "--workdir", "fixtures/attach", "--project-name", projectName, "db")
+	res.Assert(t, icmd.Expected{Out: "db-started"})
+
+	res = c.RunDockerCmd("compose", "ps", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: projectName + "_db_1"})
+}
+
+func TestLocalComposeBuildSecrets(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	c.RunDockerOrExitError("rmi", "compose-e2e-build-secrets")
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("rmi", "compose-e2e-build-secrets")
+	})
+
+	cmd := c.NewDockerCmd("compose", "build", "--workdir", "fixtures/build-secrets")
+	cmd.Env = append(cmd.Env, "COMPOSE_E2E_BUILD_SECRET=s3cr3t-from-env")
+	icmd.RunCmd(cmd).Assert(t, icmd.Success)
+
+	c.RunDockerCmd("run", "--rm", "compose-e2e-build-secrets", "ls", "/secrets-were-available")
+
+	res := c.RunDockerOrExitError("run", "--rm", "compose-e2e-build-secrets", "ls", "/run/secrets")
+	assert.Assert(t, !strings.Contains(res.Stdout(), "secret"), res.Combined())
+}
+
+func TestLocalComposeCommit(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-commit"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/commit", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerOrExitError("rmi", "compose-e2e-commit:debug", "compose-e2e-commit:worker")
+	})
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		res := c.RunDockerOrExitError("exec", projectName+"_web_1", "cat", "/tweak")
+		if strings.TrimSpace(res.Stdout()) == "tweaked" {
+			return poll.Success()
+		}
+		return poll.Continue("container not tweaked yet: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+	res := c.RunDockerCmd("compose", "alpha", "commit", "--project-name", projectName, "web", "compose-e2e-commit:debug",
+		"--message", "debug snapshot", "--change", "ENV DEBUG=1")
+	id := strings.TrimSpace(res.Stdout())
+	assert.Assert(t, strings.HasPrefix(id, "sha256:"), id)
+
+	res = c.RunDockerCmd("image", "inspect", "compose-e2e-commit:debug", "--format", "{{ .Id }} {{ .Comment }}")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), id+" debug snapshot")
+	res = c.RunDockerCmd("run", "--rm", "compose-e2e-commit:debug", "sh", "-c", "cat /tweak && echo $DEBUG")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "tweaked\n1")
+
+	res = c.RunDockerOrExitError("compose", "alpha", "commit", "--project-name", projectName, "worker", "compose-e2e-commit:worker")
+	res.Assert(t, icmd.Expected{ExitCode: 1, Err: `service "worker" has 2 containers`})
+	c.RunDockerCmd("compose", "alpha", "commit", "--project-name", projectName, "--index", "2", "worker", "compose-e2e-commit:worker")
+}
+
+func TestLocalComposeEphemeralPort(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-ephemeral-port"
+	res := c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/ephemeral-port", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+	res.Assert(t, icmd.Expected{Out: "PUBLISHED"})
+
+	res = c.RunDockerCmd("compose", "port", "--project-name", projectName, "web", "80")
+	address := strings.TrimSpace(res.Stdout())
+	assert.Assert(t, strings.Contains(address, ":") && !strings.HasSuffix(address, ":0"), address)
+
+	res = c.RunDockerCmd("port", projectName+"_web_1", "80/tcp")
+	assert.Assert(t, strings.Contains(res.Stdout(), address[strings.LastIndex(address, ":"):]), res.Stdout())
+
+	res = c.RunDockerOrExitError("compose", "port", "--project-name", projectName, "web", "443")
+	res.Assert(t, icmd.Expected{ExitCode: 1, Err: "doesn't publish port 443/tcp"})
+}
+
+func TestLocalComposeRecreateIfChanged(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-recreate-if-changed"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/recreate-if-changed", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+	containerID := func(name string) string {
+		return strings.TrimSpace(c.RunDockerCmd("inspect", projectName+"_"+name+"_1", "--format", "{{ .Id }}").Stdout())
+	}
+	webID, dbID := containerID("web"), containerID("db")
+
+	t.Run("no-op", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "alpha", "recreate-if-changed", "--workdir", "fixtures/recreate-if-changed", "--project-name", projectName)
+		res.Assert(t, icmd.Expected{Out: "No service changed"})
+		assert.Equal(t, containerID("web"), webID)
+		assert.Equal(t, containerID("db"), dbID)
+	})
+
+	t.Run("drifted service", func(t *testing.T) {
+		cmd := c.NewDockerCmd("compose", "alpha", "recreate-if-changed", "--workdir", "fixtures/recreate-if-changed", "--project-name", projectName)
+		cmd.Env = append(cmd.Env, "GREETING=bonjour")
+		res := icmd.RunCmd(cmd)
+		res.Assert(t, icmd.Expected{Out: "web: service configuration changed, recreating " + projectName + "_web_1"})
+		assert.Assert(t, !strings.Contains(res.Stdout(), "db:"), res.Stdout())
+		assert.Assert(t, containerID("web") != webID)
+		assert.Equal(t, containerID("db"), dbID)
+	})
+}
+
+func TestLocalComposeListExited(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-ls-exited"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/ls-exited", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	poll.WaitOn(t, func(l poll.LogT) poll.Result {
+		res := c.RunDockerCmd("compose", "ls", "--filter", "status=exited")
+		if strings.Contains(res.Stdout(), projectName) {
+			return poll.Success()
+		}
+		return poll.Continue("project not listed as exited: %s", res.Combined())
+	}, poll.WithDelay(time.Second), poll.WithTimeout(20*time.Second))
+
+	res := c.RunDockerCmd("compose", "ls")
+	assert.Assert(t, !strings.Contains(res.Stdout(), projectName), res.Stdout())
+	res = c.RunDockerCmd("compose", "ls", "--filter", "status=running")
+	assert.Assert(t, !strings.Contains(res.Stdout(), projectName), res.Stdout())
+
+	res = c.RunDockerCmd("compose", "ls", "--filter", "status=exited", "--format", "json")
+	assert.Assert(t, strings.Contains(res.Stdout(), `"Containers":{"exited":1}`), res.Stdout())
+
+	res = c.RunDockerCmd("compose", "ls", "--prunable", "1ns")
+	res.Assert(t, icmd.Expected{Out: "docker compose down -p " + projectName})
+	res = c.RunDockerCmd("compose", "ls", "--prunable", "24h")
+	assert.Assert(t, !strings.Contains(res.Stdout(), projectName), res.Stdout())
+
+	res = c.RunDockerOrExitError("compose", "ls", "--filter", "status=sleeping")
+	res.Assert(t, icmd.Expected{ExitCode: 1, Err: `invalid filter "status=sleeping"`})
+}
+
+func TestLocalComposeEnvironmentSecrets(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-env-secrets"
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("compose", "down", "--project-name", projectName)
+	})
+
+	t.Run("up", func(t *testing.T) {
+		cmd := c.NewDockerCmd("compose", "up", "-d", "--workdir", "fixtures/env-secrets", "--project-name", projectName,
+			"--secret", "api_key=env:COMPOSE_E2E_API_KEY")
+		cmd.Env = append(cmd.Env, "COMPOSE_E2E_DB_PASS=s3cr3t", "COMPOSE_E2E_API_KEY=from-env")
+		res := icmd.RunCmd(cmd)
+		res.Assert(t, icmd.Success)
+		assert.Assert(t, !strings.Contains(res.Combined(), "s3cr3t"), res.Combined())
+
+		res = c.RunDockerCmd("exec", projectName+"_db_1", "cat", "/run/secrets/db_pass")
+		res.Assert(t, icmd.Expected{Out: "s3cr3t"})
+		res = c.RunDockerCmd("exec", projectName+"_db_1", "cat", "/etc/api_key")
+		res.Assert(t, icmd.Expected{Out: "from-env"})
+	})
+
+	t.Run("convert redacts secrets", func(t *testing.T) {
+		cmd := c.NewDockerCmd("compose", "convert", "--workdir", "fixtures/env-secrets")
+		cmd.Env = append(cmd.Env, "COMPOSE_E2E_DB_PASS=s3cr3t")
+		res := icmd.RunCmd(cmd)
+		res.Assert(t, icmd.Expected{Out: "PASSWORD: '********'"})
+		assert.Assert(t, !strings.Contains(res.Stdout(), "s3cr3t"), res.Stdout())
+
+		cmd = c.NewDockerCmd("compose", "convert", "--show-secrets", "--workdir", "fixtures/env-secrets")
+		cmd.Env = append(cmd.Env, "COMPOSE_E2E_DB_PASS=s3cr3t")
+		icmd.RunCmd(cmd).Assert(t, icmd.Expected{Out: "PASSWORD: s3cr3t"})
+	})
+
+	t.Run("down", func(t *testing.T) {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		_, err := os.Stat(filepath.Join(os.TempDir(), "compose-secrets", projectName))
+		assert.Assert(t, os.IsNotExist(err))
+	})
+}
+
+func TestLocalComposeLabelFile(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-label-file"
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/label-file", "--project-name", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+	})
+
+	res := c.RunDockerCmd("inspect", projectName+"_web_1", "--format", `{{ index .Config.Labels "com.example.tier" }}`)
+	res.Assert(t, icmd.Expected{Out: "backend"})
+	res = c.RunDockerCmd("inspect", projectName+"_web_1", "--format", `{{ index .Config.Labels "com.example.team" }}`)
+	res.Assert(t, icmd.Expected{Out: "inline"})
+}
+
+func TestLocalComposeImagesMismatch(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-images-mismatch"
+	c.RunDockerCmd("pull", "busybox")
+	c.RunDockerCmd("pull", "alpine")
+	c.RunDockerCmd("tag", "busybox", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerOrExitError("rmi", projectName)
+	})
+	c.RunDockerCmd("compose", "up", "-d", "--offline", "--workdir", "fixtures/images-mismatch", "--project-name", projectName)
+
+	res := c.RunDockerCmd("compose", "images", "--workdir", "fixtures/images-mismatch", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: projectName + "_app_1"})
+	assert.Assert(t, strings.Contains(res.Stdout(), "DIGEST"), res.Stdout())
+	res = c.RunDockerCmd("compose", "images", "--filter", "mismatch=true", "--workdir", "fixtures/images-mismatch", "--project-name", projectName)
+	assert.Assert(t, !strings.Contains(res.Stdout(), projectName+"_app_1"), res.Stdout())
+
+	// image is updated, but project isn't brought up again
+	c.RunDockerCmd("tag", "alpine", projectName)
+	res = c.RunDockerCmd("compose", "images", "--filter", "mismatch=true", "--workdir", "fixtures/images-mismatch", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: projectName + "_app_1"})
+	res = c.RunDockerCmd("compose", "ps", "--orphans", "--workdir", "fixtures/images-mismatch", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: "image tag points to a newer image"})
+}
+
+func TestLocalComposeReplaceImageOnTagChange(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-replace-image"
+	c.RunDockerCmd("pull", "busybox")
+	c.RunDockerCmd("pull", "alpine")
+	c.RunDockerCmd("tag", "busybox", projectName)
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerOrExitError("rmi", projectName)
+	})
+	containerID := func() string {
+		res := c.RunDockerCmd("inspect", "--format", "{{.Id}}", projectName+"_app_1")
+		return strings.TrimSpace(res.Stdout())
+	}
+
+	c.RunDockerCmd("compose", "alpha", "up", "-d", "--offline", "--workdir", "fixtures/replace-image", "--project-name", projectName)
+	created := containerID()
+
+	// tag now points to another image, configuration is unchanged
+	c.RunDockerCmd("tag", "alpine", projectName)
+
+	t.Run("tag change is ignored by default", func(t *testing.T) {
+		c.RunDockerCmd("compose", "alpha", "up", "-d", "--offline", "--workdir", "fixtures/replace-image", "--project-name", projectName)
+		assert.Equal(t, containerID(), created)
+	})
+
+	t.Run("tag change recreates container", func(t *testing.T) {
+		c.RunDockerCmd("compose", "alpha", "up", "-d", "--offline", "--replace-image-on-tag-change", "--workdir", "fixtures/replace-image", "--project-name", projectName)
+		assert.Assert(t, containerID() != created)
+
+		res := c.RunDockerCmd("compose", "images", "--filter", "mismatch=true", "--workdir", "fixtures/replace-image", "--project-name", projectName)
+		assert.Assert(t, !strings.Contains(res.Stdout(), projectName+"_app_1"), res.Stdout())
+	})
+}
+
+func TestLocalComposeRunBuild(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-run-build"
+	// fixture source gets edited, so run against a copy of it
+	dir := t.TempDir()
+	files, err := ioutil.ReadDir("fixtures/run-build")
+	assert.NilError(t, err)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(filepath.Join("fixtures/run-build", file.Name()))
+		assert.NilError(t, err)
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, file.Name()), content, 0644))
+	}
+	t.Cleanup(func() {
+		c.RunDockerCmd("compose", "down", "--project-name", projectName)
+		c.RunDockerOrExitError("rmi", projectName)
+	})
+
+	res := c.RunDockerCmd("compose", "run", "--workdir", dir, "--project-name", projectName, "app")
+	res.Assert(t, icmd.Expected{Out: "bonjour\nfirst message"})
+
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "message.txt"), []byte("second message\n"), 0644))
+
+	t.Run("image is reused by default", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "run", "--workdir", dir, "--project-name", projectName, "app")
+		res.Assert(t, icmd.Expected{Out: "first message"})
+	})
+
+	t.Run("run --build rebuilds image", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "run", "--build", "--quiet-pull", "--workdir", dir, "--project-name", projectName, "app")
+		res.Assert(t, icmd.Expected{Out: "bonjour\nsecond message"})
+	})
+
+	t.Run("up --build rebuilds image", func(t *testing.T) {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "message.txt"), []byte("third message\n"), 0644))
+		res := c.RunDockerCmd("compose", "up", "--build", "--workdir", dir, "--project-name", projectName)
+		res.Assert(t, icmd.Expected{Out: "third message"})
+	})
+}
+
+func TestLocalComposeBuildSinceBuild(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-since-build"
+	// fixture source gets edited, so build a copy of it
+	dir := t.TempDir()
+	err := filepath.Walk("fixtures/since-build", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel("fixtures/since-build", path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0755)
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dir, rel), content, 0644)
+	})
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("rmi", projectName+"_front", projectName+"_back")
+	})
+
+	c.RunDockerCmd("compose", "build", "--since-build", "--workdir", dir, "--project-name", projectName)
+
+	t.Run("unchanged services are skipped", func(t *testing.T) {
+		res := c.RunDockerCmd("compose", "build", "--since-build", "--workdir", dir, "--project-name", projectName)
+		res.Assert(t, icmd.Expected{Out: "Service front build context unchanged, skipping build"})
+		res.Assert(t, icmd.Expected{Out: "Service back build context unchanged, skipping build"})
+	})
+
+	t.Run("changed service is rebuilt", func(t *testing.T) {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "front", "message.txt"), []byte("front v2\n"), 0644))
+
+		res := c.RunDockerCmd("compose", "build", "--since-build", "--workdir", dir, "--project-name", projectName)
+		res.Assert(t, icmd.Expected{Out: "Service back build context unchanged, skipping build"})
+		assert.Assert(t, !strings.Contains(res.Stdout(), "Service front build context unchanged"), res.Stdout())
+
+		res = c.RunDockerCmd("run", "--rm", projectName+"_front")
+		res.Assert(t, icmd.Expected{Out: "front v2"})
+	})
+}
+
+func TestLocalComposeDownWithoutImage(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-down-no-image"
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/down-no-image", "--project-name", projectName)
+	c.RunDockerCmd("compose", "stop", "--project-name", projectName)
+	c.RunDockerCmd("rmi", "-f", projectName+"_app")
+
+	res := c.RunDockerCmd("compose", "down", "--rmi", "local", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Err: "Skipped"})
+
+	res = c.RunDockerCmd("ps", "--all", "--filter", "label="+ComposeLabelPrefix+".project="+projectName, "--quiet")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "")
+	res = c.RunDockerCmd("network", "ls", "--filter", "label="+ComposeLabelPrefix+".project="+projectName, "--quiet")
+	assert.Equal(t, strings.TrimSpace(res.Stdout()), "")
+}
+
+func TestLocalComposeOptionalEnvFile(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-optional-env-file"
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/optional-env-file", "--project-name", projectName)
+	res := c.RunDockerCmd("exec", projectName+"_app_1", "printenv", "LEVEL")
+	res.Assert(t, icmd.Expected{Out: "info"})
+
+	res = c.RunDockerOrExitError("compose", "up", "-d", "-f", "./fixtures/optional-env-file/required.yml", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{ExitCode: 1, Err: "local.env not found"})
+}
+
+func TestLocalComposePsNoTrunc(t *testing.T) {
+	c := NewParallelE2eCLI(t, binDir)
+
+	const projectName = "compose-e2e-ps-no-trunc"
+	t.Cleanup(func() {
+		c.RunDockerOrExitError("compose", "down", "--project-name", projectName)
+	})
+
+	c.RunDockerCmd("compose", "up", "-d", "--workdir", "fixtures/logs-test", "--project-name", projectName)
+	res := c.RunDockerCmd("ps", "--no-trunc", "--filter", "label="+ComposeLabelPrefix+".project="+projectName, "--format", "{{.ID}}")
+	id := strings.TrimSpace(res.Stdout())
+	assert.Equal(t, len(id), 64, id)
+
+	res = c.RunDockerCmd("compose", "ps", "--no-trunc", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: id})
+	res.Assert(t, icmd.Expected{Out: "echo container started && sleep 600"})
+
+	res = c.RunDockerCmd("compose", "ps", "--no-trunc", "--format", "json", "--project-name", projectName)
+	res.Assert(t, icmd.Expected{Out: `"ID":"` + id + `"`})
+}
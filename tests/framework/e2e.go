@@ -43,6 +43,10 @@ var (
 	existingExectuableName = "com.docker.cli"
 )
 
+// ComposeLabelPrefix is the label prefix used by compose resources created from e2e tests,
+// so that they do not interfere with stacks run by the developer on the same engine
+const ComposeLabelPrefix = "com.docker.compose.e2e"
+
 func init() {
 	if runtime.GOOS == "windows" {
 		DockerExecutableName = DockerExecutableName + ".exe"
@@ -179,6 +183,7 @@ func (c *E2eCLI) NewCmd(command string, args ...string) icmd.Cmd {
 		"KUBECONFIG=invalid",
 		"TEST_METRICS_SOCKET="+c.MetricsSocket(),
 		"PATH="+c.PathEnvVar(),
+		"COMPOSE_LABEL_PREFIX="+ComposeLabelPrefix,
 	)
 	return icmd.Cmd{
 		Command: append([]string{command}, args...),
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// New creates an http client honoring HTTP_PROXY, HTTPS_PROXY and NO_PROXY, and trusting the CA bundle set by SSL_CERT_FILE
+func New() *http.Client {
+	return &http.Client{
+		Transport: NewTransport(),
+	}
+}
+
+// NewTransport creates an http transport honoring HTTP_PROXY, HTTPS_PROXY and NO_PROXY, and trusting the CA bundle set by SSL_CERT_FILE
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFromEnvironment()
+	if pool := certPool(); pool != nil {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs: pool,
+		}
+	}
+	return transport
+}
+
+// proxyFromEnvironment selects proxy as configured by environment, logging the choice for each request host
+func proxyFromEnvironment() func(*http.Request) (*url.URL, error) {
+	proxyFunc := httpproxy.FromEnvironment().ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		proxy, err := proxyFunc(req.URL)
+		if err != nil {
+			return nil, err
+		}
+		if proxy != nil {
+			logrus.Debugf("using proxy %s for %s", proxy.Redacted(), req.URL.Host)
+		} else {
+			logrus.Debugf("no proxy for %s", req.URL.Host)
+		}
+		return proxy, nil
+	}
+}
+
+// certPool adds the CA bundle set by SSL_CERT_FILE to system ones, as this variable is not honored on all platforms
+func certPool() *x509.CertPool {
+	file := os.Getenv("SSL_CERT_FILE")
+	if file == "" {
+		return nil
+	}
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		logrus.Warnf("failed to read SSL_CERT_FILE %s: %v", file, err)
+		return nil
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		logrus.Warnf("no certificate found in SSL_CERT_FILE %s", file)
+	}
+	return pool
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Compose CLI authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package httpclient
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestClientUsesProxyFromEnvironment(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	defer proxy.Close()
+
+	setenv(t, "HTTP_PROXY", proxy.URL)
+	setenv(t, "NO_PROXY", "")
+
+	res, err := New().Get("http://registry.example.com/v2/")
+	assert.NilError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, string(body), "proxied")
+	assert.DeepEqual(t, proxied, []string{"http://registry.example.com/v2/"})
+}
+
+func TestClientHonorsNoProxy(t *testing.T) {
+	setenv(t, "HTTP_PROXY", "http://proxy.example.com:3128")
+	setenv(t, "NO_PROXY", "registry.example.com")
+
+	req, err := http.NewRequest(http.MethodGet, "http://registry.example.com/v2/", nil)
+	assert.NilError(t, err)
+	proxy, err := NewTransport().Proxy(req)
+	assert.NilError(t, err)
+	assert.Assert(t, proxy == nil)
+
+	req, err = http.NewRequest(http.MethodGet, "http://other.example.com/v2/", nil)
+	assert.NilError(t, err)
+	proxy, err = NewTransport().Proxy(req)
+	assert.NilError(t, err)
+	assert.Equal(t, proxy.String(), "http://proxy.example.com:3128")
+}
+
+func TestClientTrustsSSLCertFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("trusted"))
+	}))
+	defer server.Close()
+
+	setenv(t, "NO_PROXY", "*")
+	setenv(t, "SSL_CERT_FILE", "")
+	_, err := New().Get(server.URL)
+	assert.ErrorContains(t, err, "certificate")
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NilError(t, ioutil.WriteFile(bundle, cert, 0600))
+	setenv(t, "SSL_CERT_FILE", bundle)
+
+	res, err := New().Get(server.URL)
+	assert.NilError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+	body, err := ioutil.ReadAll(res.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, string(body), "trusted")
+}
+
+func setenv(t *testing.T, key, value string) {
+	previous, ok := os.LookupEnv(key)
+	assert.NilError(t, os.Setenv(key, value))
+	t.Cleanup(func() {
+		if ok {
+			_ = os.Setenv(key, previous)
+		} else {
+			_ = os.Unsetenv(key)
+		}
+	})
+}